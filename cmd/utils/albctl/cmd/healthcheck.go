@@ -0,0 +1,134 @@
+package cmd
+
+import (
+	"crypto/tls"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+	"time"
+
+	"github.com/markkurossi/tabulate"
+	"github.com/spf13/cobra"
+)
+
+// state of endpoint reported by the /healthcheck api of metrics server, see balancer/healthcheck.lua.
+type EndpointHealth struct {
+	Status    string  `json:"status"`
+	Successes int     `json:"successes"`
+	Failures  int     `json:"failures"`
+	Msg       string  `json:"msg"`
+	CheckedAt float64 `json:"checked_at"`
+}
+
+type HealthCheckFlags struct {
+	Addr     string
+	Token    string
+	JsonMode bool
+}
+
+var HC_FLAG = HealthCheckFlags{}
+
+var healthCheckCmd = &cobra.Command{
+	Use:   "healthcheck",
+	Short: "show the state of endpoints reported by active health check, should be run in the alb pod",
+	// it talks to nginx directly, kubeconfig is not needed.
+	PersistentPreRunE: func(cmd *cobra.Command, args []string) error {
+		return nil
+	},
+	RunE: func(cmd *cobra.Command, args []string) error {
+		status, err := fetchHealthCheck(HC_FLAG)
+		if err != nil {
+			return err
+		}
+		if HC_FLAG.JsonMode {
+			out, err := json.MarshalIndent(status, "", "  ")
+			if err != nil {
+				return err
+			}
+			fmt.Println(string(out))
+			return nil
+		}
+		showHealthCheck(status)
+		return nil
+	},
+}
+
+func init() {
+	rootCmd.AddCommand(healthCheckCmd)
+	flags := healthCheckCmd.Flags()
+	flags.StringVar(&HC_FLAG.Addr, "addr", "https://127.0.0.1:1936", "address of the metrics server of alb")
+	flags.StringVar(&HC_FLAG.Token, "token", "", "bearer token used by the metrics auth")
+	flags.BoolVar(&HC_FLAG.JsonMode, "json", false, "output full json")
+}
+
+func fetchHealthCheck(flag HealthCheckFlags) (map[string]map[string]EndpointHealth, error) {
+	cli := http.Client{
+		Timeout: 10 * time.Second,
+		Transport: &http.Transport{
+			// the metrics server uses a self-signed cert.
+			TLSClientConfig: &tls.Config{InsecureSkipVerify: true}, //nolint:gosec
+		},
+	}
+	req, err := http.NewRequest(http.MethodGet, flag.Addr+"/healthcheck", nil)
+	if err != nil {
+		return nil, err
+	}
+	if flag.Token != "" {
+		req.Header.Set("Authorization", "Bearer "+flag.Token)
+	}
+	resp, err := cli.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("fetch health check fail %d %s", resp.StatusCode, string(body))
+	}
+	status := map[string]map[string]EndpointHealth{}
+	if err := json.Unmarshal(body, &status); err != nil {
+		return nil, err
+	}
+	return status, nil
+}
+
+func showHealthCheck(status map[string]map[string]EndpointHealth) {
+	tab := tabulate.New(tabulate.Plain)
+	for _, h := range []string{"BACKEND_GROUP", "ENDPOINT", "STATUS", "SUCCESSES", "FAILURES", "CHECKED_AT", "MSG"} {
+		tab.Header(h)
+	}
+	groups := []string{}
+	for g := range status {
+		groups = append(groups, g)
+	}
+	sort.Strings(groups)
+	for _, g := range groups {
+		eps := []string{}
+		for ep := range status[g] {
+			eps = append(eps, ep)
+		}
+		sort.Strings(eps)
+		for _, ep := range eps {
+			s := status[g][ep]
+			row := tab.Row()
+			for _, c := range []string{
+				g,
+				ep,
+				s.Status,
+				fmt.Sprintf("%d", s.Successes),
+				fmt.Sprintf("%d", s.Failures),
+				time.Unix(int64(s.CheckedAt), 0).Format(time.RFC3339),
+				s.Msg,
+			} {
+				row.Column(c)
+			}
+		}
+	}
+	tab.Print(os.Stdout)
+}
@@ -9,7 +9,14 @@ import (
 	"text/template"
 
 	. "alauda.io/alb2/pkg/controller/ext/auth/types"
+	. "alauda.io/alb2/pkg/controller/ext/clienttls/types"
+	. "alauda.io/alb2/pkg/controller/ext/errorpage/types"
+	. "alauda.io/alb2/pkg/controller/ext/healthcheck/types"
+	. "alauda.io/alb2/pkg/controller/ext/ipacl/types"
+	. "alauda.io/alb2/pkg/controller/ext/proxy/types"
+	. "alauda.io/alb2/pkg/controller/ext/ratelimit/types"
 	. "alauda.io/alb2/pkg/controller/ext/redirect/types"
+	. "alauda.io/alb2/pkg/controller/ext/retry/types"
 	. "alauda.io/alb2/pkg/controller/ext/timeout/types"
 	"github.com/kr/pretty"
 )
@@ -126,6 +133,83 @@ func main() {
 				},
 			},
 		},
+		{
+			base: "./pkg/controller/ext/ratelimit/types/",
+			pkg:  "types",
+			annotations_mapping: []struct {
+				to reflect.Type
+			}{
+				{
+					to: reflect.TypeOf((*RateLimitIngress)(nil)).Elem(),
+				},
+			},
+		},
+		{
+			base: "./pkg/controller/ext/retry/types/",
+			pkg:  "types",
+			annotations_mapping: []struct {
+				to reflect.Type
+			}{
+				{
+					to: reflect.TypeOf((*RetryIngress)(nil)).Elem(),
+				},
+			},
+		},
+		{
+			base: "./pkg/controller/ext/ipacl/types/",
+			pkg:  "types",
+			annotations_mapping: []struct {
+				to reflect.Type
+			}{
+				{
+					to: reflect.TypeOf((*IpAclIngress)(nil)).Elem(),
+				},
+			},
+		},
+		{
+			base: "./pkg/controller/ext/clienttls/types/",
+			pkg:  "types",
+			annotations_mapping: []struct {
+				to reflect.Type
+			}{
+				{
+					to: reflect.TypeOf((*ClientTLSIngress)(nil)).Elem(),
+				},
+			},
+		},
+		{
+			base: "./pkg/controller/ext/healthcheck/types/",
+			pkg:  "types",
+			annotations_mapping: []struct {
+				to reflect.Type
+			}{
+				{
+					to: reflect.TypeOf((*HealthCheckIngress)(nil)).Elem(),
+				},
+			},
+		},
+		{
+			base: "./pkg/controller/ext/proxy/types/",
+			pkg:  "types",
+			annotations_mapping: []struct {
+				to reflect.Type
+			}{
+				{
+					to: reflect.TypeOf((*ProxyIngress)(nil)).Elem(),
+				},
+			},
+		},
+		{
+			base: "./pkg/controller/ext/errorpage/types/",
+			pkg:  "types",
+			annotations_mapping: []struct {
+				to reflect.Type
+			}{
+				{
+					to: reflect.TypeOf((*ErrorPageIngress)(nil)).Elem(),
+				},
+			},
+		},
 	}
 	for _, cfg := range cfg_list {
 		f := cfg.base + "codegen_mapping.go"
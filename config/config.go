@@ -42,8 +42,10 @@ type ExtraConfig struct {
 	DisablePeriodGenNginxConfig bool
 	E2eTestControllerOnly       bool
 
-	Interval             int
-	ReloadTimeout        int
+	Interval      int
+	ReloadTimeout int
+	// milliseconds to merge the changes of resources into one regeneration of nginx config.
+	ReloadDebounce       int
 	DebugRuleSync        bool
 	NginxTemplatePath    string
 	NewConfigPath        string
@@ -52,7 +54,15 @@ type ExtraConfig struct {
 	TweakDir             string
 	K8s                  K8sConfig
 	StatusFileParentPath string
-	Leader               LeaderConfig
+	// dir shared with nginx container, used to run nginx -t in nginx container.
+	NginxCheckDir string
+	// seconds to wait for the result of nginx -t.
+	NginxCheckTimeout int
+	// dir to keep the last known good nginx.conf and policy.
+	LastGoodDir string
+	// days before the expiry of certificate to emit warning events.
+	CertExpiryWarningDays int
+	Leader                LeaderConfig
 }
 
 type LeaderConfig struct {
@@ -95,10 +105,15 @@ func ExtraFlagsFromEnv(env map[string]string) ExtraConfig {
 		TweakDir:                    TWEAK_DIR_VAL,
 		Interval:                    ToIntOr(env[INTERVAL], INTERVAL_VAL),
 		ReloadTimeout:               ToIntOr(env[RELOAD_TIMEOUT], DEFAULT_RELOAD_TIMEOUT_VAL),
+		ReloadDebounce:              ToIntOr(env[RELOAD_DEBOUNCE], RELOAD_DEBOUNCE_VAL),
 		Pod:                         env[MY_POD_NAME],
 		DebugRuleSync:               ToBoolOr(env[DEBUG_RULESYNC], false),
 		K8s:                         k8sFromEnv(env),
 		StatusFileParentPath:        STATUS_FILE_PARENT_PATH_VAL,
+		NginxCheckDir:               NGINX_CHECK_DIR_VAL,
+		NginxCheckTimeout:           NGINX_CHECK_TIMEOUT_VAL,
+		LastGoodDir:                 LAST_GOOD_DIR_VAL,
+		CertExpiryWarningDays:       ToIntOr(env[CERT_EXPIRY_DAYS], CERT_EXPIRY_DAYS_VAL),
 		Leader: LeaderConfig{
 			LeaseDuration: time.Second * time.Duration(120),
 			RenewDeadline: time.Second * time.Duration(40),
@@ -185,6 +200,10 @@ func (c *Config) GetReloadTimeout() int {
 	return c.ExtraConfig.ReloadTimeout
 }
 
+func (c *Config) GetReloadDebounce() int {
+	return c.ExtraConfig.ReloadDebounce
+}
+
 func (c *Config) GetResyncPeriod() int {
 	return c.Controller.ResyncPeriod
 }
@@ -321,6 +340,22 @@ func (c *Config) GetStatusFile() string {
 	return c.StatusFileParentPath
 }
 
+func (c *Config) GetNginxCheckDir() string {
+	return c.NginxCheckDir
+}
+
+func (c *Config) GetNginxCheckTimeout() int {
+	return c.NginxCheckTimeout
+}
+
+func (c *Config) GetLastGoodDir() string {
+	return c.LastGoodDir
+}
+
+func (c *Config) GetCertExpiryWarningDays() int {
+	return c.CertExpiryWarningDays
+}
+
 func (c *Config) GetDefaultSSLCert() string {
 	return c.Controller.SSLCert
 }
@@ -5,20 +5,27 @@ import (
 	"fmt"
 	"net/http"
 	"net/http/pprof"
+	"os"
 	"time"
 
 	"alauda.io/alb2/config"
 	ctl "alauda.io/alb2/controller"
+	"alauda.io/alb2/controller/cli"
 	"alauda.io/alb2/controller/modules"
 	"alauda.io/alb2/controller/state"
 	"alauda.io/alb2/driver"
 	gctl "alauda.io/alb2/gateway/ctl"
 	"alauda.io/alb2/ingress"
+	albscheme "alauda.io/alb2/pkg/client/clientset/versioned/scheme"
 	pm "alauda.io/alb2/pkg/utils/metrics"
 	"alauda.io/alb2/utils"
 	"alauda.io/alb2/utils/log"
 	"github.com/go-logr/logr"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+	corev1 "k8s.io/api/core/v1"
+	typedcorev1 "k8s.io/client-go/kubernetes/typed/core/v1"
 	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/record"
 )
 
 type Alb struct {
@@ -27,16 +34,29 @@ type Alb struct {
 	albCfg    *config.Config
 	le        *ctl.LeaderElection
 	portProbe *ctl.PortProbe
-	log       logr.Logger
+	recorder  record.EventRecorder
+	// the nginx controller is created in each regeneration, the cache should be kept here.
+	policyCache *cli.PolicyCache
+	certWarner  *ctl.CertWarner
+	// the objects referenced by the last regeneration, the changes of others are ignored.
+	serviceRefs   *ctl.ObjectRefs
+	secretRefs    *ctl.ObjectRefs
+	configMapRefs *ctl.ObjectRefs
+	log           logr.Logger
 }
 
 func NewAlb(ctx context.Context, restCfg *rest.Config, albCfg *config.Config, le *ctl.LeaderElection, log logr.Logger) *Alb {
 	return &Alb{
-		ctx:    ctx,
-		cfg:    restCfg,
-		albCfg: albCfg,
-		le:     le,
-		log:    log,
+		ctx:           ctx,
+		cfg:           restCfg,
+		albCfg:        albCfg,
+		le:            le,
+		policyCache:   cli.NewPolicyCache(),
+		certWarner:    ctl.NewCertWarner(),
+		serviceRefs:   ctl.NewObjectRefs(),
+		secretRefs:    ctl.NewObjectRefs(),
+		configMapRefs: ctl.NewObjectRefs(),
+		log:           log,
 	}
 }
 
@@ -62,6 +82,8 @@ func (a *Alb) Start() error {
 		return err
 	}
 
+	a.recorder = newEventRecorder(drv, a.albCfg, l.WithName("event"))
+
 	if a.albCfg.Controller.Flags.EnablePortProbe {
 		port, err := ctl.NewPortProbe(a.ctx, drv, l.WithName("portprobe"), a.albCfg)
 		if err != nil {
@@ -123,60 +145,129 @@ func (a *Alb) Start() error {
 }
 
 // start reload alb, block util ctx is done
-// reload in every INTERVAL sec
+// the nginx config is regenerated on the changes of resources (debounced), and resynced every RESYNC_PERIOD sec as a safety net.
 // it will gc rules, generate nginx config and reload nginx, assume that those cr really take effect.
-// TODO add a work queue.
 func (a *Alb) StartReloadLoadBalancerLoop(drv *driver.KubernetesDriver, ctx context.Context) {
 	interval := time.Duration(a.albCfg.GetInterval()) * time.Second
 	reloadTimeout := time.Duration(a.albCfg.GetReloadTimeout()) * time.Second
+	debounce := time.Duration(a.albCfg.GetReloadDebounce()) * time.Millisecond
+	resync := time.Duration(a.albCfg.GetResyncPeriod()) * time.Second
+	if resync < interval {
+		resync = interval
+	}
 	l := a.log
-	l.Info("start reload loop", "interval", interval, "reload timeout", reloadTimeout)
-
-	isTimeout := utils.UtilWithContextAndTimeout(ctx, func() {
-		startTime := time.Now()
-
-		nctl := ctl.NewNginxController(drv, ctx, a.albCfg, l.WithName("nginx"), a.le)
-		nctl.PortProber = a.portProbe
+	l.Info("start reload loop", "interval", interval, "resync", resync, "debounce", debounce, "reload timeout", reloadTimeout)
 
-		l.Info("reload: ctl init", "cost", time.Since(startTime))
-		// do leader stuff
-		if a.le.AmILeader() {
-			if a.portProbe != nil {
+	// do leader stuff
+	go func() {
+		isTimeout := utils.UtilWithContextAndTimeout(ctx, func() {
+			if a.le.AmILeader() && a.portProbe != nil {
 				err := a.portProbe.LeaderUpdateAlbPortStatus()
 				if err != nil {
 					l.Error(err, "leader update alb status fail")
 				}
 			}
+		}, reloadTimeout, interval)
+		if isTimeout {
+			l.Error(nil, "leader update alb status timeout")
 		}
-		l.Info("reload: leader update", "cost", time.Since(startTime))
+	}()
 
-		if a.albCfg.GetFlags().DisablePeriodGenNginxConfig {
-			l.Info("reload: period regenerated config disabled")
-			return
-		}
+	trigger := ctl.NewReloadTrigger(debounce, resync, l.WithName("trigger"))
+	if err := a.watchChanges(drv, trigger); err != nil {
+		l.Error(err, "watch changes fail, only resync")
+	}
 
-		if err := nctl.GenerateConf(); err != nil {
-			l.Error(err, "generate conf failed")
-			return
+	// the regeneration which is still running after the timeout. there is no way to stop it, the following regenerations are skipped
+	// until it finishes, since they share the cache and the files. a regeneration is triggered again when it finishes.
+	var stuck chan struct{}
+	trigger.Run(ctx, func(kind string, since time.Time) {
+		if stuck != nil {
+			select {
+			case <-stuck:
+				stuck = nil
+			default:
+				l.Error(nil, "reload: the last regeneration is still running, skip", "kind", kind)
+				ctl.ObserveRegenerateAbort(kind, ctl.RegenerateSkip)
+				return
+			}
 		}
-		l.Info("time", "policy-gen", pm.Read())
-
-		if err := nctl.ReloadLoadBalancer(); err != nil {
-			l.Error(err, "reload load balancer failed")
+		rctx, cancel := context.WithTimeout(ctx, reloadTimeout)
+		done := make(chan struct{})
+		var err error
+		go func() {
+			defer close(done)
+			err = a.regenerate(drv, rctx, kind)
+		}()
+		select {
+		case <-done:
+			cancel()
+			ctl.ObserveRegenerate(kind, since, err)
+			if err == nil {
+				ctl.CleanRegenerateTimeout(drv, a.albCfg, l)
+			}
+		case <-time.After(reloadTimeout):
+			cancel()
+			l.Error(nil, "reload timeout, skip this regeneration", "kind", kind, "timeout", reloadTimeout)
+			ctl.ObserveRegenerateAbort(kind, ctl.RegenerateTimeout)
+			ctl.ReportRegenerateTimeout(drv, a.albCfg, a.recorder, reloadTimeout, l)
+			stuck = done
+			go func() {
+				<-done
+				l.Info("reload: the timeout regeneration finishes", "kind", kind)
+				trigger.Notify(kind)
+			}()
 		}
+	})
 
-		l.Info("reload: End update reload loop", "cost", time.Since(startTime))
-	}, reloadTimeout, interval)
+	l.Info("reload: end of reload loop")
+}
 
-	// TODO did we ready need this?
-	// just crash if timeout
-	if isTimeout {
-		l.Error(nil, "reload timeout")
-		// TODO release leader..
-		panic(fmt.Sprintf("reach the timeout %v", reloadTimeout))
+func (a *Alb) regenerate(drv *driver.KubernetesDriver, ctx context.Context, kind string) error {
+	l := a.log
+	startTime := time.Now()
+	if a.albCfg.GetFlags().DisablePeriodGenNginxConfig {
+		l.Info("reload: period regenerated config disabled")
+		return nil
 	}
 
-	l.Info("reload: end of reload loop")
+	nctl := ctl.NewNginxController(drv, ctx, a.albCfg, l.WithName("nginx"), a.le)
+	nctl.PortProber = a.portProbe
+	nctl.Recorder = a.recorder
+	nctl.PolicyCache = a.policyCache
+	nctl.CertWarner = a.certWarner
+	nctl.ServiceRefs = a.serviceRefs
+	nctl.SecretRefs = a.secretRefs
+	nctl.ConfigMapRefs = a.configMapRefs
+	l.Info("reload: ctl init", "kind", kind, "cost", time.Since(startTime))
+
+	if err := nctl.GenerateConf(); err != nil {
+		l.Error(err, "generate conf failed")
+		return err
+	}
+	l.Info("time", "policy-gen", pm.Read())
+
+	if err := nctl.ReloadLoadBalancer(); err != nil {
+		l.Error(err, "reload load balancer failed")
+		return err
+	}
+
+	l.Info("reload: End update reload loop", "cost", time.Since(startTime))
+	return nil
+}
+
+// events of alb itself, e.g. the nginx.conf is rejected by nginx -t.
+func newEventRecorder(drv *driver.KubernetesDriver, cfg *config.Config, log logr.Logger) record.EventRecorder {
+	broadcaster := record.NewBroadcaster()
+	broadcaster.StartLogging(func(fmts string, args ...interface{}) {
+		log.Info(fmt.Sprintf(fmts, args...))
+	})
+	broadcaster.StartRecordingToSink(&typedcorev1.EventSinkImpl{Interface: drv.Client.CoreV1().Events("")})
+	hostname, err := os.Hostname()
+	if err != nil {
+		hostname = "unknown"
+	}
+	return broadcaster.NewRecorder(albscheme.Scheme, corev1.EventSource{Component: fmt.Sprintf("alb2-%s", cfg.GetAlbName()), Host: hostname})
 }
 
 func (a *Alb) StartGoMonitorLoop(ctx context.Context) {
@@ -195,6 +286,7 @@ func (a *Alb) StartGoMonitorLoop(ctx context.Context) {
 	log.Info("init", "port", port)
 
 	mux := http.NewServeMux()
+	mux.Handle("/metrics", promhttp.HandlerFor(ctl.MetricsRegistry, promhttp.HandlerOpts{}))
 
 	if flags.EnableProfile {
 		mux.HandleFunc("/debug/pprof/", pprof.Index)
@@ -0,0 +1,182 @@
+package alb
+
+import (
+	"fmt"
+	"reflect"
+
+	"alauda.io/alb2/config"
+	ctl "alauda.io/alb2/controller"
+	"alauda.io/alb2/driver"
+	corev1 "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"k8s.io/client-go/metadata"
+	"k8s.io/client-go/metadata/metadatainformer"
+	"k8s.io/client-go/tools/cache"
+)
+
+type watchTarget struct {
+	kind     string
+	informer cache.SharedIndexInformer
+	filter   func(obj interface{}) bool
+	// only the change of spec matters for the cr with status subresource, the status is updated by alb/operator frequently.
+	specOnly bool
+}
+
+// watchChanges notify the trigger on the changes of resources which are used to generate the nginx config.
+// the informers are shared with others, only the event handlers are added here, except the secret and configmap ones.
+// the secret one is shared with the policy cache.
+func (a *Alb) watchChanges(drv *driver.KubernetesDriver, trigger *ctl.ReloadTrigger) error {
+	inf := drv.Informers
+	n := config.NewNames(a.albCfg.GetDomain())
+	albName := a.albCfg.GetAlbName()
+	// ft and rule of other albs in the same ns should be ignored.
+	belongToAlb := func(obj interface{}) bool {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return true
+		}
+		return m.GetLabels()[n.GetLabelAlbName()] == albName
+	}
+	isAlb := func(obj interface{}) bool {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return true
+		}
+		return m.GetName() == albName
+	}
+	// services not used by the last regeneration are ignored. a new reference comes with the change of rule/ft/route,
+	// which triggers a regeneration anyway.
+	isRefService := func(obj interface{}) bool {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return true
+		}
+		return a.serviceRefs.Has(m.GetNamespace(), m.GetName())
+	}
+	// the same for secrets and configmaps, a new reference comes with the change of rule/ft/route/ingress.
+	isRefSecret := func(obj interface{}) bool {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return true
+		}
+		return a.secretRefs.Has(m.GetNamespace(), m.GetName())
+	}
+	isRefConfigMap := func(obj interface{}) bool {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return true
+		}
+		return a.configMapRefs.Has(m.GetNamespace(), m.GetName())
+	}
+	isRefSlice := func(obj interface{}) bool {
+		m, err := meta.Accessor(obj)
+		if err != nil {
+			return true
+		}
+		return a.serviceRefs.Has(m.GetNamespace(), m.GetLabels()[discoveryv1.LabelServiceName])
+	}
+	targets := []watchTarget{
+		{kind: "ALB2", informer: inf.Alb.Alb.Informer(), filter: isAlb, specOnly: true},
+		{kind: "Frontend", informer: inf.Alb.Ft.Informer(), filter: belongToAlb, specOnly: true},
+		{kind: "Rule", informer: inf.Alb.Rule.Informer(), filter: belongToAlb},
+		{kind: "Service", informer: inf.K8s.Service.Informer(), filter: isRefService},
+		{kind: "Endpoints", informer: inf.K8s.Endpoint.Informer(), filter: isRefService},
+		{kind: "EndpointSlice", informer: inf.K8s.EndpointSlice.Informer(), filter: isRefSlice},
+	}
+	if a.albCfg.Gateway.Enable {
+		targets = append(targets,
+			watchTarget{kind: "Gateway", informer: inf.Gateway.Gateway.Informer()},
+			watchTarget{kind: "HTTPRoute", informer: inf.Gateway.HttpRoute.Informer()},
+			// the sessionPersistence is read from another informer, the regeneration should wait for it too.
+			watchTarget{kind: "HTTPRoute", informer: inf.Gateway.HttpRouteSessionPersistence.Informer(), specOnly: true},
+			watchTarget{kind: "TCPRoute", informer: inf.Gateway.TcpRoute.Informer()},
+			watchTarget{kind: "UDPRoute", informer: inf.Gateway.UdpRoute.Informer()},
+			watchTarget{kind: "TLSRoute", informer: inf.Gateway.TlsRoute.Informer()},
+			watchTarget{kind: "GRPCRoute", informer: inf.Gateway.GrpcRoute.Informer()},
+			watchTarget{kind: "BackendTLSPolicy", informer: inf.Gateway.BackendTLSPolicy.Informer()},
+			watchTarget{kind: "TimeoutPolicy", informer: inf.Alb.TimeoutPolicy.Informer(), specOnly: true},
+			watchTarget{kind: "RetryPolicy", informer: inf.Alb.RetryPolicy.Informer(), specOnly: true},
+		)
+	}
+	secret, err := a.metadataInformer("secrets")
+	if err != nil {
+		return err
+	}
+	configmap, err := a.metadataInformer("configmaps")
+	if err != nil {
+		return err
+	}
+	targets = append(targets,
+		watchTarget{kind: "Secret", informer: secret, filter: isRefSecret},
+		watchTarget{kind: "ConfigMap", informer: configmap, filter: isRefConfigMap},
+	)
+	a.policyCache.UseSecretInformer(secret)
+
+	for _, t := range targets {
+		if _, err := t.informer.AddEventHandler(changeHandler(t, trigger)); err != nil {
+			return fmt.Errorf("watch %s fail %v", t.kind, err)
+		}
+	}
+	go secret.Run(a.ctx.Done())
+	go configmap.Run(a.ctx.Done())
+	return nil
+}
+
+// only the metadata of secrets and configmaps is watched, the content is read from apiserver when generating the config.
+// the cert is read only when the version of secret changed, see PolicyCache.
+func (a *Alb) metadataInformer(resource string) (cache.SharedIndexInformer, error) {
+	cli, err := metadata.NewForConfig(a.cfg)
+	if err != nil {
+		return nil, err
+	}
+	gvr := corev1.SchemeGroupVersion.WithResource(resource)
+	return metadatainformer.NewFilteredMetadataInformer(cli, gvr, "", 0, cache.Indexers{}, nil).Informer(), nil
+}
+
+func changed(oldObj, newObj interface{}, specOnly bool) bool {
+	o, oerr := meta.Accessor(oldObj)
+	n, nerr := meta.Accessor(newObj)
+	if oerr != nil || nerr != nil {
+		return true
+	}
+	if !specOnly {
+		return o.GetResourceVersion() != n.GetResourceVersion()
+	}
+	return o.GetGeneration() != n.GetGeneration() ||
+		!reflect.DeepEqual(o.GetLabels(), n.GetLabels()) ||
+		!reflect.DeepEqual(o.GetAnnotations(), n.GetAnnotations())
+}
+
+func changeHandler(t watchTarget, trigger *ctl.ReloadTrigger) cache.ResourceEventHandler {
+	kind := t.kind
+	filter := t.filter
+	if filter == nil {
+		filter = func(obj interface{}) bool { return true }
+	}
+	return cache.FilteringResourceEventHandler{
+		FilterFunc: func(obj interface{}) bool {
+			if d, ok := obj.(cache.DeletedFinalStateUnknown); ok {
+				obj = d.Obj
+			}
+			return filter(obj)
+		},
+		Handler: cache.ResourceEventHandlerDetailedFuncs{
+			AddFunc: func(obj interface{}, isInInitialList bool) {
+				// the initial list is handled by the first regeneration.
+				if isInInitialList {
+					return
+				}
+				trigger.Notify(kind)
+			},
+			UpdateFunc: func(oldObj, newObj interface{}) {
+				if changed(oldObj, newObj, t.specOnly) {
+					trigger.Notify(kind)
+				}
+			},
+			DeleteFunc: func(obj interface{}) {
+				trigger.Notify(kind)
+			},
+		},
+	}
+}
@@ -0,0 +1,100 @@
+package controller
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+
+	"alauda.io/alb2/controller/cli"
+	. "alauda.io/alb2/controller/types"
+	albv2 "alauda.io/alb2/pkg/apis/alauda/v2beta1"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/equality"
+)
+
+// the warning event of a certificate is emitted at most once in this interval.
+const certWarnInterval = 24 * time.Hour
+
+// CertWarner remember when the warning event of a certificate is emitted, it is only used in the goroutine of regeneration.
+// the nginx controller is created in each regeneration, so it should be kept by the caller.
+type CertWarner struct {
+	warned map[string]time.Time
+}
+
+func NewCertWarner() *CertWarner {
+	return &CertWarner{warned: map[string]time.Time{}}
+}
+
+type certWarning struct {
+	Secret   string
+	NotAfter time.Time
+	Domains  []string
+}
+
+// due return the certificates which expire within the window and have not been warned in certWarnInterval, grouped by secret.
+func (w *CertWarner) due(certs []albv2.CertificateStatus, now time.Time, window time.Duration) []certWarning {
+	group := map[string]*certWarning{}
+	keys := []string{}
+	for _, c := range certs {
+		if c.NotAfter.Sub(now) > window {
+			continue
+		}
+		// the secret may be updated with a new cert, which should be warned again.
+		key := fmt.Sprintf("%s/%d", c.Secret, c.NotAfter.Unix())
+		if last, ok := w.warned[key]; ok && now.Sub(last) < certWarnInterval {
+			continue
+		}
+		if _, ok := group[key]; !ok {
+			group[key] = &certWarning{Secret: c.Secret, NotAfter: c.NotAfter.Time}
+			keys = append(keys, key)
+		}
+		group[key].Domains = append(group[key].Domains, c.Domain)
+	}
+	sort.Strings(keys)
+	ret := []certWarning{}
+	for _, k := range keys {
+		w.warned[k] = now
+		ret = append(ret, *group[k])
+	}
+	return ret
+}
+
+// reportCertificates log the certificates which do not cover the domain. the leader also updates the status of alb,
+// and emits warning events for the certificates which are going to expire.
+// the expiry metrics is exported by nginx from the certificate_expiry of policy, see GenCertExpiry.
+func (nc *NginxController) reportCertificates(certMap map[string]Certificate) {
+	certs := cli.GenCertStatus(certMap)
+	now := time.Now()
+	for _, c := range certs {
+		if c.Mismatch {
+			nc.log.Info("the domain is not covered by the certificate", "domain", c.Domain, "secret", c.Secret, "sans", c.SANs)
+		}
+	}
+	if nc.Driver == nil || nc.lc == nil || !nc.lc.AmILeader() {
+		return
+	}
+	ns, name := nc.albcfg.GetAlbNsAndName()
+	alb, err := nc.Driver.LoadAlbResource(ns, name)
+	if err != nil {
+		nc.log.Error(err, "get alb fail")
+		return
+	}
+	if !equality.Semantic.DeepEqual(alb.Status.Detail.Certificates, certs) {
+		if err := nc.Driver.PatchAlbCertificates(ns, name, certs); err != nil {
+			nc.log.Error(err, "update certificates status fail")
+		}
+	}
+	if nc.Recorder == nil || nc.CertWarner == nil {
+		return
+	}
+	window := time.Duration(nc.albcfg.GetCertExpiryWarningDays()) * 24 * time.Hour
+	for _, w := range nc.CertWarner.due(certs, now, window) {
+		domains := strings.Join(w.Domains, ",")
+		if w.NotAfter.Before(now) {
+			nc.Recorder.Eventf(alb, corev1.EventTypeWarning, "CertificateExpired", "certificate in secret %s used by %s has expired at %s", w.Secret, domains, w.NotAfter.Format(time.RFC3339))
+			continue
+		}
+		nc.Recorder.Eventf(alb, corev1.EventTypeWarning, "CertificateExpiring", "certificate in secret %s used by %s will expire at %s", w.Secret, domains, w.NotAfter.Format(time.RFC3339))
+	}
+}
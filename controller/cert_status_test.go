@@ -0,0 +1,35 @@
+package controller
+
+import (
+	"testing"
+	"time"
+
+	albv2 "alauda.io/alb2/pkg/apis/alauda/v2beta1"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestCertWarnerDue(t *testing.T) {
+	now := time.Now().Truncate(time.Second)
+	window := 30 * 24 * time.Hour
+	soon := metav1.Time{Time: now.Add(24 * time.Hour)}
+	certs := []albv2.CertificateStatus{
+		{Domain: "a.com", Secret: "ns/a", NotAfter: soon},
+		{Domain: "443", Secret: "ns/a", NotAfter: soon},
+		{Domain: "b.com", Secret: "ns/b", NotAfter: metav1.Time{Time: now.Add(-time.Hour)}},
+		{Domain: "c.com", Secret: "ns/c", NotAfter: metav1.Time{Time: now.Add(60 * 24 * time.Hour)}},
+	}
+	w := NewCertWarner()
+	assert.Equal(t, []certWarning{
+		{Secret: "ns/a", NotAfter: soon.Time, Domains: []string{"a.com", "443"}},
+		{Secret: "ns/b", NotAfter: now.Add(-time.Hour), Domains: []string{"b.com"}},
+	}, w.due(certs, now, window))
+
+	// warned at most once in a day.
+	assert.Empty(t, w.due(certs, now.Add(time.Hour), window))
+	assert.Len(t, w.due(certs, now.Add(25*time.Hour), window), 2)
+
+	// the renewed cert is warned again if it is still in the window.
+	renewed := []albv2.CertificateStatus{{Domain: "a.com", Secret: "ns/a", NotAfter: metav1.Time{Time: now.Add(48 * time.Hour)}}}
+	assert.Len(t, w.due(renewed, now.Add(26*time.Hour), window), 1)
+}
@@ -62,6 +62,8 @@ func (c *AlbCli) RuleToInternalRule(mr *modules.Rule, ir *InternalRule) {
 		up.BackendProtocol = strings.ToLower(mrs.BackendProtocol)
 		up.SessionAffinityPolicy = mrs.ServiceGroup.SessionAffinityPolicy
 		up.SessionAffinityAttr = mrs.ServiceGroup.SessionAffinityAttribute
+		up.SessionAffinityCookie = mrs.ServiceGroup.SessionAffinityCookie
+		up.HealthCheck = mrs.ServiceGroup.HealthCheck
 		if up.Services == nil {
 			up.Services = []*BackendService{}
 		}
@@ -134,10 +136,14 @@ func (c *AlbCli) GetLBConfig(ns string, name string) (*LoadBalancer, error) {
 		}
 
 		c.log.Info("rule", "ft", ft.FtName, "rule", len(mft.Rules))
+		ftVersion := objectVersion(mAlb.Alb.ObjectMeta) + "/" + objectVersion(mft.ObjectMeta)
 		// translate rule cr to our rule struct
 		for _, marl := range mft.Rules {
 			rule := &InternalRule{}
 			c.RuleToInternalRule(marl, rule)
+			if marl.ResourceVersion != "" {
+				rule.Version = marl.ResourceVersion + "/" + ftVersion
+			}
 			ft.Rules = append(ft.Rules, rule)
 		}
 
@@ -148,6 +154,8 @@ func (c *AlbCli) GetLBConfig(ns string, name string) (*LoadBalancer, error) {
 				Name:                     ft.String(),
 				SessionAffinityAttribute: mft.Spec.ServiceGroup.SessionAffinityAttribute,
 				SessionAffinityPolicy:    mft.Spec.ServiceGroup.SessionAffinityPolicy,
+				SessionAffinityCookie:    mft.Spec.ServiceGroup.SessionAffinityCookie,
+				HealthCheck:              toHealthCheckPolicy(mft.Spec.ServiceGroup.HealthCheck, ft.String(), c.log),
 			}
 
 			for _, svc := range mft.Spec.ServiceGroup.Services {
@@ -180,6 +188,12 @@ func (c *AlbCli) CollectAndFetchRefs(lb *LoadBalancer) {
 		e := time.Now()
 		pm.Write("collect-refs", float64(e.UnixMilli())-float64(s.UnixMilli()))
 	}()
+	c.CollectRefs(lb)
+	c.FetchRefs(lb)
+}
+
+// CollectRefs collect the configmaps and secrets referenced by rules into lb.Refs, they are fetched by FetchRefs.
+func (c *AlbCli) CollectRefs(lb *LoadBalancer) {
 	s_pick_refs := time.Now()
 	for _, ft := range lb.Frontends {
 		for _, rule := range ft.Rules {
@@ -187,6 +201,19 @@ func (c *AlbCli) CollectAndFetchRefs(lb *LoadBalancer) {
 		}
 	}
 	pm.Write("collect-refs/pick-refs", float64(time.Since(s_pick_refs).Milliseconds()))
+}
+
+// ReferencedConfigMaps return the configmaps referenced by extensions, it should be called after CollectRefs.
+func ReferencedConfigMaps(alb *LoadBalancer) map[client.ObjectKey]bool {
+	ret := map[client.ObjectKey]bool{}
+	for k := range alb.Refs.ConfigMap {
+		ret[k] = true
+	}
+	return ret
+}
+
+// FetchRefs fetch the configmaps and secrets in lb.Refs, the ones could not be fetched are removed.
+func (c *AlbCli) FetchRefs(lb *LoadBalancer) {
 	for k := range lb.Refs.ConfigMap {
 		cm := &corev1.ConfigMap{}
 		err := c.drv.Cli.Get(c.drv.Ctx, k, cm)
@@ -3,6 +3,7 @@ package cli
 import (
 	"context"
 	"crypto/tls"
+	"crypto/x509"
 	"errors"
 	"fmt"
 	"net"
@@ -15,6 +16,10 @@ import (
 	. "alauda.io/alb2/controller/types"
 	"alauda.io/alb2/driver"
 	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	albv2 "alauda.io/alb2/pkg/apis/alauda/v2beta1"
+	"alauda.io/alb2/pkg/controller/ext/clienttls"
+	clienttls_t "alauda.io/alb2/pkg/controller/ext/clienttls/types"
+	"alauda.io/alb2/pkg/controller/ext/waf"
 	pm "alauda.io/alb2/pkg/utils/metrics"
 	"github.com/go-logr/logr"
 	apiv1 "k8s.io/api/core/v1"
@@ -23,7 +28,7 @@ import (
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
-func getCertMap(alb *LoadBalancer, d *driver.KubernetesDriver) map[string]Certificate {
+func getCertMap(alb *LoadBalancer, d *driver.KubernetesDriver, cache *PolicyCache) map[string]Certificate {
 	s := time.Now()
 	defer func() {
 		pm.Write("gen-cert", float64(time.Since(s).Milliseconds()))
@@ -76,7 +81,7 @@ func getCertMap(alb *LoadBalancer, d *driver.KubernetesDriver) map[string]Certif
 			continue
 		}
 		d.Log.V(3).Info("get cert for domain", "key", secretKey, "domain", domain)
-		cert, err := getCertificateFromSecret(d, secret.Namespace, secret.Name)
+		cert, err := getCertificateWithCache(d, secret, cache)
 		if err != nil {
 			d.Log.Error(err, "get secret failed", "secret", secret)
 			continue
@@ -84,18 +89,163 @@ func getCertMap(alb *LoadBalancer, d *driver.KubernetesDriver) map[string]Certif
 		certMap[domain] = *cert
 		certCache[secretKey] = *cert
 	}
+	getCa := func(ref string) (string, error) {
+		return getCaFromSecret(d, ref)
+	}
+	attachClientCa(certMap, getClientTLSConfig(alb, certProtocol, d.Log), getCa, d.Log)
 	return certMap
 }
 
+// domain/port or port (without sni) / client tls
+func getClientTLSConfig(alb *LoadBalancer, certProtocol map[albv1.FtProtocol]bool, log logr.Logger) map[string]*clienttls_t.ClientTLSCr {
+	ret := map[string]*clienttls_t.ClientTLSCr{}
+	add := func(key string, cfg *clienttls_t.ClientTLSCr) {
+		if !clienttls.Enabled(cfg) {
+			return
+		}
+		if err := clienttls.Valid(cfg); err != nil {
+			log.Error(err, "invalid client tls", "key", key)
+			return
+		}
+		if exist, has := ret[key]; has {
+			if exist.CaSecretRef != cfg.CaSecretRef || clienttls.GetVerifyDepth(exist) != clienttls.GetVerifyDepth(cfg) {
+				log.Info("conflict client tls, the ca is bound to sni, use the first one", "key", key, "use", exist.CaSecretRef, "ignore", cfg.CaSecretRef)
+			}
+			return
+		}
+		ret[key] = cfg
+	}
+	for _, ft := range alb.Frontends {
+		if ft.Conflict || !certProtocol[ft.Protocol] {
+			continue
+		}
+		port := strconv.Itoa(int(ft.Port))
+		add(port, ft.Config.ClientTLS)
+		for _, rule := range ft.Rules {
+			if rule.Domain == "" {
+				continue
+			}
+			add(rule.Domain+"/"+port, rule.Config.ClientTLS)
+		}
+	}
+	return ret
+}
+
+// attachClientCa set the ca of client tls to the cert which is used in the tls handshake of the domain.
+// the cert is looked up in the same order as cert_tool.lua. if the cert is shared with other domains or ports (e.g. wildcard cert),
+// a copy of it is added as domain/port, which has higher priority in lookup.
+func attachClientCa(certMap map[string]Certificate, cfgs map[string]*clienttls_t.ClientTLSCr, getCa func(ref string) (string, error), log logr.Logger) {
+	keys := make([]string, 0, len(cfgs))
+	for k := range cfgs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	caCache := map[string]string{}
+	for _, key := range keys {
+		cfg := cfgs[key]
+		ca, has := caCache[cfg.CaSecretRef]
+		if !has {
+			var err error
+			ca, err = getCa(cfg.CaSecretRef)
+			if err != nil {
+				log.Error(err, "get client ca failed", "key", key, "secret", cfg.CaSecretRef)
+				continue
+			}
+			caCache[cfg.CaSecretRef] = ca
+		}
+		domain, port, withSni := strings.Cut(key, "/")
+		if !withSni {
+			domain, port = "", key
+		}
+		certKey, cert, find := lookupCert(certMap, domain, port)
+		if !find {
+			log.Info("could not find cert for client tls", "key", key)
+			continue
+		}
+		if domain != "" {
+			certKey = key
+		}
+		cert.ClientCa = &CaCertificate{Cert: ca, Depth: clienttls.GetVerifyDepth(cfg), Id: clienttls.CaId(cfg)}
+		certMap[certKey] = cert
+	}
+}
+
+func lookupCert(certMap map[string]Certificate, domain, port string) (string, Certificate, bool) {
+	keys := []string{port}
+	if domain != "" {
+		keys = []string{domain + "/" + port, domain}
+		if index := strings.Index(domain, "."); index != -1 {
+			wildcard := "*" + domain[index:]
+			keys = append(keys, wildcard+"/"+port, wildcard)
+		}
+	}
+	for _, k := range keys {
+		if cert, ok := certMap[k]; ok {
+			return k, cert, true
+		}
+	}
+	return "", Certificate{}, false
+}
+
+func getCaFromSecret(driver *driver.KubernetesDriver, ref string) (string, error) {
+	ns, name, key, err := waf.ParseCmRef(ref)
+	if err != nil {
+		return "", err
+	}
+	secret, err := driver.Client.CoreV1().Secrets(ns).Get(context.TODO(), name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	ca := secret.Data[key]
+	if _, err := certutil.ParseCertsPEM(ca); err != nil {
+		return "", fmt.Errorf("invalid ca in secret %s %w", ref, err)
+	}
+	return string(ca), nil
+}
+
+// the parse of cert is skipped if the secret does not change.
+// with the secret informer, the secret is not read from apiserver either if the version in informer is the cached one.
+func getCertificateWithCache(driver *driver.KubernetesDriver, key client.ObjectKey, cache *PolicyCache) (*Certificate, error) {
+	if cache == nil {
+		return getCertificateFromSecret(driver, key.Namespace, key.Name)
+	}
+	if version, ok := cache.secretVersion(key); ok {
+		if cert, ok := cache.getCert(key, version); ok {
+			return cert, nil
+		}
+	}
+	secret, err := driver.Client.CoreV1().Secrets(key.Namespace).Get(context.TODO(), key.Name, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	if cert, ok := cache.getCert(key, secret.ResourceVersion); ok {
+		return cert, nil
+	}
+	cert, err := certFromSecret(secret)
+	if err != nil {
+		return nil, err
+	}
+	cache.setCert(key, secret.ResourceVersion, cert)
+	return cert, nil
+}
+
 func getCertificateFromSecret(driver *driver.KubernetesDriver, namespace, name string) (*Certificate, error) {
 	secret, err := driver.Client.CoreV1().Secrets(namespace).Get(context.TODO(), name, metav1.GetOptions{})
 	if err != nil {
 		return nil, err
 	}
+	return certFromSecret(secret)
+}
+
+func certFromSecret(secret *apiv1.Secret) (*Certificate, error) {
 	if len(secret.Data[apiv1.TLSCertKey]) == 0 || len(secret.Data[apiv1.TLSPrivateKeyKey]) == 0 {
 		return nil, errors.New("invalid secret")
 	}
-	_, err = tls.X509KeyPair(secret.Data[apiv1.TLSCertKey], secret.Data[apiv1.TLSPrivateKeyKey])
+	pair, err := tls.X509KeyPair(secret.Data[apiv1.TLSCertKey], secret.Data[apiv1.TLSPrivateKeyKey])
+	if err != nil {
+		return nil, err
+	}
+	leaf, err := x509.ParseCertificate(pair.Certificate[0])
 	if err != nil {
 		return nil, err
 	}
@@ -112,9 +262,77 @@ func getCertificateFromSecret(driver *driver.KubernetesDriver, namespace, name s
 	return &Certificate{
 		Key:  key,
 		Cert: cert,
+		Info: &CertInfo{
+			Secret: secret.Namespace + "/" + secret.Name,
+			Leaf:   leaf,
+		},
 	}, nil
 }
 
+// GenCertStatus return the certificates in the cert map, sorted by the key of cert map.
+// the certificate without info (e.g. the self-signed cert of metrics port) is skipped.
+func GenCertStatus(certMap map[string]Certificate) []albv2.CertificateStatus {
+	keys := make([]string, 0, len(certMap))
+	for k, c := range certMap {
+		if c.Info == nil || c.Info.Leaf == nil {
+			continue
+		}
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	ret := make([]albv2.CertificateStatus, 0, len(keys))
+	for _, k := range keys {
+		info := certMap[k].Info
+		leaf := info.Leaf
+		sans := append([]string{}, leaf.DNSNames...)
+		for _, ip := range leaf.IPAddresses {
+			sans = append(sans, ip.String())
+		}
+		domain, _, _ := strings.Cut(k, "/")
+		ret = append(ret, albv2.CertificateStatus{
+			Domain:   k,
+			Secret:   info.Secret,
+			Subject:  leaf.Subject.String(),
+			SANs:     sans,
+			NotAfter: metav1.Time{Time: leaf.NotAfter},
+			Mismatch: !isPort(domain) && !certCoverDomain(leaf, domain),
+		})
+	}
+	return ret
+}
+
+// GenCertExpiry return the expiry of certificates in the cert map, in the same order as GenCertStatus.
+func GenCertExpiry(certMap map[string]Certificate) []CertificateExpiry {
+	ret := []CertificateExpiry{}
+	for _, c := range GenCertStatus(certMap) {
+		ret = append(ret, CertificateExpiry{Domain: c.Domain, Secret: c.Secret, NotAfter: c.NotAfter.Unix()})
+	}
+	return ret
+}
+
+func isPort(key string) bool {
+	_, err := strconv.Atoi(key)
+	return err == nil
+}
+
+// certCoverDomain check whether the domain of rule is covered by the SANs (or the CN if there is no SANs) of the cert.
+// the wildcard domain of rule is covered only if the cert has the same wildcard name.
+func certCoverDomain(leaf *x509.Certificate, domain string) bool {
+	if leaf.VerifyHostname(domain) == nil {
+		return true
+	}
+	names := leaf.DNSNames
+	if len(names) == 0 && len(leaf.IPAddresses) == 0 {
+		names = []string{leaf.Subject.CommonName}
+	}
+	for _, n := range names {
+		if strings.EqualFold(n, domain) {
+			return true
+		}
+	}
+	return false
+}
+
 func ParseCertificateName(n string) (string, string, error) {
 	// backward compatibility
 	if strings.Contains(n, "_") {
@@ -233,3 +451,37 @@ func init() {
 func genMetricsCert() Certificate {
 	return metricsCert
 }
+
+// ReferencedSecrets return the secrets used by alb, the certs of ft/rule, the ca of client tls and the ones referenced by extensions (lb.Refs).
+// it should be called after CollectRefs, and before FetchRefs which removes the secrets could not be fetched.
+func ReferencedSecrets(alb *LoadBalancer) map[client.ObjectKey]bool {
+	ret := map[client.ObjectKey]bool{}
+	addCert := func(cert string) {
+		if cert == "" {
+			return
+		}
+		if ns, name, err := ParseCertificateName(cert); err == nil {
+			ret[client.ObjectKey{Namespace: ns, Name: name}] = true
+		}
+	}
+	addCa := func(cfg *clienttls_t.ClientTLSCr) {
+		if cfg == nil || cfg.CaSecretRef == "" {
+			return
+		}
+		if ns, name, _, err := waf.ParseCmRef(cfg.CaSecretRef); err == nil {
+			ret[client.ObjectKey{Namespace: ns, Name: name}] = true
+		}
+	}
+	for _, ft := range alb.Frontends {
+		addCert(ft.CertificateName)
+		addCa(ft.Config.ClientTLS)
+		for _, rule := range ft.Rules {
+			addCert(rule.CertificateName)
+			addCa(rule.Config.ClientTLS)
+		}
+	}
+	for k := range alb.Refs.Secret {
+		ret[k] = true
+	}
+	return ret
+}
@@ -1,9 +1,17 @@
 package cli
 
 import (
+	"fmt"
+	"net"
 	"testing"
 
+	. "alauda.io/alb2/controller/types"
+	clienttls_t "alauda.io/alb2/pkg/controller/ext/clienttls/types"
+	"github.com/go-logr/logr"
 	"github.com/stretchr/testify/assert"
+	apiv1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	certutil "k8s.io/client-go/util/cert"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
@@ -39,3 +47,104 @@ func TestCert(t *testing.T) {
 		},
 	)
 }
+
+func TestAttachClientCa(t *testing.T) {
+	a := Certificate{Cert: "a", Key: "a"}
+	wildcard := Certificate{Cert: "w", Key: "w"}
+	port := Certificate{Cert: "p", Key: "p"}
+	certMap := map[string]Certificate{
+		"a.com":     a,
+		"*.b.com":   wildcard,
+		"443":       port,
+		"c.com/443": a,
+	}
+	depth := 2
+	cfgs := map[string]*clienttls_t.ClientTLSCr{
+		"a.com/443":   {CaSecretRef: "cpaas-system/ca#ca.crt"},
+		"x.b.com/443": {CaSecretRef: "cpaas-system/ca#ca.crt", VerifyDepth: &depth},
+		"c.com/443":   {CaSecretRef: "cpaas-system/ca2#ca.crt"},
+		"d.com/443":   {CaSecretRef: "cpaas-system/ca#ca.crt"},
+		"443":         {CaSecretRef: "cpaas-system/none#ca.crt"},
+	}
+	getCa := func(ref string) (string, error) {
+		if ref == "cpaas-system/none#ca.crt" {
+			return "", fmt.Errorf("not found")
+		}
+		return "ca-of-" + ref, nil
+	}
+	attachClientCa(certMap, cfgs, getCa, logr.Discard())
+	// the cert of domain is shared by all ports, it is copied, the other ports are not affected.
+	assert.Nil(t, certMap["a.com"].ClientCa)
+	assert.Equal(t, &CaCertificate{Cert: "ca-of-cpaas-system/ca#ca.crt", Depth: 1, Id: "cpaas-system/ca#ca.crt/1"}, certMap["a.com/443"].ClientCa)
+	assert.Equal(t, &CaCertificate{Cert: "ca-of-cpaas-system/ca2#ca.crt", Depth: 1, Id: "cpaas-system/ca2#ca.crt/1"}, certMap["c.com/443"].ClientCa)
+	// wildcard cert is copied, the other domains are not affected.
+	assert.Nil(t, certMap["*.b.com"].ClientCa)
+	assert.Equal(t, Certificate{Cert: "w", Key: "w", ClientCa: &CaCertificate{Cert: "ca-of-cpaas-system/ca#ca.crt", Depth: 2, Id: "cpaas-system/ca#ca.crt/2"}}, certMap["x.b.com/443"])
+	// domain without cert does not fall back to the default cert of port.
+	_, has := certMap["d.com/443"]
+	assert.False(t, has)
+	assert.Nil(t, certMap["443"].ClientCa)
+	assert.Len(t, certMap, 6)
+}
+
+func TestGenCertStatus(t *testing.T) {
+	genCert := func(name string, host string, sans []string) Certificate {
+		cert, key, err := certutil.GenerateSelfSignedCertKey(host, []net.IP{net.ParseIP("10.0.0.1")}, sans)
+		assert.NoError(t, err)
+		c, err := certFromSecret(&apiv1.Secret{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "cpaas-system", Name: name},
+			Data:       map[string][]byte{apiv1.TLSCertKey: cert, apiv1.TLSPrivateKeyKey: key},
+		})
+		assert.NoError(t, err)
+		return *c
+	}
+	a := genCert("a", "a.com", nil)
+	wildcard := genCert("wildcard", "b.com", []string{"*.b.com"})
+	certMap := map[string]Certificate{
+		"443":         a,
+		"a.com":       a,
+		"x.b.com/443": wildcard,
+		"*.b.com":     wildcard,
+		"c.com":       a,
+		"10.0.0.1":    a,
+		"1936":        genMetricsCert(),
+	}
+	status := GenCertStatus(certMap)
+	domains := []string{}
+	mismatch := map[string]bool{}
+	for _, s := range status {
+		domains = append(domains, s.Domain)
+		mismatch[s.Domain] = s.Mismatch
+	}
+	// the cert of metrics port is skipped.
+	assert.Equal(t, []string{"*.b.com", "10.0.0.1", "443", "a.com", "c.com", "x.b.com/443"}, domains)
+	assert.Equal(t, map[string]bool{"*.b.com": false, "10.0.0.1": false, "443": false, "a.com": false, "c.com": true, "x.b.com/443": false}, mismatch)
+	assert.Equal(t, "cpaas-system/wildcard", status[0].Secret)
+	assert.Equal(t, []string{"b.com", "*.b.com", "10.0.0.1"}, status[0].SANs)
+	assert.Equal(t, wildcard.Info.Leaf.NotAfter, status[0].NotAfter.Time)
+
+	expiry := GenCertExpiry(certMap)
+	assert.Len(t, expiry, 6)
+	assert.Equal(t, CertificateExpiry{Domain: "*.b.com", Secret: "cpaas-system/wildcard", NotAfter: wildcard.Info.Leaf.NotAfter.Unix()}, expiry[0])
+}
+
+func TestReferencedSecrets(t *testing.T) {
+	rule := &InternalRule{}
+	rule.CertificateName = "ns/rule-cert"
+	rule.Config.ClientTLS = &clienttls_t.ClientTLSCr{CaSecretRef: "ns/rule-ca#ca.crt"}
+	ft := &Frontend{CertificateName: "ns/ft-cert", Rules: RuleList{rule, &InternalRule{}}}
+	lb := &LoadBalancer{
+		Frontends: []*Frontend{ft},
+		Refs: RefMap{
+			ConfigMap: map[client.ObjectKey]*apiv1.ConfigMap{{Namespace: "ns", Name: "waf"}: nil},
+			Secret:    map[client.ObjectKey]*apiv1.Secret{{Namespace: "ns", Name: "auth"}: nil},
+		},
+	}
+	assert.Equal(t, map[client.ObjectKey]bool{
+		{Namespace: "ns", Name: "rule-cert"}: true,
+		{Namespace: "ns", Name: "rule-ca"}:   true,
+		{Namespace: "ns", Name: "ft-cert"}:   true,
+		{Namespace: "ns", Name: "auth"}:      true,
+	}, ReferencedSecrets(lb))
+	assert.Equal(t, map[client.ObjectKey]bool{{Namespace: "ns", Name: "waf"}: true}, ReferencedConfigMaps(lb))
+}
@@ -19,6 +19,7 @@ type PolicyCli struct {
 }
 type PolicyCliOpt struct {
 	MetricsPort int
+	Cache       *PolicyCache // keep the translation results between regenerations, could be nil
 }
 
 func NewPolicyCli(drv *driver.KubernetesDriver, log logr.Logger, opt PolicyCliOpt) PolicyCli {
@@ -30,6 +31,10 @@ func NewPolicyCli(drv *driver.KubernetesDriver, log logr.Logger, opt PolicyCliOp
 	}
 }
 
+func (p *PolicyCli) SetCache(cache *PolicyCache) {
+	p.opt.Cache = cache
+}
+
 // fetch cert and backend info that lb config need, constructs a "dynamic config" used by openresty.
 func (p *PolicyCli) GenerateAlbPolicy(alb *LoadBalancer) NgxPolicy {
 	s := time.Now()
@@ -37,19 +42,23 @@ func (p *PolicyCli) GenerateAlbPolicy(alb *LoadBalancer) NgxPolicy {
 		pm.Write("gen-policy", float64(time.Since(s).Milliseconds()))
 	}()
 
+	p.opt.Cache.begin()
+	defer p.opt.Cache.end()
+
 	s_other := time.Now()
-	certificateMap := getCertMap(alb, p.drv)
+	certificateMap := getCertMap(alb, p.drv, p.opt.Cache)
 
 	p.setMetricsPortCert(certificateMap)
 	backendGroup := pickAllBackendGroup(alb)
 	pm.Write("gen-policy/pick", float64(time.Since(s_other).Milliseconds()))
 
 	ngxPolicy := NgxPolicy{
-		CertificateMap: certificateMap,
-		Http:           HttpPolicy{Tcp: make(map[albv1.PortNumber]Policies)},
-		SharedConfig:   SharedExtPolicyConfig{},
-		Stream:         StreamPolicy{Tcp: make(map[albv1.PortNumber]Policies), Udp: make(map[albv1.PortNumber]Policies)},
-		BackendGroup:   backendGroup,
+		CertificateMap:    certificateMap,
+		CertificateExpiry: GenCertExpiry(certificateMap),
+		Http:              HttpPolicy{Tcp: make(map[albv1.PortNumber]Policies)},
+		SharedConfig:      SharedExtPolicyConfig{},
+		Stream:            StreamPolicy{Tcp: make(map[albv1.PortNumber]Policies), Udp: make(map[albv1.PortNumber]Policies), Tls: make(map[albv1.PortNumber]SNIPolicies)},
+		BackendGroup:      backendGroup,
 	}
 
 	sf := time.Now()
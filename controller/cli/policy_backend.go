@@ -10,10 +10,16 @@ import (
 	m "alauda.io/alb2/controller/modules"
 	. "alauda.io/alb2/controller/types"
 	"alauda.io/alb2/driver"
+	"alauda.io/alb2/pkg/controller/ext/errorpage"
+	errorpage_t "alauda.io/alb2/pkg/controller/ext/errorpage/types"
+	"alauda.io/alb2/pkg/controller/ext/healthcheck"
+	hct "alauda.io/alb2/pkg/controller/ext/healthcheck/types"
 	pm "alauda.io/alb2/pkg/utils/metrics"
+	"github.com/go-logr/logr"
 	"github.com/thoas/go-funk"
 	corev1 "k8s.io/api/core/v1"
 	"k8s.io/klog/v2"
+	"sigs.k8s.io/controller-runtime/pkg/client"
 )
 
 func (p *PolicyCli) FillUpBackends(cAlb *LoadBalancer) error {
@@ -24,6 +30,7 @@ func (p *PolicyCli) FillUpBackends(cAlb *LoadBalancer) error {
 
 	services := p.loadServices(cAlb)
 	backendMap := make(map[string][]*driver.Backend)
+	usedBackends := map[string]bool{}
 	log := p.log
 	for key, svc := range services {
 		if svc == nil {
@@ -44,8 +51,10 @@ func (p *PolicyCli) FillUpBackends(cAlb *LoadBalancer) error {
 				Mode:                     FtProtocolToBackendMode(ft.Protocol),
 				SessionAffinityPolicy:    rule.SessionAffinityPolicy,
 				SessionAffinityAttribute: rule.SessionAffinityAttr,
+				SessionAffinityCookie:    rule.SessionAffinityCookie,
+				HealthCheck:              toHealthCheckPolicy(rule.HealthCheck, rule.RuleID, log),
 			}
-			rule.BackendGroup.Backends = generateBackend(backendMap, rule.Services, protocol)
+			rule.BackendGroup.Backends = p.generateBackendWithCache(backendMap, usedBackends, rule.Services, protocol)
 			// if backend app protocol is https. use https.
 			if rule.BackendProtocol == "$http_backend_protocol" {
 				rule.BackendProtocol = "http"
@@ -55,6 +64,21 @@ func (p *PolicyCli) FillUpBackends(cAlb *LoadBalancer) error {
 					}
 				}
 			}
+			if rule.Config.Mirror != nil {
+				svc := mirrorService(rule)
+				rule.MirrorBackendGroup = &BackendGroup{
+					Name:     MirrorUpstreamName(svc),
+					Mode:     FtProtocolToBackendMode(ft.Protocol),
+					Backends: p.generateBackendWithCache(backendMap, usedBackends, []*BackendService{svc}, protocol),
+				}
+			}
+			for _, svc := range errorPageServices(rule) {
+				rule.ErrorPageGroups = append(rule.ErrorPageGroups, &BackendGroup{
+					Name:     ErrorPageUpstreamName(svc),
+					Mode:     FtProtocolToBackendMode(ft.Protocol),
+					Backends: p.generateBackendWithCache(backendMap, usedBackends, []*BackendService{svc}, protocol),
+				})
+			}
 			rules = append(rules, rule)
 		}
 		if len(rules) > 0 {
@@ -68,27 +92,42 @@ func (p *PolicyCli) FillUpBackends(cAlb *LoadBalancer) error {
 		}
 		if len(ft.Services) != 0 {
 			// set ft default services group.
-			ft.BackendGroup.Backends = generateBackend(backendMap, ft.Services, protocol)
+			ft.BackendGroup.Backends = p.generateBackendWithCache(backendMap, usedBackends, ft.Services, protocol)
 			ft.BackendGroup.Mode = FtProtocolToBackendMode(ft.Protocol)
 		}
 	}
+	if p.opt.Cache != nil {
+		p.opt.Cache.evictServices(services, usedBackends)
+	}
 	return nil
 }
 
 func (p *PolicyCli) loadServices(alb *LoadBalancer) map[string]*driver.Service {
 	kd := p.drv
+	cache := p.opt.Cache
 	svcMap := make(map[string]*driver.Service)
 
 	getServiceWithCache := func(svc *BackendService, protocol corev1.Protocol, svcMap map[string]*driver.Service) error {
 		svcKey := generateServiceKey(svc.ServiceNs, svc.ServiceName, protocol, svc.ServicePort)
-		if _, ok := svcMap[svcKey]; !ok {
-			service, err := kd.GetServiceByName(svc.ServiceNs, svc.ServiceName, svc.ServicePort, protocol)
-			if service != nil {
+		if _, ok := svcMap[svcKey]; ok {
+			return nil
+		}
+		version := ""
+		if cache != nil {
+			version = kd.ServiceVersion(svc.ServiceNs, svc.ServiceName)
+			if service, ok := cache.getService(svcKey, version); ok {
 				svcMap[svcKey] = service
+				return nil
 			}
-			return err
 		}
-		return nil
+		service, err := kd.GetServiceByName(svc.ServiceNs, svc.ServiceName, svc.ServicePort, protocol)
+		if service != nil {
+			svcMap[svcKey] = service
+			if cache != nil && err == nil {
+				cache.setService(svcKey, version, service)
+			}
+		}
+		return err
 	}
 
 	for _, ft := range alb.Frontends {
@@ -110,16 +149,131 @@ func (p *PolicyCli) loadServices(alb *LoadBalancer) map[string]*driver.Service {
 					klog.Errorf("get backends for rule fail svc %s/%s %d protocol %s rule %s err %v", svc.ServiceName, svc.ServiceNs, svc.ServicePort, protocol, rule.RuleID, err)
 				}
 			}
+			if rule.Config.Mirror != nil {
+				svc := mirrorService(rule)
+				err := getServiceWithCache(svc, protocol, svcMap)
+				if err != nil {
+					klog.Errorf("get backends for mirror fail svc %s/%s %d protocol %s rule %s err %v", svc.ServiceName, svc.ServiceNs, svc.ServicePort, protocol, rule.RuleID, err)
+				}
+			}
+			for _, svc := range errorPageServices(rule) {
+				err := getServiceWithCache(svc, protocol, svcMap)
+				if err != nil {
+					klog.Errorf("get backends for errorpage fail svc %s/%s %d protocol %s rule %s err %v", svc.ServiceName, svc.ServiceNs, svc.ServicePort, protocol, rule.RuleID, err)
+				}
+			}
 		}
 	}
 	return svcMap
 }
 
+// ReferencedServices return the services used by alb, the same ones loaded by loadServices.
+// the services of rules which allow no address are included too, the change of them does not hurt.
+func ReferencedServices(alb *LoadBalancer) map[client.ObjectKey]bool {
+	ret := map[client.ObjectKey]bool{}
+	add := func(svcs ...*BackendService) {
+		for _, svc := range svcs {
+			ret[client.ObjectKey{Namespace: svc.ServiceNs, Name: svc.ServiceName}] = true
+		}
+	}
+	for _, ft := range alb.Frontends {
+		add(ft.Services...)
+		for _, rule := range ft.Rules {
+			add(rule.Services...)
+			if rule.Config.Mirror != nil {
+				add(mirrorService(rule))
+			}
+			add(errorPageServices(rule)...)
+		}
+	}
+	return ret
+}
+
+// invalid health check is ignored, since all endpoints are considered healthy without health check.
+func toHealthCheckPolicy(cfg *hct.HealthCheckCr, name string, log logr.Logger) *hct.HealthCheckPolicy {
+	p, err := healthcheck.ToPolicy(cfg)
+	if err != nil {
+		log.Error(err, "invalid health check, ignore it", "backend-group", name)
+		return nil
+	}
+	return p
+}
+
+func mirrorService(rule *InternalRule) *BackendService {
+	mirror := rule.Config.Mirror
+	ns := mirror.Namespace
+	if ns == "" && rule.Source != nil {
+		ns = rule.Source.Namespace
+	}
+	return &BackendService{
+		ServiceNs:   ns,
+		ServiceName: mirror.Name,
+		ServicePort: mirror.Port,
+		Weight:      100,
+	}
+}
+
+// mirror backend group is shared by all rules which mirror to the same service.
+func MirrorUpstreamName(svc *BackendService) string {
+	return fmt.Sprintf("mirror-%s-%s-%d", svc.ServiceNs, svc.ServiceName, svc.ServicePort)
+}
+
+func errorPageServices(rule *InternalRule) []*BackendService {
+	if rule.Config.ErrorPage == nil {
+		return nil
+	}
+	ret := []*BackendService{}
+	for _, svc := range errorpage.Services(rule.Config.ErrorPage) {
+		ret = append(ret, &BackendService{
+			ServiceNs:   svc.Namespace,
+			ServiceName: svc.Name,
+			ServicePort: svc.Port,
+			Weight:      100,
+		})
+	}
+	return ret
+}
+
+// errorpage backend group is shared by all rules which use the same service, the name is used in the errorpage policy.
+func ErrorPageUpstreamName(svc *BackendService) string {
+	return errorpage.UpstreamName(errorpage_t.ErrorPageService{Namespace: svc.ServiceNs, Name: svc.ServiceName, Port: svc.ServicePort})
+}
+
 func generateServiceKey(ns string, name string, protocol corev1.Protocol, svcPort int) string {
 	key := fmt.Sprintf("%s-%s-%s-%d", ns, name, protocol, svcPort)
 	return strings.ToLower(key)
 }
 
+// the backends of the same services are reused until the version of any of the services changes, they are not modified once generated.
+func (p *PolicyCli) generateBackendWithCache(backendMap map[string][]*driver.Backend, used map[string]bool, services []*BackendService, protocol corev1.Protocol) Backends {
+	cache := p.opt.Cache
+	if cache == nil {
+		return generateBackend(backendMap, services, protocol)
+	}
+	key := backendCacheKey(cache, backendMap, services, protocol)
+	used[key] = true
+	if bes, ok := cache.backends[key]; ok {
+		return bes
+	}
+	bes := generateBackend(backendMap, services, protocol)
+	cache.backends[key] = bes
+	return bes
+}
+
+func backendCacheKey(cache *PolicyCache, backendMap map[string][]*driver.Backend, services []*BackendService, protocol corev1.Protocol) string {
+	parts := []string{string(protocol)}
+	for _, svc := range services {
+		name := generateServiceKey(svc.ServiceNs, svc.ServiceName, protocol, svc.ServicePort)
+		// the service which could not be loaded in this round has no backends, whatever its version is.
+		version := "-"
+		if _, ok := backendMap[name]; ok {
+			version = cache.serviceVersion(name)
+		}
+		parts = append(parts, fmt.Sprintf("%s/%d/%s", name, svc.Weight, version))
+	}
+	return strings.Join(parts, "|")
+}
+
 // 找到 service 对应的后端
 func generateBackend(backendMap map[string][]*driver.Backend, services []*BackendService, protocol corev1.Protocol) Backends {
 	totalWeight := 0
@@ -180,12 +334,25 @@ func pickAllBackendGroup(alb *LoadBalancer) BackendGroups {
 		pm.Write("pick-backends", float64(time.Since(s).Milliseconds()))
 	}()
 	backendGroup := BackendGroups{}
+	// backend groups of mirror and errorpage are shared by rules.
+	sharedGroup := map[string]bool{}
 	for _, ft := range alb.Frontends {
 		if ft.Conflict {
 			continue
 		}
 		for _, rule := range ft.Rules {
 			backendGroup = append(backendGroup, rule.BackendGroup)
+			mirror := rule.MirrorBackendGroup
+			if mirror != nil && !sharedGroup[mirror.Name] {
+				sharedGroup[mirror.Name] = true
+				backendGroup = append(backendGroup, mirror)
+			}
+			for _, g := range rule.ErrorPageGroups {
+				if !sharedGroup[g.Name] {
+					sharedGroup[g.Name] = true
+					backendGroup = append(backendGroup, g)
+				}
+			}
 		}
 
 		if ft.BackendGroup != nil && len(ft.BackendGroup.Backends) > 0 {
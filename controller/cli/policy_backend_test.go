@@ -0,0 +1,28 @@
+package cli
+
+import (
+	"testing"
+
+	. "alauda.io/alb2/controller/types"
+	mirror_t "alauda.io/alb2/pkg/controller/ext/mirror/types"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestReferencedServices(t *testing.T) {
+	svc := func(ns, name string) *BackendService {
+		return &BackendService{ServiceNs: ns, ServiceName: name, ServicePort: 80}
+	}
+	rule := &InternalRule{}
+	rule.Services = []*BackendService{svc("ns", "a"), svc("ns", "b")}
+	rule.Config.Mirror = &mirror_t.MirrorCr{Namespace: "ns1", Name: "m", Port: 80}
+	lb := &LoadBalancer{Frontends: []*Frontend{
+		{Services: []*BackendService{svc("ns", "ft")}, Rules: RuleList{rule}},
+	}}
+	assert.Equal(t, map[client.ObjectKey]bool{
+		{Namespace: "ns", Name: "a"}:  true,
+		{Namespace: "ns", Name: "b"}:  true,
+		{Namespace: "ns", Name: "ft"}: true,
+		{Namespace: "ns1", Name: "m"}: true,
+	}, ReferencedServices(lb))
+}
@@ -0,0 +1,239 @@
+package cli
+
+import (
+	"fmt"
+	"hash/fnv"
+	"maps"
+	"sort"
+	"strings"
+
+	. "alauda.io/alb2/controller/types"
+	"alauda.io/alb2/driver"
+	pm "alauda.io/alb2/pkg/utils/metrics"
+	corev1 "k8s.io/api/core/v1"
+	"k8s.io/apimachinery/pkg/api/meta"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// PolicyCache keep the translation results between the regenerations of policy, so a regeneration only re-translates what changed.
+//   - the l7 policy of a rule is keyed on the version of rule/ft/alb (see InternalRule.Version), the upstream and the versions of referenced configmaps/secrets.
+//   - the certificate is keyed on the resource version of the secret, which is read from the secret informer if it is set.
+//   - the service (backends) is keyed on the versions of service/endpoints/endpointslices (see KubernetesDriver.ServiceVersion),
+//     and the backends of a group are keyed on its services and their versions.
+//
+// rules without version (e.g. from gateway api) are always translated.
+// it is not thread safe, it should be used by one reload loop only.
+type PolicyCache struct {
+	rules map[string]*cachedPolicy
+	certs map[client.ObjectKey]*cachedCert
+	// entries used in this round, the others are removed at the end of round.
+	usedRules map[string]bool
+	usedCerts map[client.ObjectKey]bool
+	// the backends are filled before the policy is generated, they are evicted in FillUpBackends.
+	services map[string]*cachedService
+	backends map[string]Backends
+	// the metadata of secrets, used to check whether the secret changed without reading it from apiserver.
+	secrets toolscache.Indexer
+	// reused to collect the refs of each rule.
+	scratch RefMap
+	hit     int
+	miss    int
+}
+
+type cachedPolicy struct {
+	key    string
+	policy *Policy
+}
+
+type cachedCert struct {
+	version string
+	cert    *Certificate
+}
+
+type cachedService struct {
+	version string
+	svc     *driver.Service
+}
+
+func NewPolicyCache() *PolicyCache {
+	return &PolicyCache{
+		rules:    map[string]*cachedPolicy{},
+		certs:    map[client.ObjectKey]*cachedCert{},
+		services: map[string]*cachedService{},
+		backends: map[string]Backends{},
+		scratch: RefMap{
+			ConfigMap: map[client.ObjectKey]*corev1.ConfigMap{},
+			Secret:    map[client.ObjectKey]*corev1.Secret{},
+		},
+	}
+}
+
+// UseSecretInformer set the (metadata) informer of secrets, the secret is read from apiserver only when its version changed.
+func (c *PolicyCache) UseSecretInformer(i toolscache.SharedIndexInformer) {
+	c.secrets = i.GetIndexer()
+}
+
+func (c *PolicyCache) secretVersion(k client.ObjectKey) (string, bool) {
+	if c.secrets == nil {
+		return "", false
+	}
+	obj, exist, err := c.secrets.GetByKey(k.String())
+	if err != nil || !exist {
+		return "", false
+	}
+	m, err := meta.Accessor(obj)
+	if err != nil {
+		return "", false
+	}
+	return m.GetResourceVersion(), true
+}
+
+func (c *PolicyCache) begin() {
+	if c == nil {
+		return
+	}
+	c.usedRules = map[string]bool{}
+	c.usedCerts = map[client.ObjectKey]bool{}
+	c.hit = 0
+	c.miss = 0
+}
+
+func (c *PolicyCache) end() {
+	if c == nil {
+		return
+	}
+	for id := range c.rules {
+		if !c.usedRules[id] {
+			delete(c.rules, id)
+		}
+	}
+	for k := range c.certs {
+		if !c.usedCerts[k] {
+			delete(c.certs, k)
+		}
+	}
+	pm.Write("gen-policy/cache-hit", float64(c.hit))
+	pm.Write("gen-policy/cache-miss", float64(c.miss))
+}
+
+// the policy will be modified when merging the same config (see ExtCtl.MergeSamePolicyConfig), so a copy is kept and returned.
+func (c *PolicyCache) getPolicy(id, key string) (*Policy, bool) {
+	c.usedRules[id] = true
+	cached, ok := c.rules[id]
+	if !ok || cached.key != key {
+		c.miss++
+		return nil, false
+	}
+	c.hit++
+	return clonePolicy(cached.policy), true
+}
+
+func (c *PolicyCache) setPolicy(id, key string, p *Policy) {
+	c.usedRules[id] = true
+	c.rules[id] = &cachedPolicy{key: key, policy: clonePolicy(p)}
+}
+
+func (c *PolicyCache) getCert(k client.ObjectKey, version string) (*Certificate, bool) {
+	c.usedCerts[k] = true
+	cached, ok := c.certs[k]
+	if !ok || cached.version != version {
+		return nil, false
+	}
+	cert := *cached.cert
+	return &cert, true
+}
+
+func (c *PolicyCache) setCert(k client.ObjectKey, version string, cert *Certificate) {
+	c.usedCerts[k] = true
+	cp := *cert
+	c.certs[k] = &cachedCert{version: version, cert: &cp}
+}
+
+func (c *PolicyCache) getService(key, version string) (*driver.Service, bool) {
+	cached, ok := c.services[key]
+	if !ok || cached.version != version {
+		return nil, false
+	}
+	return cached.svc, true
+}
+
+func (c *PolicyCache) setService(key, version string, svc *driver.Service) {
+	c.services[key] = &cachedService{version: version, svc: svc}
+}
+
+func (c *PolicyCache) serviceVersion(key string) string {
+	if cached, ok := c.services[key]; ok {
+		return cached.version
+	}
+	return "-"
+}
+
+// evictServices remove the services and backends which are not used in this round.
+func (c *PolicyCache) evictServices(usedServices map[string]*driver.Service, usedBackends map[string]bool) {
+	for k := range c.services {
+		if _, ok := usedServices[k]; !ok {
+			delete(c.services, k)
+		}
+	}
+	for k := range c.backends {
+		if !usedBackends[k] {
+			delete(c.backends, k)
+		}
+	}
+}
+
+// the config of extensions is shared, only the fields which will be modified are copied.
+func clonePolicy(p *Policy) *Policy {
+	cp := *p
+	cp.Config.Refs = maps.Clone(p.Config.Refs)
+	return &cp
+}
+
+// objectVersion is the version of ft/alb which rules inherit config from.
+// they have status subresource, so the generation is used instead of the resource version, the status is updated frequently.
+func objectVersion(m metav1.ObjectMeta) string {
+	h := fnv.New64a()
+	fmt.Fprintf(h, "%v%v", m.Labels, m.Annotations)
+	return fmt.Sprintf("%d-%x", m.Generation, h.Sum64())
+}
+
+func (p *PolicyCli) policyCacheKey(rule *InternalRule, refs RefMap) string {
+	used := p.opt.Cache.scratch
+	clear(used.ConfigMap)
+	clear(used.Secret)
+	p.cus.CollectRefs(rule, used)
+	vers := []string{}
+	for k := range used.ConfigMap {
+		v := "-"
+		if cm := refs.ConfigMap[k]; cm != nil {
+			v = cm.ResourceVersion
+		}
+		vers = append(vers, "cm/"+k.String()+"/"+v)
+	}
+	for k := range used.Secret {
+		v := "-"
+		if s := refs.Secret[k]; s != nil {
+			v = s.ResourceVersion
+		}
+		vers = append(vers, "secret/"+k.String()+"/"+v)
+	}
+	sort.Strings(vers)
+	upstream := rule.RuleID
+	if rule.BackendGroup != nil {
+		upstream = rule.BackendGroup.Name
+	}
+	// the mirror policy depends on the app protocol of mirror backends.
+	mirror := ""
+	if bg := rule.MirrorBackendGroup; bg != nil {
+		mirror = bg.Name
+		for _, b := range bg.Backends {
+			if b.AppProtocol != nil && strings.ToLower(*b.AppProtocol) == "https" {
+				mirror += "/https"
+				break
+			}
+		}
+	}
+	return strings.Join(append([]string{rule.Version, rule.BackendProtocol, upstream, mirror}, vers...), "|")
+}
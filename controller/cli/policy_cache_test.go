@@ -0,0 +1,133 @@
+package cli
+
+import (
+	"testing"
+
+	. "alauda.io/alb2/controller/types"
+	"alauda.io/alb2/driver"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	cus "alauda.io/alb2/pkg/controller/extctl"
+	"alauda.io/alb2/utils"
+	"alauda.io/alb2/utils/log"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	toolscache "k8s.io/client-go/tools/cache"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestPolicyCache(t *testing.T) {
+	cache := NewPolicyCache()
+	p := PolicyCli{log: log.L(), cus: cus.NewExtensionCtl(cus.ExtCtlCfgOpt{Log: log.L()}), opt: PolicyCliOpt{Cache: cache}}
+	refs := RefMap{ConfigMap: map[client.ObjectKey]*corev1.ConfigMap{}, Secret: map[client.ObjectKey]*corev1.Secret{}}
+	rule := &InternalRule{}
+	rule.RuleID = "r1"
+	rule.Version = "1/1-a/1-b"
+	rule.DSLX = albv1.DSLX{{Values: [][]string{{utils.OP_EQ, "/a"}}, Type: utils.KEY_URL}}
+	rule.BackendGroup = &BackendGroup{Name: "r1"}
+	rule.BackendProtocol = "http"
+	rule.Config = RuleExt{Source: ConfigSource{}}
+
+	cache.begin()
+	p1, err := p.ruleToL7PolicyWithCache(rule, refs)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cache.miss)
+	// the policy will be modified when merging config, it should not affect the cached one.
+	p1.Config.Refs[Timeout] = "x"
+	cache.end()
+
+	cache.begin()
+	p2, err := p.ruleToL7PolicyWithCache(rule, refs)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cache.hit)
+	assert.False(t, p1 == p2)
+	assert.Equal(t, "r1", p2.Rule)
+	assert.Equal(t, "r1", p2.Upstream)
+	assert.Equal(t, 0, len(p2.Config.Refs))
+	cache.end()
+
+	// upstream protocol changed, e.g. the app protocol of backend changed.
+	cache.begin()
+	rule.BackendProtocol = "https"
+	p3, err := p.ruleToL7PolicyWithCache(rule, refs)
+	assert.NoError(t, err)
+	assert.Equal(t, 1, cache.miss)
+	assert.Equal(t, "https", p3.BackendProtocol)
+	cache.end()
+
+	// rule without version is not cached.
+	cache.begin()
+	rule.Version = ""
+	_, err = p.ruleToL7PolicyWithCache(rule, refs)
+	assert.NoError(t, err)
+	assert.Equal(t, 0, cache.miss+cache.hit)
+	cache.end()
+	// the rule is not used in last round, it should be removed.
+	assert.Equal(t, 0, len(cache.rules))
+
+	// nil cache is ok.
+	p.opt.Cache = nil
+	_, err = p.ruleToL7PolicyWithCache(rule, refs)
+	assert.NoError(t, err)
+}
+
+func TestObjectVersion(t *testing.T) {
+	m := metav1.ObjectMeta{Generation: 1, ResourceVersion: "100", Annotations: map[string]string{"a": "b"}}
+	v := objectVersion(m)
+	// status update changes the resource version only.
+	m.ResourceVersion = "101"
+	assert.Equal(t, v, objectVersion(m))
+	m.Annotations["a"] = "c"
+	assert.NotEqual(t, v, objectVersion(m))
+	v = objectVersion(m)
+	m.Generation = 2
+	assert.NotEqual(t, v, objectVersion(m))
+}
+
+func TestCertCacheWithSecretInformer(t *testing.T) {
+	cache := NewPolicyCache()
+	key := client.ObjectKey{Namespace: "ns", Name: "s1"}
+	indexer := toolscache.NewIndexer(toolscache.MetaNamespaceKeyFunc, toolscache.Indexers{})
+	cache.secrets = indexer
+	_, ok := cache.secretVersion(key)
+	assert.False(t, ok)
+
+	assert.NoError(t, indexer.Add(&metav1.PartialObjectMetadata{ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "s1", ResourceVersion: "1"}}))
+	cache.begin()
+	cache.setCert(key, "1", &Certificate{Cert: "c1", Key: "k1"})
+	cache.end()
+
+	// the version in informer is the cached one, the secret is not read from apiserver (the driver is nil).
+	cache.begin()
+	cert, err := getCertificateWithCache(nil, key, cache)
+	assert.NoError(t, err)
+	assert.Equal(t, "c1", cert.Cert)
+	cache.end()
+	assert.Equal(t, 1, len(cache.certs))
+}
+
+func TestBackendCache(t *testing.T) {
+	cache := NewPolicyCache()
+	p := PolicyCli{log: log.L(), opt: PolicyCliOpt{Cache: cache}}
+	svcs := []*BackendService{{ServiceNs: "ns", ServiceName: "a", ServicePort: 80, Weight: 100}}
+	key := generateServiceKey("ns", "a", corev1.ProtocolTCP, 80)
+	backendMap := map[string][]*driver.Backend{key: {{IP: "1.1.1.1", Port: 8080}}}
+	cache.setService(key, "v1", &driver.Service{Backends: backendMap[key]})
+
+	used := map[string]bool{}
+	b1 := p.generateBackendWithCache(backendMap, used, svcs, corev1.ProtocolTCP)
+	assert.Equal(t, 1, len(b1))
+	b2 := p.generateBackendWithCache(backendMap, used, svcs, corev1.ProtocolTCP)
+	assert.True(t, &b1[0] == &b2[0])
+
+	// the version of service changed.
+	cache.setService(key, "v2", &driver.Service{Backends: backendMap[key]})
+	b3 := p.generateBackendWithCache(backendMap, used, svcs, corev1.ProtocolTCP)
+	assert.False(t, &b1[0] == &b3[0])
+
+	// the backends of last version are not used any more.
+	cache.evictServices(map[string]*driver.Service{key: nil}, map[string]bool{backendCacheKey(cache, backendMap, svcs, corev1.ProtocolTCP): true})
+	assert.Equal(t, 1, len(cache.backends))
+	cache.evictServices(map[string]*driver.Service{}, map[string]bool{})
+	assert.Equal(t, 0, len(cache.services)+len(cache.backends))
+}
@@ -1,11 +1,17 @@
 package cli
 
 import (
+	"strings"
+
 	. "alauda.io/alb2/controller/types"
 	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
 )
 
 func (p *PolicyCli) initStreamModeFt(ft *Frontend, ngxPolicy *NgxPolicy) {
+	if ft.TlsPassthrough {
+		p.initTlsPassthroughFt(ft, ngxPolicy)
+		return
+	}
 	// create a default rule for stream mode ft.
 	policy := Policy{}
 	policy.Source = Source{}
@@ -24,6 +30,35 @@ func (p *PolicyCli) initStreamModeFt(ft *Frontend, ngxPolicy *NgxPolicy) {
 	}
 }
 
+// tls passthrough ft has multiple rules, each rule is a sni.
+// rules are already sorted by the creation time of tlsroute (see gateway/nginx/tls.go), if two rules have the same sni, the oldest one wins.
+func (p *PolicyCli) initTlsPassthroughFt(ft *Frontend, ngxPolicy *NgxPolicy) {
+	sniPolicies := SNIPolicies{}
+	for _, rule := range ft.Rules {
+		sni := strings.ToLower(rule.SNI)
+		if _, ok := sniPolicies[sni]; ok {
+			p.log.Info("duplicate sni ignore", "ft", ft.String(), "sni", sni, "rule", rule.RuleID)
+			continue
+		}
+		policy := Policy{}
+		policy.Subsystem = SubsystemStream
+		policy.Rule = rule.RuleID
+		policy.Upstream = rule.RuleID
+		if rule.BackendGroup != nil {
+			policy.Upstream = rule.BackendGroup.Name
+		}
+		if rule.Source != nil {
+			policy.Source = Source{SourceType: rule.Source.Type, SourceName: rule.Source.Name, SourceNs: rule.Source.Namespace}
+		}
+		p.cus.InitL4DefaultPolicy(ft, &policy)
+		sniPolicies[sni] = &policy
+	}
+	if len(sniPolicies) == 0 {
+		return
+	}
+	ngxPolicy.Stream.Tls[ft.Port] = sniPolicies
+}
+
 // gateway-api中就是ft没有默认backend-group 但是有rule
 func getName(ft *Frontend) (upstream string, rule string) {
 	if len(ft.Rules) > 0 {
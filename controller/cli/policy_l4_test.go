@@ -0,0 +1,41 @@
+package cli
+
+import (
+	"testing"
+
+	. "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	"alauda.io/alb2/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTlsPassthroughPolicy(t *testing.T) {
+	p := PolicyCli{log: log.L()}
+	rule := func(id string, sni string) *InternalRule {
+		r := &InternalRule{}
+		r.RuleID = id
+		r.SNI = sni
+		r.BackendGroup = &BackendGroup{Name: id}
+		return r
+	}
+	ft := &Frontend{
+		Port:           443,
+		Protocol:       albv1.FtProtocolTCP,
+		TlsPassthrough: true,
+		Rules: RuleList{
+			rule("r1", "a.com"),
+			rule("r2", "A.com"),
+			rule("r3", "*.b.com"),
+			rule("r4", ""),
+		},
+	}
+	ngx := NgxPolicy{Stream: StreamPolicy{Tcp: map[albv1.PortNumber]Policies{}, Tls: map[albv1.PortNumber]SNIPolicies{}}}
+	p.initStreamModeFt(ft, &ngx)
+	assert.Equal(t, 0, len(ngx.Stream.Tcp))
+	ps := ngx.Stream.Tls[443]
+	assert.Equal(t, 3, len(ps))
+	assert.Equal(t, "r1", ps["a.com"].Rule)
+	assert.Equal(t, "r3", ps["*.b.com"].Upstream)
+	assert.Equal(t, "r4", ps[""].Rule)
+	assert.Equal(t, SubsystemStream, ps[""].Subsystem)
+}
@@ -63,12 +63,11 @@ func (p *PolicyCli) initHttpModeFt(ft *Frontend, ngxPolicy *NgxPolicy, refs RefM
 	}
 
 	for _, rule := range ft.Rules {
-		policy, err := p.InternalRuleToL7Policy(rule, refs)
+		policy, err := p.ruleToL7PolicyWithCache(rule, refs)
 		if err != nil {
 			p.log.Error(err, "to policy fail, skip this rule", "rule", rule.RuleID)
 			continue
 		}
-		policy.Config.Refs = rule.Config.Source // source init in RuleToInternalRule
 		ngxPolicy.Http.Tcp[ft.Port] = append(ngxPolicy.Http.Tcp[ft.Port], policy)
 	}
 
@@ -107,6 +106,27 @@ func (p *PolicyCli) initHttpModeFt(ft *Frontend, ngxPolicy *NgxPolicy, refs RefM
 	sort.Sort(ngxPolicy.Http.Tcp[ft.Port]) // IMPORTANT sort to make sure priority work.
 }
 
+// only the rule which changed (or the things it depends on changed) is translated again.
+func (p *PolicyCli) ruleToL7PolicyWithCache(rule *InternalRule, refs RefMap) (*Policy, error) {
+	cache := p.opt.Cache
+	key := ""
+	if cache != nil && rule.Version != "" {
+		key = p.policyCacheKey(rule, refs)
+		if policy, ok := cache.getPolicy(rule.RuleID, key); ok {
+			return policy, nil
+		}
+	}
+	policy, err := p.InternalRuleToL7Policy(rule, refs)
+	if err != nil {
+		return nil, err
+	}
+	policy.Config.Refs = rule.Config.Source // source init in RuleToInternalRule
+	if key != "" {
+		cache.setPolicy(rule.RuleID, key, policy)
+	}
+	return policy, nil
+}
+
 func initPolicySource(p *Policy, rule *InternalRule) {
 	if rule.Source == nil {
 		return
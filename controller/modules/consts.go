@@ -16,6 +16,8 @@ const (
 	TypeHttpRoute       = "httpRoute"
 	TypeTCPRoute        = "tcpRoute"
 	TypeUDPRoute        = "udpRoute"
+	TypeGRPCRoute       = "grpcRoute"
+	TypeTLSRoute        = "tlsRoute"
 )
 
 const (
@@ -18,9 +18,13 @@ import (
 	. "alauda.io/alb2/controller/types"
 	"alauda.io/alb2/driver"
 	gateway "alauda.io/alb2/gateway/nginx"
+	albv2 "alauda.io/alb2/pkg/apis/alauda/v2beta1"
 	. "alauda.io/alb2/pkg/controller/ngxconf"
 	. "alauda.io/alb2/pkg/controller/ngxconf/types"
 	pm "alauda.io/alb2/pkg/utils/metrics"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
 	"k8s.io/klog/v2"
 )
 
@@ -35,9 +39,15 @@ type NginxController struct {
 	log           logr.Logger
 	lc            *LeaderElection
 	PortProber    *PortProbe
-	albcli        cli.AlbCli    // load alb tree from k8s
-	policycli     cli.PolicyCli // fetch policy needed cr from k8s into alb tree
-	ngxcli        NgxCli        // fetch ngxconf need cr from k8s into alb tree
+	Recorder      record.EventRecorder // used to report the invalid nginx.conf, could be nil
+	PolicyCache   *cli.PolicyCache     // keep the translation results between regenerations, could be nil
+	CertWarner    *CertWarner          // remember the expiring certificates which have been warned, no event is emitted if nil
+	ServiceRefs   *ObjectRefs          // record the services used by alb, could be nil
+	SecretRefs    *ObjectRefs          // record the secrets used by alb, could be nil
+	ConfigMapRefs *ObjectRefs          // record the configmaps used by alb, could be nil
+	albcli        cli.AlbCli           // load alb tree from k8s
+	policycli     cli.PolicyCli        // fetch policy needed cr from k8s into alb tree
+	ngxcli        NgxCli               // fetch ngxconf need cr from k8s into alb tree
 }
 
 func NewNginxController(kd *driver.KubernetesDriver, ctx context.Context, cfg *config.Config, log logr.Logger, leader *LeaderElection) *NginxController {
@@ -95,6 +105,11 @@ func (nc *NginxController) GenerateNginxConfigAndPolicy() (nginxTemplateConfig N
 	if err != nil {
 		return NginxTemplateConfig{}, NgxPolicy{}, err
 	}
+	// set before the endpoints are loaded, the change after it will trigger the next regeneration.
+	if nc.ServiceRefs != nil {
+		nc.ServiceRefs.Set(cli.ReferencedServices(alb))
+	}
+	nc.policycli.SetCache(nc.PolicyCache)
 	if err = nc.policycli.FillUpBackends(alb); err != nil {
 		return NginxTemplateConfig{}, NgxPolicy{}, err
 	}
@@ -102,9 +117,17 @@ func (nc *NginxController) GenerateNginxConfigAndPolicy() (nginxTemplateConfig N
 	if len(alb.Frontends) == 0 {
 		l.Info("No service bind to this nginx now ", "key", nc.albcfg.GeKey())
 	}
-	nc.albcli.CollectAndFetchRefs(alb)
+	nc.albcli.CollectRefs(alb)
+	if nc.SecretRefs != nil {
+		nc.SecretRefs.Set(cli.ReferencedSecrets(alb))
+	}
+	if nc.ConfigMapRefs != nil {
+		nc.ConfigMapRefs.Set(cli.ReferencedConfigMaps(alb))
+	}
+	nc.albcli.FetchRefs(alb)
 
 	nginxPolicy = nc.policycli.GenerateAlbPolicy(alb)
+	nc.reportCertificates(nginxPolicy.CertificateMap)
 	phase := state.GetState().GetPhase()
 	if phase != m.PhaseTerminating {
 		phase = m.PhaseRunning
@@ -251,6 +274,21 @@ func (nc *NginxController) ReloadLoadBalancer() error {
 
 	configChanged := !sameFiles(nc.NewConfigPath, nc.OldConfigPath)
 
+	// the policy is generated with the nginx.conf, neither of them should be promoted if the nginx.conf is invalid.
+	if configChanged {
+		if err = nc.checkConfig(); err != nil {
+			klog.Errorf("refuse to promote invalid config, keep the last valid one: %v", err)
+			return err
+		}
+	} else if !nc.albcfg.GetFlags().E2eTestControllerOnly {
+		// the nginx.conf is the same as the running one, clean the status left by a rejected one.
+		nc.reportCheckResult(&NginxCheckResult{Ok: true})
+	}
+	if err = nc.PromotePolicy(); err != nil {
+		klog.Errorf("failed to promote policy: %s", err.Error())
+		return err
+	}
+
 	// No change, Nginx running, skip
 	if !configChanged && getLastReloadStatus(StatusFileParentPath) == SUCCESS {
 		klog.Info("Config not changed and last reload success")
@@ -269,6 +307,9 @@ func (nc *NginxController) ReloadLoadBalancer() error {
 			klog.Errorf("failed to replace config: %s", err.Error())
 			return err
 		}
+		if lerr := saveLastGood(nc.albcfg.GetLastGoodDir(), nc.OldConfigPath, nc.policyPath()); lerr != nil {
+			klog.Errorf("failed to save last good config: %v", lerr)
+		}
 	}
 
 	if nc.albcfg.GetFlags().E2eTestControllerOnly {
@@ -292,6 +333,70 @@ func (nc *NginxController) ReloadLoadBalancer() error {
 	return err
 }
 
+// checkConfig run nginx -t with the new nginx.conf in the nginx container, and report the result on the status of alb.
+func (nc *NginxController) checkConfig() error {
+	if nc.albcfg.GetFlags().E2eTestControllerOnly {
+		return nil
+	}
+	checker := NewNginxChecker(nc.albcfg.GetNginxCheckDir(), time.Duration(nc.albcfg.GetNginxCheckTimeout())*time.Second)
+	if !checker.Ready() {
+		klog.Warning("nginx config checker is not ready, skip nginx -t")
+		return nil
+	}
+	version, err := fileSha256(nc.NewConfigPath)
+	if err != nil {
+		return err
+	}
+	ret, err := checker.Check(version, nc.NewConfigPath)
+	if err != nil {
+		return err
+	}
+	nc.reportCheckResult(ret)
+	if !ret.Ok {
+		return fmt.Errorf("nginx -t fail: %s", ret.Msg)
+	}
+	return nil
+}
+
+// reportCheckResult record the rejected nginx.conf of this pod on the status of alb, and emit a event when it is rejected the first time.
+func (nc *NginxController) reportCheckResult(ret *NginxCheckResult) {
+	if nc.Driver == nil {
+		return
+	}
+	ns, name := nc.albcfg.GetAlbNsAndName()
+	pod := nc.albcfg.GetPodName()
+	alb, err := nc.Driver.LoadAlbResource(ns, name)
+	if err != nil {
+		nc.log.Error(err, "get alb fail")
+		return
+	}
+	cur, has := alb.Status.Detail.Alb.ReloadStatus[pod]
+	if ret.Ok {
+		if !has {
+			return
+		}
+		nc.log.Info("nginx.conf is valid now, clean reload status", "pod", pod)
+		if err := nc.Driver.PatchAlbReloadStatus(ns, name, pod, nil); err != nil {
+			nc.log.Error(err, "clean reload status fail")
+		}
+		return
+	}
+	if has && cur.Version == ret.Version {
+		return
+	}
+	status := &albv2.ReloadStatus{
+		Msg:          ret.Msg,
+		Version:      ret.Version,
+		ProbeTimeStr: metav1.Time{Time: time.Now()},
+	}
+	if err := nc.Driver.PatchAlbReloadStatus(ns, name, pod, status); err != nil {
+		nc.log.Error(err, "update reload status fail")
+	}
+	if nc.Recorder != nil {
+		nc.Recorder.Eventf(alb, corev1.EventTypeWarning, "InvalidNginxConfig", "nginx.conf of %s is rejected by nginx -t, keep the last valid one: %s", pod, ret.Msg)
+	}
+}
+
 func (nc *NginxController) reload(nginxPid string) error {
 	klog.Info("Send HUP signal to reload nginx")
 	output, err := exec.Command("kill", "-HUP", nginxPid).CombinedOutput()
@@ -0,0 +1,154 @@
+package controller
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"k8s.io/klog/v2"
+)
+
+const (
+	checkRequestFile = "request"
+	checkResultFile  = "result"
+	checkReadyFile   = "ready"
+	// how many versions of last known good config should be kept.
+	maxLastGood = 3
+)
+
+// NginxChecker run nginx -t in the nginx container, since there is no nginx in the alb container.
+// the handshake is done via the files in the shared volume:
+//  1. alb writes "<version>.<nonce> <path of nginx.conf>" into <dir>/request.
+//  2. the nginx container (see run-nginx.sh) runs nginx -t when the request changes, and writes "<version>.<nonce>\n<exit code>\n<output>" into <dir>/result.
+//  3. alb waits until the version of result is the same as the request.
+//
+// the nginx container creates <dir>/ready when it starts, the check should be skipped if it does not exist, e.g. the nginx image is an old one.
+type NginxChecker struct {
+	dir      string
+	timeout  time.Duration
+	interval time.Duration
+}
+
+type NginxCheckResult struct {
+	Version string
+	Ok      bool
+	Msg     string
+}
+
+func NewNginxChecker(dir string, timeout time.Duration) *NginxChecker {
+	return &NginxChecker{
+		dir:      dir,
+		timeout:  timeout,
+		interval: 100 * time.Millisecond,
+	}
+}
+
+func (c *NginxChecker) Ready() bool {
+	_, err := os.Stat(filepath.Join(c.dir, checkReadyFile))
+	return err == nil
+}
+
+// Check run nginx -t with the config. version identifies the content of config (e.g. the sha256 of it),
+// the result of the same version is reused, so a invalid config will not be tested again and again.
+func (c *NginxChecker) Check(version string, path string) (*NginxCheckResult, error) {
+	if ret, err := c.readResult(); err == nil && ret.Version == version {
+		return ret, nil
+	}
+	// the id of request is unique, so the nginx container will run nginx -t again even if the version is the same as the last request.
+	id := fmt.Sprintf("%s.%d", version, time.Now().UnixNano())
+	if err := writeFileAtomic(filepath.Join(c.dir, checkRequestFile), []byte(id+" "+path)); err != nil {
+		return nil, err
+	}
+	deadline := time.Now().Add(c.timeout)
+	for time.Now().Before(deadline) {
+		time.Sleep(c.interval)
+		ret, err := c.readResult()
+		if err == nil && ret.Version == version {
+			return ret, nil
+		}
+	}
+	return nil, fmt.Errorf("wait result of nginx -t timeout %v, is the nginx container running", c.timeout)
+}
+
+func (c *NginxChecker) readResult() (*NginxCheckResult, error) {
+	raw, err := os.ReadFile(filepath.Join(c.dir, checkResultFile))
+	if err != nil {
+		return nil, err
+	}
+	return parseCheckResult(string(raw))
+}
+
+func parseCheckResult(raw string) (*NginxCheckResult, error) {
+	lines := strings.SplitN(raw, "\n", 3)
+	if len(lines) < 2 {
+		return nil, fmt.Errorf("invalid result of nginx -t %q", raw)
+	}
+	code, err := strconv.Atoi(strings.TrimSpace(lines[1]))
+	if err != nil {
+		return nil, fmt.Errorf("invalid exit code of nginx -t %q", lines[1])
+	}
+	version, _, _ := strings.Cut(strings.TrimSpace(lines[0]), ".")
+	ret := &NginxCheckResult{
+		Version: version,
+		Ok:      code == 0,
+	}
+	if len(lines) == 3 {
+		ret.Msg = strings.TrimSpace(lines[2])
+	}
+	return ret, nil
+}
+
+func writeFileAtomic(path string, data []byte) error {
+	tmp := path + ".tmp"
+	if err := os.WriteFile(tmp, data, 0o644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, path)
+}
+
+// saveLastGood keep a copy of the nginx.conf and policy accepted by nginx in <dir>/<unix nano>/, and remove the old versions.
+// run-nginx.sh rolls back to the latest one if the nginx.conf is invalid when nginx starts.
+func saveLastGood(dir string, files ...string) error {
+	verDir := filepath.Join(dir, strconv.FormatInt(time.Now().UnixNano(), 10))
+	if err := os.MkdirAll(verDir, 0o755); err != nil {
+		return err
+	}
+	for _, f := range files {
+		raw, err := os.ReadFile(f)
+		if err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(verDir, filepath.Base(f)), raw, 0o640); err != nil {
+			return err
+		}
+	}
+	return cleanLastGood(dir, maxLastGood)
+}
+
+func cleanLastGood(dir string, keep int) error {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	versions := []int64{}
+	for _, e := range entries {
+		v, err := strconv.ParseInt(e.Name(), 10, 64)
+		if err != nil || !e.IsDir() {
+			continue
+		}
+		versions = append(versions, v)
+	}
+	sort.Slice(versions, func(i, j int) bool { return versions[i] > versions[j] })
+	for i := keep; i < len(versions); i++ {
+		p := filepath.Join(dir, strconv.FormatInt(versions[i], 10))
+		klog.Infof("remove old last good config %s", p)
+		if err := os.RemoveAll(p); err != nil {
+			return err
+		}
+	}
+	return nil
+}
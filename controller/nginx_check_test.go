@@ -0,0 +1,121 @@
+package controller
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseCheckResult(t *testing.T) {
+	ret, err := parseCheckResult("abc.123\n0\nnginx: configuration file /etc/alb2/nginx/nginx.conf test is successful\n")
+	assert.NoError(t, err)
+	assert.Equal(t, "abc", ret.Version)
+	assert.True(t, ret.Ok)
+
+	ret, err = parseCheckResult("abc.123\n1\nnginx: [emerg] unknown directive \"xx\"\nnginx: configuration file test failed")
+	assert.NoError(t, err)
+	assert.False(t, ret.Ok)
+	assert.Equal(t, "nginx: [emerg] unknown directive \"xx\"\nnginx: configuration file test failed", ret.Msg)
+
+	_, err = parseCheckResult("abc.123")
+	assert.Error(t, err)
+	_, err = parseCheckResult("abc.123\nx\n")
+	assert.Error(t, err)
+}
+
+// fakeNginx act as the checker loop of run-nginx.sh, reject the config which contains "invalid".
+func fakeNginx(t *testing.T, dir string, stop chan struct{}) {
+	last := ""
+	for {
+		select {
+		case <-stop:
+			return
+		case <-time.After(10 * time.Millisecond):
+		}
+		raw, err := os.ReadFile(filepath.Join(dir, checkRequestFile))
+		if err != nil || string(raw) == last {
+			continue
+		}
+		last = string(raw)
+		id, path, _ := strings.Cut(last, " ")
+		cfg, err := os.ReadFile(path)
+		assert.NoError(t, err)
+		code := "0"
+		if strings.Contains(string(cfg), "invalid") {
+			code = "1"
+		}
+		assert.NoError(t, writeFileAtomic(filepath.Join(dir, checkResultFile), []byte(id+"\n"+code+"\n"+"output of "+path)))
+	}
+}
+
+func TestNginxChecker(t *testing.T) {
+	dir := t.TempDir()
+	conf := filepath.Join(dir, "nginx.conf")
+	checker := NewNginxChecker(dir, 3*time.Second)
+	checker.interval = 10 * time.Millisecond
+	assert.False(t, checker.Ready())
+	assert.NoError(t, os.WriteFile(filepath.Join(dir, checkReadyFile), nil, 0o644))
+	assert.True(t, checker.Ready())
+
+	stop := make(chan struct{})
+	defer close(stop)
+	go fakeNginx(t, dir, stop)
+
+	assert.NoError(t, os.WriteFile(conf, []byte("valid"), 0o644))
+	ret, err := checker.Check("v1", conf)
+	assert.NoError(t, err)
+	assert.Equal(t, "v1", ret.Version)
+	assert.True(t, ret.Ok)
+
+	assert.NoError(t, os.WriteFile(conf, []byte("invalid"), 0o644))
+	ret, err = checker.Check("v2", conf)
+	assert.NoError(t, err)
+	assert.Equal(t, "v2", ret.Version)
+	assert.False(t, ret.Ok)
+	assert.Equal(t, "output of "+conf, ret.Msg)
+
+	// the result of the same version is reused.
+	req, err := os.ReadFile(filepath.Join(dir, checkRequestFile))
+	assert.NoError(t, err)
+	ret, err = checker.Check("v2", conf)
+	assert.NoError(t, err)
+	assert.False(t, ret.Ok)
+	req2, err := os.ReadFile(filepath.Join(dir, checkRequestFile))
+	assert.NoError(t, err)
+	assert.Equal(t, string(req), string(req2))
+}
+
+func TestNginxCheckerTimeout(t *testing.T) {
+	dir := t.TempDir()
+	checker := NewNginxChecker(dir, 100*time.Millisecond)
+	checker.interval = 10 * time.Millisecond
+	_, err := checker.Check("v1", filepath.Join(dir, "nginx.conf"))
+	assert.Error(t, err)
+}
+
+func TestSaveLastGood(t *testing.T) {
+	dir := t.TempDir()
+	lastGood := filepath.Join(dir, "last_good")
+	conf := filepath.Join(dir, "nginx.conf")
+	policy := filepath.Join(dir, "policy.new")
+	for i := 0; i < maxLastGood+2; i++ {
+		assert.NoError(t, os.WriteFile(conf, []byte("conf"+string(rune('0'+i))), 0o644))
+		assert.NoError(t, os.WriteFile(policy, []byte("policy"), 0o644))
+		assert.NoError(t, saveLastGood(lastGood, conf, policy))
+		time.Sleep(time.Millisecond)
+	}
+	entries, err := os.ReadDir(lastGood)
+	assert.NoError(t, err)
+	assert.Equal(t, maxLastGood, len(entries))
+	// the latest one is kept.
+	latest := entries[len(entries)-1].Name()
+	raw, err := os.ReadFile(filepath.Join(lastGood, latest, "nginx.conf"))
+	assert.NoError(t, err)
+	assert.Equal(t, "conf"+string(rune('0'+maxLastGood+1)), string(raw))
+	_, err = os.Stat(filepath.Join(lastGood, latest, "policy.new"))
+	assert.NoError(t, err)
+}
@@ -0,0 +1,30 @@
+package controller
+
+import (
+	"sync"
+
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ObjectRefs is the objects (services, secrets or configmaps) referenced by the last regeneration, the changes of other objects are ignored.
+// it is set by the regeneration and read by the event handlers of informers.
+type ObjectRefs struct {
+	mu   sync.RWMutex
+	refs map[client.ObjectKey]bool
+}
+
+func NewObjectRefs() *ObjectRefs {
+	return &ObjectRefs{refs: map[client.ObjectKey]bool{}}
+}
+
+func (s *ObjectRefs) Set(refs map[client.ObjectKey]bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.refs = refs
+}
+
+func (s *ObjectRefs) Has(ns, name string) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.refs[client.ObjectKey{Namespace: ns, Name: name}]
+}
@@ -11,19 +11,41 @@ import (
 	"k8s.io/klog/v2"
 )
 
+// UpdatePolicyFile write the policy into a pending file, it will be promoted with the nginx.conf generated at the same time, see PromotePolicy.
 func (nc *NginxController) UpdatePolicyFile(ngxPolicies NgxPolicy) error {
 	zip := nc.albcfg.GetFlags().PolicyZip
+	path := pendingPolicyPath(nc.policyPath())
+	klog.Infof("update policy %v", path)
+	return nc.updatePolicyFileRaw(ngxPolicies, path, zip)
+}
+
+// the policy file read by nginx.
+func (nc *NginxController) policyPath() string {
 	path := nc.NewPolicyPath
-	if zip {
+	if nc.albcfg.GetFlags().PolicyZip {
 		path += ".bin"
 	}
-	klog.Infof("update policy %v", path)
-	return nc.updatePolicyFileRaw(ngxPolicies, path, zip)
+	return path
+}
+
+func pendingPolicyPath(path string) string {
+	return path + ".pending"
+}
+
+// PromotePolicy make the pending policy take effect. do nothing if there is no pending policy.
+func (nc *NginxController) PromotePolicy() error {
+	path := nc.policyPath()
+	pending := pendingPolicyPath(path)
+	if _, err := os.Stat(pending); os.IsNotExist(err) {
+		return nil
+	}
+	return os.Rename(pending, path)
 }
 
 func (nc *NginxController) updatePolicyFileRaw(ngxPolicies NgxPolicy, path string, zip bool) error {
 	oldpath := path + ".old"
-	policyBytes, err := json.MarshalIndent(ngxPolicies, "", "\t")
+	// the policy is large with thousands of rules, indent makes the marshal much slower.
+	policyBytes, err := json.Marshal(ngxPolicies)
 	if err != nil {
 		klog.Error()
 		return err
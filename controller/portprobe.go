@@ -88,6 +88,10 @@ func (p *PortProbe) LeaderUpdateAlbPortStatus() error {
 		return nil
 	}
 	status := genCurPortConflictStatus(fts)
+	status.ReloadStatus, err = p.cleanUpOldReloadStatus(albRes.Status.Detail.Alb.ReloadStatus)
+	if err != nil {
+		return err
+	}
 	if !albStatusChange(albRes.Status.Detail.Alb, status) {
 		return nil
 	}
@@ -156,6 +160,9 @@ func albStatusChange(origin, latest v2beta1.AlbStatus) bool {
 	if len(origin.PortStatus) != len(latest.PortStatus) {
 		return true
 	}
+	if len(origin.ReloadStatus) != len(latest.ReloadStatus) {
+		return true
+	}
 	for key, op := range origin.PortStatus {
 		np, find := latest.PortStatus[key]
 		if !find {
@@ -168,6 +175,24 @@ func albStatusChange(origin, latest v2beta1.AlbStatus) bool {
 	return false
 }
 
+// the reload status is set by each pod itself, remove the ones of pods which do not exist anymore.
+func (p *PortProbe) cleanUpOldReloadStatus(origin map[string]v2beta1.ReloadStatus) (map[string]v2beta1.ReloadStatus, error) {
+	if len(origin) == 0 {
+		return nil, nil
+	}
+	curPods, err := p.getAlbPod()
+	if err != nil {
+		return nil, err
+	}
+	ret := map[string]v2beta1.ReloadStatus{}
+	for pod, s := range origin {
+		if curPods.Has(pod) {
+			ret[pod] = s
+		}
+	}
+	return ret, nil
+}
+
 func genPodPortConflictKey(host string, pod string) string {
 	return host + "/" + pod
 }
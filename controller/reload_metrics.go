@@ -0,0 +1,53 @@
+package controller
+
+import (
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+)
+
+// MetricsRegistry hold the metrics of alb controller itself, it is exposed at /metrics of the go monitor server.
+var MetricsRegistry = prometheus.NewRegistry()
+
+var (
+	applyLatency = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "alb_config_apply_latency_seconds",
+		Help:    "latency from the change of resource to the nginx config is applied",
+		Buckets: []float64{0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30, 60, 120},
+	}, []string{"kind"})
+	regenerateTotal = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "alb_config_regenerate_total",
+		Help: "times of nginx config regeneration",
+	}, []string{"kind", "result"})
+)
+
+// results of the regeneration which is aborted.
+const (
+	// the regeneration does not finish in the reload timeout.
+	RegenerateTimeout = "timeout"
+	// the regeneration is skipped since the last timeout one is still running.
+	RegenerateSkip = "skip"
+)
+
+func init() {
+	MetricsRegistry.MustRegister(applyLatency, regenerateTotal)
+}
+
+// ObserveRegenerate record the result of a regeneration triggered by the change of kind at since.
+// the latency of resync is meaningless, only the count is recorded.
+func ObserveRegenerate(kind string, since time.Time, err error) {
+	result := "success"
+	if err != nil {
+		result = "fail"
+	}
+	regenerateTotal.WithLabelValues(kind, result).Inc()
+	if err != nil || kind == ReasonResync {
+		return
+	}
+	applyLatency.WithLabelValues(kind).Observe(time.Since(since).Seconds())
+}
+
+// ObserveRegenerateAbort record a regeneration which is aborted, result is RegenerateTimeout or RegenerateSkip.
+func ObserveRegenerateAbort(kind string, result string) {
+	regenerateTotal.WithLabelValues(kind, result).Inc()
+}
@@ -0,0 +1,60 @@
+package controller
+
+import (
+	"fmt"
+	"time"
+
+	"alauda.io/alb2/config"
+	"alauda.io/alb2/driver"
+	albv2 "alauda.io/alb2/pkg/apis/alauda/v2beta1"
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/record"
+)
+
+// RegenerateTimeoutVersion is the version of the reload status which records a regeneration timeout, instead of a nginx.conf rejected by nginx -t.
+const RegenerateTimeoutVersion = "regenerate-timeout"
+
+// ReportRegenerateTimeout record the timeout regeneration of this pod on the status of alb, the same place as the rejected nginx.conf,
+// so the state of alb is warning. recorder could be nil.
+func ReportRegenerateTimeout(drv *driver.KubernetesDriver, cfg *config.Config, recorder record.EventRecorder, timeout time.Duration, log logr.Logger) {
+	ns, name := cfg.GetAlbNsAndName()
+	pod := cfg.GetPodName()
+	alb, err := drv.LoadAlbResource(ns, name)
+	if err != nil {
+		log.Error(err, "get alb fail")
+		return
+	}
+	msg := fmt.Sprintf("regeneration of nginx config does not finish in %v, the following changes will not take effect until it finishes", timeout)
+	status := &albv2.ReloadStatus{
+		Msg:          msg,
+		Version:      RegenerateTimeoutVersion,
+		ProbeTimeStr: metav1.Time{Time: time.Now()},
+	}
+	if err := drv.PatchAlbReloadStatus(ns, name, pod, status); err != nil {
+		log.Error(err, "update reload status fail")
+	}
+	if recorder != nil {
+		recorder.Eventf(alb, corev1.EventTypeWarning, "RegenerateTimeout", "%s: %s", pod, msg)
+	}
+}
+
+// CleanRegenerateTimeout remove the status recorded by ReportRegenerateTimeout after a regeneration succeeds.
+// the status of rejected nginx.conf is left to the nginx -t.
+func CleanRegenerateTimeout(drv *driver.KubernetesDriver, cfg *config.Config, log logr.Logger) {
+	ns, name := cfg.GetAlbNsAndName()
+	pod := cfg.GetPodName()
+	alb, err := drv.LoadAlbResource(ns, name)
+	if err != nil {
+		log.Error(err, "get alb fail")
+		return
+	}
+	cur, has := alb.Status.Detail.Alb.ReloadStatus[pod]
+	if !has || cur.Version != RegenerateTimeoutVersion {
+		return
+	}
+	if err := drv.PatchAlbReloadStatus(ns, name, pod, nil); err != nil {
+		log.Error(err, "clean reload status fail")
+	}
+}
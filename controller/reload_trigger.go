@@ -0,0 +1,101 @@
+package controller
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"github.com/go-logr/logr"
+)
+
+const (
+	ReasonResync = "resync"
+)
+
+// ReloadTrigger merge the changes of resources into one regeneration of the nginx config.
+// the first change starts a debounce window, changes in the window (or during the regeneration) are handled by the next regeneration.
+// a resync is done periodically, in case of some change is missed.
+type ReloadTrigger struct {
+	debounce time.Duration
+	resync   time.Duration
+	log      logr.Logger
+
+	mu      sync.Mutex
+	pending *pendingChange
+	signal  chan struct{}
+}
+
+// the first change since the last regeneration.
+type pendingChange struct {
+	Kind string
+	At   time.Time
+}
+
+func NewReloadTrigger(debounce, resync time.Duration, log logr.Logger) *ReloadTrigger {
+	return &ReloadTrigger{
+		debounce: debounce,
+		resync:   resync,
+		log:      log,
+		signal:   make(chan struct{}, 1),
+	}
+}
+
+// Notify is called by the event handlers of informers, it never blocks.
+func (t *ReloadTrigger) Notify(kind string) {
+	t.mu.Lock()
+	if t.pending == nil {
+		t.pending = &pendingChange{Kind: kind, At: time.Now()}
+	}
+	t.mu.Unlock()
+	select {
+	case t.signal <- struct{}{}:
+	default:
+	}
+}
+
+func (t *ReloadTrigger) take() *pendingChange {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	p := t.pending
+	t.pending = nil
+	return p
+}
+
+// Run call fn once at start, then on changes and resync, block util ctx is done.
+// kind is the kind of the first merged change or ReasonResync, since is the time of it.
+func (t *ReloadTrigger) Run(ctx context.Context, fn func(kind string, since time.Time)) {
+	fn(ReasonResync, time.Now())
+	resync := time.NewTimer(t.resync)
+	defer resync.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-t.signal:
+			select {
+			case <-ctx.Done():
+				return
+			case <-time.After(t.debounce):
+			}
+		case <-resync.C:
+			t.mu.Lock()
+			if t.pending == nil {
+				t.pending = &pendingChange{Kind: ReasonResync, At: time.Now()}
+			}
+			t.mu.Unlock()
+		}
+		p := t.take()
+		if p == nil {
+			continue
+		}
+		t.log.V(2).Info("regenerate", "kind", p.Kind, "wait", time.Since(p.At))
+		fn(p.Kind, p.At)
+		if !resync.Stop() {
+			select {
+			case <-resync.C:
+			default:
+			}
+		}
+		resync.Reset(t.resync)
+	}
+}
@@ -0,0 +1,52 @@
+package controller
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"alauda.io/alb2/utils/log"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestReloadTrigger(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trigger := NewReloadTrigger(50*time.Millisecond, time.Hour, log.L())
+	kinds := make(chan string, 10)
+	go trigger.Run(ctx, func(kind string, since time.Time) {
+		kinds <- kind
+	})
+	assert.Equal(t, ReasonResync, <-kinds)
+
+	// changes in the debounce window are merged into one regeneration, the kind of the first one is reported.
+	trigger.Notify("Rule")
+	trigger.Notify("Service")
+	trigger.Notify("EndpointSlice")
+	assert.Equal(t, "Rule", <-kinds)
+	select {
+	case k := <-kinds:
+		t.Fatalf("should not regenerate again %v", k)
+	case <-time.After(200 * time.Millisecond):
+	}
+
+	trigger.Notify("Frontend")
+	assert.Equal(t, "Frontend", <-kinds)
+}
+
+func TestReloadTriggerResync(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+	trigger := NewReloadTrigger(10*time.Millisecond, 100*time.Millisecond, log.L())
+	kinds := make(chan string, 10)
+	go trigger.Run(ctx, func(kind string, since time.Time) {
+		kinds <- kind
+	})
+	assert.Equal(t, ReasonResync, <-kinds)
+	select {
+	case k := <-kinds:
+		assert.Equal(t, ReasonResync, k)
+	case <-time.After(time.Second):
+		t.Fatal("should resync")
+	}
+}
@@ -2,6 +2,7 @@ package types
 
 import (
 	"fmt"
+	"reflect"
 
 	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
 	v1 "alauda.io/alb2/pkg/apis/alauda/v1"
@@ -78,6 +79,8 @@ func (bg BackendGroup) Eq(other BackendGroup) bool {
 		bg.Mode == other.Mode &&
 		bg.SessionAffinityAttribute == other.SessionAffinityAttribute &&
 		bg.SessionAffinityPolicy == other.SessionAffinityPolicy &&
+		reflect.DeepEqual(bg.SessionAffinityCookie, other.SessionAffinityCookie) &&
+		reflect.DeepEqual(bg.HealthCheck, other.HealthCheck) &&
 		bg.Backends.Eq(other.Backends)
 }
 
@@ -160,5 +163,5 @@ func (bs Backends) Eq(other Backends) bool {
 }
 
 func (r RewriteResponseConfig) IsEmpty() bool {
-	return len(r.Headers) == 0
+	return len(r.Headers) == 0 && len(r.HeadersAdd) == 0 && len(r.HeadersRemove) == 0
 }
@@ -1,15 +1,29 @@
 package types
 
 import (
+	"crypto/x509"
+
 	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
 	v1 "alauda.io/alb2/pkg/apis/alauda/v1"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 
 	auth_t "alauda.io/alb2/pkg/controller/ext/auth/types"
+	clienttls_t "alauda.io/alb2/pkg/controller/ext/clienttls/types"
+	errorpage_t "alauda.io/alb2/pkg/controller/ext/errorpage/types"
+	healthcheck_t "alauda.io/alb2/pkg/controller/ext/healthcheck/types"
+	ipacl_t "alauda.io/alb2/pkg/controller/ext/ipacl/types"
 	keepalive_t "alauda.io/alb2/pkg/controller/ext/keepalive/types"
+	mirror_t "alauda.io/alb2/pkg/controller/ext/mirror/types"
 	otelt "alauda.io/alb2/pkg/controller/ext/otel/types"
+	outlier_t "alauda.io/alb2/pkg/controller/ext/outlier/types"
+	proxy_t "alauda.io/alb2/pkg/controller/ext/proxy/types"
+	ratelimit_t "alauda.io/alb2/pkg/controller/ext/ratelimit/types"
+	realip_t "alauda.io/alb2/pkg/controller/ext/realip/types"
 	redirect_t "alauda.io/alb2/pkg/controller/ext/redirect/types"
+	retry_t "alauda.io/alb2/pkg/controller/ext/retry/types"
 	timeout_t "alauda.io/alb2/pkg/controller/ext/timeout/types"
+	tlspolicy_t "alauda.io/alb2/pkg/controller/ext/tlspolicy/types"
+	upstreamtls_t "alauda.io/alb2/pkg/controller/ext/upstreamtls/types"
 	waft "alauda.io/alb2/pkg/controller/ext/waf/types"
 	corev1 "k8s.io/api/core/v1"
 )
@@ -32,9 +46,14 @@ type LoadBalancer struct {
 
 // 代表的是ft单独作为一个路由规则时的配置。比如l4的配置。l7 redirect或者默认转发的配置
 type FtConf struct {
-	Timeout   *timeout_t.TimeoutCr     `json:"timeout,omitempty"`
-	Redirect  *redirect_t.RedirectCr   `json:"redirect,omitempty"`
-	KeepAlive *keepalive_t.KeepAliveCr `json:"keepalive,omitempty"`
+	Timeout       *timeout_t.TimeoutCr      `json:"timeout,omitempty"`
+	Redirect      *redirect_t.RedirectCr    `json:"redirect,omitempty"`
+	KeepAlive     *keepalive_t.KeepAliveCr  `json:"keepalive,omitempty"`
+	RateLimit     *ratelimit_t.RateLimitCr  `json:"ratelimit,omitempty"`
+	ClientTLS     *clienttls_t.ClientTLSCr  `json:"client_tls,omitempty"`
+	ProxyProtocol *realip_t.ProxyProtocolCr `json:"proxy_protocol,omitempty"`
+	RealIP        *realip_t.RealIPCr        `json:"real_ip,omitempty"`
+	TLS           *tlspolicy_t.TLSCr        `json:"tls,omitempty"`
 }
 
 type Frontend struct {
@@ -49,6 +68,7 @@ type Frontend struct {
 	BackendProtocol string            `json:"backend_protocol"` // ft 默认后端路由组对应的协议
 	BackendGroup    *BackendGroup     `json:"-"`                // ft 默认后端路由组对应的 endpoint 权重、均衡算法等相关信息
 	CertificateName string            `json:"certificate_name"` // ft 默认证书
+	TlsPassthrough  bool              `json:"tls_passthrough"`  // tcp ft which route by sni via ssl_preread, used by gateway-api tls route
 	Conflict        bool              `json:"-"`
 }
 
@@ -61,18 +81,34 @@ type Domain struct {
 type Certificate struct {
 	Cert string `json:"cert"`
 	Key  string `json:"key"`
+	// ca used to verify the certificate of client, only set when mtls is enabled on this domain.
+	ClientCa *CaCertificate `json:"client_ca,omitempty"`
+	// parsed from the cert, used to report the certificate inventory, not a part of policy.
+	Info *CertInfo `json:"-"`
+}
+
+type CertInfo struct {
+	// namespace/name of the secret which the cert comes from.
+	Secret string
+	// the leaf certificate.
+	Leaf *x509.Certificate
 }
 
 type CaCertificate struct {
-	Cert string `json:"cert"`
+	Cert  string `json:"cert"`
+	Depth int    `json:"depth"`
+	// the ca id of client tls, see clienttls.CaId.
+	Id string `json:"id"`
 }
 
 type BackendGroup struct {
-	Name                     string   `json:"name"`
-	SessionAffinityPolicy    string   `json:"session_affinity_policy"`
-	SessionAffinityAttribute string   `json:"session_affinity_attribute"`
-	Mode                     string   `json:"mode"`
-	Backends                 Backends `json:"backends"`
+	Name                     string                           `json:"name"`
+	SessionAffinityPolicy    string                           `json:"session_affinity_policy"`
+	SessionAffinityAttribute string                           `json:"session_affinity_attribute"`
+	SessionAffinityCookie    *albv1.SessionAffinityCookie     `json:"session_affinity_cookie,omitempty"`
+	HealthCheck              *healthcheck_t.HealthCheckPolicy `json:"health_check,omitempty"`
+	Mode                     string                           `json:"mode"`
+	Backends                 Backends                         `json:"backends"`
 }
 
 type Backends []*Backend
@@ -104,6 +140,16 @@ type NgxPolicy struct {
 	Stream         StreamPolicy           `json:"stream"`
 	SharedConfig   SharedExtPolicyConfig  `json:"config"`
 	BackendGroup   []*BackendGroup        `json:"backend_group"`
+
+	// the expiry of certificates in certificate_map, exported as metrics by nginx.
+	CertificateExpiry []CertificateExpiry `json:"certificate_expiry,omitempty"`
+}
+
+type CertificateExpiry struct {
+	Domain string `json:"domain"`
+	Secret string `json:"secret"`
+	// unix timestamp in seconds.
+	NotAfter int64 `json:"not_after"`
 }
 
 type (
@@ -116,10 +162,14 @@ type (
 type SharedExtPolicyConfig map[string]RefBox
 
 type StreamPolicy struct {
-	Tcp map[albv1.PortNumber]Policies `json:"tcp"`
-	Udp map[albv1.PortNumber]Policies `json:"udp"`
+	Tcp map[albv1.PortNumber]Policies    `json:"tcp"`
+	Udp map[albv1.PortNumber]Policies    `json:"udp"`
+	Tls map[albv1.PortNumber]SNIPolicies `json:"tls"` // tls passthrough, route via sni instead of one rule per port
 }
 
+// sni => policy. "*.a.com" is a wildcard sni, "" match any sni (or client without sni)
+type SNIPolicies map[string]*Policy
+
 // keep it as same as rule
 type Source struct {
 	SourceType string `json:"source_type,omitempty"`
@@ -185,10 +235,12 @@ type RuleMeta struct {
 	RuleID   string     `json:"rule_id"` // rule的标示,对alb-rule 是alb-rule的name，对gateway api route是这个route的唯一路径
 	Source   *v1.Source `json:"source,omitempty"`
 	Priority int        `json:"-"` // priority set by user, used to sort policy which is rule's priority
+	Version  string     `json:"-"` // version of the rule and the ft/alb it inherits config from, used as the key of policy cache. empty means do not cache
 }
 
 type RuleMatch struct { // 和匹配规则
 	DSLX v1.DSLX `json:"-"`
+	SNI  string  `json:"sni,omitempty"` // only used in tls passthrough ft. empty means match any sni
 }
 
 type RuleCert struct { // 和证书有关的配置
@@ -227,6 +279,15 @@ type RuleExt struct { // 不同的扩展的配置
 	Redirect        *redirect_t.RedirectCr `json:"redirect,omitempty"`
 	Waf             *waft.WafInternal
 	Auth            *auth_t.AuthCr
+	Mirror          *mirror_t.MirrorCr           `json:"mirror,omitempty"`
+	UpstreamTLS     *upstreamtls_t.UpstreamTLSCr `json:"upstream_tls,omitempty"`
+	RateLimit       *ratelimit_t.RateLimitCr     `json:"ratelimit,omitempty"`
+	Retry           *retry_t.RetryCr             `json:"retry,omitempty"`
+	IpAcl           *ipacl_t.IpAclCr             `json:"ipacl,omitempty"`
+	ClientTLS       *clienttls_t.ClientTLSCr     `json:"client_tls,omitempty"`
+	Outlier         *outlier_t.OutlierCr         `json:"outlier,omitempty"`
+	Proxy           *proxy_t.ProxyCr             `json:"proxy,omitempty"`
+	ErrorPage       *errorpage_t.ErrorPageCr     `json:"errorpage,omitempty"`
 	Source          ConfigSource
 }
 
@@ -246,16 +307,32 @@ const (
 	Otel            PolicyExtKind = "otel"
 	Waf             PolicyExtKind = "waf"
 	Auth            PolicyExtKind = "auth"
+	Mirror          PolicyExtKind = "mirror"
+	UpstreamTLS     PolicyExtKind = "upstream_tls"
+	RateLimit       PolicyExtKind = "ratelimit"
+	IpAcl           PolicyExtKind = "ipacl"
+	ClientTLS       PolicyExtKind = "client_tls"
+	Outlier         PolicyExtKind = "outlier"
+	Proxy           PolicyExtKind = "proxy"
+	ErrorPage       PolicyExtKind = "errorpage"
 )
 
 type PolicyExt struct {
-	RewriteResponse *RewriteResponseConfig `json:"rewrite_response,omitempty"`
-	RewriteRequest  *RewriteRequestConfig  `json:"rewrite_request,omitempty"`
-	Timeout         *timeout_t.TimeoutCr   `json:"timeout,omitempty"`
-	Otel            *otelt.OtelConf        `json:"otel,omitempty"`
-	Auth            *auth_t.AuthPolicy     `json:"auth,omitempty"`
-	Redirect        *redirect_t.RedirectCr `json:"redirect,omitempty"`
-	Source          string                 `json:"-"`
+	RewriteResponse *RewriteResponseConfig           `json:"rewrite_response,omitempty"`
+	RewriteRequest  *RewriteRequestConfig            `json:"rewrite_request,omitempty"`
+	Timeout         *timeout_t.TimeoutCr             `json:"timeout,omitempty"`
+	Otel            *otelt.OtelConf                  `json:"otel,omitempty"`
+	Auth            *auth_t.AuthPolicy               `json:"auth,omitempty"`
+	Redirect        *redirect_t.RedirectCr           `json:"redirect,omitempty"`
+	Mirror          *mirror_t.MirrorPolicy           `json:"mirror,omitempty"`
+	UpstreamTLS     *upstreamtls_t.UpstreamTLSPolicy `json:"upstream_tls,omitempty"`
+	RateLimit       *ratelimit_t.RateLimitPolicy     `json:"ratelimit,omitempty"`
+	IpAcl           *ipacl_t.IpAclPolicy             `json:"ipacl,omitempty"`
+	ClientTLS       *clienttls_t.ClientTLSPolicy     `json:"client_tls,omitempty"`
+	Outlier         *outlier_t.OutlierPolicy         `json:"outlier,omitempty"`
+	Proxy           *proxy_t.ProxyPolicy             `json:"proxy,omitempty"`
+	ErrorPage       *errorpage_t.ErrorPagePolicy     `json:"errorpage,omitempty"`
+	Source          string                           `json:"-"`
 }
 
 type PolicyExtCfg struct {
@@ -283,6 +360,30 @@ func (p *PolicyExt) Clean(key PolicyExtKind) {
 	if key == Redirect {
 		p.Redirect = nil
 	}
+	if key == Mirror {
+		p.Mirror = nil
+	}
+	if key == UpstreamTLS {
+		p.UpstreamTLS = nil
+	}
+	if key == RateLimit {
+		p.RateLimit = nil
+	}
+	if key == IpAcl {
+		p.IpAcl = nil
+	}
+	if key == ClientTLS {
+		p.ClientTLS = nil
+	}
+	if key == Outlier {
+		p.Outlier = nil
+	}
+	if key == Proxy {
+		p.Proxy = nil
+	}
+	if key == ErrorPage {
+		p.ErrorPage = nil
+	}
 }
 
 // 将其转换为map方便后续去重
@@ -307,6 +408,30 @@ func (p PolicyExtCfg) ToMaps() PolicyExtMap {
 	if p.Redirect != nil {
 		m[Redirect] = &PolicyExt{Redirect: p.Redirect, Source: p.Refs[Redirect]}
 	}
+	if p.Mirror != nil {
+		m[Mirror] = &PolicyExt{Mirror: p.Mirror, Source: p.Refs[Mirror]}
+	}
+	if p.UpstreamTLS != nil {
+		m[UpstreamTLS] = &PolicyExt{UpstreamTLS: p.UpstreamTLS, Source: p.Refs[UpstreamTLS]}
+	}
+	if p.RateLimit != nil {
+		m[RateLimit] = &PolicyExt{RateLimit: p.RateLimit, Source: p.Refs[RateLimit]}
+	}
+	if p.IpAcl != nil {
+		m[IpAcl] = &PolicyExt{IpAcl: p.IpAcl, Source: p.Refs[IpAcl]}
+	}
+	if p.ClientTLS != nil {
+		m[ClientTLS] = &PolicyExt{ClientTLS: p.ClientTLS, Source: p.Refs[ClientTLS]}
+	}
+	if p.Outlier != nil {
+		m[Outlier] = &PolicyExt{Outlier: p.Outlier, Source: p.Refs[Outlier]}
+	}
+	if p.Proxy != nil {
+		m[Proxy] = &PolicyExt{Proxy: p.Proxy, Source: p.Refs[Proxy]}
+	}
+	if p.ErrorPage != nil {
+		m[ErrorPage] = &PolicyExt{ErrorPage: p.ErrorPage, Source: p.Refs[ErrorPage]}
+	}
 	return m
 }
 
@@ -319,11 +444,15 @@ type LegacyExtInPolicy struct {
 }
 
 type RuleUpstream struct { // 不同的扩展的配置
-	BackendProtocol       string            `json:"backend_protocol"`           // set to variable $backend_protocol, used in proxy_pass $backend_protocol://http_backend; in nginx.conf
-	SessionAffinityPolicy string            `json:"session_affinity_policy"`    // will be set in upstream config
-	SessionAffinityAttr   string            `json:"session_affinity_attribute"` // will be set in upstream config
-	Services              []*BackendService `json:"services"`                   // 这条规则对应的后端服务
-	BackendGroup          *BackendGroup     `json:"-"`                          // 这条规则对应的后端 pod 的 ip
+	BackendProtocol       string                       `json:"backend_protocol"`                  // set to variable $backend_protocol, used in proxy_pass $backend_protocol://http_backend; in nginx.conf
+	SessionAffinityPolicy string                       `json:"session_affinity_policy"`           // will be set in upstream config
+	SessionAffinityAttr   string                       `json:"session_affinity_attribute"`        // will be set in upstream config
+	SessionAffinityCookie *albv1.SessionAffinityCookie `json:"session_affinity_cookie,omitempty"` // will be set in upstream config
+	HealthCheck           *healthcheck_t.HealthCheckCr `json:"health_check,omitempty"`            // will be set in upstream config
+	Services              []*BackendService            `json:"services"`                          // 这条规则对应的后端服务
+	BackendGroup          *BackendGroup                `json:"-"`                                 // 这条规则对应的后端 pod 的 ip
+	MirrorBackendGroup    *BackendGroup                `json:"-"`                                 // 流量镜像的后端 pod 的 ip, 只有配置了 mirror 时才有
+	ErrorPageGroups       []*BackendGroup              `json:"-"`                                 // 错误页服务的后端 pod 的 ip, 只有配置了 errorpage service 时才有
 }
 
 // policy.json http match rule config
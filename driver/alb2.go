@@ -2,6 +2,7 @@ package driver
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 
 	m "alauda.io/alb2/controller/modules"
@@ -10,6 +11,7 @@ import (
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/labels"
+	"k8s.io/apimachinery/pkg/types"
 	"k8s.io/klog/v2"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 )
@@ -37,6 +39,45 @@ func (kd *KubernetesDriver) UpdateAlbStatus(alb *albv2.ALB2) error {
 	return nil
 }
 
+// PatchAlbReloadStatus set the reload status of a pod, nil means the last reload of pod succeeds and the status should be removed.
+// it only touches the key of the pod, so pods of the same alb will not conflict with each other.
+func (kd *KubernetesDriver) PatchAlbReloadStatus(namespace, name, pod string, status *albv2.ReloadStatus) error {
+	patch := map[string]interface{}{
+		"status": map[string]interface{}{
+			"detail": map[string]interface{}{
+				"alb": map[string]interface{}{
+					"reloadstatus": map[string]interface{}{
+						pod: status,
+					},
+				},
+			},
+		},
+	}
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = kd.ALBClient.CrdV2beta1().ALB2s(namespace).Patch(context.TODO(), name, types.MergePatchType, raw, metav1.PatchOptions{}, "status")
+	return err
+}
+
+// PatchAlbCertificates replace the certificates in the status of alb.
+func (kd *KubernetesDriver) PatchAlbCertificates(namespace, name string, certs []albv2.CertificateStatus) error {
+	patch := map[string]interface{}{
+		"status": map[string]interface{}{
+			"detail": map[string]interface{}{
+				"certificates": certs,
+			},
+		},
+	}
+	raw, err := json.Marshal(patch)
+	if err != nil {
+		return err
+	}
+	_, err = kd.ALBClient.CrdV2beta1().ALB2s(namespace).Patch(context.TODO(), name, types.MergePatchType, raw, metav1.PatchOptions{}, "status")
+	return err
+}
+
 func (kd *KubernetesDriver) LoadFrontends(namespace, lbname string) ([]*alb2v1.Frontend, error) {
 	sel := labels.Set{kd.n.GetLabelAlbName(): lbname}.AsSelector()
 	resList, err := kd.FrontendLister.Frontends(namespace).List(sel)
@@ -4,15 +4,18 @@ import (
 	"context"
 	"errors"
 
+	"alauda.io/alb2/gateway"
 	albinformers "alauda.io/alb2/pkg/client/informers/externalversions"
 	albv1 "alauda.io/alb2/pkg/client/informers/externalversions/alauda/v1"
 	albv2 "alauda.io/alb2/pkg/client/informers/externalversions/alauda/v2beta1"
 	albGateway "alauda.io/alb2/pkg/client/informers/externalversions/gateway/v1alpha1"
+	"k8s.io/client-go/dynamic/dynamicinformer"
 	kubeinformers "k8s.io/client-go/informers"
 	v1 "k8s.io/client-go/informers/core/v1"
 	discoveryv1 "k8s.io/client-go/informers/discovery/v1"
 	networkingV1 "k8s.io/client-go/informers/networking/v1"
 	"k8s.io/client-go/tools/cache"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gatewayExternal "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions"
 	gv1b1i "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1"
 	gv1a2i "sigs.k8s.io/gateway-api/pkg/client/informers/externalversions/apis/v1alpha2"
@@ -35,12 +38,16 @@ type K8sInformers struct {
 }
 
 type GatewayInformers struct {
-	Gateway      gv1b1i.GatewayInformer
-	GatewayClass gv1b1i.GatewayClassInformer
-	HttpRoute    gv1b1i.HTTPRouteInformer
-	TcpRoute     gv1a2i.TCPRouteInformer
-	UdpRoute     gv1a2i.UDPRouteInformer
-	TlsRoute     gv1a2i.TLSRouteInformer
+	Gateway          gv1b1i.GatewayInformer
+	GatewayClass     gv1b1i.GatewayClassInformer
+	HttpRoute        gv1b1i.HTTPRouteInformer
+	TcpRoute         gv1a2i.TCPRouteInformer
+	UdpRoute         gv1a2i.UDPRouteInformer
+	TlsRoute         gv1a2i.TLSRouteInformer
+	GrpcRoute        gv1a2i.GRPCRouteInformer
+	BackendTLSPolicy gv1a2i.BackendTLSPolicyInformer
+	// the raw httproute which only keeps the sessionPersistence of rules, see gateway.SessionPersistenceOfRules.
+	HttpRouteSessionPersistence kubeinformers.GenericInformer
 }
 
 type AlbInformers struct {
@@ -48,6 +55,7 @@ type AlbInformers struct {
 	Ft            albv1.FrontendInformer
 	Rule          albv1.RuleInformer
 	TimeoutPolicy albGateway.TimeoutPolicyInformer
+	RetryPolicy   albGateway.RetryPolicyInformer
 }
 
 type InitInformersOptions struct {
@@ -126,13 +134,29 @@ func InitInformers(driver *KubernetesDriver, ctx context.Context, options InitIn
 	tlsRouteInformer := gatewayInformerFactory.Gateway().V1alpha2().TLSRoutes()
 	tlsRouteSynced := tlsRouteInformer.Informer().HasSynced
 
+	grpcRouteInformer := gatewayInformerFactory.Gateway().V1alpha2().GRPCRoutes()
+	grpcRouteSynced := grpcRouteInformer.Informer().HasSynced
+
+	backendTLSPolicyInformer := gatewayInformerFactory.Gateway().V1alpha2().BackendTLSPolicies()
+	backendTLSPolicySynced := backendTLSPolicyInformer.Informer().HasSynced
+
 	gatewayInformerFactory.Start(ctx.Done())
 
+	dynamicInformerFactory := dynamicinformer.NewDynamicSharedInformerFactory(driver.DynamicClient, 0)
+	httpRouteSessionPersistenceInformer := dynamicInformerFactory.ForResource(gv1.SchemeGroupVersion.WithResource("httproutes"))
+	if err := httpRouteSessionPersistenceInformer.Informer().SetTransform(gateway.TrimHttpRouteToSessionPersistence); err != nil {
+		return nil, err
+	}
+	httpRouteSessionPersistenceSynced := httpRouteSessionPersistenceInformer.Informer().HasSynced
+	dynamicInformerFactory.Start(ctx.Done())
+
 	// gateway policyattachment could used in any ns.
 	albGatewayInformerFactory := albinformers.NewSharedInformerFactoryWithOptions(driver.ALBClient, 0)
 
 	timeoutPolicyInformer := albGatewayInformerFactory.Gateway().V1alpha1().TimeoutPolicies()
 	timeoutPolicySynced := timeoutPolicyInformer.Informer().HasSynced
+	retryPolicyInformer := albGatewayInformerFactory.Gateway().V1alpha1().RetryPolicies()
+	retryPolicySynced := retryPolicyInformer.Informer().HasSynced
 
 	albGatewayInformerFactory.Start(ctx.Done())
 
@@ -149,10 +173,14 @@ func InitInformers(driver *KubernetesDriver, ctx context.Context, options InitIn
 		gatewayClassSynced,
 		gatewaySynced,
 		httpRouteSynced,
+		httpRouteSessionPersistenceSynced,
 		tcpRouteSynced,
 		udpRouteSynced,
 		tlsRouteSynced,
+		grpcRouteSynced,
+		backendTLSPolicySynced,
 		timeoutPolicySynced,
+		retryPolicySynced,
 	); !ok {
 		if options.ErrorIfWaitSyncFail {
 			return nil, errors.New("wait alb2 informers sync fail")
@@ -173,14 +201,18 @@ func InitInformers(driver *KubernetesDriver, ctx context.Context, options InitIn
 			Ft:            frontendInformer,
 			Rule:          ruleInformer,
 			TimeoutPolicy: timeoutPolicyInformer,
+			RetryPolicy:   retryPolicyInformer,
 		},
 		Gateway: GatewayInformers{
-			GatewayClass: gatewayClassInformer,
-			Gateway:      gatewayInformer,
-			HttpRoute:    httpRouteInformer,
-			TcpRoute:     tcpRouteInformer,
-			UdpRoute:     udpRouteInformer,
-			TlsRoute:     tlsRouteInformer,
+			GatewayClass:                gatewayClassInformer,
+			Gateway:                     gatewayInformer,
+			HttpRoute:                   httpRouteInformer,
+			TcpRoute:                    tcpRouteInformer,
+			UdpRoute:                    udpRouteInformer,
+			TlsRoute:                    tlsRouteInformer,
+			GrpcRoute:                   grpcRouteInformer,
+			BackendTLSPolicy:            backendTLSPolicyInformer,
+			HttpRouteSessionPersistence: httpRouteSessionPersistenceInformer,
 		},
 	}, nil
 }
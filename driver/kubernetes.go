@@ -4,14 +4,17 @@ import (
 	"errors"
 	"fmt"
 	"net"
+	"sort"
 	"strings"
 
 	"k8s.io/apimachinery/pkg/util/intstr"
 
 	"alauda.io/alb2/controller/modules"
 	v1types "k8s.io/api/core/v1"
+	discoveryv1 "k8s.io/api/discovery/v1"
 	k8serrors "k8s.io/apimachinery/pkg/api/errors"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/labels"
 	"k8s.io/apimachinery/pkg/runtime/schema"
 )
 
@@ -330,6 +333,34 @@ func (kd *KubernetesDriver) mergeSubmarinerCrossClusterBackends(namespace, name,
 	return backends
 }
 
+// ServiceVersion is the versions of the objects which the backends of service are read from, the service, the endpoints and the endpointslices.
+// the backends are unchanged as long as the version is unchanged.
+func (kd *KubernetesDriver) ServiceVersion(namespace, name string) string {
+	vers := []string{}
+	if svc, err := kd.ServiceLister.Services(namespace).Get(name); err == nil {
+		vers = append(vers, "svc/"+svc.ResourceVersion)
+	}
+	if ep, err := kd.EndpointLister.Endpoints(namespace).Get(name); err == nil {
+		vers = append(vers, "ep/"+ep.ResourceVersion)
+	}
+	selector := labels.SelectorFromSet(labels.Set{discoveryv1.LabelServiceName: name})
+	if kd.Opt.EnableCrossClusters {
+		// the cross-cluster endpointslices are matched by name prefix, see mergeSubmarinerCrossClusterBackends.
+		selector = labels.Everything()
+	}
+	slices, err := kd.Informers.K8s.EndpointSlice.Lister().EndpointSlices(namespace).List(selector)
+	if err == nil {
+		for _, s := range slices {
+			if kd.Opt.EnableCrossClusters && !strings.HasPrefix(s.Name, name) {
+				continue
+			}
+			vers = append(vers, "eps/"+s.Name+"/"+s.ResourceVersion)
+		}
+	}
+	sort.Strings(vers)
+	return strings.Join(vers, ",")
+}
+
 // IsPodReady returns true if a pod is ready; false otherwise.
 func IsPodReady(pod *v1types.Pod) bool {
 	return isPodReadyConditionTrue(pod.Status)
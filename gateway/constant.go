@@ -21,13 +21,16 @@ const (
 	HttpRouteKind    = "HTTPRoute"
 	TcpRouteKind     = "TCPRoute"
 	UdpRouteKind     = "UDPRoute"
+	GrpcRouteKind    = "GRPCRoute"
+	TlsRouteKind     = "TLSRoute"
 )
 
 var SUPPORT_KIND_MAP map[string][]string = map[string][]string{
 	"TCP":   {TcpRouteKind},
 	"UDP":   {UdpRouteKind},
-	"HTTP":  {HttpRouteKind},
-	"HTTPS": {HttpRouteKind},
+	"TLS":   {TlsRouteKind},
+	"HTTP":  {HttpRouteKind, GrpcRouteKind},
+	"HTTPS": {HttpRouteKind, GrpcRouteKind},
 }
 
-var SUPPORT_KIND_SET sets.Set[string] = sets.NewSet(TcpRouteKind, UdpRouteKind, HttpRouteKind)
+var SUPPORT_KIND_SET sets.Set[string] = sets.NewSet(TcpRouteKind, UdpRouteKind, HttpRouteKind, GrpcRouteKind, TlsRouteKind)
@@ -28,6 +28,19 @@ func (c *CommonFilter) Name() string {
 func (c *CommonFilter) FilteListener(gateway client.ObjectKey, ls []*Listener, allls []*Listener) {
 	c.filteListenerConflictProtocol(gateway, ls, allls)
 	c.filteListenerInvalidKind(gateway, ls, allls)
+	c.filteListenerTlsMode(gateway, ls, allls)
+}
+
+// only passthrough mode tls listener is supported, the tls stream is proxied to backend according to the sni.
+func (c *CommonFilter) filteListenerTlsMode(gateway client.ObjectKey, ls []*Listener, allls []*Listener) {
+	for _, l := range ls {
+		if l.Protocol != gv1.TLSProtocolType {
+			continue
+		}
+		if mode := TlsMode(l.TLS); mode != gv1.TLSModePassthrough {
+			l.status.unsupportedProtocol(fmt.Sprintf("tls mode %s is not supported, only %s is supported", mode, gv1.TLSModePassthrough))
+		}
+	}
 }
 
 func (c *CommonFilter) filteListenerInvalidKind(gateway client.ObjectKey, ls []*Listener, allls []*Listener) {
@@ -0,0 +1,30 @@
+package ctl
+
+import (
+	"testing"
+
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestFilteListenerTlsMode(t *testing.T) {
+	c := CommonFilter{log: logr.Discard()}
+	mode := func(m gv1.TLSModeType) *gv1.GatewayTLSConfig { return &gv1.GatewayTLSConfig{Mode: &m} }
+	passthrough := &Listener{Listener: gv1.Listener{Name: "p", Protocol: gv1.TLSProtocolType, TLS: mode(gv1.TLSModePassthrough)}, status: ListenerStatus{valid: true}}
+	terminate := &Listener{Listener: gv1.Listener{Name: "t", Protocol: gv1.TLSProtocolType, TLS: mode(gv1.TLSModeTerminate)}, status: ListenerStatus{valid: true}}
+	https := &Listener{Listener: gv1.Listener{Name: "h", Protocol: gv1.HTTPSProtocolType, TLS: mode(gv1.TLSModeTerminate)}, status: ListenerStatus{valid: true}}
+	// the mode defaults to Terminate.
+	noMode := &Listener{Listener: gv1.Listener{Name: "n", Protocol: gv1.TLSProtocolType, TLS: &gv1.GatewayTLSConfig{}}, status: ListenerStatus{valid: true}}
+	ls := []*Listener{passthrough, terminate, https, noMode}
+	c.filteListenerTlsMode(client.ObjectKey{Namespace: "ns", Name: "g1"}, ls, ls)
+
+	assert.True(t, passthrough.status.valid)
+	assert.True(t, https.status.valid)
+	assert.False(t, terminate.status.valid)
+	assert.False(t, noMode.status.valid)
+	conditions := terminate.status.toConditions(&gv1.Gateway{})
+	assert.Equal(t, string(gv1.ListenerConditionAccepted), conditions[1].Type)
+	assert.Equal(t, string(gv1.ListenerReasonUnsupportedProtocol), conditions[1].Reason)
+}
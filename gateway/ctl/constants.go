@@ -7,3 +7,9 @@ const (
 	CommonRouteReasonUnAllowRoute       CommonRouteConditionReason = "UnAllowRoute"
 	CommonRouteReasonInvalidKind        CommonRouteConditionReason = "InvalidKind"
 )
+
+const (
+	// condition of httproute which shows the effective timeouts after merged with timeoutpolicy.
+	RouteConditionTimeouts      = "Timeouts"
+	RouteReasonTimeoutsResolved = "Resolved"
+)
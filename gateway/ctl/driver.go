@@ -20,7 +20,7 @@ func ListListener(ctx context.Context, c client.Client, sel config.GatewaySelect
 	ret := []*Listener{}
 	for _, g := range gs {
 		for _, ls := range g.Spec.Listeners {
-			ret = append(ret, &Listener{Listener: ls, gateway: client.ObjectKeyFromObject(&g)})
+			ret = append(ret, &Listener{Listener: ls, gateway: client.ObjectKeyFromObject(&g), gatewayClass: string(g.Spec.GatewayClassName)})
 		}
 	}
 	return ret, nil
@@ -58,6 +58,7 @@ func ListRoutesByGateway(ctx context.Context, c client.Client, gateway client.Ob
 	tcpRouteList := &gv1a2t.TCPRouteList{}
 	tlsRouteList := &gv1a2t.TLSRouteList{}
 	udpRouteList := &gv1a2t.UDPRouteList{}
+	grpcRouteList := &gv1a2t.GRPCRouteList{}
 	err := c.List(ctx, httpRouteList, &client.ListOptions{})
 	if err != nil {
 		return nil, err
@@ -74,11 +75,16 @@ func ListRoutesByGateway(ctx context.Context, c client.Client, gateway client.Ob
 	if err != nil {
 		return nil, err
 	}
+	err = c.List(ctx, grpcRouteList, &client.ListOptions{})
+	if err != nil {
+		return nil, err
+	}
 	routes := []*Route{}
 	httpCommonRoutes := []*Route{}
 	tcpCommonRoutes := []*Route{}
 	tlsCommonRoutes := []*Route{}
 	udpCommonRoutes := []*Route{}
+	grpcCommonRoutes := []*Route{}
 
 	for _, route := range httpRouteList.Items {
 		ref := IsRefsToGateway(route.Spec.ParentRefs, gateway)
@@ -120,10 +126,21 @@ func ListRoutesByGateway(ctx context.Context, c client.Client, gateway client.Ob
 	}
 	log.Info("list udp route", "total-len", len(udpRouteList.Items), "len", len(udpCommonRoutes))
 
+	for _, route := range grpcRouteList.Items {
+		ref := IsRefsToGateway(route.Spec.ParentRefs, gateway)
+		log.V(4).Info("grpc route ref to gateway?", "result", ref, "route", client.ObjectKeyFromObject(&route))
+		if ref {
+			r := GRPCRoute(route)
+			grpcCommonRoutes = append(grpcCommonRoutes, &Route{route: &r})
+		}
+	}
+	log.Info("list grpc route", "total-len", len(grpcRouteList.Items), "len", len(grpcCommonRoutes))
+
 	routes = append(routes, httpCommonRoutes...)
 	routes = append(routes, tlsCommonRoutes...)
 	routes = append(routes, tcpCommonRoutes...)
 	routes = append(routes, udpCommonRoutes...)
+	routes = append(routes, grpcCommonRoutes...)
 	for _, r := range routes {
 		r.status = make(map[string]RouteStatus)
 	}
@@ -143,6 +160,8 @@ func findGatewayByRouteObject(ctx context.Context, c client.Client, object clien
 		refs = object.Spec.ParentRefs
 	case *gv1a2t.UDPRoute:
 		refs = object.Spec.ParentRefs
+	case *gv1a2t.GRPCRoute:
+		refs = object.Spec.ParentRefs
 	default:
 		return false, nil, fmt.Errorf("invalid route type %v", client.ObjectKeyFromObject(object))
 	}
@@ -35,6 +35,7 @@ type GatewayReconciler struct {
 	log                   logr.Logger
 	invalidListenerfilter []ListenerFilter
 	invalidRoutefilter    []RouteFilter
+	portShareFilter       *PortShareFilter
 	supportKind           map[string][]string
 	albcfg                *config.Config
 	cfg                   config.GatewayCfg
@@ -55,16 +56,30 @@ type RouteFilter interface {
 func NewGatewayReconciler(ctx context.Context, c client.Client, log logr.Logger, cfg *config.Config) GatewayReconciler {
 	commonFilter := CommonFilter{log: log, c: c, ctx: ctx}
 	hostNameFilter := HostNameFilter{log: log}
+	referenceGrantFilter := ReferenceGrantFilter{log: log, c: c, ctx: ctx}
+	timeoutFilter := TimeoutFilter{log: log, c: c, ctx: ctx}
+	tcpRouteFilter := TcpRouteFilter{log: log}
+	grpcRouteFilter := GrpcRouteFilter{log: log}
+	httpRouteFilter := HttpRouteFilter{log: log}
 	gc := cfg.GetGatewayCfg()
+	portShareFilter := PortShareFilter{log: log}
 	reservedPortFilter := NewReservedPortFilter(log, []int{gc.ReservedPort, 1936, 11782})
 
 	listenerFilter := []ListenerFilter{
 		&commonFilter,
+		&referenceGrantFilter,
 	}
 	routeFilter := []RouteFilter{
 		&commonFilter,
 		&hostNameFilter,
 		&reservedPortFilter,
+		&referenceGrantFilter,
+		&tcpRouteFilter,
+		&grpcRouteFilter,
+		&httpRouteFilter,
+		&portShareFilter,
+		// must be the last one, it only records the timeouts of accepted route.
+		&timeoutFilter,
 	}
 
 	return GatewayReconciler{
@@ -74,6 +89,7 @@ func NewGatewayReconciler(ctx context.Context, c client.Client, log logr.Logger,
 		controllerName:        GetControllerName(cfg),
 		invalidListenerfilter: listenerFilter,
 		invalidRoutefilter:    routeFilter,
+		portShareFilter:       &portShareFilter,
 		supportKind:           SUPPORT_KIND_MAP,
 		cfg:                   gc,
 		albcfg:                cfg,
@@ -86,6 +102,9 @@ func (g *GatewayReconciler) SetupWithManager(mgr ctrl.Manager) error {
 
 	b = g.watchRoutes(b)
 	b = g.watchAlb(b)
+	b = g.watchReferenceGrant(b)
+	b = g.watchTimeoutPolicy(b)
+	b = g.watchRetryPolicy(b)
 	// default rate limit should be enough for use.
 	b = b.WithOptions(controller.Options{RateLimiter: workqueue.DefaultControllerRateLimiter()})
 
@@ -136,7 +155,7 @@ func (g *GatewayReconciler) Reconcile(ctx context.Context, request reconcile.Req
 
 	listenerInGateway := []*Listener{}
 	for _, l := range gateway.Spec.Listeners {
-		listenerInGateway = append(listenerInGateway, &Listener{Listener: l, gateway: key, status: ListenerStatus{valid: true}})
+		listenerInGateway = append(listenerInGateway, &Listener{Listener: l, gateway: key, gatewayClass: string(gateway.Spec.GatewayClassName), status: ListenerStatus{valid: true}})
 	}
 
 	log.Info("list listener", "all-len", len(allListener), "ls", len(listenerInGateway))
@@ -150,6 +169,12 @@ func (g *GatewayReconciler) Reconcile(ctx context.Context, request reconcile.Req
 	}
 	log.Info("list route by gateway", "key", key, "routes-len", len(routes))
 
+	portKinds, err := ListPortRouteKinds(ctx, g.c, allListener)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("list route kinds of port fail %v", err)
+	}
+	g.portShareFilter.SetPortRouteKinds(portKinds)
+
 	g.filteRoutes(key, routes, listenerInGateway)
 
 	alb, getAlbErr := g.GetGatewayAlb(gateway)
@@ -165,6 +190,16 @@ func (g *GatewayReconciler) Reconcile(ctx context.Context, request reconcile.Req
 	if err != nil {
 		return reconcile.Result{}, fmt.Errorf("update route status fail %v", err)
 	}
+
+	err = g.updateTimeoutPolicyStatus(gateway, routes)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("update timeout policy status fail %v", err)
+	}
+
+	err = g.updateRetryPolicyStatus(gateway, routes)
+	if err != nil {
+		return reconcile.Result{}, fmt.Errorf("update retry policy status fail %v", err)
+	}
 	// retry to sync gateway ip
 	if getAlbErr != nil {
 		return reconcile.Result{RequeueAfter: time.Second * 3}, nil
@@ -395,19 +430,40 @@ func (g *GatewayReconciler) updateRouteStatus(rs []*Route) error {
 			if p.reason != "" {
 				reason = p.reason
 			}
+			conditions := []metav1.Condition{
+				{
+					Type:               string(gv1.ListenerConditionReady),
+					Status:             status,
+					Reason:             reason,
+					LastTransitionTime: metav1.Now(),
+					ObservedGeneration: r.route.GetObject().GetGeneration(),
+					Message:            p.msg,
+				},
+			}
+			if p.accept && p.timeouts != "" {
+				conditions = append(conditions, metav1.Condition{
+					Type:               RouteConditionTimeouts,
+					Status:             metav1.ConditionTrue,
+					Reason:             RouteReasonTimeoutsResolved,
+					LastTransitionTime: metav1.Now(),
+					ObservedGeneration: r.route.GetObject().GetGeneration(),
+					Message:            p.timeouts,
+				})
+			}
+			if p.unresolvedRefs {
+				conditions = append(conditions, metav1.Condition{
+					Type:               string(gv1.RouteConditionResolvedRefs),
+					Status:             metav1.ConditionFalse,
+					Reason:             reason,
+					LastTransitionTime: metav1.Now(),
+					ObservedGeneration: r.route.GetObject().GetGeneration(),
+					Message:            p.msg,
+				})
+			}
 			psMap[key] = gv1.RouteParentStatus{
 				ParentRef:      p.ref,
 				ControllerName: gv1.GatewayController(g.controllerName),
-				Conditions: []metav1.Condition{
-					{
-						Type:               string(gv1.ListenerConditionReady),
-						Status:             status,
-						Reason:             reason,
-						LastTransitionTime: metav1.Now(),
-						ObservedGeneration: r.route.GetObject().GetGeneration(),
-						Message:            p.msg,
-					},
-				},
+				Conditions:     conditions,
 			}
 		}
 
@@ -0,0 +1,35 @@
+package ctl
+
+import (
+	"fmt"
+
+	. "alauda.io/alb2/gateway"
+	"github.com/go-logr/logr"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// GrpcRouteFilter reject grpcroute which uses the feature we could not translate.
+// filters of backendRef could not be applied per backend, reject the route instead of ignoring them silently.
+type GrpcRouteFilter struct {
+	log logr.Logger
+}
+
+func (g *GrpcRouteFilter) Name() string {
+	return "GrpcRouteFilter"
+}
+
+func (g *GrpcRouteFilter) FilteRoute(ref gv1.ParentReference, r *Route, ls *Listener) bool {
+	route, ok := r.route.(*GRPCRoute)
+	if !ok {
+		return true
+	}
+	for i, rule := range route.Spec.Rules {
+		for _, b := range rule.BackendRefs {
+			if len(b.Filters) != 0 {
+				r.unAllowRouteWithReason(ref, fmt.Sprintf("rule %d backend %s: filters of backendRef are not supported, use filters of rule instead", i, b.Name), string(gv1.RouteReasonUnsupportedValue))
+				return false
+			}
+		}
+	}
+	return true
+}
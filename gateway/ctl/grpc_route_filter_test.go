@@ -0,0 +1,30 @@
+package ctl
+
+import (
+	"testing"
+
+	. "alauda.io/alb2/gateway"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gv1a2t "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestGrpcRouteFilter(t *testing.T) {
+	f := GrpcRouteFilter{log: logr.Discard()}
+	ns := gv1.Namespace("ns")
+	section := gv1.SectionName("http")
+	ref := gv1.ParentReference{Namespace: &ns, Name: "g1", SectionName: &section}
+	backend := gv1a2t.GRPCBackendRef{BackendRef: gv1.BackendRef{BackendObjectReference: gv1.BackendObjectReference{Name: "svc"}}}
+	route := GRPCRoute{Spec: gv1a2t.GRPCRouteSpec{Rules: []gv1a2t.GRPCRouteRule{{BackendRefs: []gv1a2t.GRPCBackendRef{backend}}}}}
+	r := &Route{route: &route, status: map[string]RouteStatus{}}
+	r.accept(ref)
+	assert.True(t, f.FilteRoute(ref, r, &Listener{}))
+	assert.True(t, r.status[RefsToString(ref)].accept)
+
+	route.Spec.Rules[0].BackendRefs[0].Filters = []gv1a2t.GRPCRouteFilter{{Type: gv1a2t.GRPCRouteFilterRequestHeaderModifier}}
+	assert.False(t, f.FilteRoute(ref, r, &Listener{}))
+	status := r.status[RefsToString(ref)]
+	assert.False(t, status.accept)
+	assert.Equal(t, string(gv1.RouteReasonUnsupportedValue), status.reason)
+}
@@ -23,13 +23,18 @@ func (c *HostNameFilter) FilteRoute(ref gv1.ParentReference, r *Route, ls *Liste
 		return true
 	}
 
-	h, ok := r.route.(*HTTPRoute)
-	// only focus on http route.
-	if !ok {
+	var hostnames []gv1.Hostname
+	// only focus on route which has hostnames.
+	switch h := r.route.(type) {
+	case *HTTPRoute:
+		hostnames = h.Spec.Hostnames
+	case *TLSRoute:
+		hostnames = h.Spec.Hostnames
+	default:
 		return true
 	}
 
-	routeHost := lo.Map(h.Spec.Hostnames, func(s gv1.Hostname, _ int) string { return string(s) })
+	routeHost := lo.Map(hostnames, func(s gv1.Hostname, _ int) string { return string(s) })
 
 	domains := FindIntersection(string(*lsHost), routeHost)
 	if len(domains) == 0 {
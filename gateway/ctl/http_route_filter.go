@@ -0,0 +1,33 @@
+package ctl
+
+import (
+	"fmt"
+
+	. "alauda.io/alb2/gateway"
+	"github.com/go-logr/logr"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// HttpRouteFilter reject httproute which backend filters could not be applied.
+// backends of a rule share the same policy, so backends with different filters could not be translated.
+type HttpRouteFilter struct {
+	log logr.Logger
+}
+
+func (h *HttpRouteFilter) Name() string {
+	return "HttpRouteFilter"
+}
+
+func (h *HttpRouteFilter) FilteRoute(ref gv1.ParentReference, r *Route, ls *Listener) bool {
+	route, ok := r.route.(*HTTPRoute)
+	if !ok {
+		return true
+	}
+	for i, rule := range route.Spec.Rules {
+		if _, err := PickHttpBackendFilters(rule.BackendRefs); err != nil {
+			r.unAllowRouteWithReason(ref, fmt.Sprintf("rule %d: %v", i, err), string(gv1.RouteReasonUnsupportedValue))
+			return false
+		}
+	}
+	return true
+}
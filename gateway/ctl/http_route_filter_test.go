@@ -0,0 +1,33 @@
+package ctl
+
+import (
+	"testing"
+
+	. "alauda.io/alb2/gateway"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestHttpRouteFilter(t *testing.T) {
+	f := HttpRouteFilter{log: logr.Discard()}
+	ns := gv1.Namespace("ns")
+	section := gv1.SectionName("http")
+	ref := gv1.ParentReference{Namespace: &ns, Name: "g1", SectionName: &section}
+	header := gv1.HTTPRouteFilter{Type: gv1.HTTPRouteFilterRequestHeaderModifier, RequestHeaderModifier: &gv1.HTTPHeaderFilter{Set: []gv1.HTTPHeader{{Name: "a", Value: "b"}}}}
+	backend := func(name string, filters ...gv1.HTTPRouteFilter) gv1.HTTPBackendRef {
+		return gv1.HTTPBackendRef{BackendRef: gv1.BackendRef{BackendObjectReference: gv1.BackendObjectReference{Name: gv1.ObjectName(name)}}, Filters: filters}
+	}
+	route := HTTPRoute{Spec: gv1.HTTPRouteSpec{Rules: []gv1.HTTPRouteRule{{BackendRefs: []gv1.HTTPBackendRef{backend("a", header), backend("b", header)}}}}}
+	r := &Route{route: &route, status: map[string]RouteStatus{}}
+	r.accept(ref)
+	// all backends have the same filters.
+	assert.True(t, f.FilteRoute(ref, r, &Listener{}))
+	assert.True(t, r.status[RefsToString(ref)].accept)
+
+	route.Spec.Rules[0].BackendRefs[1].Filters = nil
+	assert.False(t, f.FilteRoute(ref, r, &Listener{}))
+	status := r.status[RefsToString(ref)]
+	assert.False(t, status.accept)
+	assert.Equal(t, string(gv1.RouteReasonUnsupportedValue), status.reason)
+}
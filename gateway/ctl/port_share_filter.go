@@ -0,0 +1,99 @@
+package ctl
+
+import (
+	"context"
+	"fmt"
+
+	. "alauda.io/alb2/gateway"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gv1a2t "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+const RouteReasonPortUsedByOtherKind = "PortUsedByOtherKind"
+
+// route kind => the kind which owns the port when both of them attach to the same port.
+// nginx could not proxy_pass and grpc_pass in the same location, so grpcroute is ignored on a port which has httproute.
+// tcp route proxy the whole stream without ssl_preread, so tlsroute is ignored on a port which has tcproute.
+var portOwnerKind = map[string]string{
+	GrpcRouteKind: HttpRouteKind,
+	TlsRouteKind:  TcpRouteKind,
+}
+
+// port => kinds of the routes which attach to a listener on this port.
+type PortRouteKinds map[gv1.PortNumber]map[string]bool
+
+// PortShareFilter reject the route which attach to a port owned by routes of another kind,
+// those routes are ignored when translate to nginx config, so it should be shown in route status.
+type PortShareFilter struct {
+	log logr.Logger
+	// computed once in each reconcile by ListPortRouteKinds, the gateway reconciler does not run concurrently.
+	portKinds PortRouteKinds
+}
+
+func (p *PortShareFilter) Name() string {
+	return "PortShareFilter"
+}
+
+func (p *PortShareFilter) SetPortRouteKinds(kinds PortRouteKinds) {
+	p.portKinds = kinds
+}
+
+func (p *PortShareFilter) FilteRoute(ref gv1.ParentReference, r *Route, ls *Listener) bool {
+	kind := GetRouteKind(r.route)
+	owner, ok := portOwnerKind[kind]
+	if !ok {
+		return true
+	}
+	if !p.portKinds[ls.Port][owner] {
+		return true
+	}
+	r.unAllowRouteWithReason(ref, fmt.Sprintf("port %d is already used by %s, %s is ignored", ls.Port, owner, kind), RouteReasonPortUsedByOtherKind)
+	return false
+}
+
+// ListPortRouteKinds list the routes of the kinds which could own a port, and group them by the port of listeners they attach to.
+func ListPortRouteKinds(ctx context.Context, c client.Client, listeners []*Listener) (PortRouteKinds, error) {
+	refs := map[string][]gv1.ParentReference{}
+	httpList := &gv1.HTTPRouteList{}
+	if err := c.List(ctx, httpList); err != nil {
+		return nil, err
+	}
+	for _, r := range httpList.Items {
+		refs[HttpRouteKind] = append(refs[HttpRouteKind], r.Spec.ParentRefs...)
+	}
+	tcpList := &gv1a2t.TCPRouteList{}
+	if err := c.List(ctx, tcpList); err != nil {
+		return nil, err
+	}
+	for _, r := range tcpList.Items {
+		refs[TcpRouteKind] = append(refs[TcpRouteKind], r.Spec.ParentRefs...)
+	}
+	return portRouteKinds(listeners, refs), nil
+}
+
+// refs is kind => parentRefs of all routes of the kind.
+func portRouteKinds(listeners []*Listener, refs map[string][]gv1.ParentReference) PortRouteKinds {
+	lsPort := map[string]gv1.PortNumber{}
+	for _, l := range listeners {
+		lsPort[fmt.Sprintf("%s/%s/%s", l.gateway.Namespace, l.gateway.Name, l.Name)] = l.Port
+	}
+	ret := PortRouteKinds{}
+	for kind, rs := range refs {
+		for _, ref := range rs {
+			if ref.Namespace == nil || ref.SectionName == nil {
+				continue
+			}
+			port, ok := lsPort[fmt.Sprintf("%s/%s/%s", *ref.Namespace, ref.Name, *ref.SectionName)]
+			if !ok {
+				continue
+			}
+			if ret[port] == nil {
+				ret[port] = map[string]bool{}
+			}
+			ret[port][kind] = true
+		}
+	}
+	return ret
+}
@@ -0,0 +1,43 @@
+package ctl
+
+import (
+	"testing"
+
+	. "alauda.io/alb2/gateway"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestPortRouteKinds(t *testing.T) {
+	gw := client.ObjectKey{Namespace: "ns", Name: "g1"}
+	listeners := []*Listener{
+		{Listener: gv1.Listener{Name: "http", Port: 80}, gateway: gw},
+		{Listener: gv1.Listener{Name: "https", Port: 443}, gateway: gw},
+	}
+	ref := func(section string) gv1.ParentReference {
+		ns := gv1.Namespace("ns")
+		name := gv1.SectionName(section)
+		return gv1.ParentReference{Namespace: &ns, Name: "g1", SectionName: &name}
+	}
+	kinds := portRouteKinds(listeners, map[string][]gv1.ParentReference{
+		HttpRouteKind: {ref("http"), ref("http")},
+		// ref to a listener which does not exist.
+		TcpRouteKind: {ref("tcp"), {Name: "g1"}},
+	})
+	assert.Equal(t, PortRouteKinds{80: {HttpRouteKind: true}}, kinds)
+
+	f := PortShareFilter{log: logr.Discard()}
+	f.SetPortRouteKinds(kinds)
+	grpc := &Route{route: &GRPCRoute{}, status: map[string]RouteStatus{}}
+	grpc.accept(ref("http"))
+	assert.True(t, f.FilteRoute(ref("https"), grpc, listeners[1]))
+	assert.False(t, f.FilteRoute(ref("http"), grpc, listeners[0]))
+	status := grpc.status[RefsToString(ref("http"))]
+	assert.False(t, status.accept)
+	assert.Equal(t, RouteReasonPortUsedByOtherKind, status.reason)
+	// httproute is the owner.
+	http := &Route{route: &HTTPRoute{}, status: map[string]RouteStatus{}}
+	assert.True(t, f.FilteRoute(ref("http"), http, listeners[0]))
+}
@@ -0,0 +1,148 @@
+package ctl
+
+import (
+	"context"
+	"fmt"
+
+	. "alauda.io/alb2/gateway"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gv1b1t "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// ReferenceGrantFilter reject the cross namespace backendRefs and certificateRefs which are not allowed by ReferenceGrant.
+// https://gateway-api.sigs.k8s.io/api-types/referencegrant/
+type ReferenceGrantFilter struct {
+	log logr.Logger
+	c   client.Client
+	ctx context.Context
+}
+
+// ref from one namespace to another
+type crossNsRef struct {
+	fromGroup string
+	fromKind  string
+	fromNs    string
+	toGroup   string
+	toKind    string
+	toNs      string
+	toName    string
+}
+
+func (r crossNsRef) String() string {
+	return fmt.Sprintf("%s in %s to %s %s/%s", r.fromKind, r.fromNs, r.toKind, r.toNs, r.toName)
+}
+
+func (c *ReferenceGrantFilter) Name() string {
+	return "ReferenceGrantFilter"
+}
+
+func (c *ReferenceGrantFilter) FilteListener(gateway client.ObjectKey, ls []*Listener, allls []*Listener) {
+	log := c.log.WithValues("gateway", gateway)
+	for _, l := range ls {
+		if l.TLS == nil {
+			continue
+		}
+		for _, cert := range l.TLS.CertificateRefs {
+			if cert.Namespace == nil || string(*cert.Namespace) == gateway.Namespace {
+				continue
+			}
+			ref := crossNsRef{
+				fromGroup: GATEWAY_GROUP,
+				fromKind:  GatewayKind,
+				fromNs:    gateway.Namespace,
+				toGroup:   "",
+				toKind:    "Secret",
+				toNs:      string(*cert.Namespace),
+				toName:    string(cert.Name),
+			}
+			if cert.Group != nil {
+				ref.toGroup = string(*cert.Group)
+			}
+			if cert.Kind != nil {
+				ref.toKind = string(*cert.Kind)
+			}
+			allow, err := c.isAllowed(ref)
+			if err != nil {
+				log.Error(err, "check reference grant fail", "listener", l.Name)
+			}
+			if !allow {
+				log.Info("certificate ref not permitted", "listener", l.Name, "ref", ref.String())
+				l.status.refNotPermitted(fmt.Sprintf("certificate ref %s/%s not permitted by any ReferenceGrant", ref.toNs, ref.toName))
+				break
+			}
+		}
+	}
+}
+
+func (c *ReferenceGrantFilter) FilteRoute(ref gv1.ParentReference, r *Route, ls *Listener) bool {
+	obj := r.route.GetObject()
+	kind := GetRouteKind(r.route)
+	for _, b := range routeBackendRefs(r.route) {
+		if b.Namespace == nil || string(*b.Namespace) == obj.GetNamespace() {
+			continue
+		}
+		cref := crossNsRef{
+			fromGroup: GATEWAY_GROUP,
+			fromKind:  kind,
+			fromNs:    obj.GetNamespace(),
+			toGroup:   "",
+			toKind:    "Service",
+			toNs:      string(*b.Namespace),
+			toName:    string(b.Name),
+		}
+		if b.Group != nil {
+			cref.toGroup = string(*b.Group)
+		}
+		if b.Kind != nil {
+			cref.toKind = string(*b.Kind)
+		}
+		allow, err := c.isAllowed(cref)
+		if err != nil {
+			c.log.Error(err, "check reference grant fail", "route", GetObjectKey(r.route))
+		}
+		if !allow {
+			r.refNotPermitted(ref, fmt.Sprintf("backend ref %s/%s not permitted by any ReferenceGrant", cref.toNs, cref.toName))
+			return false
+		}
+	}
+	return true
+}
+
+func (c *ReferenceGrantFilter) isAllowed(ref crossNsRef) (bool, error) {
+	grants := &gv1b1t.ReferenceGrantList{}
+	err := c.c.List(c.ctx, grants, client.InNamespace(ref.toNs))
+	if err != nil {
+		return false, err
+	}
+	return isReferenceAllowed(grants.Items, ref), nil
+}
+
+// the grant must be in the namespace of the referent, and match both from and to.
+func isReferenceAllowed(grants []gv1b1t.ReferenceGrant, ref crossNsRef) bool {
+	for _, g := range grants {
+		if g.Namespace != ref.toNs {
+			continue
+		}
+		fromMatch := false
+		for _, f := range g.Spec.From {
+			if string(f.Group) == ref.fromGroup && string(f.Kind) == ref.fromKind && string(f.Namespace) == ref.fromNs {
+				fromMatch = true
+				break
+			}
+		}
+		if !fromMatch {
+			continue
+		}
+		for _, t := range g.Spec.To {
+			if string(t.Group) != ref.toGroup || string(t.Kind) != ref.toKind {
+				continue
+			}
+			if t.Name == nil || string(*t.Name) == ref.toName {
+				return true
+			}
+		}
+	}
+	return false
+}
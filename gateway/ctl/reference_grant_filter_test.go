@@ -0,0 +1,44 @@
+package ctl
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gv1b1t "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestIsReferenceAllowed(t *testing.T) {
+	svcName := gv1.ObjectName("svc-a")
+	grants := []gv1b1t.ReferenceGrant{
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "backend", Name: "allow-route"},
+			Spec: gv1b1t.ReferenceGrantSpec{
+				From: []gv1b1t.ReferenceGrantFrom{{Group: "gateway.networking.k8s.io", Kind: "HTTPRoute", Namespace: "tenant-a"}},
+				To:   []gv1b1t.ReferenceGrantTo{{Group: "", Kind: "Service", Name: &svcName}},
+			},
+		},
+		{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "certs", Name: "allow-gateway"},
+			Spec: gv1b1t.ReferenceGrantSpec{
+				From: []gv1b1t.ReferenceGrantFrom{{Group: "gateway.networking.k8s.io", Kind: "Gateway", Namespace: "gw"}},
+				To:   []gv1b1t.ReferenceGrantTo{{Group: "", Kind: "Secret"}},
+			},
+		},
+	}
+	route := func(ns, kind, name string) crossNsRef {
+		return crossNsRef{fromGroup: "gateway.networking.k8s.io", fromKind: "HTTPRoute", fromNs: ns, toKind: kind, toNs: "backend", toName: name}
+	}
+	assert.True(t, isReferenceAllowed(grants, route("tenant-a", "Service", "svc-a")))
+	assert.False(t, isReferenceAllowed(grants, route("tenant-a", "Service", "svc-b")))
+	assert.False(t, isReferenceAllowed(grants, route("tenant-b", "Service", "svc-a")))
+	assert.False(t, isReferenceAllowed(grants, route("tenant-a", "Secret", "svc-a")))
+
+	cert := func(fromNs, toNs string) crossNsRef {
+		return crossNsRef{fromGroup: "gateway.networking.k8s.io", fromKind: "Gateway", fromNs: fromNs, toKind: "Secret", toNs: toNs, toName: "any"}
+	}
+	assert.True(t, isReferenceAllowed(grants, cert("gw", "certs")))
+	assert.False(t, isReferenceAllowed(grants, cert("gw", "backend")))
+	assert.False(t, isReferenceAllowed(grants, cert("other", "certs")))
+}
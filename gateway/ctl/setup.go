@@ -6,6 +6,7 @@ import (
 	"alauda.io/alb2/config"
 	"alauda.io/alb2/driver"
 	g "alauda.io/alb2/gateway"
+	gatewayPolicy "alauda.io/alb2/pkg/apis/alauda/gateway/v1alpha1"
 	albType "alauda.io/alb2/pkg/apis/alauda/v1"
 	albv2Type "alauda.io/alb2/pkg/apis/alauda/v2beta1"
 	. "alauda.io/alb2/utils/log"
@@ -14,6 +15,7 @@ import (
 	ctrl "sigs.k8s.io/controller-runtime"
 	gv1 "sigs.k8s.io/gateway-api/apis/v1"
 	gv1a2t "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gv1b1t "sigs.k8s.io/gateway-api/apis/v1beta1"
 
 	metricsserver "sigs.k8s.io/controller-runtime/pkg/metrics/server"
 )
@@ -25,8 +27,10 @@ func init() {
 	_ = k8sScheme.AddToScheme(scheme)
 	_ = gv1a2t.AddToScheme(scheme)
 	_ = gv1.AddToScheme(scheme)
+	_ = gv1b1t.AddToScheme(scheme)
 	_ = albType.AddToScheme(scheme)
 	_ = albv2Type.AddToScheme(scheme)
+	_ = gatewayPolicy.AddToScheme(scheme)
 }
 
 func Run(ctx context.Context, cfg *config.Config) {
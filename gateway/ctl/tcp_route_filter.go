@@ -0,0 +1,40 @@
+package ctl
+
+import (
+	"fmt"
+
+	. "alauda.io/alb2/gateway"
+	"github.com/go-logr/logr"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// TcpRouteFilter reject tcproute which backendRefs could not be used.
+// backendRefs of all tcproutes attached to the same listener are weighted together, so one invalid route
+// should be rejected explicitly instead of breaking the whole listener.
+type TcpRouteFilter struct {
+	log logr.Logger
+}
+
+func (t *TcpRouteFilter) Name() string {
+	return "TcpRouteFilter"
+}
+
+func (t *TcpRouteFilter) FilteRoute(ref gv1.ParentReference, r *Route, ls *Listener) bool {
+	route, ok := r.route.(*TCPRoute)
+	if !ok {
+		return true
+	}
+	for i, rule := range route.Spec.Rules {
+		for _, b := range rule.BackendRefs {
+			if (b.Group != nil && *b.Group != "") || (b.Kind != nil && *b.Kind != "Service") {
+				r.invalidBackendRef(ref, fmt.Sprintf("rule %d backend %s is not a service", i, b.Name), string(gv1.RouteReasonInvalidKind))
+				return false
+			}
+			if b.Port == nil {
+				r.invalidBackendRef(ref, fmt.Sprintf("rule %d backend %s has no port", i, b.Name), string(gv1.RouteReasonUnsupportedValue))
+				return false
+			}
+		}
+	}
+	return true
+}
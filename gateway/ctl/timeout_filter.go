@@ -0,0 +1,157 @@
+package ctl
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"strings"
+
+	. "alauda.io/alb2/gateway"
+	pa "alauda.io/alb2/gateway/nginx/policyattachment"
+	pat "alauda.io/alb2/gateway/nginx/policyattachment/types"
+	gatewayPolicy "alauda.io/alb2/pkg/apis/alauda/gateway/v1alpha1"
+	timeout_t "alauda.io/alb2/pkg/controller/ext/timeout/types"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/api/meta"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// TimeoutFilter reject httproute with invalid timeouts, and record the effective timeouts of each rule
+// (after merged with timeoutpolicy) in route status.
+type TimeoutFilter struct {
+	log logr.Logger
+	c   client.Client
+	ctx context.Context
+}
+
+func (t *TimeoutFilter) Name() string {
+	return "TimeoutFilter"
+}
+
+func (t *TimeoutFilter) FilteRoute(ref gv1.ParentReference, r *Route, ls *Listener) bool {
+	route, ok := r.route.(*HTTPRoute)
+	if !ok {
+		return true
+	}
+	status, ok := r.status[RefsToString(ref)]
+	if !ok || !status.accept {
+		return true
+	}
+	log := t.log.WithValues("route", GetObjectKey(r.route))
+	policies, err := t.listTimeoutPolicy()
+	if err != nil {
+		log.Error(err, "list timeout policy fail")
+	}
+	attachRef := pat.Ref{
+		Listener: &pat.Listener{Listener: ls.Listener, Gateway: ls.gateway, GatewayClass: ls.gatewayClass},
+		Route:    r.route,
+	}
+	effective := []string{}
+	for i, rule := range route.Spec.Rules {
+		native, err := pa.HttpRouteTimeoutsToTimeoutCr(rule.Timeouts)
+		if err != nil {
+			r.unAllowRouteWithReason(ref, fmt.Sprintf("invalid timeouts of rule %d: %v", i, err), string(gv1.RouteReasonUnsupportedValue))
+			return false
+		}
+		attachRef.RuleIndex = i
+		timeout, err := pa.EffectiveTimeout(attachRef, policies, native, log)
+		if err != nil {
+			log.Error(err, "resolve timeout fail", "rule", i)
+			continue
+		}
+		if timeout == nil {
+			continue
+		}
+		effective = append(effective, fmt.Sprintf("rule %d: %s", i, describeTimeout(timeout)))
+	}
+	status.timeouts = strings.Join(effective, "; ")
+	r.status[RefsToString(ref)] = status
+	return true
+}
+
+func (t *TimeoutFilter) listTimeoutPolicy() ([]pat.CommonPolicyAttachment, error) {
+	list := &gatewayPolicy.TimeoutPolicyList{}
+	err := t.c.List(t.ctx, list)
+	if err != nil {
+		return nil, err
+	}
+	ret := []pat.CommonPolicyAttachment{}
+	for _, p := range list.Items {
+		ret = append(ret, pa.TimeoutPolicyWrapper(p))
+	}
+	return ret, nil
+}
+
+func describeTimeout(t *timeout_t.TimeoutCr) string {
+	fields := []string{}
+	add := func(name string, v *uint) {
+		if v != nil {
+			fields = append(fields, fmt.Sprintf("%s=%dms", name, *v))
+		}
+	}
+	add("connect", t.ProxyConnectTimeoutMs)
+	add("send", t.ProxySendTimeoutMs)
+	add("read", t.ProxyReadTimeoutMs)
+	add("request", t.RequestTimeoutMs)
+	return strings.Join(fields, ",")
+}
+
+// update the Accepted condition of timeoutpolicy which attach to this gateway,its gatewayclass or its routes.
+// the condition is calculated from all timeoutpolicy, so reconcile of different gateway will not fight with each other.
+func (g *GatewayReconciler) updateTimeoutPolicyStatus(gateway *gv1.Gateway, routes []*Route) error {
+	list := &gatewayPolicy.TimeoutPolicyList{}
+	err := g.c.List(g.ctx, list)
+	if err != nil {
+		return err
+	}
+	all := []pat.CommonPolicyAttachment{}
+	for _, p := range list.Items {
+		all = append(all, pa.TimeoutPolicyWrapper(p))
+	}
+	conditions := pa.PolicyAcceptedCondition(all, pa.ALLRouteKind)
+
+	for i := range list.Items {
+		p := &list.Items[i]
+		if !policyRelatedToGateway(p.Spec.TargetRef, p.Namespace, gateway, routes) {
+			continue
+		}
+		cond, ok := conditions[client.ObjectKeyFromObject(p)]
+		if !ok {
+			continue
+		}
+		origin := p.Status.DeepCopy()
+		meta.SetStatusCondition(&p.Status.Conditions, cond)
+		if reflect.DeepEqual(origin, &p.Status) {
+			continue
+		}
+		g.log.Info("update timeout policy status", "policy", client.ObjectKeyFromObject(p), "reason", cond.Reason, "msg", cond.Message)
+		err := g.c.Status().Update(g.ctx, p)
+		if err != nil {
+			g.log.Error(err, "k8s update timeout policy status fail", "policy", client.ObjectKeyFromObject(p))
+			continue
+		}
+	}
+	return nil
+}
+
+// policyRelatedToGateway check whether the policy attach to this gateway, its gatewayclass or its routes.
+func policyRelatedToGateway(target gatewayPolicy.PolicyTargetReference, policyNs string, gateway *gv1.Gateway, routes []*Route) bool {
+	ns := target.Namespace
+	if ns == "" {
+		ns = policyNs
+	}
+	switch target.Kind {
+	case GatewayClassKind:
+		return target.Name == string(gateway.Spec.GatewayClassName)
+	case GatewayKind:
+		return target.Name == gateway.Name && ns == gateway.Namespace
+	}
+	for _, r := range routes {
+		obj := r.route.GetObject()
+		if target.Kind == GetRouteKind(r.route) && target.Name == obj.GetName() && ns == obj.GetNamespace() {
+			return true
+		}
+	}
+	return false
+}
@@ -13,10 +13,11 @@ import (
 
 type Listener struct {
 	gv1.Listener
-	gateway    client.ObjectKey
-	createTime time.Time
-	version    int64
-	status     ListenerStatus
+	gateway      client.ObjectKey
+	gatewayClass string
+	createTime   time.Time
+	version      int64
+	status       ListenerStatus
 }
 
 type ListenerStatus struct {
@@ -31,6 +32,10 @@ type ListenerStatus struct {
 		reason string // InvalidCertificateRef/InvalidRouteKinds/RefNotPermitted
 		msg    string
 	}
+	unsupported *struct {
+		reason string // UnsupportedProtocol
+		msg    string
+	}
 }
 
 type Route struct {
@@ -43,6 +48,10 @@ type RouteStatus struct {
 	accept bool
 	msg    string
 	reason string
+	// refs of this route could not be resolved, route with unresolved refs will not be accepted either.
+	unresolvedRefs bool
+	// effective timeouts of each rule, only for accepted httproute.
+	timeouts string
 }
 
 func (r *Route) invalidSectionName(ref gv1.ParentReference, msg string) {
@@ -76,6 +85,30 @@ func (r *Route) unAllowRouteWithReason(ref gv1.ParentReference, msg string, reas
 	r.status[key] = status
 }
 
+func (r *Route) refNotPermitted(ref gv1.ParentReference, msg string) {
+	key := RefsToString(ref)
+	status := RouteStatus{
+		ref:            ref,
+		accept:         false,
+		msg:            msg,
+		reason:         string(gv1.RouteReasonRefNotPermitted),
+		unresolvedRefs: true,
+	}
+	r.status[key] = status
+}
+
+func (r *Route) invalidBackendRef(ref gv1.ParentReference, msg string, reason string) {
+	key := RefsToString(ref)
+	status := RouteStatus{
+		ref:            ref,
+		accept:         false,
+		msg:            msg,
+		reason:         reason,
+		unresolvedRefs: true,
+	}
+	r.status[key] = status
+}
+
 func (r *Route) unAllowRoute(ref gv1.ParentReference, msg string) {
 	r.unAllowRouteWithReason(ref, msg, "")
 }
@@ -113,6 +146,28 @@ func (l *ListenerStatus) invalidKind(allinvalid bool, invalidkinds []string) {
 	}
 }
 
+func (l *ListenerStatus) refNotPermitted(msg string) {
+	l.valid = false
+	l.resolvedRefs = &struct {
+		reason string
+		msg    string
+	}{
+		reason: string(gv1.ListenerReasonRefNotPermitted),
+		msg:    msg,
+	}
+}
+
+func (l *ListenerStatus) unsupportedProtocol(msg string) {
+	l.valid = false
+	l.unsupported = &struct {
+		reason string
+		msg    string
+	}{
+		reason: string(gv1.ListenerReasonUnsupportedProtocol),
+		msg:    msg,
+	}
+}
+
 func (l ListenerStatus) toConditions(gateway *gv1.Gateway) []metav1.Condition {
 	if l.valid {
 		return []metav1.Condition{
@@ -145,6 +200,17 @@ func (l ListenerStatus) toConditions(gateway *gv1.Gateway) []metav1.Condition {
 		})
 	}
 
+	if l.unsupported != nil {
+		conditions = append(conditions, metav1.Condition{
+			Type:               string(gv1.ListenerConditionAccepted),
+			LastTransitionTime: metav1.Now(),
+			ObservedGeneration: gateway.Generation,
+			Status:             metav1.ConditionFalse,
+			Reason:             l.unsupported.reason,
+			Message:            l.unsupported.msg,
+		})
+	}
+
 	if l.resolvedRefs != nil {
 		conditions = append(conditions, metav1.Condition{
 			Type:               string(gv1.ListenerConditionResolvedRefs),
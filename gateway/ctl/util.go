@@ -33,6 +33,9 @@ func UpdateRouteStatus(r CommonRoute, f func([]gv1.RouteParentStatus) []gv1.Rout
 	case *UDPRoute:
 		route.Status.Parents = f(route.Status.Parents)
 		return route.Status.Parents, nil
+	case *GRPCRoute:
+		route.Status.Parents = f(route.Status.Parents)
+		return route.Status.Parents, nil
 	}
 	return nil, fmt.Errorf("unsupported route type %T", r)
 }
@@ -47,6 +50,55 @@ func GetRouteStatus(r CommonRoute) ([]gv1.RouteParentStatus, error) {
 		return route.Status.Parents, nil
 	case *UDPRoute:
 		return route.Status.Parents, nil
+	case *GRPCRoute:
+		return route.Status.Parents, nil
 	}
 	return nil, fmt.Errorf("unsupported route type %T", r)
 }
+
+// all backends referenced by route, include the backend of request mirror filter.
+func routeBackendRefs(r CommonRoute) []gv1.BackendObjectReference {
+	ret := []gv1.BackendObjectReference{}
+	appendFilter := func(fs []gv1.HTTPRouteFilter) {
+		for _, f := range fs {
+			if f.RequestMirror != nil {
+				ret = append(ret, f.RequestMirror.BackendRef)
+			}
+		}
+	}
+	switch route := r.(type) {
+	case *HTTPRoute:
+		for _, rule := range route.Spec.Rules {
+			appendFilter(rule.Filters)
+			for _, b := range rule.BackendRefs {
+				ret = append(ret, b.BackendObjectReference)
+				appendFilter(b.Filters)
+			}
+		}
+	case *GRPCRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, b := range rule.BackendRefs {
+				ret = append(ret, b.BackendObjectReference)
+			}
+		}
+	case *TCPRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, b := range rule.BackendRefs {
+				ret = append(ret, b.BackendObjectReference)
+			}
+		}
+	case *TLSRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, b := range rule.BackendRefs {
+				ret = append(ret, b.BackendObjectReference)
+			}
+		}
+	case *UDPRoute:
+		for _, rule := range route.Spec.Rules {
+			for _, b := range rule.BackendRefs {
+				ret = append(ret, b.BackendObjectReference)
+			}
+		}
+	}
+	return ret
+}
@@ -0,0 +1,35 @@
+package ctl
+
+import (
+	"context"
+
+	"alauda.io/alb2/utils"
+	ctrlBuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gv1b1t "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+// a reference grant could allow or revoke refs of any route or gateway, so reconcile all gateways we care about.
+func (g *GatewayReconciler) watchReferenceGrant(b *ctrlBuilder.Builder) *ctrlBuilder.Builder {
+	log := g.log.WithName("watchreferencegrant")
+	c := g.c
+	eventhandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		reqs := []reconcile.Request{}
+		for _, gw := range getGatewayList(ctx, c, g.cfg.GatewaySelector) {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
+		}
+		log.Info("reference grant change reconcile gateway", "grant", client.ObjectKeyFromObject(o), "len", len(reqs))
+		return reqs
+	})
+
+	grant := gv1b1t.ReferenceGrant{}
+	err := utils.AddTypeInformationToObject(scheme, &grant)
+	if err != nil {
+		log.Error(err, "failed to add type information to object")
+	}
+	b = b.Watches(&grant, eventhandler, ctrlBuilder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	return b
+}
@@ -0,0 +1,77 @@
+package ctl
+
+import (
+	"context"
+	"reflect"
+
+	pa "alauda.io/alb2/gateway/nginx/policyattachment"
+	pat "alauda.io/alb2/gateway/nginx/policyattachment/types"
+	gatewayPolicy "alauda.io/alb2/pkg/apis/alauda/gateway/v1alpha1"
+	"alauda.io/alb2/utils"
+	"k8s.io/apimachinery/pkg/api/meta"
+	ctrlBuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// the Accepted condition of retrypolicy is updated when reconcile gateway, so reconcile all gateways we care about.
+func (g *GatewayReconciler) watchRetryPolicy(b *ctrlBuilder.Builder) *ctrlBuilder.Builder {
+	log := g.log.WithName("watchretrypolicy")
+	c := g.c
+	eventhandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		reqs := []reconcile.Request{}
+		for _, gw := range getGatewayList(ctx, c, g.cfg.GatewaySelector) {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
+		}
+		log.Info("retry policy change reconcile gateway", "policy", client.ObjectKeyFromObject(o), "len", len(reqs))
+		return reqs
+	})
+
+	policy := gatewayPolicy.RetryPolicy{}
+	err := utils.AddTypeInformationToObject(scheme, &policy)
+	if err != nil {
+		log.Error(err, "failed to add type information to object")
+	}
+	b = b.Watches(&policy, eventhandler, ctrlBuilder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	return b
+}
+
+// update the Accepted condition of retrypolicy which attach to this gateway,its gatewayclass or its routes.
+func (g *GatewayReconciler) updateRetryPolicyStatus(gateway *gv1.Gateway, routes []*Route) error {
+	list := &gatewayPolicy.RetryPolicyList{}
+	err := g.c.List(g.ctx, list)
+	if err != nil {
+		return err
+	}
+	all := []pat.CommonPolicyAttachment{}
+	for _, p := range list.Items {
+		all = append(all, pa.RetryPolicyWrapper(p))
+	}
+	conditions := pa.PolicyAcceptedCondition(all, pa.RetryRouteKind)
+
+	for i := range list.Items {
+		p := &list.Items[i]
+		if !policyRelatedToGateway(p.Spec.TargetRef, p.Namespace, gateway, routes) {
+			continue
+		}
+		cond, ok := conditions[client.ObjectKeyFromObject(p)]
+		if !ok {
+			continue
+		}
+		origin := p.Status.DeepCopy()
+		meta.SetStatusCondition(&p.Status.Conditions, cond)
+		if reflect.DeepEqual(origin, &p.Status) {
+			continue
+		}
+		g.log.Info("update retry policy status", "policy", client.ObjectKeyFromObject(p), "reason", cond.Reason, "msg", cond.Message)
+		err := g.c.Status().Update(g.ctx, p)
+		if err != nil {
+			g.log.Error(err, "k8s update retry policy status fail", "policy", client.ObjectKeyFromObject(p))
+			continue
+		}
+	}
+	return nil
+}
@@ -75,10 +75,13 @@ func (g *GatewayReconciler) watchRoutes(b *ctrlBuilder.Builder) *ctrlBuilder.Bui
 	_ = utils.AddTypeInformationToObject(scheme, &tlspRoute)
 	udpRoute := gv1a2t.UDPRoute{}
 	_ = utils.AddTypeInformationToObject(scheme, &udpRoute)
+	grpcRoute := gv1a2t.GRPCRoute{}
+	_ = utils.AddTypeInformationToObject(scheme, &grpcRoute)
 
 	b = b.Watches(&httpRoute, eventhandler, options)
 	b = b.Watches(&tcpRoute, eventhandler, options)
 	b = b.Watches(&tlspRoute, eventhandler, options)
 	b = b.Watches(&udpRoute, eventhandler, options)
+	b = b.Watches(&grpcRoute, eventhandler, options)
 	return b
 }
@@ -0,0 +1,35 @@
+package ctl
+
+import (
+	"context"
+
+	gatewayPolicy "alauda.io/alb2/pkg/apis/alauda/gateway/v1alpha1"
+	"alauda.io/alb2/utils"
+	ctrlBuilder "sigs.k8s.io/controller-runtime/pkg/builder"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/controller-runtime/pkg/handler"
+	"sigs.k8s.io/controller-runtime/pkg/predicate"
+	"sigs.k8s.io/controller-runtime/pkg/reconcile"
+)
+
+// the effective timeouts in route status depend on timeoutpolicy, so reconcile all gateways we care about.
+func (g *GatewayReconciler) watchTimeoutPolicy(b *ctrlBuilder.Builder) *ctrlBuilder.Builder {
+	log := g.log.WithName("watchtimeoutpolicy")
+	c := g.c
+	eventhandler := handler.EnqueueRequestsFromMapFunc(func(ctx context.Context, o client.Object) []reconcile.Request {
+		reqs := []reconcile.Request{}
+		for _, gw := range getGatewayList(ctx, c, g.cfg.GatewaySelector) {
+			reqs = append(reqs, reconcile.Request{NamespacedName: client.ObjectKeyFromObject(&gw)})
+		}
+		log.Info("timeout policy change reconcile gateway", "policy", client.ObjectKeyFromObject(o), "len", len(reqs))
+		return reqs
+	})
+
+	policy := gatewayPolicy.TimeoutPolicy{}
+	err := utils.AddTypeInformationToObject(scheme, &policy)
+	if err != nil {
+		log.Error(err, "failed to add type information to object")
+	}
+	b = b.Watches(&policy, eventhandler, ctrlBuilder.WithPredicates(predicate.GenerationChangedPredicate{}))
+	return b
+}
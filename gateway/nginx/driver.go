@@ -14,6 +14,7 @@ import (
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/controller-runtime/pkg/client"
 	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gv1a2t "sigs.k8s.io/gateway-api/apis/v1alpha2"
 )
 
 var scheme = runtime.NewScheme()
@@ -21,6 +22,7 @@ var scheme = runtime.NewScheme()
 //nolint:errcheck
 func init() {
 	_ = gv1.AddToScheme(scheme)
+	_ = gv1a2t.AddToScheme(scheme)
 }
 
 type Driver struct {
@@ -80,10 +82,11 @@ func (d *Driver) ListListener(sel config.GatewaySelector) ([]*types.Listener, er
 			for _, l := range g.Spec.Listeners {
 				if IsListenerReady(g.Status.Listeners, key, string(l.Name), g.Generation) {
 					ls := &types.Listener{
-						Listener:   l,
-						Gateway:    client.ObjectKeyFromObject(g),
-						Generation: g.Generation,
-						Routes:     []gateway.CommonRoute{},
+						Listener:     l,
+						Gateway:      client.ObjectKeyFromObject(g),
+						GatewayClass: string(g.Spec.GatewayClassName),
+						Generation:   g.Generation,
+						Routes:       []gateway.CommonRoute{},
 					}
 					key := ListenerToKey(ls)
 					lsMap[key] = ls
@@ -191,6 +194,10 @@ func listRoutes(kd *driver.KubernetesDriver) ([]gateway.CommonRoute, error) {
 	if err != nil {
 		return nil, err
 	}
+	grpcList, err := kd.Informers.Gateway.GrpcRoute.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
 	for _, http := range httpList {
 		_ = utils.AddTypeInformationToObject(scheme, http)
 		r := gateway.HTTPRoute(*http)
@@ -206,6 +213,11 @@ func listRoutes(kd *driver.KubernetesDriver) ([]gateway.CommonRoute, error) {
 		r := gateway.TLSRoute(*tls)
 		ret = append(ret, &r)
 	}
+	for _, grpc := range grpcList {
+		_ = utils.AddTypeInformationToObject(scheme, grpc)
+		r := gateway.GRPCRoute(*grpc)
+		ret = append(ret, &r)
+	}
 	for _, udp := range udpList {
 		_ = utils.AddTypeInformationToObject(scheme, udp)
 		r := gateway.UDPRoute(*udp)
@@ -263,6 +275,8 @@ func GetStatus(r gateway.CommonRoute) []gv1.RouteParentStatus {
 		return route.Status.Parents
 	case *gateway.UDPRoute:
 		return route.Status.Parents
+	case *gateway.GRPCRoute:
+		return route.Status.Parents
 	}
 	return nil
 }
@@ -0,0 +1,327 @@
+package http
+
+import (
+	"fmt"
+	"regexp"
+
+	"alauda.io/alb2/config"
+	"alauda.io/alb2/controller/modules"
+	ctltype "alauda.io/alb2/controller/types"
+	"alauda.io/alb2/driver"
+	"alauda.io/alb2/gateway"
+	ngxtype "alauda.io/alb2/gateway/nginx/types"
+	nu "alauda.io/alb2/gateway/nginx/utils"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	"alauda.io/alb2/utils"
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+
+	gatewayPolicyType "alauda.io/alb2/gateway/nginx/policyattachment/types"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gv1a2t "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// grpc route could attach to http/https listener. a grpc request is a http2 POST to /{service}/{method},
+// so we translate it into a grpc frontend, whose rules match on URL.
+// NOTE: nginx could not proxy_pass and grpc_pass in the same location, so a port which already has http route will ignore grpc route.
+// such grpc route is rejected by the PortShareFilter of gateway controller, and grpc route with filters of backendRef is rejected by the GrpcRouteFilter.
+type GrpcProtocolTranslate struct {
+	drv    *driver.KubernetesDriver
+	handle gatewayPolicyType.PolicyAttachmentHandle
+	log    logr.Logger
+	cfg    *config.Config
+}
+
+func NewGrpcProtocolTranslate(drv *driver.KubernetesDriver, log logr.Logger, cfg *config.Config) GrpcProtocolTranslate {
+	return GrpcProtocolTranslate{drv: drv, log: log, cfg: cfg}
+}
+
+func (g *GrpcProtocolTranslate) SetPolicyAttachmentHandle(handle gatewayPolicyType.PolicyAttachmentHandle) {
+	g.handle = handle
+}
+
+// ctx is sth that contain enough information to identify a rule.
+// each 'match' correspond to a alb rule, the same as http route.
+type GrpcCtx struct {
+	listener   *ngxtype.Listener
+	grpcRoute  *gateway.GRPCRoute
+	ruleIndex  uint
+	rule       *gv1a2t.GRPCRouteRule
+	matchIndex uint
+	// only exist when listener is https
+	cert       *client.ObjectKey
+	certDomain string
+}
+
+func (c *GrpcCtx) ToString() string {
+	return fmt.Sprintf("%s-%s-%s-%s-%s", c.listener.Gateway.Namespace, c.listener.Gateway.Name, c.listener.Listener.Name, c.grpcRoute.Namespace, c.grpcRoute.Name)
+}
+
+func (c *GrpcCtx) ToAttachRef() gatewayPolicyType.Ref {
+	return gatewayPolicyType.Ref{
+		Listener: &gatewayPolicyType.Listener{
+			Listener:     c.listener.Listener,
+			Gateway:      c.listener.Gateway,
+			GatewayClass: c.listener.GatewayClass,
+			Generation:   c.listener.Generation,
+			CreateTime:   c.listener.CreateTime,
+		},
+		Route:      c.grpcRoute,
+		RuleIndex:  int(c.ruleIndex),
+		MatchIndex: int(c.matchIndex),
+	}
+}
+
+func (g *GrpcProtocolTranslate) TransLate(ls []*ngxtype.Listener, ftMap ngxtype.FtMap) error {
+	log := g.log.WithName("grpc")
+	PatchGrpcRouteDefaultMatch(ls) // if grpc route has empty matches, match all method of all service
+
+	portMap := make(map[int][]GrpcCtx)
+	for _, protocol := range []gv1.ProtocolType{gv1.HTTPProtocolType, gv1.HTTPSProtocolType} {
+		plsMap := GroupListenerByProtocol(ls, protocol)
+		for port, lss := range plsMap {
+			ctxList := IterGrpcListener(lss, func(ctx GrpcCtx) *GrpcCtx {
+				if protocol == gv1.HTTPSProtocolType {
+					cert, domain, err := getCert(ctx.listener)
+					if err != nil {
+						log.Error(err, "get cert from https listener fail")
+						return nil
+					}
+					ctx.cert = cert
+					ctx.certDomain = *domain
+				}
+				return &ctx
+			})
+			portMap[port] = append(portMap[port], ctxList...)
+		}
+	}
+
+	for port, ctxList := range portMap {
+		if len(ctxList) == 0 {
+			continue
+		}
+		if ftMap.HasFt(string(albv1.FtProtocolHTTP), albv1.PortNumber(port)) || ftMap.HasFt(string(albv1.FtProtocolHTTPS), albv1.PortNumber(port)) {
+			// should not happen, unless the status of grpc route is not updated yet.
+			log.Info("port already used by http route. ignore grpc route", "port", port, "len", len(ctxList))
+			continue
+		}
+		ft := &ctltype.Frontend{}
+		ft.Port = albv1.PortNumber(port)
+		ft.Protocol = albv1.FtProtocolgRPC
+		rules := []*ctltype.InternalRule{}
+		for _, ctx := range ctxList {
+			rule, err := g.generateGrpcRule(ctx)
+			if err != nil {
+				log.Error(err, "generate grpc rule failed", "ctx", ctx.ToString())
+				continue
+			}
+			// grpc ft with a certificate will be rendered as a tls server. the certificate of each listener is picked by sni,
+			// the certificate of ft is only the default one for the client without matched sni, pick the smallest name to keep it stable.
+			if ctx.cert != nil && (ft.CertificateName == "" || rule.CertificateName < ft.CertificateName) {
+				ft.CertificateName = rule.CertificateName
+			}
+			if g.handle != nil {
+				err = g.handle.OnRule(ft, rule, ctx.ToAttachRef())
+				// NOTE: if attach is failed, we just log and ignore.
+				if err != nil {
+					log.Error(err, "attach policy fail")
+				}
+			}
+			err = applyGrpcFilterOnRule(rule, ctx.rule.Filters)
+			if err != nil {
+				log.Error(err, "apply filter fail")
+			}
+			rules = append(rules, rule)
+		}
+		ft.Rules = append(ft.Rules, rules...)
+
+		if len(ft.Rules) == 0 {
+			log.V(2).Info("could not find rule. ignore this port", "port", port)
+			continue
+		}
+		ftMap.SetFt(string(ft.Protocol), ft.Port, ft)
+	}
+	return nil
+}
+
+func (g *GrpcProtocolTranslate) generateGrpcRule(ctx GrpcCtx) (*ctltype.InternalRule, error) {
+	route := ctx.grpcRoute
+	gRule := route.Spec.Rules[ctx.ruleIndex]
+	match := gRule.Matches[ctx.matchIndex]
+
+	rule := &ctltype.InternalRule{}
+	rule.Source = &albv1.Source{
+		Type:      modules.TypeGRPCRoute,
+		Namespace: route.Namespace,
+		Name:      route.Name,
+	}
+	rule.Type = ctltype.RuleTypeGateway
+	rule.RuleID = genGrpcRuleIdViaCtx(ctx)
+	if ctx.cert != nil {
+		rule.Domain = ctx.certDomain
+		rule.CertificateName = fmt.Sprintf("%s/%s", ctx.cert.Namespace, ctx.cert.Name)
+	}
+
+	hostnames := JoinHostnames((*string)(ctx.listener.Hostname), lo.Map(route.Spec.Hostnames, func(h gv1.Hostname, _ int) string { return string(h) }))
+	dslx, err := GrpcRuleMatchToDSLX(hostnames, match)
+	if err != nil {
+		return nil, err
+	}
+	rule.DSLX = dslx
+
+	rule.BackendProtocol = "grpc"
+	svcs, err := nu.BackendRefsToService(pickGrpcBackendRefs(gRule.BackendRefs))
+	if err != nil {
+		return nil, err
+	}
+	rule.Services = svcs
+	return rule, nil
+}
+
+// translate grpc match to dslx
+// service and method are matched on the request path (/{service}/{method}).
+// Exact service+method => EQ /svc/method
+// Exact service => STARTS_WITH /svc/
+// Exact method => REGEX ^/[^/]+/method$
+// RegularExpression => REGEX ^/svc/method$ (missing part match any)
+func GrpcRuleMatchToDSLX(hostnameStrs []string, m gv1a2t.GRPCRouteMatch) (albv1.DSLX, error) {
+	// host match are the same as http route.
+	dslx, err := HttpRuleMatchToDSLX(hostnameStrs, gv1.HTTPRouteMatch{})
+	if err != nil {
+		return nil, err
+	}
+
+	op, path, err := grpcMethodToPathMatch(m.Method)
+	if err != nil {
+		return nil, err
+	}
+	dslx = append(dslx, albv1.DSLXTerm{Type: utils.KEY_URL, Values: [][]string{{op, path}}})
+
+	for _, h := range m.Headers {
+		op, err := nu.ToOP((*string)(h.Type))
+		if err != nil {
+			return nil, fmt.Errorf("invalid header match err %v", err)
+		}
+		dslx = append(dslx, albv1.DSLXTerm{Type: utils.KEY_HEADER, Values: [][]string{{op, h.Value}}, Key: string(h.Name)})
+	}
+	return dslx, nil
+}
+
+func grpcMethodToPathMatch(m *gv1a2t.GRPCMethodMatch) (op string, path string, err error) {
+	if m == nil || (m.Service == nil && m.Method == nil) {
+		return utils.OP_STARTS_WITH, "/", nil
+	}
+	matchType := gv1a2t.GRPCMethodMatchExact
+	if m.Type != nil {
+		matchType = *m.Type
+	}
+	switch matchType {
+	case gv1a2t.GRPCMethodMatchExact:
+		if m.Service != nil && m.Method != nil {
+			return utils.OP_EQ, fmt.Sprintf("/%s/%s", *m.Service, *m.Method), nil
+		}
+		if m.Service != nil {
+			return utils.OP_STARTS_WITH, fmt.Sprintf("/%s/", *m.Service), nil
+		}
+		return utils.OP_REGEX, fmt.Sprintf("^/[^/]+/%s$", regexp.QuoteMeta(*m.Method)), nil
+	case gv1a2t.GRPCMethodMatchRegularExpression:
+		service := "[^/]+"
+		method := "[^/]+"
+		if m.Service != nil {
+			service = *m.Service
+		}
+		if m.Method != nil {
+			method = *m.Method
+		}
+		return utils.OP_REGEX, fmt.Sprintf("^/%s/%s$", service, method), nil
+	default:
+		return "", "", fmt.Errorf("unsupported grpc method match type %v", matchType)
+	}
+}
+
+func applyGrpcFilterOnRule(rule *ctltype.InternalRule, filters []gv1a2t.GRPCRouteFilter) error {
+	headerModifyFilter := []gv1.HTTPHeaderFilter{}
+	responseHeaderModifyFilter := []gv1.HTTPHeaderFilter{}
+	for _, f := range filters {
+		if f.Type == gv1a2t.GRPCRouteFilterRequestHeaderModifier && f.RequestHeaderModifier != nil {
+			headerModifyFilter = append(headerModifyFilter, *f.RequestHeaderModifier)
+		}
+		if f.Type == gv1a2t.GRPCRouteFilterResponseHeaderModifier && f.ResponseHeaderModifier != nil {
+			responseHeaderModifyFilter = append(responseHeaderModifyFilter, *f.ResponseHeaderModifier)
+		}
+	}
+	err := applyHeaderModifyFilter(rule, headerModifyFilter)
+	if err != nil {
+		return err
+	}
+	return applyResponseHeaderModifyFilter(rule, responseHeaderModifyFilter)
+}
+
+func genGrpcRuleIdViaCtx(ctx GrpcCtx) string {
+	gateway := ctx.listener.Gateway
+	route := ctx.grpcRoute
+	return fmt.Sprintf("%d-%s-%s-%s-grpc-%s-%s-%d-%d",
+		ctx.listener.Port,
+		gateway.Namespace,
+		gateway.Name,
+		ctx.listener.Name,
+		route.Namespace,
+		route.Name,
+		ctx.ruleIndex,
+		ctx.matchIndex,
+	)
+}
+
+func PatchGrpcRouteDefaultMatch(listenerList []*ngxtype.Listener) {
+	for _, listener := range listenerList {
+		for _, route := range listener.Routes {
+			grpcRoute, ok := route.(*gateway.GRPCRoute)
+			if !ok {
+				continue
+			}
+			for ruleIndex, rule := range grpcRoute.Spec.Rules {
+				if len(rule.Matches) == 0 {
+					grpcRoute.Spec.Rules[ruleIndex].Matches = []gv1a2t.GRPCRouteMatch{{}}
+				}
+			}
+		}
+	}
+}
+
+func IterGrpcListener[T any, F func(GrpcCtx) *T](listenerList []*ngxtype.Listener, f F) []T {
+	retList := []T{}
+	for _, listener := range listenerList {
+		for _, route := range listener.Routes {
+			grpcRoute, ok := route.(*gateway.GRPCRoute)
+			if !ok {
+				continue
+			}
+			for ruleIndex, r := range grpcRoute.Spec.Rules {
+				rule := r
+				for matchIndex := range rule.Matches {
+					ctx := GrpcCtx{
+						listener:   listener,
+						grpcRoute:  grpcRoute,
+						rule:       &rule,
+						ruleIndex:  uint(ruleIndex),
+						matchIndex: uint(matchIndex),
+					}
+					t := f(ctx)
+					if t != nil {
+						retList = append(retList, *t)
+					}
+				}
+			}
+		}
+	}
+	return retList
+}
+
+func pickGrpcBackendRefs(refs []gv1a2t.GRPCBackendRef) []gv1.BackendRef {
+	ret := []gv1.BackendRef{}
+	for _, r := range refs {
+		ret = append(ret, r.BackendRef)
+	}
+	return ret
+}
@@ -0,0 +1,78 @@
+package http
+
+import (
+	"testing"
+
+	"alauda.io/alb2/utils"
+	"github.com/stretchr/testify/assert"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gv1a2t "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestGrpcMatchesToDSLX(t *testing.T) {
+	exact := gv1a2t.GRPCMethodMatchExact
+	regex := gv1a2t.GRPCMethodMatchRegularExpression
+	headerExact := gv1.HeaderMatchExact
+	type TestCase struct {
+		hostnames []string
+		match     gv1a2t.GRPCRouteMatch
+		expect    string
+	}
+	cases := []TestCase{
+		{
+			expect:    `["AND",["IN","HOST","a.com"],["STARTS_WITH","URL","/"]]`,
+			hostnames: []string{"a.com"},
+			match:     gv1a2t.GRPCRouteMatch{},
+		},
+		{
+			expect: `[["EQ","URL","/helloworld.Greeter/SayHello"]]`,
+			match: gv1a2t.GRPCRouteMatch{
+				Method: &gv1a2t.GRPCMethodMatch{
+					Type:    &exact,
+					Service: utils.StringRefs("helloworld.Greeter"),
+					Method:  utils.StringRefs("SayHello"),
+				},
+			},
+		},
+		{
+			expect: `["AND",["STARTS_WITH","URL","/helloworld.Greeter/"],["EQ","HEADER","version","v2"]]`,
+			match: gv1a2t.GRPCRouteMatch{
+				Method: &gv1a2t.GRPCMethodMatch{
+					Service: utils.StringRefs("helloworld.Greeter"),
+				},
+				Headers: []gv1a2t.GRPCHeaderMatch{
+					{
+						Type:  &headerExact,
+						Name:  "version",
+						Value: "v2",
+					},
+				},
+			},
+		},
+		{
+			expect: `[["REGEX","URL","^/[^/]+/SayHello$"]]`,
+			match: gv1a2t.GRPCRouteMatch{
+				Method: &gv1a2t.GRPCMethodMatch{
+					Type:   &exact,
+					Method: utils.StringRefs("SayHello"),
+				},
+			},
+		},
+		{
+			expect: `[["REGEX","URL","^/helloworld\\..*/[^/]+$"]]`,
+			match: gv1a2t.GRPCRouteMatch{
+				Method: &gv1a2t.GRPCMethodMatch{
+					Type:    &regex,
+					Service: utils.StringRefs(`helloworld\..*`),
+				},
+			},
+		},
+	}
+	for _, c := range cases {
+		dslx, err := GrpcRuleMatchToDSLX(c.hostnames, c.match)
+		assert.NoError(t, err)
+		internalDslStr, err := toInternalDslJsonStr(dslx)
+		assert.NoError(t, err)
+		assert.Equal(t, c.expect, internalDslStr)
+	}
+}
@@ -10,6 +10,7 @@ import (
 	ctltype "alauda.io/alb2/controller/types"
 	"alauda.io/alb2/driver"
 	"alauda.io/alb2/gateway"
+	"alauda.io/alb2/gateway/nginx/policyattachment"
 	ngxtype "alauda.io/alb2/gateway/nginx/types"
 	nu "alauda.io/alb2/gateway/nginx/utils"
 	u "alauda.io/alb2/gateway/utils"
@@ -155,6 +156,14 @@ func (h *HttpProtocolTranslate) generateHttpRule(ctx HttpCtx) (*ctltype.Internal
 		return nil, err
 	}
 	rule.Services = svcs
+	timeout, err := policyattachment.HttpRouteTimeoutsToTimeoutCr(gRule.Timeouts)
+	if err != nil {
+		return nil, err
+	}
+	rule.Config.Timeout = timeout
+	if err := h.applySessionPersistence(ctx, rule); err != nil {
+		return nil, err
+	}
 	return rule, nil
 }
 
@@ -255,6 +264,14 @@ func (h *HttpProtocolTranslate) generateHttpsRule(ctx HttpsCtx) (*ctltype.Intern
 		return nil, err
 	}
 	rule.Services = svcs
+	timeout, err := policyattachment.HttpRouteTimeoutsToTimeoutCr(gRule.Timeouts)
+	if err != nil {
+		return nil, err
+	}
+	rule.Config.Timeout = timeout
+	if err := h.applySessionPersistence(ctx.HttpCtx, rule); err != nil {
+		return nil, err
+	}
 	return rule, nil
 }
 
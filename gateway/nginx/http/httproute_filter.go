@@ -1,35 +1,59 @@
 package http
 
 import (
+	"fmt"
 	"strings"
 
 	"alauda.io/alb2/controller/types"
+	"alauda.io/alb2/gateway"
+	mirror_t "alauda.io/alb2/pkg/controller/ext/mirror/types"
 	redirect_t "alauda.io/alb2/pkg/controller/ext/redirect/types"
 	gv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func (h *HttpProtocolTranslate) applyHttpFilterOnRule(ctx HttpCtx, rule *types.InternalRule, filters []gv1.HTTPRouteFilter) error {
 	log := h.log.WithValues("ctx", ctx.ToString())
+	if ctx.rule != nil {
+		backendFilters, err := gateway.PickHttpBackendFilters(ctx.rule.BackendRefs)
+		if err != nil {
+			// should not happen, unless the status of http route is not updated yet.
+			log.Error(err, "ignore backend filters")
+		}
+		// backend level filters are applied after rule level filters
+		filters = append(append([]gv1.HTTPRouteFilter{}, filters...), backendFilters...)
+	}
 
 	headerModifyFilter := []gv1.HTTPHeaderFilter{}
+	responseHeaderModifyFilter := []gv1.HTTPHeaderFilter{}
 	redirectFilter := []gv1.HTTPRequestRedirectFilter{}
 	rewriteFilter := []gv1.HTTPURLRewriteFilter{}
+	mirrorFilter := []gv1.HTTPRequestMirrorFilter{}
 	// groupby
 	for _, f := range filters {
 		if f.Type == gv1.HTTPRouteFilterRequestHeaderModifier && f.RequestHeaderModifier != nil {
 			headerModifyFilter = append(headerModifyFilter, *f.RequestHeaderModifier)
 		}
+		if f.Type == gv1.HTTPRouteFilterResponseHeaderModifier && f.ResponseHeaderModifier != nil {
+			responseHeaderModifyFilter = append(responseHeaderModifyFilter, *f.ResponseHeaderModifier)
+		}
 		if f.Type == gv1.HTTPRouteFilterRequestRedirect && f.RequestRedirect != nil {
 			redirectFilter = append(redirectFilter, *f.RequestRedirect)
 		}
 		if f.Type == gv1.HTTPRouteFilterURLRewrite && f.URLRewrite != nil {
 			rewriteFilter = append(rewriteFilter, *f.URLRewrite)
 		}
+		if f.Type == gv1.HTTPRouteFilterRequestMirror && f.RequestMirror != nil {
+			mirrorFilter = append(mirrorFilter, *f.RequestMirror)
+		}
 	}
-	err := h.applyHeaderModifyFilter(rule, headerModifyFilter)
+	err := applyHeaderModifyFilter(rule, headerModifyFilter)
 	if err != nil {
 		log.Error(err, "apply header modify filter fail")
 	}
+	err = applyResponseHeaderModifyFilter(rule, responseHeaderModifyFilter)
+	if err != nil {
+		log.Error(err, "apply response header modify filter fail")
+	}
 	if len(redirectFilter) > 1 {
 		log.Info("should only have one http redirect filter")
 	}
@@ -50,16 +74,72 @@ func (h *HttpProtocolTranslate) applyHttpFilterOnRule(ctx HttpCtx, rule *types.I
 			log.Error(err, "apply rewrite filter fail")
 		}
 	}
+	if len(mirrorFilter) > 1 {
+		log.Info("should only have one http request mirror filter")
+	}
+	if len(mirrorFilter) == 1 {
+		err = applyMirrorFilter(ctx.httpRoute.Namespace, rule, mirrorFilter[0])
+		if err != nil {
+			log.Error(err, "apply mirror filter fail")
+		}
+	}
 	return nil
 }
 
-func (h *HttpProtocolTranslate) applyHeaderModifyFilter(rule *types.InternalRule, filters []gv1.HTTPHeaderFilter) error {
+// the namespace of mirror backend default to the namespace of route.
+func applyMirrorFilter(routeNs string, rule *types.InternalRule, mirror gv1.HTTPRequestMirrorFilter) error {
+	ref := mirror.BackendRef
+	if ref.Kind != nil && *ref.Kind != "Service" {
+		return fmt.Errorf("mirror backend ref kind is not service but %v", *ref.Kind)
+	}
+	if ref.Port == nil {
+		return fmt.Errorf("mirror backend ref port is required")
+	}
+	ns := routeNs
+	if ref.Namespace != nil {
+		ns = string(*ref.Namespace)
+	}
+	rule.Config.Mirror = &mirror_t.MirrorCr{
+		Namespace: ns,
+		Name:      string(ref.Name),
+		Port:      int(*ref.Port),
+	}
+	return nil
+}
+
+// shared by http route and grpc route
+func applyHeaderModifyFilter(rule *types.InternalRule, filters []gv1.HTTPHeaderFilter) error {
 	if len(filters) == 0 {
 		return nil
 	}
-	set := map[string]string{}
-	add := map[string][]string{}
-	remove := []string{}
+	set, add, remove := mergeHeaderFilter(filters)
+	rule.Config.RewriteRequest = &types.RewriteRequestConfig{
+		Headers:       set,
+		HeadersAdd:    add,
+		HeadersRemove: remove,
+	}
+	return nil
+}
+
+// shared by http route and grpc route
+func applyResponseHeaderModifyFilter(rule *types.InternalRule, filters []gv1.HTTPHeaderFilter) error {
+	if len(filters) == 0 {
+		return nil
+	}
+	set, add, remove := mergeHeaderFilter(filters)
+	rule.Config.RewriteResponse = &types.RewriteResponseConfig{
+		Headers:       set,
+		HeadersAdd:    add,
+		HeadersRemove: remove,
+	}
+	return nil
+}
+
+// the latter filter wins when set the same header.
+func mergeHeaderFilter(filters []gv1.HTTPHeaderFilter) (set map[string]string, add map[string][]string, remove []string) {
+	set = map[string]string{}
+	add = map[string][]string{}
+	remove = []string{}
 	for _, f := range filters {
 		for _, h := range f.Set {
 			set[string(h.Name)] = h.Value
@@ -70,13 +150,7 @@ func (h *HttpProtocolTranslate) applyHeaderModifyFilter(rule *types.InternalRule
 		}
 		remove = append(remove, f.Remove...)
 	}
-
-	rule.Config.RewriteRequest = &types.RewriteRequestConfig{
-		Headers:       set,
-		HeadersAdd:    add,
-		HeadersRemove: remove,
-	}
-	return nil
+	return set, add, remove
 }
 
 func (h *HttpProtocolTranslate) applyRedirectFilter(ctx HttpCtx, r *types.InternalRule, redirect gv1.HTTPRequestRedirectFilter) error {
@@ -199,3 +199,72 @@ func TestHttpFilterRewrite(t *testing.T) {
 	assert.Equal(t, *rw.RewritePrefixMatch, "/abc")
 	assert.Equal(t, *rw.RewriteReplacePrefix, "/xxx")
 }
+
+func TestHttpFilterMirror(t *testing.T) {
+	h := NewHttpProtocolTranslate(nil, log.Log.WithName("test"), config.DefaultMock())
+	ctx := MockCtx()
+	ctx.httpRoute.Namespace = "route-ns"
+	rule := albType.InternalRule{}
+	port := gv1.PortNumber(8080)
+	err := h.applyHttpFilterOnRule(ctx, &rule, []gv1.HTTPRouteFilter{
+		{
+			Type: gv1.HTTPRouteFilterRequestMirror,
+			RequestMirror: &gv1.HTTPRequestMirrorFilter{
+				BackendRef: gv1.BackendObjectReference{
+					Name: "shadow",
+					Port: &port,
+				},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	mirror := rule.Config.Mirror
+	assert.NotNil(t, mirror)
+	assert.Equal(t, "route-ns", mirror.Namespace)
+	assert.Equal(t, "shadow", mirror.Name)
+	assert.Equal(t, 8080, mirror.Port)
+}
+
+func TestHttpFilterResponseHeaderModify(t *testing.T) {
+	h := NewHttpProtocolTranslate(nil, log.Log.WithName("test"), config.DefaultMock())
+	ctx := MockCtx()
+	ctx.rule.BackendRefs = []gv1.HTTPBackendRef{
+		{
+			Filters: []gv1.HTTPRouteFilter{
+				{
+					Type: gv1.HTTPRouteFilterResponseHeaderModifier,
+					ResponseHeaderModifier: &gv1.HTTPHeaderFilter{
+						Set: []gv1.HTTPHeader{{Name: "Content-Security-Policy", Value: "default-src 'self'"}},
+					},
+				},
+			},
+		},
+	}
+	rule := albType.InternalRule{}
+	err := h.applyHttpFilterOnRule(ctx, &rule, []gv1.HTTPRouteFilter{
+		{
+			Type: gv1.HTTPRouteFilterResponseHeaderModifier,
+			ResponseHeaderModifier: &gv1.HTTPHeaderFilter{
+				Set:    []gv1.HTTPHeader{{Name: "Strict-Transport-Security", Value: "max-age=31536000"}},
+				Add:    []gv1.HTTPHeader{{Name: "x-a", Value: "a1"}},
+				Remove: []string{"server"},
+			},
+		},
+	})
+	assert.NoError(t, err)
+	rw := rule.Config.RewriteResponse
+	assert.Equal(t, map[string]string{
+		"Strict-Transport-Security": "max-age=31536000",
+		"Content-Security-Policy":   "default-src 'self'",
+	}, rw.Headers)
+	assert.Equal(t, map[string][]string{"x-a": {"a1"}}, rw.HeadersAdd)
+	assert.Equal(t, []string{"server"}, rw.HeadersRemove)
+	assert.Nil(t, rule.Config.RewriteRequest)
+
+	// backends with different filters are ignored
+	ctx.rule.BackendRefs = append(ctx.rule.BackendRefs, gv1.HTTPBackendRef{})
+	rule = albType.InternalRule{}
+	err = h.applyHttpFilterOnRule(ctx, &rule, nil)
+	assert.NoError(t, err)
+	assert.Nil(t, rule.Config.RewriteResponse)
+}
@@ -0,0 +1,112 @@
+package http
+
+import (
+	"fmt"
+	"time"
+
+	ctltype "alauda.io/alb2/controller/types"
+	"alauda.io/alb2/gateway"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+)
+
+// sessionPersistence return the sessionPersistence of the rule of httproute, nil if it is not set.
+func (h *HttpProtocolTranslate) sessionPersistence(ctx HttpCtx) (*gateway.SessionPersistence, error) {
+	if h.drv == nil || h.drv.Informers.Gateway.HttpRouteSessionPersistence == nil {
+		return nil, nil
+	}
+	obj, err := h.drv.Informers.Gateway.HttpRouteSessionPersistence.Lister().ByNamespace(ctx.httpRoute.Namespace).Get(ctx.httpRoute.Name)
+	if err != nil {
+		// the raw informer may lag behind, the rule is translated without session persistence until next regeneration.
+		h.log.Info("get raw httproute fail", "route", ctx.httpRoute.Name, "ns", ctx.httpRoute.Namespace, "err", err)
+		return nil, nil
+	}
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return nil, nil
+	}
+	sps, err := gateway.SessionPersistenceOfRules(u)
+	if err != nil {
+		return nil, err
+	}
+	if int(ctx.ruleIndex) >= len(sps) {
+		return nil, nil
+	}
+	return sps[ctx.ruleIndex], nil
+}
+
+// SessionPersistenceToAffinity map the sessionPersistence of httproute rule to the session affinity of alb rule.
+//   - Cookie type use the cookie policy, the cookie is named by sessionName. it is a session cookie unless the lifetimeType is Permanent,
+//     then the max-age is the absoluteTimeout.
+//   - Header type use the header policy, requests are hashed by the header named by sessionName.
+//   - idleTimeout make the cookie expire after idleTimeout of inactivity, the cookie is set again on each response.
+//     the absoluteTimeout is not enforced in this case, the cookie is refreshed as long as the session is active.
+func SessionPersistenceToAffinity(sp *gateway.SessionPersistence, rule *ctltype.InternalRule) error {
+	if sp == nil {
+		return nil
+	}
+	name := ""
+	if sp.SessionName != nil {
+		name = *sp.SessionName
+	}
+	spType := gateway.CookieBasedSessionPersistence
+	if sp.Type != nil {
+		spType = *sp.Type
+	}
+	switch spType {
+	case gateway.HeaderBasedSessionPersistence:
+		if name == "" {
+			return fmt.Errorf("sessionName is required for header based session persistence")
+		}
+		rule.SessionAffinityPolicy = "header"
+		rule.SessionAffinityAttr = name
+		return nil
+	case gateway.CookieBasedSessionPersistence:
+	default:
+		return fmt.Errorf("unsupported session persistence type %s", spType)
+	}
+
+	cookie := &albv1.SessionAffinityCookie{Name: name}
+	permanent := sp.CookieConfig != nil && sp.CookieConfig.LifetimeType != nil && *sp.CookieConfig.LifetimeType == gateway.PermanentCookieLifetimeType
+	if permanent {
+		if sp.AbsoluteTimeout == nil {
+			return fmt.Errorf("absoluteTimeout is required for permanent cookie")
+		}
+		age, err := durationToSec(string(*sp.AbsoluteTimeout))
+		if err != nil {
+			return err
+		}
+		cookie.MaxAge = &age
+	}
+	if sp.IdleTimeout != nil {
+		age, err := durationToSec(string(*sp.IdleTimeout))
+		if err != nil {
+			return err
+		}
+		cookie.MaxAge = &age
+		cookie.Refresh = true
+	}
+	rule.SessionAffinityPolicy = "cookie"
+	rule.SessionAffinityCookie = cookie
+	return nil
+}
+
+func (h *HttpProtocolTranslate) applySessionPersistence(ctx HttpCtx, rule *ctltype.InternalRule) error {
+	sp, err := h.sessionPersistence(ctx)
+	if err != nil {
+		return err
+	}
+	return SessionPersistenceToAffinity(sp, rule)
+}
+
+func durationToSec(d string) (int, error) {
+	duration, err := time.ParseDuration(d)
+	if err != nil {
+		return 0, fmt.Errorf("invalid duration %s %w", d, err)
+	}
+	sec := int(duration.Seconds())
+	if sec < 1 {
+		return 0, fmt.Errorf("duration %s should be at least 1s", d)
+	}
+	return sec, nil
+}
@@ -0,0 +1,90 @@
+package http
+
+import (
+	"testing"
+
+	ctltype "alauda.io/alb2/controller/types"
+	"alauda.io/alb2/gateway"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestSessionPersistenceOfRules(t *testing.T) {
+	u := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": "gateway.networking.k8s.io/v1",
+		"kind":       "HTTPRoute",
+		"metadata":   map[string]interface{}{"name": "r1", "namespace": "ns", "resourceVersion": "1", "labels": map[string]interface{}{"a": "b"}},
+		"spec": map[string]interface{}{
+			"hostnames": []interface{}{"a.com"},
+			"rules": []interface{}{
+				map[string]interface{}{"backendRefs": []interface{}{}},
+				map[string]interface{}{
+					"backendRefs":        []interface{}{},
+					"sessionPersistence": map[string]interface{}{"sessionName": "s", "type": "Cookie", "idleTimeout": "10m"},
+				},
+			},
+		},
+	}}
+	obj, err := gateway.TrimHttpRouteToSessionPersistence(u)
+	assert.NoError(t, err)
+	trimmed := obj.(*unstructured.Unstructured)
+	assert.Equal(t, "r1", trimmed.GetName())
+	assert.Equal(t, "1", trimmed.GetResourceVersion())
+	assert.Nil(t, trimmed.Object["spec"].(map[string]interface{})["hostnames"])
+
+	sps, err := gateway.SessionPersistenceOfRules(trimmed)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(sps))
+	assert.Nil(t, sps[0])
+	assert.Equal(t, "s", *sps[1].SessionName)
+	assert.Equal(t, gv1.Duration("10m"), *sps[1].IdleTimeout)
+}
+
+func TestSessionPersistenceToAffinity(t *testing.T) {
+	cookie := gateway.CookieBasedSessionPersistence
+	header := gateway.HeaderBasedSessionPersistence
+	permanent := gateway.PermanentCookieLifetimeType
+	session := gateway.SessionCookieLifetimeType
+	dur := func(d string) *gv1.Duration {
+		ret := gv1.Duration(d)
+		return &ret
+	}
+
+	// nil does nothing.
+	rule := &ctltype.InternalRule{}
+	assert.NoError(t, SessionPersistenceToAffinity(nil, rule))
+	assert.Equal(t, "", rule.SessionAffinityPolicy)
+
+	// cookie is the default type, it is a session cookie by default.
+	rule = &ctltype.InternalRule{}
+	assert.NoError(t, SessionPersistenceToAffinity(&gateway.SessionPersistence{SessionName: pointy.String("s"), AbsoluteTimeout: dur("1h")}, rule))
+	assert.Equal(t, "cookie", rule.SessionAffinityPolicy)
+	assert.Equal(t, "s", rule.SessionAffinityCookie.Name)
+	assert.Nil(t, rule.SessionAffinityCookie.MaxAge)
+
+	rule = &ctltype.InternalRule{}
+	sp := &gateway.SessionPersistence{Type: &cookie, AbsoluteTimeout: dur("1h"), CookieConfig: &gateway.CookieConfig{LifetimeType: &permanent}}
+	assert.NoError(t, SessionPersistenceToAffinity(sp, rule))
+	assert.Equal(t, 3600, *rule.SessionAffinityCookie.MaxAge)
+	assert.False(t, rule.SessionAffinityCookie.Refresh)
+
+	// idle timeout refresh the cookie.
+	rule = &ctltype.InternalRule{}
+	sp = &gateway.SessionPersistence{IdleTimeout: dur("10m"), CookieConfig: &gateway.CookieConfig{LifetimeType: &session}}
+	assert.NoError(t, SessionPersistenceToAffinity(sp, rule))
+	assert.Equal(t, 600, *rule.SessionAffinityCookie.MaxAge)
+	assert.True(t, rule.SessionAffinityCookie.Refresh)
+
+	rule = &ctltype.InternalRule{}
+	assert.NoError(t, SessionPersistenceToAffinity(&gateway.SessionPersistence{Type: &header, SessionName: pointy.String("x-session")}, rule))
+	assert.Equal(t, "header", rule.SessionAffinityPolicy)
+	assert.Equal(t, "x-session", rule.SessionAffinityAttr)
+	assert.Nil(t, rule.SessionAffinityCookie)
+
+	// invalid ones.
+	assert.Error(t, SessionPersistenceToAffinity(&gateway.SessionPersistence{Type: &header}, &ctltype.InternalRule{}))
+	assert.Error(t, SessionPersistenceToAffinity(&gateway.SessionPersistence{CookieConfig: &gateway.CookieConfig{LifetimeType: &permanent}}, &ctltype.InternalRule{}))
+	assert.Error(t, SessionPersistenceToAffinity(&gateway.SessionPersistence{IdleTimeout: dur("1x")}, &ctltype.InternalRule{}))
+}
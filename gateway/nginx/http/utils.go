@@ -37,10 +37,11 @@ func (c *HttpCtx) ToAttachRef() gatewayPolicyType.Ref {
 	ctx := c
 	return gatewayPolicyType.Ref{
 		Listener: &gatewayPolicyType.Listener{
-			Listener:   ctx.listener.Listener,
-			Gateway:    ctx.listener.Gateway,
-			Generation: ctx.listener.Generation,
-			CreateTime: ctx.listener.CreateTime,
+			Listener:     ctx.listener.Listener,
+			Gateway:      ctx.listener.Gateway,
+			GatewayClass: ctx.listener.GatewayClass,
+			Generation:   ctx.listener.Generation,
+			CreateTime:   ctx.listener.CreateTime,
 		},
 		Route:      ctx.httpRoute,
 		RuleIndex:  int(ctx.ruleIndex),
@@ -60,6 +60,14 @@ func GetLBConfig(ctx context.Context, drv *driver.KubernetesDriver, cfg *config.
 		return nil, err
 	}
 
+	// grpc must translate after http, since they may share the same port.
+	grpc := httproute.NewGrpcProtocolTranslate(drv, log, cfg)
+	grpc.SetPolicyAttachmentHandle(pm)
+	err = grpc.TransLate(lss, ftMap)
+	if err != nil {
+		return nil, err
+	}
+
 	tcp := NewTcpProtocolTranslate(drv, log)
 	tcp.SetPolicyAttachmentHandle(pm)
 	err = tcp.TransLate(lss, ftMap)
@@ -67,6 +75,14 @@ func GetLBConfig(ctx context.Context, drv *driver.KubernetesDriver, cfg *config.
 		return nil, err
 	}
 
+	// tls must translate after tcp, since they may share the same port.
+	tls := NewTlsProtocolTranslate(drv, log)
+	tls.SetPolicyAttachmentHandle(pm)
+	err = tls.TransLate(lss, ftMap)
+	if err != nil {
+		return nil, err
+	}
+
 	udp := NewUdpProtocolTranslate(drv, log)
 	err = udp.TransLate(lss, ftMap)
 	if err != nil {
@@ -0,0 +1,122 @@
+package policyattachment
+
+import (
+	"context"
+	"fmt"
+
+	. "alauda.io/alb2/controller/types"
+	"alauda.io/alb2/driver"
+	. "alauda.io/alb2/gateway/nginx/policyattachment/types"
+	upstreamtls_t "alauda.io/alb2/pkg/controller/ext/upstreamtls/types"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
+	gv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// BackendTLSPolicy config the tls between alb and the service which the rule proxy to.
+// https://gateway-api.sigs.k8s.io/api-types/backendtlspolicy/
+// it is not a default/override policy, it attach to the service directly.
+type BackendTLSPolicy struct {
+	ctx       context.Context
+	log       logr.Logger
+	drv       *driver.KubernetesDriver
+	allPolicy []*gv1a2.BackendTLSPolicy
+}
+
+func NewBackendTLSPolicy(ctx context.Context, log logr.Logger, drv *driver.KubernetesDriver) (*BackendTLSPolicy, error) {
+	allPolicy, err := drv.Informers.Gateway.BackendTLSPolicy.Lister().List(labels.Everything())
+	if err != nil {
+		return nil, err
+	}
+	return &BackendTLSPolicy{
+		ctx:       ctx,
+		log:       log,
+		drv:       drv,
+		allPolicy: allPolicy,
+	}, nil
+}
+
+func (b *BackendTLSPolicy) OnRule(ft *Frontend, rule *InternalRule, ref Ref) error {
+	if !ft.IsHttpMode() {
+		return nil
+	}
+	cfg, err := getBackendTLSConfig(rule.Services, b.allPolicy)
+	if err != nil {
+		b.log.Error(err, "invalid backend tls policy", "ref", ref.Describe())
+	}
+	if cfg == nil {
+		return nil
+	}
+	if rule.BackendProtocol == "grpc" {
+		b.log.Info("backend tls policy of grpc backend not support yet, ignore", "ref", ref.Describe())
+		return nil
+	}
+	rule.Config.UpstreamTLS = cfg
+	rule.BackendProtocol = "https"
+	return nil
+}
+
+// all backends of a rule share the same location, so they must use the same backend tls policy.
+// the policy must be in the same namespace as the service.
+func getBackendTLSConfig(svcs []*BackendService, allPolicy []*gv1a2.BackendTLSPolicy) (*upstreamtls_t.UpstreamTLSCr, error) {
+	var matched *gv1a2.BackendTLSPolicy
+	for _, svc := range svcs {
+		p := findBackendTLSPolicy(svc, allPolicy)
+		if p == nil {
+			if matched != nil {
+				return nil, fmt.Errorf("service %s/%s has no backend tls policy, but others have", svc.ServiceNs, svc.ServiceName)
+			}
+			continue
+		}
+		if matched != nil && (matched.Namespace != p.Namespace || matched.Name != p.Name) {
+			return nil, fmt.Errorf("backends use different backend tls policy %s/%s %s/%s", matched.Namespace, matched.Name, p.Namespace, p.Name)
+		}
+		matched = p
+	}
+	if matched == nil {
+		return nil, nil
+	}
+	return backendTLSPolicyToCr(matched), nil
+}
+
+func findBackendTLSPolicy(svc *BackendService, allPolicy []*gv1a2.BackendTLSPolicy) *gv1a2.BackendTLSPolicy {
+	for _, p := range allPolicy {
+		target := p.Spec.TargetRef
+		if target.Group != "" || target.Kind != "Service" {
+			continue
+		}
+		ns := p.Namespace
+		if target.Namespace != nil {
+			ns = string(*target.Namespace)
+		}
+		if ns != p.Namespace {
+			continue
+		}
+		if ns == svc.ServiceNs && string(target.Name) == svc.ServiceName {
+			return p
+		}
+	}
+	return nil
+}
+
+// only the first ca cert ref is used, the ca should in key ca.crt.
+func backendTLSPolicyToCr(p *gv1a2.BackendTLSPolicy) *upstreamtls_t.UpstreamTLSCr {
+	cfg := &upstreamtls_t.UpstreamTLSCr{
+		Verify:   true,
+		Hostname: string(p.Spec.TLS.Hostname),
+	}
+	if len(p.Spec.TLS.CACertRefs) != 0 {
+		ca := p.Spec.TLS.CACertRefs[0]
+		ref := fmt.Sprintf("%s/%s#ca.crt", p.Namespace, ca.Name)
+		switch ca.Kind {
+		case "ConfigMap":
+			cfg.CaCmRef = ref
+		case "Secret":
+			cfg.CaSecretRef = ref
+		}
+	}
+	if p.Spec.TLS.WellKnownCACerts != nil && *p.Spec.TLS.WellKnownCACerts == gv1a2.WellKnownCACertSystem {
+		cfg.UseSystemCa = true
+	}
+	return cfg
+}
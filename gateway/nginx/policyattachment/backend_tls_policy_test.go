@@ -0,0 +1,61 @@
+package policyattachment
+
+import (
+	"testing"
+
+	. "alauda.io/alb2/controller/types"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+	gv1b1 "sigs.k8s.io/gateway-api/apis/v1beta1"
+)
+
+func TestBackendTLSPolicy(t *testing.T) {
+	system := gv1a2.WellKnownCACertSystem
+	policy := func(name, svc string, tls gv1a2.BackendTLSPolicyConfig) *gv1a2.BackendTLSPolicy {
+		return &gv1a2.BackendTLSPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gv1a2.BackendTLSPolicySpec{
+				TargetRef: gv1a2.PolicyTargetReferenceWithSectionName{
+					PolicyTargetReference: gv1a2.PolicyTargetReference{Group: "", Kind: "Service", Name: gv1a2.ObjectName(svc)},
+				},
+				TLS: tls,
+			},
+		}
+	}
+	all := []*gv1a2.BackendTLSPolicy{
+		policy("p1", "svc-1", gv1a2.BackendTLSPolicyConfig{
+			Hostname:   "a.com",
+			CACertRefs: []gv1b1.LocalObjectReference{{Kind: "ConfigMap", Name: "ca"}},
+		}),
+		policy("p2", "svc-2", gv1a2.BackendTLSPolicyConfig{
+			Hostname:         "b.com",
+			WellKnownCACerts: &system,
+		}),
+	}
+	svc := func(ns, name string) *BackendService {
+		return &BackendService{ServiceNs: ns, ServiceName: name, ServicePort: 443}
+	}
+
+	cfg, err := getBackendTLSConfig([]*BackendService{svc("default", "svc-1")}, all)
+	assert.NoError(t, err)
+	assert.Equal(t, "a.com", cfg.Hostname)
+	assert.Equal(t, "default/ca#ca.crt", cfg.CaCmRef)
+	assert.True(t, cfg.Verify)
+	assert.False(t, cfg.UseSystemCa)
+
+	cfg, err = getBackendTLSConfig([]*BackendService{svc("default", "svc-2")}, all)
+	assert.NoError(t, err)
+	assert.Equal(t, "b.com", cfg.Hostname)
+	assert.True(t, cfg.UseSystemCa)
+
+	// policy in other ns should not match
+	cfg, err = getBackendTLSConfig([]*BackendService{svc("other", "svc-1")}, all)
+	assert.NoError(t, err)
+	assert.Nil(t, cfg)
+
+	_, err = getBackendTLSConfig([]*BackendService{svc("default", "svc-1"), svc("default", "svc-2")}, all)
+	assert.Error(t, err)
+	_, err = getBackendTLSConfig([]*BackendService{svc("default", "svc-1"), svc("default", "svc-3")}, all)
+	assert.Error(t, err)
+}
@@ -10,6 +10,7 @@ import (
 
 type NamedPolicyAttachmentConfig struct {
 	Describe string
+	Override bool
 	Config   PolicyAttachmentConfig
 }
 
@@ -19,72 +20,73 @@ type OrderedPolicyAttachmentConfigList []NamedPolicyAttachmentConfig
 type PolicyAttachmentFilterConfig struct {
 	AllowRouteKind        map[string]bool
 	AllowListenerProtocol map[string]bool // not implement yet.
-	AllowAttachList       map[string]bool // not fully implement yet. gatewayclass/gateway/gateway-listener/route/route-rule now is gateway,route.
+	AllowAttachList       map[string]bool // attach points which are allowed, nil means all of gatewayclass/gateway/gateway-listener/route/route-rule.
 }
 
 var ALLRouteKind map[string]bool = map[string]bool{
 	HttpRouteKind: true,
 	TcpRouteKind:  true,
 	UdpRouteKind:  true,
+	GrpcRouteKind: true,
 }
 
+// the attach points, from the top of hierarchy to the bottom.
+const (
+	AttachGatewayClass    = "gatewayclass"
+	AttachGateway         = "gateway"
+	AttachGatewayListener = "gateway-listener"
+	AttachRoute           = "route"
+	AttachRouteRule       = "route-rule"
+)
+
+var attachChain = []string{AttachGatewayClass, AttachGateway, AttachGatewayListener, AttachRoute, AttachRouteRule}
+
+// the config list are ordered by priority, the latter one wins.
+// defaults from top to bottom, then overrides from bottom to top. so override of gatewayclass (the top of chain) beats everything,
+// override of gateway beats the overrides below it, and default of route rule beats the defaults above it.
 func getConfigList(ref Ref, allPolicy []CommonPolicyAttachment, cfg PolicyAttachmentFilterConfig, log logr.Logger) OrderedPolicyAttachmentConfigList {
 	// TODO it should/could optimize for speed,but the most important is that we need a way to know exactly how the finalized config come from.
-	// TODO add more attach point here,such like gatewayclass gatewaylistener routeindex etc
-
 	log = log.V(8).WithName("merge-config")
-	var gateway CommonPolicyAttachment
-	var route CommonPolicyAttachment
-	{
-		// get timeoutpolicy attached to the chain of this ref
-		// TODO now,we just ignore overlapped policy.
-		for i, p := range allPolicy {
-			target := p.GetTargetRef()
-			log.Info("find p", "target", target, "name", p.GetObject().GetName(), "ns", p.GetObject().GetNamespace())
-			if target.Kind == GatewayKind && target.Name == ref.Listener.Gateway.Name && target.Namespace == ref.Listener.Gateway.Namespace {
-				if target.SectionIndex == nil && target.SectionName == nil {
-					log.Info("find one attch the gateway")
-					gateway = allPolicy[i]
-					continue
-				}
-				// TODO allow listeners protocol filter
-			}
-			// allow route kind filter
-			if _, ok := cfg.AllowRouteKind[target.Kind]; ok {
-				routeObj := ref.Route.GetObject()
-				if target.Name == routeObj.GetName() && target.Namespace == routeObj.GetNamespace() {
-					if target.SectionIndex == nil && target.SectionName == nil {
-						log.Info("find one attch the route")
-						route = allPolicy[i]
-						continue
-					}
-				}
-			}
+	attached := map[string]CommonPolicyAttachment{}
+	for _, p := range allPolicy {
+		target := p.GetTargetRef()
+		log.Info("find p", "target", target, "name", p.GetObject().GetName(), "ns", p.GetObject().GetNamespace())
+		point := attachPointOf(ref, p, cfg)
+		if point == "" {
+			continue
+		}
+		if cfg.AllowAttachList != nil && !cfg.AllowAttachList[point] {
+			continue
 		}
+		// multiple policy attach to the same point, the oldest one wins.
+		if origin, ok := attached[point]; ok && !policyLess(p, origin) {
+			log.Info("conflict policy ignored", "point", point, "name", p.GetObject().GetName(), "ns", p.GetObject().GetNamespace())
+			continue
+		}
+		log.Info("find one attach", "point", point)
+		attached[point] = p
 	}
 
 	defaultCfg := []NamedPolicyAttachmentConfig{}
 	overideCfg := []NamedPolicyAttachmentConfig{}
-	{
-		add := func(t CommonPolicyAttachment, name string) {
-			if t != nil && t.GetDefault() != nil {
-				defaultCfg = append(defaultCfg, NamedPolicyAttachmentConfig{
-					Describe: fmt.Sprintf("%s-default", name),
-					Config:   t.GetDefault(),
-				})
-			}
-			if t != nil && t.GetOverride() != nil {
-				overideCfg = append(overideCfg, NamedPolicyAttachmentConfig{
-					Describe: fmt.Sprintf("%s-overide", name),
-					Config:   t.GetOverride(),
-				})
-			}
+	for _, point := range attachChain {
+		t, ok := attached[point]
+		if !ok {
+			continue
 		}
-		if gateway != nil {
-			add(gateway, fmt.Sprintf("gateway-%s-%s", gateway.GetObject().GetName(), gateway.GetObject().GetNamespace()))
+		name := fmt.Sprintf("%s-%s-%s", point, t.GetObject().GetName(), t.GetObject().GetNamespace())
+		if t.GetDefault() != nil {
+			defaultCfg = append(defaultCfg, NamedPolicyAttachmentConfig{
+				Describe: fmt.Sprintf("%s-default", name),
+				Config:   t.GetDefault(),
+			})
 		}
-		if route != nil {
-			add(route, fmt.Sprintf("route-%s-%s", route.GetObject().GetName(), route.GetObject().GetNamespace()))
+		if t.GetOverride() != nil {
+			overideCfg = append(overideCfg, NamedPolicyAttachmentConfig{
+				Describe: fmt.Sprintf("%s-overide", name),
+				Override: true,
+				Config:   t.GetOverride(),
+			})
 		}
 	}
 
@@ -100,6 +102,50 @@ func getConfigList(ref Ref, allPolicy []CommonPolicyAttachment, cfg PolicyAttach
 	return list
 }
 
+// attachPointOf return the attach point of policy in the chain of ref, or "" if policy does not attach to this ref.
+func attachPointOf(ref Ref, p CommonPolicyAttachment, cfg PolicyAttachmentFilterConfig) string {
+	target := p.GetTargetRef()
+	ns := targetNamespace(p)
+	switch {
+	case target.Kind == GatewayClassKind:
+		if target.Name == ref.Listener.GatewayClass && target.SectionName == nil && target.SectionIndex == nil {
+			return AttachGatewayClass
+		}
+	case target.Kind == GatewayKind:
+		if target.Name != ref.Listener.Gateway.Name || ns != ref.Listener.Gateway.Namespace {
+			return ""
+		}
+		if target.SectionName != nil {
+			if *target.SectionName == string(ref.Listener.Name) {
+				return AttachGatewayListener
+			}
+			return ""
+		}
+		// listener should be referenced by name.
+		if target.SectionIndex != nil {
+			return ""
+		}
+		return AttachGateway
+	case cfg.AllowRouteKind[target.Kind] && target.Kind == GetRouteKind(ref.Route):
+		routeObj := ref.Route.GetObject()
+		if target.Name != routeObj.GetName() || ns != routeObj.GetNamespace() {
+			return ""
+		}
+		// rule of route does not has name yet.
+		if target.SectionName != nil {
+			return ""
+		}
+		if target.SectionIndex != nil {
+			if int(*target.SectionIndex) == ref.RuleIndex {
+				return AttachRouteRule
+			}
+			return ""
+		}
+		return AttachRoute
+	}
+	return ""
+}
+
 // given a ref and list of policy, getConfig will find policy attach to the ref,and merge all those policy into a config.
 // return nil if no such config
 func getConfig(ref Ref, allPolicy []CommonPolicyAttachment, cfg PolicyAttachmentFilterConfig, log logr.Logger) PolicyAttachmentConfig {
@@ -108,7 +154,33 @@ func getConfig(ref Ref, allPolicy []CommonPolicyAttachment, cfg PolicyAttachment
 	if len(list) == 0 {
 		return nil
 	}
-	// merge
+	return mergeConfigList(list, log)
+}
+
+// like getConfig, but keep the merged default and override apart, so the config set on the resource itself could be put in the middle.
+// return nil if no such config
+func getDefaultAndOverrideConfig(ref Ref, allPolicy []CommonPolicyAttachment, cfg PolicyAttachmentFilterConfig, log logr.Logger) (def PolicyAttachmentConfig, override PolicyAttachmentConfig) {
+	list := getConfigList(ref, allPolicy, cfg, log)
+	log = log.V(8).WithName("merge-config")
+	defList := OrderedPolicyAttachmentConfigList{}
+	overrideList := OrderedPolicyAttachmentConfigList{}
+	for _, pc := range list {
+		if pc.Override {
+			overrideList = append(overrideList, pc)
+		} else {
+			defList = append(defList, pc)
+		}
+	}
+	if len(defList) != 0 {
+		def = mergeConfigList(defList, log)
+	}
+	if len(overrideList) != 0 {
+		override = mergeConfigList(overrideList, log)
+	}
+	return def, override
+}
+
+func mergeConfigList(list OrderedPolicyAttachmentConfigList, log logr.Logger) PolicyAttachmentConfig {
 	ret := PolicyAttachmentConfig{}
 	for _, pc := range list {
 		for key, val := range pc.Config {
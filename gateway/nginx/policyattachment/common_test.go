@@ -0,0 +1,124 @@
+package policyattachment
+
+import (
+	"testing"
+	"time"
+
+	"alauda.io/alb2/gateway"
+	. "alauda.io/alb2/gateway/nginx/policyattachment/types"
+	gatewayPolicy "alauda.io/alb2/pkg/apis/alauda/gateway/v1alpha1"
+	"github.com/go-logr/logr"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+type testPolicy struct {
+	name     string
+	kind     string
+	target   string
+	section  *string
+	index    *uint
+	def      *uint
+	override *uint
+	create   time.Time
+}
+
+func (t testPolicy) wrap() CommonPolicyAttachment {
+	cfg := func(v *uint) *gatewayPolicy.TimeoutPolicyConfig {
+		if v == nil {
+			return nil
+		}
+		return &gatewayPolicy.TimeoutPolicyConfig{ProxyReadTimeoutMs: v}
+	}
+	return TimeoutPolicyWrapper(gatewayPolicy.TimeoutPolicy{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: t.name, CreationTimestamp: metav1.NewTime(t.create)},
+		Spec: gatewayPolicy.TimeoutPolicySpec{
+			TargetRef: gatewayPolicy.PolicyTargetReference{Group: "gateway.networking.k8s.io", Kind: t.kind, Name: t.target, SectionName: t.section, SectionIndex: t.index},
+			Default:   cfg(t.def),
+			Override:  cfg(t.override),
+		},
+	})
+}
+
+func TestGetConfigChain(t *testing.T) {
+	ref := Ref{
+		Listener: &Listener{Listener: gv1.Listener{Name: "http"}, Gateway: client.ObjectKey{Namespace: "default", Name: "g1"}, GatewayClass: "exclusive-gateway"},
+		Route:    &gateway.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "r1"}},
+		// the second rule of route
+		RuleIndex: 1,
+	}
+	read := func(ps ...testPolicy) *uint {
+		all := []CommonPolicyAttachment{}
+		for _, p := range ps {
+			all = append(all, p.wrap())
+		}
+		cfg := getConfig(ref, all, PolicyAttachmentFilterConfig{AllowRouteKind: ALLRouteKind}, logr.Discard())
+		if cfg == nil {
+			return nil
+		}
+		ret := TimeoutPolicyConfig{}
+		_ = ret.FromConfig(cfg)
+		return ret.ProxyReadTimeoutMs
+	}
+	class := testPolicy{name: "class", kind: gateway.GatewayClassKind, target: "exclusive-gateway", def: pointy.Uint(1)}
+	gw := testPolicy{name: "gw", kind: gateway.GatewayKind, target: "g1", def: pointy.Uint(2)}
+	ls := testPolicy{name: "ls", kind: gateway.GatewayKind, target: "g1", section: pointy.String("http"), def: pointy.Uint(3)}
+	otherLs := testPolicy{name: "other-ls", kind: gateway.GatewayKind, target: "g1", section: pointy.String("https"), def: pointy.Uint(30)}
+	route := testPolicy{name: "route", kind: gateway.HttpRouteKind, target: "r1", def: pointy.Uint(4)}
+	rule := testPolicy{name: "rule", kind: gateway.HttpRouteKind, target: "r1", index: pointy.Uint(1), def: pointy.Uint(5)}
+	otherRule := testPolicy{name: "other-rule", kind: gateway.HttpRouteKind, target: "r1", index: pointy.Uint(0), def: pointy.Uint(50)}
+	tcpRoute := testPolicy{name: "tcp", kind: gateway.TcpRouteKind, target: "r1", def: pointy.Uint(60)}
+
+	assert.Nil(t, read(otherLs, otherRule, tcpRoute))
+	assert.Equal(t, uint(1), *read(class))
+	assert.Equal(t, uint(2), *read(class, gw))
+	assert.Equal(t, uint(3), *read(class, gw, ls, otherLs))
+	assert.Equal(t, uint(4), *read(class, gw, ls, route, tcpRoute))
+	// the most specific default wins
+	assert.Equal(t, uint(5), *read(class, gw, ls, route, rule, otherRule))
+
+	// the override of the top wins
+	lsOverride := ls
+	lsOverride.override = pointy.Uint(33)
+	assert.Equal(t, uint(33), *read(class, gw, lsOverride, route, rule))
+	gwOverride := gw
+	gwOverride.override = pointy.Uint(22)
+	assert.Equal(t, uint(22), *read(class, gwOverride, lsOverride, route, rule))
+	// override of gatewayclass is the last one in the list, it beats the override of gateway.
+	classOverride := class
+	classOverride.override = pointy.Uint(11)
+	assert.Equal(t, uint(11), *read(classOverride, gwOverride, lsOverride, route, rule))
+	assert.Equal(t, uint(11), *read(gwOverride, classOverride))
+
+	// the oldest one wins when conflict
+	now := time.Now()
+	old := testPolicy{name: "z-old", kind: gateway.HttpRouteKind, target: "r1", index: pointy.Uint(1), def: pointy.Uint(6), create: now.Add(-time.Hour)}
+	rule.create = now
+	assert.Equal(t, uint(6), *read(rule, old))
+	assert.Equal(t, uint(6), *read(old, rule))
+}
+
+func TestPolicyAcceptedCondition(t *testing.T) {
+	now := time.Now()
+	all := []CommonPolicyAttachment{
+		testPolicy{name: "a", kind: gateway.GatewayKind, target: "g1", section: pointy.String("http"), create: now}.wrap(),
+		testPolicy{name: "b", kind: gateway.GatewayKind, target: "g1", section: pointy.String("http"), create: now.Add(-time.Hour)}.wrap(),
+		testPolicy{name: "c", kind: gateway.GatewayKind, target: "g1", create: now}.wrap(),
+		testPolicy{name: "d", kind: gateway.HttpRouteKind, target: "r1", section: pointy.String("rule-1")}.wrap(),
+		testPolicy{name: "e", kind: "Service", target: "svc"}.wrap(),
+	}
+	conds := PolicyAcceptedCondition(all, ALLRouteKind)
+	reason := func(name string) string {
+		return conds[client.ObjectKey{Namespace: "default", Name: name}].Reason
+	}
+	assert.Equal(t, string(gv1a2.PolicyReasonConflicted), reason("a"))
+	assert.Equal(t, metav1.ConditionFalse, conds[client.ObjectKey{Namespace: "default", Name: "a"}].Status)
+	assert.Equal(t, string(gv1a2.PolicyReasonAccepted), reason("b"))
+	assert.Equal(t, string(gv1a2.PolicyReasonAccepted), reason("c"))
+	assert.Equal(t, string(gv1a2.PolicyReasonInvalid), reason("d"))
+	assert.Equal(t, string(gv1a2.PolicyReasonInvalid), reason("e"))
+}
@@ -0,0 +1,110 @@
+package policyattachment
+
+import (
+	"fmt"
+	"sort"
+
+	. "alauda.io/alb2/gateway"
+	. "alauda.io/alb2/gateway/nginx/policyattachment/types"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+// namespace of the target, default to the namespace of policy. gatewayclass is cluster scope.
+func targetNamespace(p CommonPolicyAttachment) string {
+	target := p.GetTargetRef()
+	if target.Kind == GatewayClassKind {
+		return ""
+	}
+	if target.Namespace != "" {
+		return target.Namespace
+	}
+	return p.GetObject().GetNamespace()
+}
+
+// the attach point which the policy targets, policies with the same attach point are conflicted.
+func targetKey(p CommonPolicyAttachment) string {
+	target := p.GetTargetRef()
+	section := ""
+	if target.SectionName != nil {
+		section = *target.SectionName
+	} else if target.SectionIndex != nil {
+		section = fmt.Sprintf("%d", *target.SectionIndex)
+	}
+	return fmt.Sprintf("%s/%s/%s/%s", target.Kind, targetNamespace(p), target.Name, section)
+}
+
+// https://gateway-api.sigs.k8s.io/geps/gep-713/#conflict-resolution
+// the oldest policy wins, if they are created at the same time, the first one in alphabetical order of namespace/name wins.
+func policyLess(a, b CommonPolicyAttachment) bool {
+	ao := a.GetObject()
+	bo := b.GetObject()
+	at := ao.GetCreationTimestamp()
+	bt := bo.GetCreationTimestamp()
+	if !at.Equal(&bt) {
+		return at.Before(&bt)
+	}
+	return client.ObjectKeyFromObject(ao).String() < client.ObjectKeyFromObject(bo).String()
+}
+
+// validTarget check the target could be attached to, regardless of whether the target exists.
+func validTarget(p CommonPolicyAttachment, routeKinds map[string]bool) error {
+	target := p.GetTargetRef()
+	switch {
+	case target.Kind == GatewayClassKind:
+		if target.SectionName != nil || target.SectionIndex != nil {
+			return fmt.Errorf("section of gatewayclass is not supported")
+		}
+	case target.Kind == GatewayKind:
+		if target.SectionName == nil && target.SectionIndex != nil {
+			return fmt.Errorf("listener of gateway should be referenced by sectionName")
+		}
+	case routeKinds[target.Kind]:
+		if target.SectionName != nil {
+			return fmt.Errorf("rule of %s should be referenced by sectionIndex", target.Kind)
+		}
+	default:
+		return fmt.Errorf("unsupported target kind %s", target.Kind)
+	}
+	return nil
+}
+
+// PolicyAcceptedCondition calculate the Accepted condition of each policy, key is namespace/name of policy.
+// it only depends on the policy list, so every caller get the same result.
+func PolicyAcceptedCondition(allPolicy []CommonPolicyAttachment, routeKinds map[string]bool) map[client.ObjectKey]metav1.Condition {
+	ret := map[client.ObjectKey]metav1.Condition{}
+	cond := func(p CommonPolicyAttachment, accept bool, reason gv1a2.PolicyConditionReason, msg string) metav1.Condition {
+		status := metav1.ConditionTrue
+		if !accept {
+			status = metav1.ConditionFalse
+		}
+		return metav1.Condition{
+			Type:               string(gv1a2.PolicyConditionAccepted),
+			Status:             status,
+			Reason:             string(reason),
+			Message:            msg,
+			ObservedGeneration: p.GetObject().GetGeneration(),
+		}
+	}
+	group := map[string][]CommonPolicyAttachment{}
+	for _, p := range allPolicy {
+		key := client.ObjectKeyFromObject(p.GetObject())
+		if err := validTarget(p, routeKinds); err != nil {
+			ret[key] = cond(p, false, gv1a2.PolicyReasonInvalid, err.Error())
+			continue
+		}
+		tk := targetKey(p)
+		group[tk] = append(group[tk], p)
+	}
+	for _, ps := range group {
+		sort.Slice(ps, func(i, j int) bool { return policyLess(ps[i], ps[j]) })
+		winner := client.ObjectKeyFromObject(ps[0].GetObject())
+		ret[winner] = cond(ps[0], true, gv1a2.PolicyReasonAccepted, "")
+		for _, p := range ps[1:] {
+			msg := fmt.Sprintf("conflict with %s which attach to the same target", winner)
+			ret[client.ObjectKeyFromObject(p.GetObject())] = cond(p, false, gv1a2.PolicyReasonConflicted, msg)
+		}
+	}
+	return ret
+}
@@ -14,6 +14,8 @@ type PolicyAttachmentManager struct {
 	log     logr.Logger
 	drv     *driver.KubernetesDriver
 	timeout TimeoutPolicy
+	tls     BackendTLSPolicy
+	retry   RetryPolicy
 }
 
 // manager of all policyattachment, recreate when re-render config.
@@ -22,11 +24,21 @@ func NewPolicyAttachmentManager(ctx context.Context, drv *driver.KubernetesDrive
 	if err != nil {
 		return nil, err
 	}
+	tls, err := NewBackendTLSPolicy(ctx, log.WithName("backendtls"), drv)
+	if err != nil {
+		return nil, err
+	}
+	retry, err := NewRetryPolicy(ctx, log.WithName("retry"), drv)
+	if err != nil {
+		return nil, err
+	}
 	return &PolicyAttachmentManager{
 		ctx:     ctx,
 		log:     log,
 		drv:     drv,
 		timeout: *timeout,
+		tls:     *tls,
+		retry:   *retry,
 	}, nil
 }
 
@@ -36,5 +48,13 @@ func (pm *PolicyAttachmentManager) OnRule(ft *Frontend, rule *InternalRule, ref
 	if err != nil {
 		return err
 	}
+	err = pm.tls.OnRule(ft, rule, ref)
+	if err != nil {
+		return err
+	}
+	err = pm.retry.OnRule(ft, rule, ref)
+	if err != nil {
+		return err
+	}
 	return nil
 }
@@ -0,0 +1,173 @@
+package policyattachment
+
+import (
+	"context"
+
+	. "alauda.io/alb2/controller/types"
+	"alauda.io/alb2/driver"
+	. "alauda.io/alb2/gateway"
+	. "alauda.io/alb2/gateway/nginx/policyattachment/types"
+	gatewayPolicy "alauda.io/alb2/pkg/apis/alauda/gateway/v1alpha1"
+	"alauda.io/alb2/pkg/controller/ext/retry"
+	retry_t "alauda.io/alb2/pkg/controller/ext/retry/types"
+	"github.com/go-logr/logr"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// retry only make sense for l7 route.
+var RetryRouteKind map[string]bool = map[string]bool{
+	HttpRouteKind: true,
+	GrpcRouteKind: true,
+}
+
+type RetryPolicyConfig gatewayPolicy.RetryPolicyConfig
+
+type RetryPolicyWrapper gatewayPolicy.RetryPolicy
+
+func (r RetryPolicyWrapper) GetDefault() PolicyAttachmentConfig {
+	if r.Spec.Default == nil {
+		return nil
+	}
+	cfg := RetryPolicyConfig(*r.Spec.Default)
+	return cfg.IntoConfig()
+}
+
+func (r RetryPolicyWrapper) GetOverride() PolicyAttachmentConfig {
+	if r.Spec.Override == nil {
+		return nil
+	}
+	cfg := RetryPolicyConfig(*r.Spec.Override)
+	return cfg.IntoConfig()
+}
+
+func (r RetryPolicyWrapper) GetTargetRef() gatewayPolicy.PolicyTargetReference {
+	return r.Spec.TargetRef
+}
+
+func (r RetryPolicyWrapper) GetObject() client.Object {
+	rp := gatewayPolicy.RetryPolicy(r)
+	return &rp
+}
+
+func (rc *RetryPolicyConfig) IntoConfig() PolicyAttachmentConfig {
+	ret := PolicyAttachmentConfig{}
+	if rc.Attempts != nil {
+		ret["attempts"] = *rc.Attempts
+	}
+	if len(rc.On) != 0 {
+		ret["on"] = append([]string{}, rc.On...)
+	}
+	if rc.PerTryTimeoutMs != nil {
+		ret["per_try_timeout_ms"] = *rc.PerTryTimeoutMs
+	}
+	if rc.TimeoutMs != nil {
+		ret["timeout_ms"] = *rc.TimeoutMs
+	}
+	if rc.RetryNonIdempotent != nil {
+		ret["retry_non_idempotent"] = *rc.RetryNonIdempotent
+	}
+	return ret
+}
+
+func (rc *RetryPolicyConfig) FromConfig(m PolicyAttachmentConfig) error {
+	if v := m["attempts"]; v != nil {
+		attempts := v.(uint)
+		rc.Attempts = &attempts
+	}
+	if v := m["on"]; v != nil {
+		rc.On = append([]string{}, v.([]string)...)
+	}
+	if v := m["per_try_timeout_ms"]; v != nil {
+		ms := v.(uint)
+		rc.PerTryTimeoutMs = &ms
+	}
+	if v := m["timeout_ms"]; v != nil {
+		ms := v.(uint)
+		rc.TimeoutMs = &ms
+	}
+	if v := m["retry_non_idempotent"]; v != nil {
+		b := v.(bool)
+		rc.RetryNonIdempotent = &b
+	}
+	return nil
+}
+
+type RetryPolicy struct {
+	ctx       context.Context
+	log       logr.Logger
+	drv       *driver.KubernetesDriver
+	allPolicy []CommonPolicyAttachment
+}
+
+func NewRetryPolicy(ctx context.Context, log logr.Logger, drv *driver.KubernetesDriver) (*RetryPolicy, error) {
+	allPolicy, err := getAllRetryPolicy(drv)
+	if err != nil {
+		return nil, err
+	}
+	return &RetryPolicy{
+		ctx:       ctx,
+		log:       log,
+		drv:       drv,
+		allPolicy: allPolicy,
+	}, nil
+}
+
+func (r *RetryPolicy) OnRule(ft *Frontend, rule *InternalRule, ref Ref) error {
+	if !ft.IsHttpMode() && !ft.IsGRPCMode() {
+		return nil
+	}
+	log := r.log.V(3).WithName("onrule").WithValues("ref", ref.Describe())
+	cfg, err := EffectiveRetry(ref, r.allPolicy, rule.Config.Retry, r.log.WithName("merge-attach"))
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		return nil
+	}
+	log.V(5).Info("retry cfg", "cfg", cfg)
+	rule.Config.Retry = cfg
+	return nil
+}
+
+// EffectiveRetry merge the retrypolicy attached to ref with the retry set on the rule itself, per field.
+// the precedence is override of retrypolicy > retry of rule > default of retrypolicy.
+// return nil if there is no retry config at all.
+func EffectiveRetry(ref Ref, allPolicy []CommonPolicyAttachment, native *retry_t.RetryCr, log logr.Logger) (*retry_t.RetryCr, error) {
+	def, override := getDefaultAndOverrideConfig(ref, allPolicy, PolicyAttachmentFilterConfig{AllowRouteKind: RetryRouteKind}, log)
+	if def == nil && override == nil {
+		return native, nil
+	}
+	ret := RetryPolicyConfig{}
+	if def != nil {
+		if err := ret.FromConfig(def); err != nil {
+			return nil, err
+		}
+	}
+	if native != nil {
+		nativeCfg := RetryPolicyConfig(*native)
+		if err := ret.FromConfig(nativeCfg.IntoConfig()); err != nil {
+			return nil, err
+		}
+	}
+	if override != nil {
+		if err := ret.FromConfig(override); err != nil {
+			return nil, err
+		}
+	}
+	cr := retry_t.RetryCr(ret)
+	if err := retry.Valid(&cr); err != nil {
+		return nil, err
+	}
+	return &cr, nil
+}
+
+func getAllRetryPolicy(drv *driver.KubernetesDriver) ([]CommonPolicyAttachment, error) {
+	lister := drv.Informers.Alb.RetryPolicy.Lister()
+	retrypolicies, err := lister.List(labels.Everything())
+	ret := []CommonPolicyAttachment{}
+	for _, p := range retrypolicies {
+		ret = append(ret, RetryPolicyWrapper(*p))
+	}
+	return ret, err
+}
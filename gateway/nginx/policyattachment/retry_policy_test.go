@@ -0,0 +1,72 @@
+package policyattachment
+
+import (
+	"testing"
+
+	"alauda.io/alb2/gateway"
+	. "alauda.io/alb2/gateway/nginx/policyattachment/types"
+	gatewayPolicy "alauda.io/alb2/pkg/apis/alauda/gateway/v1alpha1"
+	retry_t "alauda.io/alb2/pkg/controller/ext/retry/types"
+	"github.com/go-logr/logr"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+func TestRetryPolicyConfig(t *testing.T) {
+	cfg := RetryPolicyConfig{Attempts: pointy.Uint(3), On: []string{"error", "http_502"}, RetryNonIdempotent: pointy.Bool(false)}
+	ret := RetryPolicyConfig{}
+	assert.NoError(t, ret.FromConfig(cfg.IntoConfig()))
+	assert.Equal(t, cfg, ret)
+}
+
+func TestEffectiveRetry(t *testing.T) {
+	route := &gateway.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "r1"}}
+	ref := Ref{
+		Listener: &Listener{Listener: gv1.Listener{Name: "http"}, Gateway: client.ObjectKey{Namespace: "default", Name: "g1"}},
+		Route:    route,
+	}
+	policy := func(name string, kind string, target string, def, override *gatewayPolicy.RetryPolicyConfig) CommonPolicyAttachment {
+		return RetryPolicyWrapper(gatewayPolicy.RetryPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayPolicy.RetryPolicySpec{
+				TargetRef: gatewayPolicy.PolicyTargetReference{Group: "gateway.networking.k8s.io", Kind: kind, Name: target, Namespace: "default"},
+				Default:   def,
+				Override:  override,
+			},
+		})
+	}
+
+	cr, err := EffectiveRetry(ref, nil, nil, logr.Discard())
+	assert.NoError(t, err)
+	assert.Nil(t, cr)
+
+	all := []CommonPolicyAttachment{
+		policy("p1", gateway.GatewayKind, "g1", &gatewayPolicy.RetryPolicyConfig{Attempts: pointy.Uint(3), On: []string{"error"}}, nil),
+	}
+	cr, err = EffectiveRetry(ref, all, nil, logr.Discard())
+	assert.NoError(t, err)
+	assert.Equal(t, &retry_t.RetryCr{Attempts: pointy.Uint(3), On: []string{"error"}}, cr)
+
+	// override of route wins over default of gateway
+	all = append(all, policy("p2", gateway.HttpRouteKind, "r1", nil, &gatewayPolicy.RetryPolicyConfig{On: []string{"http_503"}, PerTryTimeoutMs: pointy.Uint(500)}))
+	cr, err = EffectiveRetry(ref, all, nil, logr.Discard())
+	assert.NoError(t, err)
+	assert.Equal(t, &retry_t.RetryCr{Attempts: pointy.Uint(3), On: []string{"http_503"}, PerTryTimeoutMs: pointy.Uint(500)}, cr)
+
+	// tcproute could not be the target of retrypolicy
+	all = []CommonPolicyAttachment{
+		policy("p3", gateway.TcpRouteKind, "r1", &gatewayPolicy.RetryPolicyConfig{Attempts: pointy.Uint(3)}, nil),
+	}
+	cr, err = EffectiveRetry(ref, all, nil, logr.Discard())
+	assert.NoError(t, err)
+	assert.Nil(t, cr)
+
+	all = []CommonPolicyAttachment{
+		policy("p4", gateway.GatewayKind, "g1", &gatewayPolicy.RetryPolicyConfig{On: []string{"http_501"}}, nil),
+	}
+	_, err = EffectiveRetry(ref, all, nil, logr.Discard())
+	assert.Error(t, err)
+}
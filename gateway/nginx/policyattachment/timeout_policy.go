@@ -2,6 +2,8 @@ package policyattachment
 
 import (
 	"context"
+	"fmt"
+	"time"
 
 	. "alauda.io/alb2/controller/types"
 	"alauda.io/alb2/driver"
@@ -10,8 +12,10 @@ import (
 	timeout_t "alauda.io/alb2/pkg/controller/ext/timeout/types"
 	"alauda.io/alb2/utils/log"
 	"github.com/go-logr/logr"
+	"github.com/openlyinc/pointy"
 	"k8s.io/apimachinery/pkg/labels"
 	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 type TimeoutPolicyConfig gatewayPolicy.TimeoutPolicyConfig
@@ -54,6 +58,9 @@ func (tc *TimeoutPolicyConfig) IntoConfig() PolicyAttachmentConfig {
 	if tc.ProxyReadTimeoutMs != nil {
 		ret["proxy_read_timeout_ms"] = *tc.ProxyReadTimeoutMs
 	}
+	if tc.RequestTimeoutMs != nil {
+		ret["request_timeout_ms"] = *tc.RequestTimeoutMs
+	}
 	return ret
 }
 
@@ -61,6 +68,7 @@ func (tc *TimeoutPolicyConfig) FromConfig(m PolicyAttachmentConfig) error {
 	connect := m["proxy_connect_timeout_ms"]
 	read := m["proxy_read_timeout_ms"]
 	send := m["proxy_send_timeout_ms"]
+	request := m["request_timeout_ms"]
 	if connect != nil {
 		connect := connect.(uint)
 		log.L().Info("onrule connect not nil", "c", connect)
@@ -74,6 +82,10 @@ func (tc *TimeoutPolicyConfig) FromConfig(m PolicyAttachmentConfig) error {
 		send := send.(uint)
 		tc.ProxySendTimeoutMs = &send
 	}
+	if request != nil {
+		request := request.(uint)
+		tc.RequestTimeoutMs = &request
+	}
 	return nil
 }
 
@@ -97,24 +109,113 @@ func NewTimeoutPolicy(ctx context.Context, log logr.Logger, drv *driver.Kubernet
 	}, nil
 }
 
+// the timeout of a rule is resolved per field, the precedence is
+// override of timeoutpolicy > timeouts of httproute rule > default of timeoutpolicy.
 func (t *TimeoutPolicy) OnRule(ft *Frontend, rule *InternalRule, ref Ref) error {
 	log := t.log.V(3).WithName("onrule").WithValues("ref", ref.Describe())
 	log.V(5).Info("len of all timeout policy", "len", len(t.allPolicy))
-	config := getConfig(ref, t.allPolicy, PolicyAttachmentFilterConfig{AllowRouteKind: ALLRouteKind}, t.log.WithName("merge-attach"))
-	if config == nil {
+	timeout, err := EffectiveTimeout(ref, t.allPolicy, rule.Config.Timeout, t.log.WithName("merge-attach"))
+	if err != nil {
+		return err
+	}
+	if timeout == nil {
 		t.log.V(3).Info("could not find timeoutconfig ignore")
 		return nil
 	}
+	log.V(5).Info("timeout cfg ", "cfg", timeout)
+	rule.Config.Timeout = timeout
+	return nil
+}
 
-	timeout := TimeoutPolicyConfig{}
-	err := timeout.FromConfig(config)
+// EffectiveTimeout merge the timeoutpolicy attached to ref with the timeout set on the rule itself.
+// return nil if there is no timeout config at all.
+func EffectiveTimeout(ref Ref, allPolicy []CommonPolicyAttachment, native *timeout_t.TimeoutCr, log logr.Logger) (*timeout_t.TimeoutCr, error) {
+	def, override := getDefaultAndOverrideConfig(ref, allPolicy, PolicyAttachmentFilterConfig{AllowRouteKind: ALLRouteKind}, log)
+	if def == nil && override == nil {
+		return native, nil
+	}
+	ret := TimeoutPolicyConfig{}
+	if def != nil {
+		if err := ret.FromConfig(def); err != nil {
+			return nil, err
+		}
+	}
+	if native != nil {
+		if native.ProxyConnectTimeoutMs != nil {
+			ret.ProxyConnectTimeoutMs = native.ProxyConnectTimeoutMs
+		}
+		if native.ProxySendTimeoutMs != nil {
+			ret.ProxySendTimeoutMs = native.ProxySendTimeoutMs
+		}
+		if native.ProxyReadTimeoutMs != nil {
+			ret.ProxyReadTimeoutMs = native.ProxyReadTimeoutMs
+		}
+		if native.RequestTimeoutMs != nil {
+			ret.RequestTimeoutMs = native.RequestTimeoutMs
+		}
+	}
+	if override != nil {
+		if err := ret.FromConfig(override); err != nil {
+			return nil, err
+		}
+	}
+	timeout := timeout_t.TimeoutCr(ret)
+	return &timeout, nil
+}
+
+// "0s" means no timeout in gateway api, but nginx could not disable the timeouts of upstream,
+// so a large one (about 24 days) is used, instead of falling back to the default 60s of nginx.
+const noTimeoutMs uint = 1<<31 - 1
+
+// HttpRouteTimeoutsToTimeoutCr translate the timeouts of httproute rule to timeout cr.
+// request is the deadline of the whole request (including retries), it is enforced in the balancer by limiting the timeouts of each try to the time left.
+// backendRequest is the timeout of each try, it is mapped to proxy_send_timeout and proxy_read_timeout. if it is not set, request is used instead.
+func HttpRouteTimeoutsToTimeoutCr(timeouts *gv1.HTTPRouteTimeouts) (*timeout_t.TimeoutCr, error) {
+	if timeouts == nil {
+		return nil, nil
+	}
+	request, err := gatewayDurationToMs(timeouts.Request)
 	if err != nil {
-		return err
+		return nil, fmt.Errorf("invalid request timeout %w", err)
 	}
-	log.V(5).Info("timeout cfg ", "cfg", timeout)
-	timeoutCfg := timeout_t.TimeoutCr(timeout)
-	rule.Config.Timeout = &timeoutCfg
-	return nil
+	backend, err := gatewayDurationToMs(timeouts.BackendRequest)
+	if err != nil {
+		return nil, fmt.Errorf("invalid backendRequest timeout %w", err)
+	}
+	if request != nil && backend != nil && *request != 0 && *backend > *request {
+		return nil, fmt.Errorf("backendRequest timeout %s should not be greater than request timeout %s", *timeouts.BackendRequest, *timeouts.Request)
+	}
+	if request == nil && backend == nil {
+		return nil, nil
+	}
+	ret := &timeout_t.TimeoutCr{}
+	if request != nil && *request != 0 {
+		ret.RequestTimeoutMs = pointy.Uint(*request)
+	}
+	try := request
+	if backend != nil {
+		try = backend
+	}
+	if *try == 0 {
+		try = pointy.Uint(noTimeoutMs)
+	}
+	ret.ProxySendTimeoutMs = pointy.Uint(*try)
+	ret.ProxyReadTimeoutMs = pointy.Uint(*try)
+	return ret, nil
+}
+
+func gatewayDurationToMs(d *gv1.Duration) (*uint, error) {
+	if d == nil {
+		return nil, nil
+	}
+	duration, err := time.ParseDuration(string(*d))
+	if err != nil {
+		return nil, err
+	}
+	if duration < 0 {
+		return nil, fmt.Errorf("negative duration %s", *d)
+	}
+	return pointy.Uint(uint(duration.Milliseconds())), nil
 }
 
 func getAllTimeoutPolicy(drv *driver.KubernetesDriver) ([]CommonPolicyAttachment, error) {
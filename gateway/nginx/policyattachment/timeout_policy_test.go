@@ -4,9 +4,16 @@ import (
 	"encoding/json"
 	"testing"
 
+	"alauda.io/alb2/gateway"
+	. "alauda.io/alb2/gateway/nginx/policyattachment/types"
 	gatewayPolicy "alauda.io/alb2/pkg/apis/alauda/gateway/v1alpha1"
+	timeout_t "alauda.io/alb2/pkg/controller/ext/timeout/types"
+	"github.com/go-logr/logr"
 	"github.com/openlyinc/pointy"
 	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
 )
 
 func TestIPolicyAttachmentConfig(t *testing.T) {
@@ -24,4 +31,104 @@ func TestIPolicyAttachmentConfig(t *testing.T) {
 	assert.Nil(t, ret.ProxyConnectTimeoutMs)
 	assert.Equal(t, *ret.ProxyReadTimeoutMs, uint(10))
 	assert.Nil(t, ret.ProxySendTimeoutMs)
+	assert.Nil(t, ret.RequestTimeoutMs)
+
+	timeout1.RequestTimeoutMs = pointy.Uint(1000)
+	ret = TimeoutPolicyConfig{}
+	ret.FromConfig(timeout1.IntoConfig())
+	assert.Equal(t, uint(1000), *ret.RequestTimeoutMs)
+}
+
+func TestHttpRouteTimeoutsToTimeoutCr(t *testing.T) {
+	d := func(s string) *gv1.Duration {
+		x := gv1.Duration(s)
+		return &x
+	}
+	cr, err := HttpRouteTimeoutsToTimeoutCr(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, cr)
+
+	// request is the deadline of the whole request, and the timeout of each try if backendRequest is not set.
+	cr, err = HttpRouteTimeoutsToTimeoutCr(&gv1.HTTPRouteTimeouts{Request: d("10s")})
+	assert.NoError(t, err)
+	assert.Nil(t, cr.ProxyConnectTimeoutMs)
+	assert.Equal(t, uint(10000), *cr.RequestTimeoutMs)
+	assert.Equal(t, uint(10000), *cr.ProxyReadTimeoutMs)
+	assert.Equal(t, uint(10000), *cr.ProxySendTimeoutMs)
+
+	cr, err = HttpRouteTimeoutsToTimeoutCr(&gv1.HTTPRouteTimeouts{Request: d("10s"), BackendRequest: d("500ms")})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10000), *cr.RequestTimeoutMs)
+	assert.Equal(t, uint(500), *cr.ProxyReadTimeoutMs)
+	assert.Equal(t, uint(500), *cr.ProxySendTimeoutMs)
+
+	// only the timeout of each try.
+	cr, err = HttpRouteTimeoutsToTimeoutCr(&gv1.HTTPRouteTimeouts{BackendRequest: d("2s")})
+	assert.NoError(t, err)
+	assert.Nil(t, cr.RequestTimeoutMs)
+	assert.Equal(t, uint(2000), *cr.ProxyReadTimeoutMs)
+
+	// 0s means no timeout, it should not fall back to the default timeout of nginx.
+	cr, err = HttpRouteTimeoutsToTimeoutCr(&gv1.HTTPRouteTimeouts{Request: d("0s")})
+	assert.NoError(t, err)
+	assert.Nil(t, cr.RequestTimeoutMs)
+	assert.Equal(t, noTimeoutMs, *cr.ProxyReadTimeoutMs)
+	assert.Equal(t, noTimeoutMs, *cr.ProxySendTimeoutMs)
+
+	cr, err = HttpRouteTimeoutsToTimeoutCr(&gv1.HTTPRouteTimeouts{Request: d("10s"), BackendRequest: d("0s")})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10000), *cr.RequestTimeoutMs)
+	assert.Equal(t, noTimeoutMs, *cr.ProxyReadTimeoutMs)
+
+	cr, err = HttpRouteTimeoutsToTimeoutCr(&gv1.HTTPRouteTimeouts{Request: d("0s"), BackendRequest: d("3s")})
+	assert.NoError(t, err)
+	assert.Nil(t, cr.RequestTimeoutMs)
+	assert.Equal(t, uint(3000), *cr.ProxyReadTimeoutMs)
+
+	_, err = HttpRouteTimeoutsToTimeoutCr(&gv1.HTTPRouteTimeouts{Request: d("1s"), BackendRequest: d("2s")})
+	assert.Error(t, err)
+	_, err = HttpRouteTimeoutsToTimeoutCr(&gv1.HTTPRouteTimeouts{Request: d("1x")})
+	assert.Error(t, err)
+}
+
+func TestEffectiveTimeout(t *testing.T) {
+	route := &gateway.HTTPRoute{ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: "r1"}}
+	ref := Ref{
+		Listener: &Listener{Listener: gv1.Listener{Name: "http"}, Gateway: client.ObjectKey{Namespace: "default", Name: "g1"}},
+		Route:    route,
+	}
+	policy := func(name string, kind string, target string, def, override *gatewayPolicy.TimeoutPolicyConfig) CommonPolicyAttachment {
+		return TimeoutPolicyWrapper(gatewayPolicy.TimeoutPolicy{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name},
+			Spec: gatewayPolicy.TimeoutPolicySpec{
+				TargetRef: gatewayPolicy.PolicyTargetReference{Group: "gateway.networking.k8s.io", Kind: kind, Name: target, Namespace: "default"},
+				Default:   def,
+				Override:  override,
+			},
+		})
+	}
+	native := &timeout_t.TimeoutCr{ProxyReadTimeoutMs: pointy.Uint(1000), ProxySendTimeoutMs: pointy.Uint(1000)}
+
+	// no policy, use the route timeouts
+	cr, err := EffectiveTimeout(ref, nil, native, logr.Discard())
+	assert.NoError(t, err)
+	assert.Equal(t, native, cr)
+
+	// route timeouts win over default
+	all := []CommonPolicyAttachment{
+		policy("p1", gateway.GatewayKind, "g1", &gatewayPolicy.TimeoutPolicyConfig{ProxyReadTimeoutMs: pointy.Uint(3000), ProxyConnectTimeoutMs: pointy.Uint(3000)}, nil),
+	}
+	cr, err = EffectiveTimeout(ref, all, native, logr.Discard())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(1000), *cr.ProxyReadTimeoutMs)
+	assert.Equal(t, uint(1000), *cr.ProxySendTimeoutMs)
+	assert.Equal(t, uint(3000), *cr.ProxyConnectTimeoutMs)
+
+	// override win over route timeouts
+	all = append(all, policy("p2", gateway.HttpRouteKind, "r1", nil, &gatewayPolicy.TimeoutPolicyConfig{ProxyReadTimeoutMs: pointy.Uint(5000)}))
+	cr, err = EffectiveTimeout(ref, all, native, logr.Discard())
+	assert.NoError(t, err)
+	assert.Equal(t, uint(5000), *cr.ProxyReadTimeoutMs)
+	assert.Equal(t, uint(1000), *cr.ProxySendTimeoutMs)
+	assert.Equal(t, uint(3000), *cr.ProxyConnectTimeoutMs)
 }
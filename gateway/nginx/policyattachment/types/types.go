@@ -37,9 +37,10 @@ func (r *Ref) Describe() string {
 
 type Listener struct {
 	gatewayType.Listener
-	Gateway    client.ObjectKey
-	Generation int64
-	CreateTime time.Time
+	Gateway      client.ObjectKey
+	GatewayClass string
+	Generation   int64
+	CreateTime   time.Time
 }
 
 type PolicyAttachmentConfig map[string]interface{}
@@ -2,6 +2,7 @@ package nginx
 
 import (
 	"fmt"
+	"sort"
 
 	"alauda.io/alb2/controller/modules"
 	ctltype "alauda.io/alb2/controller/types"
@@ -36,43 +37,27 @@ func (t *TcpProtocolTranslate) TransLate(ls []*ngxtype.Listener, ftMap ngxtype.F
 	for _, l := range ls {
 		port := l.Port
 		log := t.log.WithValues("listener", l.Name, "gateway", l.Gateway, "port", l.Port)
-		var route gateway.CommonRoute
-		var tcpRoute *gateway.TCPRoute
-		// filter invalid listener
-		{
-			if l.Protocol != gv1.TCPProtocolType {
-				continue
-			}
-			if len(l.Routes) == 0 {
-				log.Info("could not found valid route", "error", true)
-				continue
-			}
-			if len(l.Routes) > 1 {
-				log.Info("tcp has more than one route", "port", port)
-				continue
-			}
-			route = l.Routes[0]
-			tcpRouteNew, ok := route.(*gateway.TCPRoute)
-			if !ok {
-				log.Info("only tcp route could attach to tcp listener")
-				continue
-			}
-			tcpRoute = tcpRouteNew
-			if len(tcpRoute.Spec.Rules) != 1 {
-				log.Info("route rule more than 1")
-				continue
-			}
+		if l.Protocol != gv1.TCPProtocolType {
+			continue
 		}
+		routes := pickTcpRoutes(l.Routes)
+		if len(routes) == 0 {
+			log.Info("could not found valid route", "error", true)
+			continue
+		}
+		svcs, err := tcpRoutesToService(routes)
+		if err != nil {
+			log.Error(err, "invalid backend refs")
+			continue
+		}
+		// there is no way to match tcp traffic, so backendRefs of all routes are merged into one rule and weighted together.
+		// the oldest route is used as the source of this rule.
+		tcpRoute := routes[0]
 
 		ft := &ctltype.Frontend{
 			Port:     albv1.PortNumber(port),
 			Protocol: albv1.FtProtocolTCP,
 		}
-		// TODO we do not support multiple tcp rules
-		svcs, err := utils.BackendRefsToService(tcpRoute.Spec.Rules[0].BackendRefs)
-		if err != nil {
-			return nil
-		}
 		name := fmt.Sprintf("%v-%v-%v", port, tcpRoute.Namespace, tcpRoute.Name)
 		backendGroup := &ctltype.BackendGroup{
 			Name: name,
@@ -91,12 +76,13 @@ func (t *TcpProtocolTranslate) TransLate(ls []*ngxtype.Listener, ftMap ngxtype.F
 		if t.handle != nil {
 			ref := gatewayPolicyType.Ref{
 				Listener: &gatewayPolicyType.Listener{
-					Listener:   l.Listener,
-					Gateway:    l.Gateway,
-					Generation: l.Generation,
-					CreateTime: l.CreateTime,
+					Listener:     l.Listener,
+					Gateway:      l.Gateway,
+					GatewayClass: l.GatewayClass,
+					Generation:   l.Generation,
+					CreateTime:   l.CreateTime,
 				},
-				Route:      route,
+				Route:      tcpRoute,
 				RuleIndex:  0,
 				MatchIndex: 0,
 			}
@@ -110,3 +96,40 @@ func (t *TcpProtocolTranslate) TransLate(ls []*ngxtype.Listener, ftMap ngxtype.F
 	}
 	return nil
 }
+
+// tcp routes attached to listener, sorted by creation time and name.
+func pickTcpRoutes(routes []gateway.CommonRoute) []*gateway.TCPRoute {
+	ret := []*gateway.TCPRoute{}
+	for _, r := range routes {
+		tcpRoute, ok := r.(*gateway.TCPRoute)
+		if !ok {
+			continue
+		}
+		ret = append(ret, tcpRoute)
+	}
+	sort.Slice(ret, func(i, j int) bool {
+		ti := ret[i].CreationTimestamp
+		tj := ret[j].CreationTimestamp
+		if !ti.Equal(&tj) {
+			return ti.Before(&tj)
+		}
+		return gateway.GetObjectKey(ret[i]) < gateway.GetObjectKey(ret[j])
+	})
+	return ret
+}
+
+// weights of backendRefs take effect across all rules of all routes.
+func tcpRoutesToService(routes []*gateway.TCPRoute) ([]*ctltype.BackendService, error) {
+	refs := []gv1.BackendRef{}
+	for _, r := range routes {
+		for _, rule := range r.Spec.Rules {
+			refs = append(refs, rule.BackendRefs...)
+		}
+	}
+	svcs, err := utils.BackendRefsToService(refs)
+	if err != nil {
+		return nil, err
+	}
+	utils.NormalizeServiceWeight(svcs)
+	return svcs, nil
+}
@@ -0,0 +1,62 @@
+package nginx
+
+import (
+	"testing"
+	"time"
+
+	"alauda.io/alb2/gateway"
+	"alauda.io/alb2/gateway/nginx/utils"
+	"github.com/openlyinc/pointy"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+	gv1a2 "sigs.k8s.io/gateway-api/apis/v1alpha2"
+)
+
+func TestTcpRoutesWeight(t *testing.T) {
+	backend := func(name string, weight int32) gv1.BackendRef {
+		ns := gv1.Namespace("default")
+		port := gv1.PortNumber(3306)
+		return gv1.BackendRef{
+			BackendObjectReference: gv1.BackendObjectReference{Name: gv1.ObjectName(name), Namespace: &ns, Port: &port},
+			Weight:                 pointy.Int32(weight),
+		}
+	}
+	route := func(name string, create time.Time, refs ...gv1.BackendRef) *gateway.TCPRoute {
+		return &gateway.TCPRoute{
+			ObjectMeta: metav1.ObjectMeta{Namespace: "default", Name: name, CreationTimestamp: metav1.NewTime(create)},
+			Spec: gv1a2.TCPRouteSpec{
+				Rules: []gv1a2.TCPRouteRule{{BackendRefs: refs}},
+			},
+		}
+	}
+	now := time.Now()
+	stable := route("stable", now.Add(-time.Hour), backend("proxy-v1", 900))
+	canary := route("canary", now, backend("proxy-v2", 100))
+	routes := pickTcpRoutes([]gateway.CommonRoute{canary, &gateway.UDPRoute{}, stable})
+	assert.Equal(t, 2, len(routes))
+	assert.Equal(t, "stable", routes[0].Name)
+
+	svcs, err := tcpRoutesToService(routes)
+	assert.NoError(t, err)
+	assert.Equal(t, 2, len(svcs))
+	assert.Equal(t, "proxy-v1", svcs[0].ServiceName)
+	assert.Equal(t, 90, svcs[0].Weight)
+	assert.Equal(t, "proxy-v2", svcs[1].ServiceName)
+	assert.Equal(t, 10, svcs[1].Weight)
+
+	// weight under 100 is kept as it is.
+	svcs, err = tcpRoutesToService([]*gateway.TCPRoute{route("a", now, backend("a", 1), backend("b", 3), backend("c", 0))})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{1, 3, 0}, []int{svcs[0].Weight, svcs[1].Weight, svcs[2].Weight})
+
+	// non-zero weight keeps at least 1.
+	svcs, err = tcpRoutesToService([]*gateway.TCPRoute{route("a", now, backend("a", 10000), backend("b", 1))})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{100, 1}, []int{svcs[0].Weight, svcs[1].Weight})
+
+	// only the weights of tcproute are scaled, the shared translation of other routes keeps them.
+	svcs, err = utils.BackendRefsToService([]gv1.BackendRef{backend("a", 900), backend("b", 100)})
+	assert.NoError(t, err)
+	assert.Equal(t, []int{900, 100}, []int{svcs[0].Weight, svcs[1].Weight})
+}
@@ -0,0 +1,156 @@
+package nginx
+
+import (
+	"fmt"
+	"sort"
+
+	"alauda.io/alb2/controller/modules"
+	ctltype "alauda.io/alb2/controller/types"
+	"alauda.io/alb2/driver"
+	"alauda.io/alb2/gateway"
+	httproute "alauda.io/alb2/gateway/nginx/http"
+	gatewayPolicyType "alauda.io/alb2/gateway/nginx/policyattachment/types"
+	ngxtype "alauda.io/alb2/gateway/nginx/types"
+	"alauda.io/alb2/gateway/nginx/utils"
+	"github.com/go-logr/logr"
+	"github.com/samber/lo"
+
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// tls listener in passthrough mode does not terminate tls, we peek the sni from client hello via ssl_preread,
+// and proxy the whole tcp stream to backend. so multiple tls route could share the same port.
+type TlsProtocolTranslate struct {
+	drv    *driver.KubernetesDriver
+	log    logr.Logger
+	handle gatewayPolicyType.PolicyAttachmentHandle
+}
+
+func NewTlsProtocolTranslate(drv *driver.KubernetesDriver, log logr.Logger) TlsProtocolTranslate {
+	return TlsProtocolTranslate{drv: drv, log: log}
+}
+
+func (t *TlsProtocolTranslate) SetPolicyAttachmentHandle(handle gatewayPolicyType.PolicyAttachmentHandle) {
+	t.handle = handle
+}
+
+type tlsRouteCtx struct {
+	listener *ngxtype.Listener
+	route    *gateway.TLSRoute
+}
+
+func (t *TlsProtocolTranslate) TransLate(ls []*ngxtype.Listener, ftMap ngxtype.FtMap) error {
+	log := t.log.WithName("tls")
+	portMap := map[gv1.PortNumber][]tlsRouteCtx{}
+	for _, l := range ls {
+		if l.Protocol != gv1.TLSProtocolType {
+			continue
+		}
+		if gateway.TlsMode(l.TLS) != gv1.TLSModePassthrough {
+			// the listener is marked as not accepted by the gateway controller.
+			log.Info("only passthrough mode tls listener is supported", "listener", l.Name, "gateway", l.Gateway)
+			continue
+		}
+		for _, r := range l.Routes {
+			tlsRoute, ok := r.(*gateway.TLSRoute)
+			if !ok {
+				continue
+			}
+			portMap[l.Port] = append(portMap[l.Port], tlsRouteCtx{listener: l, route: tlsRoute})
+		}
+	}
+
+	for port, ctxList := range portMap {
+		if ftMap.HasFt(string(albv1.FtProtocolTCP), albv1.PortNumber(port)) {
+			// should not happen, unless the status of tls route is not updated yet, see PortShareFilter of gateway controller.
+			log.Info("port already used by tcp route. ignore tls route", "port", port, "len", len(ctxList))
+			continue
+		}
+		// the oldest route wins when sni conflict.
+		sort.SliceStable(ctxList, func(i, j int) bool {
+			oi := ctxList[i].route.GetObject()
+			oj := ctxList[j].route.GetObject()
+			ti := oi.GetCreationTimestamp()
+			tj := oj.GetCreationTimestamp()
+			if !ti.Equal(&tj) {
+				return ti.Before(&tj)
+			}
+			return gateway.GetObjectKey(ctxList[i].route) < gateway.GetObjectKey(ctxList[j].route)
+		})
+
+		ft := &ctltype.Frontend{
+			Port:           albv1.PortNumber(port),
+			Protocol:       albv1.FtProtocolTCP,
+			TlsPassthrough: true,
+		}
+		for _, ctx := range ctxList {
+			rules, err := t.generateTlsRules(ctx)
+			if err != nil {
+				log.Error(err, "generate tls rule fail", "route", gateway.GetObjectKey(ctx.route))
+				continue
+			}
+			for _, rule := range rules {
+				if t.handle != nil {
+					ref := gatewayPolicyType.Ref{
+						Listener: &gatewayPolicyType.Listener{
+							Listener:     ctx.listener.Listener,
+							Gateway:      ctx.listener.Gateway,
+							GatewayClass: ctx.listener.GatewayClass,
+							Generation:   ctx.listener.Generation,
+							CreateTime:   ctx.listener.CreateTime,
+						},
+						Route:      ctx.route,
+						RuleIndex:  0,
+						MatchIndex: 0,
+					}
+					err = t.handle.OnRule(ft, rule, ref)
+					if err != nil {
+						log.Error(err, "onrule fail", "ref", ref.Describe())
+					}
+				}
+				ft.Rules = append(ft.Rules, rule)
+			}
+		}
+		if len(ft.Rules) == 0 {
+			log.V(2).Info("could not find rule. ignore this port", "port", port)
+			continue
+		}
+		ftMap.SetFt(string(ft.Protocol), ft.Port, ft)
+	}
+	return nil
+}
+
+// each hostname of tls route is a rule.
+func (t *TlsProtocolTranslate) generateTlsRules(ctx tlsRouteCtx) ([]*ctltype.InternalRule, error) {
+	route := ctx.route
+	if len(route.Spec.Rules) != 1 {
+		return nil, fmt.Errorf("tls route should have exactly one rule")
+	}
+	svcs, err := utils.BackendRefsToService(route.Spec.Rules[0].BackendRefs)
+	if err != nil {
+		return nil, err
+	}
+	hostnames := httproute.JoinHostnames((*string)(ctx.listener.Hostname), lo.Map(route.Spec.Hostnames, func(h gv1.Hostname, _ int) string { return string(h) }))
+	if len(hostnames) == 0 {
+		// match any sni
+		hostnames = []string{""}
+	}
+	rules := []*ctltype.InternalRule{}
+	for i, host := range hostnames {
+		name := fmt.Sprintf("%v-%v-%v-%v-tls-%v-%v-%d", ctx.listener.Port, ctx.listener.Gateway.Namespace, ctx.listener.Gateway.Name, ctx.listener.Name, route.Namespace, route.Name, i)
+		rule := &ctltype.InternalRule{}
+		rule.Type = ctltype.RuleTypeGateway
+		rule.Source = &albv1.Source{
+			Type:      modules.TypeTLSRoute,
+			Namespace: route.Namespace,
+			Name:      route.Name,
+		}
+		rule.RuleID = name
+		rule.SNI = host
+		rule.Services = svcs
+		rule.BackendGroup = &ctltype.BackendGroup{Name: name}
+		rules = append(rules, rule)
+	}
+	return rules, nil
+}
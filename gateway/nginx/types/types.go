@@ -15,10 +15,11 @@ import (
 
 type Listener struct {
 	gatewayType.Listener
-	Gateway    client.ObjectKey
-	Generation int64
-	CreateTime time.Time
-	Routes     []gateway.CommonRoute
+	Gateway      client.ObjectKey
+	GatewayClass string
+	Generation   int64
+	CreateTime   time.Time
+	Routes       []gateway.CommonRoute
 }
 
 type FtMap map[string]*types.Frontend
@@ -28,6 +29,12 @@ func (f FtMap) SetFt(protocol string, port albv1.PortNumber, ft *types.Frontend)
 	f[key] = ft
 }
 
+func (f FtMap) HasFt(protocol string, port albv1.PortNumber) bool {
+	key := fmt.Sprintf("%v:%v", protocol, port)
+	_, ok := f[key]
+	return ok
+}
+
 type GatewayAlbTranslate interface {
 	TransLate(ls []*Listener, ftMap FtMap) error
 }
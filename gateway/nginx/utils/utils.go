@@ -2,6 +2,7 @@ package utils
 
 import (
 	"fmt"
+	"math"
 
 	"alauda.io/alb2/utils"
 	gatewayType "sigs.k8s.io/gateway-api/apis/v1"
@@ -50,3 +51,29 @@ func BackendRefsToService(refs []gatewayType.BackendRef) ([]*BackendService, err
 	}
 	return svcs, nil
 }
+
+// weight of backendRef could be up to 1000000, but the weight of service is limited to 100.
+// scale the weights down while keeping the ratio between them, a service with non-zero weight keeps at least 1.
+func NormalizeServiceWeight(svcs []*BackendService) {
+	total := 0
+	max := 0
+	for _, svc := range svcs {
+		total += svc.Weight
+		if svc.Weight > max {
+			max = svc.Weight
+		}
+	}
+	if max <= 100 {
+		return
+	}
+	for _, svc := range svcs {
+		if svc.Weight == 0 {
+			continue
+		}
+		w := int(math.Round(float64(svc.Weight) * 100 / float64(total)))
+		if w == 0 {
+			w = 1
+		}
+		svc.Weight = w
+	}
+}
@@ -0,0 +1,92 @@
+package gateway
+
+import (
+	"encoding/json"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	gv1 "sigs.k8s.io/gateway-api/apis/v1"
+)
+
+// the sessionPersistence of HTTPRouteRule is added in gateway api v1.1.0, alb still uses v1.0.0 whose typed HTTPRoute drops it.
+// so those types are copied here, and the field is read from the raw httproute, see SessionPersistenceOfRules.
+
+type SessionPersistenceType string
+
+const (
+	CookieBasedSessionPersistence SessionPersistenceType = "Cookie"
+	HeaderBasedSessionPersistence SessionPersistenceType = "Header"
+)
+
+type CookieLifetimeType string
+
+const (
+	SessionCookieLifetimeType   CookieLifetimeType = "Session"
+	PermanentCookieLifetimeType CookieLifetimeType = "Permanent"
+)
+
+type SessionPersistence struct {
+	SessionName     *string                 `json:"sessionName,omitempty"`
+	AbsoluteTimeout *gv1.Duration           `json:"absoluteTimeout,omitempty"`
+	IdleTimeout     *gv1.Duration           `json:"idleTimeout,omitempty"`
+	Type            *SessionPersistenceType `json:"type,omitempty"`
+	CookieConfig    *CookieConfig           `json:"cookieConfig,omitempty"`
+}
+
+type CookieConfig struct {
+	LifetimeType *CookieLifetimeType `json:"lifetimeType,omitempty"`
+}
+
+type rawHttpRoute struct {
+	Spec struct {
+		Rules []struct {
+			SessionPersistence *SessionPersistence `json:"sessionPersistence,omitempty"`
+		} `json:"rules,omitempty"`
+	} `json:"spec"`
+}
+
+// SessionPersistenceOfRules return the sessionPersistence of each rule of the raw httproute, indexed by the rule index.
+func SessionPersistenceOfRules(obj *unstructured.Unstructured) ([]*SessionPersistence, error) {
+	data, err := json.Marshal(obj.Object)
+	if err != nil {
+		return nil, err
+	}
+	raw := rawHttpRoute{}
+	if err := json.Unmarshal(data, &raw); err != nil {
+		return nil, err
+	}
+	ret := make([]*SessionPersistence, len(raw.Spec.Rules))
+	for i, r := range raw.Spec.Rules {
+		ret[i] = r.SessionPersistence
+	}
+	return ret, nil
+}
+
+// TrimHttpRouteToSessionPersistence keep the metadata and the sessionPersistence of rules only, it is the transform of the raw httproute informer.
+// the index of rules is kept, since the sessionPersistence is looked up by the rule index.
+func TrimHttpRouteToSessionPersistence(obj interface{}) (interface{}, error) {
+	u, ok := obj.(*unstructured.Unstructured)
+	if !ok {
+		return obj, nil
+	}
+	rules, _, _ := unstructured.NestedSlice(u.Object, "spec", "rules")
+	trimmed := []interface{}{}
+	for _, r := range rules {
+		rule := map[string]interface{}{}
+		if m, ok := r.(map[string]interface{}); ok && m["sessionPersistence"] != nil {
+			rule["sessionPersistence"] = m["sessionPersistence"]
+		}
+		trimmed = append(trimmed, rule)
+	}
+	ret := &unstructured.Unstructured{Object: map[string]interface{}{
+		"apiVersion": u.GetAPIVersion(),
+		"kind":       u.GetKind(),
+		"metadata": map[string]interface{}{
+			"name":            u.GetName(),
+			"namespace":       u.GetNamespace(),
+			"resourceVersion": u.GetResourceVersion(),
+			"generation":      u.GetGeneration(),
+		},
+		"spec": map[string]interface{}{"rules": trimmed},
+	}}
+	return ret, nil
+}
@@ -2,6 +2,7 @@ package gateway
 
 import (
 	"fmt"
+	"reflect"
 	"strings"
 
 	"alauda.io/alb2/config"
@@ -51,11 +52,37 @@ func (r *TLSRoute) GetObject() client.Object {
 	return (*gv1a2t.TLSRoute)(r)
 }
 
+type GRPCRoute gv1a2t.GRPCRoute
+
+func (r *GRPCRoute) GetSpec() gv1.CommonRouteSpec {
+	return r.Spec.CommonRouteSpec
+}
+
+func (r *GRPCRoute) GetObject() client.Object {
+	return (*gv1a2t.GRPCRoute)(r)
+}
+
 type CommonRoute interface {
 	GetSpec() gv1.CommonRouteSpec
 	GetObject() client.Object
 }
 
+func GetRouteKind(r CommonRoute) string {
+	switch r.(type) {
+	case *HTTPRoute:
+		return HttpRouteKind
+	case *TCPRoute:
+		return TcpRouteKind
+	case *TLSRoute:
+		return TlsRouteKind
+	case *UDPRoute:
+		return UdpRouteKind
+	case *GRPCRoute:
+		return GrpcRouteKind
+	}
+	return ""
+}
+
 func IsRefsToGateway(refs []gv1.ParentReference, gateway client.ObjectKey) bool {
 	for _, ref := range refs {
 		if IsRefToGateway(ref, gateway) {
@@ -112,3 +139,34 @@ func SameProtocol(p1, p2 gv1.ProtocolType) bool {
 	p2Str := string(p2)
 	return strings.EqualFold(p1Str, p2Str)
 }
+
+// TlsMode return the tls mode of listener, it defaults to Terminate as gateway api does.
+func TlsMode(tls *gv1.GatewayTLSConfig) gv1.TLSModeType {
+	if tls == nil || tls.Mode == nil {
+		return gv1.TLSModeTerminate
+	}
+	return *tls.Mode
+}
+
+// backends of a rule share the same policy, so backend level filters could only be applied on the rule,
+// when all backends have the same filters. only header modifier filters are supported at backend level.
+// httproute which backend filters could not be applied is rejected by the HttpRouteFilter of gateway controller.
+func PickHttpBackendFilters(refs []gv1.HTTPBackendRef) ([]gv1.HTTPRouteFilter, error) {
+	if len(refs) == 0 {
+		return nil, nil
+	}
+	filters := refs[0].Filters
+	for _, r := range refs[1:] {
+		if !reflect.DeepEqual(filters, r.Filters) {
+			return nil, fmt.Errorf("backends with different filters are not supported")
+		}
+	}
+	ret := []gv1.HTTPRouteFilter{}
+	for _, f := range filters {
+		if f.Type != gv1.HTTPRouteFilterRequestHeaderModifier && f.Type != gv1.HTTPRouteFilterResponseHeaderModifier {
+			return nil, fmt.Errorf("backend filter %v is not supported", f.Type)
+		}
+		ret = append(ret, f)
+	}
+	return ret, nil
+}
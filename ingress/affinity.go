@@ -0,0 +1,81 @@
+package ingress
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	alb2v1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	networkingv1 "k8s.io/api/networking/v1"
+)
+
+var validSameSite = map[string]string{
+	"none":   "None",
+	"lax":    "Lax",
+	"strict": "Strict",
+}
+
+// SessionAffinity is the session affinity annotations of a ingress, it is applied to the service group of rule and default backend.
+// NOTE: only the cookie affinity is supported by ingress-nginx, and the affinity-mode is ignored, alb always works like the balanced mode.
+type SessionAffinity struct {
+	Policy    string
+	Attribute string
+	Cookie    *alb2v1.SessionAffinityCookie
+}
+
+// ParseSessionAffinity return nil if the ingress does not have affinity annotation.
+func ParseSessionAffinity(ing *networkingv1.Ingress) (*SessionAffinity, error) {
+	annotations := ing.GetAnnotations()
+	policy := strings.ToLower(strings.TrimSpace(annotations[ALBAffinityAnnotation]))
+	if policy == "" {
+		return nil, nil
+	}
+	if policy != AffinityCookie {
+		return nil, fmt.Errorf("unsupported affinity %s", policy)
+	}
+	name := strings.TrimSpace(annotations[ALBSessionCookieNameAnnotation])
+	if name == "" {
+		name = DefaultSessionCookieName
+	}
+	cookie := &alb2v1.SessionAffinityCookie{
+		Path:   strings.TrimSpace(annotations[ALBSessionCookiePathAnnotation]),
+		Domain: strings.TrimSpace(annotations[ALBSessionCookieDomainAnnotation]),
+	}
+	// both of max-age and expires are in seconds, max-age takes precedence.
+	for _, key := range []string{ALBSessionCookieMaxAgeAnnotation, ALBSessionCookieExpiresAnnotation} {
+		v := strings.TrimSpace(annotations[key])
+		if v == "" {
+			continue
+		}
+		age, err := strconv.Atoi(v)
+		if err != nil || age < 0 {
+			return nil, fmt.Errorf("invalid %s %s", key, v)
+		}
+		cookie.MaxAge = &age
+		break
+	}
+	if v := strings.TrimSpace(annotations[ALBSessionCookieSameSiteAnnotation]); v != "" {
+		samesite, ok := validSameSite[strings.ToLower(v)]
+		if !ok {
+			return nil, fmt.Errorf("invalid session cookie samesite %s", v)
+		}
+		cookie.SameSite = samesite
+	}
+	if v := strings.TrimSpace(annotations[ALBSessionCookieSecureAnnotation]); v != "" {
+		secure, err := strconv.ParseBool(v)
+		if err != nil {
+			return nil, fmt.Errorf("invalid session cookie secure %s", v)
+		}
+		cookie.Secure = &secure
+	}
+	return &SessionAffinity{Policy: policy, Attribute: name, Cookie: cookie}, nil
+}
+
+func (a *SessionAffinity) Apply(sg *alb2v1.ServiceGroup) {
+	if a == nil || sg == nil {
+		return
+	}
+	sg.SessionAffinityPolicy = a.Policy
+	sg.SessionAffinityAttribute = a.Attribute
+	sg.SessionAffinityCookie = a.Cookie.DeepCopy()
+}
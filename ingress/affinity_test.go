@@ -0,0 +1,73 @@
+package ingress
+
+import (
+	"testing"
+
+	alb2v1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	"github.com/stretchr/testify/assert"
+	n1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseSessionAffinity(t *testing.T) {
+	ing := func(annotations map[string]string) *n1.Ingress {
+		return &n1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	a, err := ParseSessionAffinity(ing(map[string]string{ALBSessionCookieNameAnnotation: "a"}))
+	assert.NoError(t, err)
+	assert.Nil(t, a)
+
+	a, err = ParseSessionAffinity(ing(map[string]string{ALBAffinityAnnotation: "cookie"}))
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultSessionCookieName, a.Attribute)
+	assert.Equal(t, &alb2v1.SessionAffinityCookie{}, a.Cookie)
+
+	a, err = ParseSessionAffinity(ing(map[string]string{
+		ALBAffinityAnnotation:              "cookie",
+		ALBSessionCookieNameAnnotation:     "route",
+		ALBSessionCookiePathAnnotation:     "/app",
+		ALBSessionCookieExpiresAnnotation:  "100",
+		ALBSessionCookieMaxAgeAnnotation:   "3600",
+		ALBSessionCookieSameSiteAnnotation: "none",
+		ALBSessionCookieSecureAnnotation:   "true",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, "route", a.Attribute)
+	assert.Equal(t, "/app", a.Cookie.Path)
+	assert.Equal(t, 3600, *a.Cookie.MaxAge)
+	assert.Equal(t, "None", a.Cookie.SameSite)
+	assert.True(t, *a.Cookie.Secure)
+
+	sg := &alb2v1.ServiceGroup{}
+	a.Apply(sg)
+	assert.Equal(t, AffinityCookie, sg.SessionAffinityPolicy)
+	assert.Equal(t, "route", sg.SessionAffinityAttribute)
+	assert.Equal(t, a.Cookie, sg.SessionAffinityCookie)
+
+	for _, annotations := range []map[string]string{
+		{ALBAffinityAnnotation: "header"},
+		{ALBAffinityAnnotation: "cookie", ALBSessionCookieMaxAgeAnnotation: "-1"},
+		{ALBAffinityAnnotation: "cookie", ALBSessionCookieSameSiteAnnotation: "x"},
+		{ALBAffinityAnnotation: "cookie", ALBSessionCookieSecureAnnotation: "x"},
+	} {
+		_, err = ParseSessionAffinity(ing(annotations))
+		assert.Error(t, err, annotations)
+	}
+}
+
+func TestRuleIdentityWithSessionCookie(t *testing.T) {
+	spec := alb2v1.RuleSpec{ServiceGroup: &alb2v1.ServiceGroup{
+		SessionAffinityPolicy:    "cookie",
+		SessionAffinityAttribute: "a",
+		Services:                 []alb2v1.Service{{Name: "s", Namespace: "ns", Port: 80, Weight: 100}},
+	}}
+	assert.Contains(t, spec.Identity(), "&{cookie a [ns-s-80]}")
+
+	age := 10
+	spec.ServiceGroup.SessionAffinityCookie = &alb2v1.SessionAffinityCookie{MaxAge: &age}
+	// the identity should not depend on the address of pointer.
+	copied := spec.DeepCopy()
+	assert.Equal(t, spec.Identity(), copied.Identity())
+	assert.Contains(t, spec.Identity(), `{"max_age":10}`)
+}
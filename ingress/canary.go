@@ -0,0 +1,191 @@
+package ingress
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	alb2v1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	"alauda.io/alb2/utils"
+	networkingv1 "k8s.io/api/networking/v1"
+	"k8s.io/apimachinery/pkg/labels"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// CanaryConfig is the canary annotations of a ingress.
+// a canary ingress with header or cookie will become a extra rule with higher priority,
+// a canary ingress with weight will be merged into the service group of the main ingress which has same host and path.
+// NOTE: the "never" value of header/cookie is not special-cased, request without the header/cookie just goes to the main rule.
+type CanaryConfig struct {
+	Enabled     bool
+	Weight      int
+	WeightTotal int
+	Header      string
+	HeaderValue string
+	Cookie      string
+}
+
+func IsCanaryIngress(ing *networkingv1.Ingress) bool {
+	return ing.GetAnnotations()[ALBCanaryAnnotation] == "true"
+}
+
+func ParseCanaryConfig(ing *networkingv1.Ingress) (CanaryConfig, error) {
+	annotations := ing.GetAnnotations()
+	cfg := CanaryConfig{
+		Enabled:     IsCanaryIngress(ing),
+		WeightTotal: DefaultCanaryWeightTotal,
+		Header:      strings.TrimSpace(annotations[ALBCanaryByHeaderAnnotation]),
+		HeaderValue: strings.TrimSpace(annotations[ALBCanaryByHeaderValueAnnotation]),
+		Cookie:      strings.TrimSpace(annotations[ALBCanaryByCookieAnnotation]),
+	}
+	if !cfg.Enabled {
+		return cfg, nil
+	}
+	if v := annotations[ALBCanaryWeightTotalAnnotation]; v != "" {
+		total, err := strconv.Atoi(v)
+		if err != nil || total <= 0 {
+			return cfg, fmt.Errorf("invalid canary weight total %s", v)
+		}
+		cfg.WeightTotal = total
+	}
+	if v := annotations[ALBCanaryWeightAnnotation]; v != "" {
+		weight, err := strconv.Atoi(v)
+		if err != nil || weight < 0 {
+			return cfg, fmt.Errorf("invalid canary weight %s", v)
+		}
+		if weight > cfg.WeightTotal {
+			weight = cfg.WeightTotal
+		}
+		cfg.Weight = weight
+	}
+	return cfg, nil
+}
+
+// HasMatch means this canary should have its own rule.
+func (c CanaryConfig) HasMatch() bool {
+	return c.Header != "" || c.Cookie != ""
+}
+
+// WeightPercent is the weight of canary in 0-100.
+func (c CanaryConfig) WeightPercent() int {
+	if c.WeightTotal <= 0 {
+		return 0
+	}
+	return c.Weight * 100 / c.WeightTotal
+}
+
+// MatchTerm is the dslx term of the canary rule, header takes precedence over cookie.
+func (c CanaryConfig) MatchTerm() *alb2v1.DSLXTerm {
+	if c.Header != "" {
+		value := c.HeaderValue
+		if value == "" {
+			value = CanaryAlways
+		}
+		return &alb2v1.DSLXTerm{
+			Type:   utils.KEY_HEADER,
+			Key:    c.Header,
+			Values: [][]string{{utils.OP_EQ, value}},
+		}
+	}
+	if c.Cookie != "" {
+		return &alb2v1.DSLXTerm{
+			Type:   utils.KEY_COOKIE,
+			Key:    c.Cookie,
+			Values: [][]string{{utils.OP_EQ, CanaryAlways}},
+		}
+	}
+	return nil
+}
+
+func ingressPathType(p networkingv1.HTTPIngressPath) networkingv1.PathType {
+	if p.PathType == nil {
+		return networkingv1.PathTypeImplementationSpecific
+	}
+	return *p.PathType
+}
+
+// findCanaryBackend find the weighted canary backend of the given path of main ingress.
+// if there are more than one canary ingresses, the first one (sort by name) wins.
+func (c *Controller) findCanaryBackend(ing *networkingv1.Ingress, host string, path networkingv1.HTTPIngressPath) (*networkingv1.IngressServiceBackend, CanaryConfig, bool) {
+	ings, err := c.ingressLister.Ingresses(ing.Namespace).List(labels.Everything())
+	if err != nil {
+		c.log.Error(err, "list ingress fail", "ns", ing.Namespace)
+		return nil, CanaryConfig{}, false
+	}
+	sort.Slice(ings, func(i, j int) bool { return ings[i].Name < ings[j].Name })
+	for _, canary := range ings {
+		if canary.Name == ing.Name || !IsCanaryIngress(canary) || !c.CheckShouldHandleViaIngressClass(canary) {
+			continue
+		}
+		cfg, err := ParseCanaryConfig(canary)
+		if err != nil {
+			c.log.Error(err, "invalid canary ingress", "ing", IngKey(canary))
+			continue
+		}
+		if cfg.Weight == 0 {
+			continue
+		}
+		if b := findSamePath(canary, host, path); b != nil {
+			return b, cfg, true
+		}
+	}
+	return nil, CanaryConfig{}, false
+}
+
+func findSamePath(ing *networkingv1.Ingress, host string, path networkingv1.HTTPIngressPath) *networkingv1.IngressServiceBackend {
+	for _, r := range ing.Spec.Rules {
+		if !strings.EqualFold(r.Host, host) || r.HTTP == nil {
+			continue
+		}
+		for _, p := range r.HTTP.Paths {
+			if p.Path == path.Path && ingressPathType(p) == ingressPathType(path) && p.Backend.Service != nil {
+				return p.Backend.Service
+			}
+		}
+	}
+	return nil
+}
+
+// mainIngressOfCanary find the ingresses which may use this canary ingress as weighted backend.
+func (c *Controller) mainIngressOfCanary(canary *networkingv1.Ingress) []client.ObjectKey {
+	ings, err := c.ingressLister.Ingresses(canary.Namespace).List(labels.Everything())
+	if err != nil {
+		c.log.Error(err, "list ingress fail", "ns", canary.Namespace)
+		return nil
+	}
+	keys := []client.ObjectKey{}
+	for _, ing := range ings {
+		if ing.Name == canary.Name || IsCanaryIngress(ing) {
+			continue
+		}
+		if sharePath(ing, canary) {
+			keys = append(keys, IngKey(ing))
+		}
+	}
+	return keys
+}
+
+func sharePath(ing *networkingv1.Ingress, canary *networkingv1.Ingress) bool {
+	for _, r := range ing.Spec.Rules {
+		if r.HTTP == nil {
+			continue
+		}
+		for _, p := range r.HTTP.Paths {
+			if findSamePath(canary, r.Host, p) != nil {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// the weight of canary ingress is part of the main ingress rule, so the main ingress should be resynced.
+func (c *Controller) enqueueMainOfCanary(ing *networkingv1.Ingress) {
+	if !IsCanaryIngress(ing) {
+		return
+	}
+	for _, key := range c.mainIngressOfCanary(ing) {
+		c.enqueue(key)
+	}
+}
@@ -0,0 +1,92 @@
+package ingress
+
+import (
+	"testing"
+
+	"alauda.io/alb2/utils"
+	"github.com/stretchr/testify/assert"
+	n1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestParseCanaryConfig(t *testing.T) {
+	ing := func(annotations map[string]string) *n1.Ingress {
+		return &n1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: annotations}}
+	}
+
+	cfg, err := ParseCanaryConfig(ing(map[string]string{ALBCanaryWeightAnnotation: "10"}))
+	assert.NoError(t, err)
+	assert.False(t, cfg.Enabled)
+
+	cfg, err = ParseCanaryConfig(ing(map[string]string{
+		ALBCanaryAnnotation:            "true",
+		ALBCanaryWeightAnnotation:      "30",
+		ALBCanaryWeightTotalAnnotation: "200",
+	}))
+	assert.NoError(t, err)
+	assert.False(t, cfg.HasMatch())
+	assert.Equal(t, 15, cfg.WeightPercent())
+	assert.Nil(t, cfg.MatchTerm())
+
+	cfg, err = ParseCanaryConfig(ing(map[string]string{
+		ALBCanaryAnnotation:       "true",
+		ALBCanaryWeightAnnotation: "300",
+	}))
+	assert.NoError(t, err)
+	assert.Equal(t, 100, cfg.WeightPercent())
+
+	_, err = ParseCanaryConfig(ing(map[string]string{
+		ALBCanaryAnnotation:       "true",
+		ALBCanaryWeightAnnotation: "x",
+	}))
+	assert.Error(t, err)
+
+	// header takes precedence over cookie
+	cfg, err = ParseCanaryConfig(ing(map[string]string{
+		ALBCanaryAnnotation:         "true",
+		ALBCanaryByHeaderAnnotation: "x-canary",
+		ALBCanaryByCookieAnnotation: "canary",
+	}))
+	assert.NoError(t, err)
+	assert.True(t, cfg.HasMatch())
+	term := cfg.MatchTerm()
+	assert.Equal(t, utils.KEY_HEADER, term.Type)
+	assert.Equal(t, "x-canary", term.Key)
+	assert.Equal(t, [][]string{{utils.OP_EQ, CanaryAlways}}, term.Values)
+
+	cfg, _ = ParseCanaryConfig(ing(map[string]string{
+		ALBCanaryAnnotation:              "true",
+		ALBCanaryByHeaderAnnotation:      "x-canary",
+		ALBCanaryByHeaderValueAnnotation: "v2",
+	}))
+	assert.Equal(t, [][]string{{utils.OP_EQ, "v2"}}, cfg.MatchTerm().Values)
+
+	cfg, _ = ParseCanaryConfig(ing(map[string]string{
+		ALBCanaryAnnotation:         "true",
+		ALBCanaryByCookieAnnotation: "canary",
+	}))
+	term = cfg.MatchTerm()
+	assert.Equal(t, utils.KEY_COOKIE, term.Type)
+	assert.Equal(t, "canary", term.Key)
+}
+
+func TestFindSamePath(t *testing.T) {
+	prefix := n1.PathTypePrefix
+	exact := n1.PathTypeExact
+	canary := &n1.Ingress{
+		Spec: n1.IngressSpec{
+			Rules: []n1.IngressRule{
+				{
+					Host: "a.com",
+					IngressRuleValue: n1.IngressRuleValue{HTTP: &n1.HTTPIngressRuleValue{Paths: []n1.HTTPIngressPath{
+						{Path: "/a", PathType: &prefix, Backend: n1.IngressBackend{Service: &n1.IngressServiceBackend{Name: "canary"}}},
+					}}},
+				},
+			},
+		},
+	}
+	b := findSamePath(canary, "A.com", n1.HTTPIngressPath{Path: "/a", PathType: &prefix})
+	assert.Equal(t, "canary", b.Name)
+	assert.Nil(t, findSamePath(canary, "a.com", n1.HTTPIngressPath{Path: "/a", PathType: &exact}))
+	assert.Nil(t, findSamePath(canary, "b.com", n1.HTTPIngressPath{Path: "/a", PathType: &prefix}))
+}
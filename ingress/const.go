@@ -43,3 +43,33 @@ var (
 	}
 	DefaultPriority = 5
 )
+
+// canary annotations, compatible with ingress-nginx.
+// https://kubernetes.github.io/ingress-nginx/user-guide/nginx-configuration/annotations/#canary
+const (
+	ALBCanaryAnnotation              = "nginx.ingress.kubernetes.io/canary"
+	ALBCanaryWeightAnnotation        = "nginx.ingress.kubernetes.io/canary-weight"
+	ALBCanaryWeightTotalAnnotation   = "nginx.ingress.kubernetes.io/canary-weight-total"
+	ALBCanaryByHeaderAnnotation      = "nginx.ingress.kubernetes.io/canary-by-header"
+	ALBCanaryByHeaderValueAnnotation = "nginx.ingress.kubernetes.io/canary-by-header-value"
+	ALBCanaryByCookieAnnotation      = "nginx.ingress.kubernetes.io/canary-by-cookie"
+
+	CanaryAlways             = "always"
+	DefaultCanaryWeightTotal = 100
+)
+
+// session affinity annotations, compatible with ingress-nginx.
+// https://kubernetes.github.io/ingress-nginx/user-guide/nginx-configuration/annotations/#session-affinity
+const (
+	ALBAffinityAnnotation              = "nginx.ingress.kubernetes.io/affinity"
+	ALBSessionCookieNameAnnotation     = "nginx.ingress.kubernetes.io/session-cookie-name"
+	ALBSessionCookiePathAnnotation     = "nginx.ingress.kubernetes.io/session-cookie-path"
+	ALBSessionCookieDomainAnnotation   = "nginx.ingress.kubernetes.io/session-cookie-domain"
+	ALBSessionCookieMaxAgeAnnotation   = "nginx.ingress.kubernetes.io/session-cookie-max-age"
+	ALBSessionCookieExpiresAnnotation  = "nginx.ingress.kubernetes.io/session-cookie-expires"
+	ALBSessionCookieSameSiteAnnotation = "nginx.ingress.kubernetes.io/session-cookie-samesite"
+	ALBSessionCookieSecureAnnotation   = "nginx.ingress.kubernetes.io/session-cookie-secure"
+
+	AffinityCookie           = "cookie"
+	DefaultSessionCookieName = "INGRESSCOOKIE"
+)
@@ -152,6 +152,7 @@ func (c *Controller) setUpEventHandler() error {
 				return
 			}
 			c.enqueue(IngKey(newIngress))
+			c.enqueueMainOfCanary(newIngress)
 		},
 		UpdateFunc: func(old, latest interface{}) {
 			newIngress := latest.(*networkingv1.Ingress)
@@ -177,6 +178,8 @@ func (c *Controller) setUpEventHandler() error {
 			}
 
 			c.enqueue(IngKey(newIngress))
+			c.enqueueMainOfCanary(oldIngress)
+			c.enqueueMainOfCanary(newIngress)
 		},
 		DeleteFunc: func(obj interface{}) {
 			ingress := obj.(*networkingv1.Ingress)
@@ -186,6 +189,7 @@ func (c *Controller) setUpEventHandler() error {
 				return
 			}
 			c.enqueue(IngKey(ingress))
+			c.enqueueMainOfCanary(ingress)
 		},
 	})
 	if err != nil {
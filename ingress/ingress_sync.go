@@ -248,6 +248,20 @@ func (c *Controller) generateExpect(alb *m.AlaudaLoadBalancer, ingress *networki
 	httpRule := []*alb2v1.Rule{}
 	httpsRule := []*alb2v1.Rule{}
 
+	canary, err := ParseCanaryConfig(ingress)
+	if err != nil {
+		return nil, err
+	}
+	// canary ingress with only weight has no rule, it will be merged into the main ingress.
+	if canary.Enabled && !canary.HasMatch() {
+		return &ExpectFtAndRule{
+			http:      httpFt,
+			https:     httpsFt,
+			httpRule:  httpRule,
+			httpsRule: httpsRule,
+		}, nil
+	}
+
 	// generate expect rules
 	for rIndex, r := range ingress.Spec.Rules {
 		host := strings.ToLower(r.Host)
@@ -430,7 +444,8 @@ func (c *Controller) generateExpectFrontend(alb *m.AlaudaLoadBalancer, ingress *
 	}
 
 	// for default backend, we will not create rules but save services to frontends' service-group
-	if HasDefaultBackend(ingress) {
+	// canary ingress should not override the default backend.
+	if HasDefaultBackend(ingress) && !IsCanaryIngress(ingress) {
 		c.log.Info("ingress has default backend", "ing", ingress.Name)
 		// just update it, let the resolver decide should we really update it.
 		annotations := ingress.GetAnnotations()
@@ -450,6 +465,11 @@ func (c *Controller) generateExpectFrontend(alb *m.AlaudaLoadBalancer, ingress *
 		if err != nil {
 			return nil, nil, err
 		}
+		affinity, err := ParseSessionAffinity(ingress)
+		if err != nil {
+			c.log.Error(err, "parse session affinity fail, ignore it", "ing", ingress.Name)
+		}
+		affinity.Apply(svc)
 		if albhttpFt != nil {
 			m.SetDefaultBackend(albhttpFt, backendProtocol, svc)
 			m.SetSource(albhttpFt, ingress)
@@ -567,6 +587,39 @@ func (c *Controller) GenerateRule(
 	ruleAnnotation[c.GetLabelSourceIngressRuleIndex()] = fmt.Sprintf("%d", ruleIndex)
 	name := strings.ToLower(utils.RandomStr(ft.Name, 4))
 	dslx := GetDSLX(host, url, pathType)
+	svcs := []alb2v1.Service{
+		{
+			Namespace: ingress.Namespace,
+			Name:      ingresPath.Backend.Service.Name,
+			Port:      portInService,
+			Weight:    100,
+		},
+	}
+	canary, err := ParseCanaryConfig(ingress)
+	if err != nil {
+		return nil, err
+	}
+	if canary.Enabled {
+		// request with the canary header/cookie should match this rule before the main rule.
+		if term := canary.MatchTerm(); term != nil {
+			dslx = append(dslx, *term)
+			priority = priority - 1
+		}
+	} else if backend, canaryCfg, find := c.findCanaryBackend(ingress, host, ingresPath); find {
+		canaryPort, err := c.kd.GetServicePortNumber(ingress.Namespace, backend.Name, ToInStr(backend.Port), corev1.ProtocolTCP)
+		if err != nil {
+			c.log.Error(err, "get canary svc port fail", "ing", ingInfo, "svc", backend.Name)
+		} else {
+			weight := canaryCfg.WeightPercent()
+			svcs[0].Weight = 100 - weight
+			svcs = append(svcs, alb2v1.Service{
+				Namespace: ingress.Namespace,
+				Name:      backend.Name,
+				Port:      canaryPort,
+				Weight:    weight,
+			})
+		}
+	}
 	ruleSpec := alb2v1.RuleSpec{
 		Domain:           host,
 		URL:              url,
@@ -583,15 +636,8 @@ func (c *Controller) GenerateRule(
 		VHost:            vhost,
 		Description:      ingInfo,
 		Config:           &alb2v1.RuleConfigInCr{},
-		ServiceGroup: &alb2v1.ServiceGroup{ // TODO ingress could only have one service as backend
-			Services: []alb2v1.Service{
-				{
-					Namespace: ingress.Namespace,
-					Name:      ingresPath.Backend.Service.Name,
-					Port:      portInService,
-					Weight:    100,
-				},
-			},
+		ServiceGroup: &alb2v1.ServiceGroup{
+			Services: svcs,
 		},
 		Source: &alb2v1.Source{
 			Type:      m.TypeIngress,
@@ -599,6 +645,11 @@ func (c *Controller) GenerateRule(
 			Name:      ingress.Name,
 		},
 	}
+	affinity, err := ParseSessionAffinity(ingress)
+	if err != nil {
+		c.log.Error(err, "parse session affinity fail, ignore it", "ing", ingInfo)
+	}
+	affinity.Apply(ruleSpec.ServiceGroup)
 
 	cfg := c
 	ruleRes := &alb2v1.Rule{
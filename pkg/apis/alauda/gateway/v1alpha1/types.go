@@ -3,12 +3,14 @@ package v1alpha1
 // +kubebuilder:validation:Optional
 
 import (
+	retry_t "alauda.io/alb2/pkg/controller/ext/retry/types"
 	timeout_t "alauda.io/alb2/pkg/controller/ext/timeout/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
 
 const (
 	TimeoutPolicyKind = "TimeoutPolicy"
+	RetryPolicyKind   = "RetryPolicy"
 )
 
 // PolicyTargetReference identifies an API object to apply policy to.
@@ -104,3 +106,49 @@ type TimeoutPolicyList struct {
 
 	Items []TimeoutPolicy `json:"items"`
 }
+
+type RetryPolicyConfig retry_t.RetryCr
+
+// RetryPolicy config how to retry the request to next upstream, it could attach to gatewayclass/gateway/listener/route/route rule.
+// +genclient
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+// +kubebuilder:resource:singular=retrypolicy,path=retrypolicies,shortName=retry,scope=Namespaced
+// +kubebuilder:subresource:status
+type RetryPolicy struct {
+	metav1.TypeMeta   `json:",inline"`
+	metav1.ObjectMeta `json:"metadata,omitempty"`
+
+	Spec   RetryPolicySpec   `json:"spec"`
+	Status RetryPolicyStatus `json:"status"`
+}
+
+type RetryPolicySpec struct {
+	TargetRef PolicyTargetReference `json:"targetRef"`
+	// Override defines policy configuration that should override policy
+	// configuration attached below the targeted resource in the hierarchy.
+	// +optional
+	Override *RetryPolicyConfig `json:"override,omitempty"`
+
+	// Default defines default policy configuration for the targeted resource.
+	// +optional
+	Default *RetryPolicyConfig `json:"default,omitempty"`
+}
+
+type RetryPolicyStatus struct {
+	// Conditions describe the current conditions of the RetryPolicy.
+	//
+	// +optional
+	// +listType=map
+	// +listMapKey=type
+	// +kubebuilder:validation:MaxItems=8
+	Conditions []metav1.Condition `json:"conditions,omitempty"`
+}
+
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+type RetryPolicyList struct {
+	metav1.TypeMeta `json:",inline"`
+	metav1.ListMeta `json:"metadata"`
+
+	Items []RetryPolicy `json:"items"`
+}
@@ -52,6 +52,158 @@ func (in *PolicyTargetReference) DeepCopy() *PolicyTargetReference {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicy) DeepCopyInto(out *RetryPolicy) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ObjectMeta.DeepCopyInto(&out.ObjectMeta)
+	in.Spec.DeepCopyInto(&out.Spec)
+	in.Status.DeepCopyInto(&out.Status)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicy.
+func (in *RetryPolicy) DeepCopy() *RetryPolicy {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicy)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RetryPolicy) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicyConfig) DeepCopyInto(out *RetryPolicyConfig) {
+	*out = *in
+	if in.Attempts != nil {
+		in, out := &in.Attempts, &out.Attempts
+		*out = new(uint)
+		**out = **in
+	}
+	if in.On != nil {
+		in, out := &in.On, &out.On
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	if in.PerTryTimeoutMs != nil {
+		in, out := &in.PerTryTimeoutMs, &out.PerTryTimeoutMs
+		*out = new(uint)
+		**out = **in
+	}
+	if in.TimeoutMs != nil {
+		in, out := &in.TimeoutMs, &out.TimeoutMs
+		*out = new(uint)
+		**out = **in
+	}
+	if in.RetryNonIdempotent != nil {
+		in, out := &in.RetryNonIdempotent, &out.RetryNonIdempotent
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicyConfig.
+func (in *RetryPolicyConfig) DeepCopy() *RetryPolicyConfig {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicyConfig)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicyList) DeepCopyInto(out *RetryPolicyList) {
+	*out = *in
+	out.TypeMeta = in.TypeMeta
+	in.ListMeta.DeepCopyInto(&out.ListMeta)
+	if in.Items != nil {
+		in, out := &in.Items, &out.Items
+		*out = make([]RetryPolicy, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicyList.
+func (in *RetryPolicyList) DeepCopy() *RetryPolicyList {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicyList)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyObject is an autogenerated deepcopy function, copying the receiver, creating a new runtime.Object.
+func (in *RetryPolicyList) DeepCopyObject() runtime.Object {
+	if c := in.DeepCopy(); c != nil {
+		return c
+	}
+	return nil
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicySpec) DeepCopyInto(out *RetryPolicySpec) {
+	*out = *in
+	in.TargetRef.DeepCopyInto(&out.TargetRef)
+	if in.Override != nil {
+		in, out := &in.Override, &out.Override
+		*out = new(RetryPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Default != nil {
+		in, out := &in.Default, &out.Default
+		*out = new(RetryPolicyConfig)
+		(*in).DeepCopyInto(*out)
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicySpec.
+func (in *RetryPolicySpec) DeepCopy() *RetryPolicySpec {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicySpec)
+	in.DeepCopyInto(out)
+	return out
+}
+
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *RetryPolicyStatus) DeepCopyInto(out *RetryPolicyStatus) {
+	*out = *in
+	if in.Conditions != nil {
+		in, out := &in.Conditions, &out.Conditions
+		*out = make([]v1.Condition, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new RetryPolicyStatus.
+func (in *RetryPolicyStatus) DeepCopy() *RetryPolicyStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(RetryPolicyStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *TimeoutPolicy) DeepCopyInto(out *TimeoutPolicy) {
 	*out = *in
@@ -98,6 +250,11 @@ func (in *TimeoutPolicyConfig) DeepCopyInto(out *TimeoutPolicyConfig) {
 		*out = new(uint)
 		**out = **in
 	}
+	if in.RequestTimeoutMs != nil {
+		in, out := &in.RequestTimeoutMs, &out.RequestTimeoutMs
+		*out = new(uint)
+		**out = **in
+	}
 	return
 }
 
@@ -2,7 +2,12 @@ package shared
 
 import (
 	auth_t "alauda.io/alb2/pkg/controller/ext/auth/types"
+	errorpage_t "alauda.io/alb2/pkg/controller/ext/errorpage/types"
+	ipacl_t "alauda.io/alb2/pkg/controller/ext/ipacl/types"
 	otelt "alauda.io/alb2/pkg/controller/ext/otel/types"
+	outlier_t "alauda.io/alb2/pkg/controller/ext/outlier/types"
+	proxy_t "alauda.io/alb2/pkg/controller/ext/proxy/types"
+	ratelimit_t "alauda.io/alb2/pkg/controller/ext/ratelimit/types"
 	timeout_t "alauda.io/alb2/pkg/controller/ext/timeout/types"
 	waft "alauda.io/alb2/pkg/controller/ext/waf/types"
 )
@@ -10,8 +15,13 @@ import (
 // struct shared in alb/ft/rule
 // +k8s:deepcopy-gen=true
 type SharedCr struct {
-	Otel         *otelt.OtelCrConf    `json:"otel,omitempty"`
-	ModeSecurity *waft.WafCrConf      `json:"modsecurity,omitempty"`
-	Auth         *auth_t.AuthCr       `json:"auth,omitempty"`
-	Timeout      *timeout_t.TimeoutCr `json:"timeout,omitempty"`
+	Otel         *otelt.OtelCrConf        `json:"otel,omitempty"`
+	ModeSecurity *waft.WafCrConf          `json:"modsecurity,omitempty"`
+	Auth         *auth_t.AuthCr           `json:"auth,omitempty"`
+	Timeout      *timeout_t.TimeoutCr     `json:"timeout,omitempty"`
+	RateLimit    *ratelimit_t.RateLimitCr `json:"ratelimit,omitempty"`
+	IpAcl        *ipacl_t.IpAclCr         `json:"ipacl,omitempty"`
+	Outlier      *outlier_t.OutlierCr     `json:"outlier,omitempty"`
+	Proxy        *proxy_t.ProxyCr         `json:"proxy,omitempty"`
+	ErrorPage    *errorpage_t.ErrorPageCr `json:"errorpage,omitempty"`
 }
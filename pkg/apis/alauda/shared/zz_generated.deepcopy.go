@@ -23,7 +23,12 @@ package shared
 
 import (
 	authtypes "alauda.io/alb2/pkg/controller/ext/auth/types"
+	errorpagetypes "alauda.io/alb2/pkg/controller/ext/errorpage/types"
+	ipacltypes "alauda.io/alb2/pkg/controller/ext/ipacl/types"
 	types "alauda.io/alb2/pkg/controller/ext/otel/types"
+	outliertypes "alauda.io/alb2/pkg/controller/ext/outlier/types"
+	proxytypes "alauda.io/alb2/pkg/controller/ext/proxy/types"
+	ratelimittypes "alauda.io/alb2/pkg/controller/ext/ratelimit/types"
 	timeouttypes "alauda.io/alb2/pkg/controller/ext/timeout/types"
 	waftypes "alauda.io/alb2/pkg/controller/ext/waf/types"
 )
@@ -51,6 +56,31 @@ func (in *SharedCr) DeepCopyInto(out *SharedCr) {
 		*out = new(timeouttypes.TimeoutCr)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.RateLimit != nil {
+		in, out := &in.RateLimit, &out.RateLimit
+		*out = new(ratelimittypes.RateLimitCr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.IpAcl != nil {
+		in, out := &in.IpAcl, &out.IpAcl
+		*out = new(ipacltypes.IpAclCr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Outlier != nil {
+		in, out := &in.Outlier, &out.Outlier
+		*out = new(outliertypes.OutlierCr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Proxy != nil {
+		in, out := &in.Proxy, &out.Proxy
+		*out = new(proxytypes.ProxyCr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ErrorPage != nil {
+		in, out := &in.ErrorPage, &out.ErrorPage
+		*out = new(errorpagetypes.ErrorPageCr)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
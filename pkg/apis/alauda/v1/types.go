@@ -8,9 +8,16 @@ import (
 	"fmt"
 
 	"alauda.io/alb2/pkg/apis/alauda/shared"
+	clienttls_t "alauda.io/alb2/pkg/controller/ext/clienttls/types"
+	healthcheck_t "alauda.io/alb2/pkg/controller/ext/healthcheck/types"
 	keepalive_t "alauda.io/alb2/pkg/controller/ext/keepalive/types"
+	mirror_t "alauda.io/alb2/pkg/controller/ext/mirror/types"
 	otelt "alauda.io/alb2/pkg/controller/ext/otel/types"
+	realip_t "alauda.io/alb2/pkg/controller/ext/realip/types"
 	redirect_t "alauda.io/alb2/pkg/controller/ext/redirect/types"
+	retry_t "alauda.io/alb2/pkg/controller/ext/retry/types"
+	tlspolicy_t "alauda.io/alb2/pkg/controller/ext/tlspolicy/types"
+	upstreamtls_t "alauda.io/alb2/pkg/controller/ext/upstreamtls/types"
 	waft "alauda.io/alb2/pkg/controller/ext/waf/types"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 )
@@ -168,8 +175,62 @@ type ServiceGroup struct {
 	// +optional
 	SessionAffinityPolicy string `json:"session_affinity_policy,omitempty"`
 	// +optional
-	SessionAffinityAttribute string    `json:"session_affinity_attribute,omitempty"`
-	Services                 []Service `json:"services"`
+	SessionAffinityAttribute string `json:"session_affinity_attribute,omitempty"`
+	// attributes of the cookie set by the cookie session affinity policy.
+	// +optional
+	SessionAffinityCookie *SessionAffinityCookie `json:"session_affinity_cookie,omitempty"`
+	// active health check of the endpoints, unhealthy endpoints will not be picked by balancer.
+	// +optional
+	HealthCheck *healthcheck_t.HealthCheckCr `json:"health_check,omitempty"`
+	Services    []Service                    `json:"services"`
+}
+
+// SessionAffinityCookie is the cookie set by alb when session affinity policy is cookie and request does not have it.
+type SessionAffinityCookie struct {
+	// name of the cookie, take precedence over session_affinity_attribute. default is JSESSIONID.
+	// +optional
+	Name string `json:"name,omitempty"`
+	// default is /
+	// +optional
+	Path string `json:"path,omitempty"`
+	// +optional
+	Domain string `json:"domain,omitempty"`
+	// max-age of the cookie in seconds, the expires is set too. it is a session cookie if not set.
+	// +optional
+	MaxAge *int `json:"max_age,omitempty"`
+	// set the cookie again on each response, so the cookie expires after max_age of inactivity instead of max_age since it is set.
+	// +optional
+	Refresh bool `json:"refresh,omitempty"`
+	// one of None, Lax, Strict.
+	// +optional
+	SameSite string `json:"samesite,omitempty"`
+	// default is true when the request is https.
+	// +optional
+	Secure *bool `json:"secure,omitempty"`
+	// default is true.
+	// +optional
+	HttpOnly *bool `json:"httponly,omitempty"`
+}
+
+// identity keep the format of the service group which does not have the cookie and health check, so the hash of exist rule does not change.
+func (s *ServiceGroup) identity() string {
+	if s == nil {
+		return fmt.Sprintf("%v", s)
+	}
+	id := fmt.Sprintf("%v", &struct {
+		P string
+		A string
+		S []Service
+	}{s.SessionAffinityPolicy, s.SessionAffinityAttribute, s.Services})
+	if s.SessionAffinityCookie != nil {
+		cookie, _ := json.Marshal(s.SessionAffinityCookie) //nolint:errcheck
+		id += string(cookie)
+	}
+	if s.HealthCheck != nil {
+		hc, _ := json.Marshal(s.HealthCheck) //nolint:errcheck
+		id += string(hc)
+	}
+	return id
 }
 
 // Source is where the frontend or rule came from.
@@ -244,11 +305,26 @@ type FTConfig struct {
 	shared.SharedCr `json:",inline"`
 	Redirect        *redirect_t.RedirectCr   `json:"redirect,omitempty"`
 	KeepAlive       *keepalive_t.KeepAliveCr `json:"keepalive,omitempty"`
+	// verify the certificate of client. only take effect on https/grpc frontend.
+	ClientTLS *clienttls_t.ClientTLSCr `json:"clientTLS,omitempty"`
+	// accept the proxy protocol from downstream or send it to upstream. udp frontend is not supported.
+	ProxyProtocol *realip_t.ProxyProtocolCr `json:"proxyProtocol,omitempty"`
+	// resolve the client ip from the request of trusted proxies. udp frontend is not supported.
+	RealIP *realip_t.RealIPCr `json:"realIP,omitempty"`
+	// tls policy of https/grpc frontend, e.g. versions and ciphers.
+	TLS *tlspolicy_t.TLSCr `json:"tls,omitempty"`
 }
 
 type RuleConfigInCr struct {
 	shared.SharedCr `json:",inline"`
 	Redirect        *redirect_t.RedirectCr `json:"redirect,omitempty"`
+	Mirror          *mirror_t.MirrorCr     `json:"mirror,omitempty"`
+	// tls between alb and upstream. only take effect when backend protocol is https.
+	UpstreamTLS *upstreamtls_t.UpstreamTLSCr `json:"upstreamTLS,omitempty"`
+	// retry request to next upstream when the upstream fails.
+	Retry *retry_t.RetryCr `json:"retry,omitempty"`
+	// verify the certificate of client. only take effect on https/grpc frontend.
+	ClientTLS *clienttls_t.ClientTLSCr `json:"clientTLS,omitempty"`
 }
 
 func (r *Rule) GetWaf() *waft.WafCrConf {
@@ -340,7 +416,7 @@ func (r *RuleSpec) Identity() string {
 	b.WriteString(r.DSL)
 	b.WriteString(fmt.Sprintf("%v", r.DSLX))
 	b.WriteString(fmt.Sprintf("%v", r.Priority))
-	b.WriteString(fmt.Sprintf("%v", r.ServiceGroup))
+	b.WriteString(r.ServiceGroup.identity())
 	b.WriteString(fmt.Sprintf("%v", r.Source))
 	b.WriteString(r.Type)
 	b.WriteString(r.URL)
@@ -22,8 +22,15 @@ limitations under the License.
 package v1
 
 import (
+	clienttlstypes "alauda.io/alb2/pkg/controller/ext/clienttls/types"
+	healthchecktypes "alauda.io/alb2/pkg/controller/ext/healthcheck/types"
 	keepalivetypes "alauda.io/alb2/pkg/controller/ext/keepalive/types"
+	mirrortypes "alauda.io/alb2/pkg/controller/ext/mirror/types"
+	realiptypes "alauda.io/alb2/pkg/controller/ext/realip/types"
 	types "alauda.io/alb2/pkg/controller/ext/redirect/types"
+	retrytypes "alauda.io/alb2/pkg/controller/ext/retry/types"
+	tlspolicytypes "alauda.io/alb2/pkg/controller/ext/tlspolicy/types"
+	upstreamtlstypes "alauda.io/alb2/pkg/controller/ext/upstreamtls/types"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
 
@@ -188,6 +195,26 @@ func (in *FTConfig) DeepCopyInto(out *FTConfig) {
 		*out = new(keepalivetypes.KeepAliveCr)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.ClientTLS != nil {
+		in, out := &in.ClientTLS, &out.ClientTLS
+		*out = new(clienttlstypes.ClientTLSCr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ProxyProtocol != nil {
+		in, out := &in.ProxyProtocol, &out.ProxyProtocol
+		*out = new(realiptypes.ProxyProtocolCr)
+		**out = **in
+	}
+	if in.RealIP != nil {
+		in, out := &in.RealIP, &out.RealIP
+		*out = new(realiptypes.RealIPCr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(tlspolicytypes.TLSCr)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -368,6 +395,26 @@ func (in *RuleConfigInCr) DeepCopyInto(out *RuleConfigInCr) {
 		*out = new(types.RedirectCr)
 		(*in).DeepCopyInto(*out)
 	}
+	if in.Mirror != nil {
+		in, out := &in.Mirror, &out.Mirror
+		*out = new(mirrortypes.MirrorCr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.UpstreamTLS != nil {
+		in, out := &in.UpstreamTLS, &out.UpstreamTLS
+		*out = new(upstreamtlstypes.UpstreamTLSCr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.Retry != nil {
+		in, out := &in.Retry, &out.Retry
+		*out = new(retrytypes.RetryCr)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.ClientTLS != nil {
+		in, out := &in.ClientTLS, &out.ClientTLS
+		*out = new(clienttlstypes.ClientTLSCr)
+		(*in).DeepCopyInto(*out)
+	}
 	return
 }
 
@@ -471,6 +518,16 @@ func (in *Service) DeepCopy() *Service {
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ServiceGroup) DeepCopyInto(out *ServiceGroup) {
 	*out = *in
+	if in.SessionAffinityCookie != nil {
+		in, out := &in.SessionAffinityCookie, &out.SessionAffinityCookie
+		*out = new(SessionAffinityCookie)
+		(*in).DeepCopyInto(*out)
+	}
+	if in.HealthCheck != nil {
+		in, out := &in.HealthCheck, &out.HealthCheck
+		*out = new(healthchecktypes.HealthCheckCr)
+		(*in).DeepCopyInto(*out)
+	}
 	if in.Services != nil {
 		in, out := &in.Services, &out.Services
 		*out = make([]Service, len(*in))
@@ -489,6 +546,37 @@ func (in *ServiceGroup) DeepCopy() *ServiceGroup {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *SessionAffinityCookie) DeepCopyInto(out *SessionAffinityCookie) {
+	*out = *in
+	if in.MaxAge != nil {
+		in, out := &in.MaxAge, &out.MaxAge
+		*out = new(int)
+		**out = **in
+	}
+	if in.Secure != nil {
+		in, out := &in.Secure, &out.Secure
+		*out = new(bool)
+		**out = **in
+	}
+	if in.HttpOnly != nil {
+		in, out := &in.HttpOnly, &out.HttpOnly
+		*out = new(bool)
+		**out = **in
+	}
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new SessionAffinityCookie.
+func (in *SessionAffinityCookie) DeepCopy() *SessionAffinityCookie {
+	if in == nil {
+		return nil
+	}
+	out := new(SessionAffinityCookie)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *Source) DeepCopyInto(out *Source) {
 	*out = *in
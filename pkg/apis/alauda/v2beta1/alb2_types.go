@@ -24,6 +24,7 @@ import (
 	"strings"
 
 	"alauda.io/alb2/pkg/apis/alauda/shared"
+	tlspolicy_t "alauda.io/alb2/pkg/controller/ext/tlspolicy/types"
 	corev1 "k8s.io/api/core/v1"
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/util/intstr"
@@ -119,6 +120,7 @@ type ExternalAlbConfig struct {
 	BindNIC              *string            `yaml:"bindNIC" json:"bindNIC,omitempty"` // json string alb绑定网卡的配置 '{"nic":["eth0"]}'
 	Overwrite            *ExternalOverwrite `yaml:"overwrite" json:"overwrite,omitempty"`
 	ReadonlyFS           *bool              `yaml:"readonlyFS" json:"readonlyFS,omitempty"`
+	TLS                  *tlspolicy_t.TLSCr `yaml:"tls" json:"tls,omitempty"` // tls policy of https/grpc frontends, the one of frontend is preferred
 	shared.SharedCr      `json:",inline"`
 }
 
@@ -233,6 +235,24 @@ type ALB2StatusDetail struct {
 	// status set by operator
 	// +optional
 	Versions VersionStatus `json:"version"`
+	// certificates used by the https/grpc frontends, set by alb itself
+	// +optional
+	Certificates []CertificateStatus `json:"certificates,omitempty"`
+}
+
+type CertificateStatus struct {
+	// domain, port (default cert of frontend) or domain/port which the certificate is used for
+	Domain string `json:"domain"`
+	// namespace/name of the secret
+	Secret  string `json:"secret"`
+	Subject string `json:"subject"`
+	// dns names and ip addresses in the subject alternative names
+	// +optional
+	SANs     []string    `json:"sans,omitempty"`
+	NotAfter metav1.Time `json:"notAfter"`
+	// the domain is not covered by the certificate
+	// +optional
+	Mismatch bool `json:"mismatch,omitempty"`
 }
 
 type VersionStatus struct {
@@ -250,6 +270,17 @@ type AlbStatus struct {
 	// port status of this alb. key format protocol-port
 	// +optional
 	PortStatus map[string]PortStatus `json:"portstatus"`
+	// nginx.conf rejected by nginx -t. key is the name of pod, only the failed pods are recorded.
+	// +optional
+	ReloadStatus map[string]ReloadStatus `json:"reloadstatus,omitempty"`
+}
+
+type ReloadStatus struct {
+	// output of nginx -t
+	Msg string `json:"msg"`
+	// sha256 of the rejected nginx.conf
+	Version      string      `json:"version"`
+	ProbeTimeStr metav1.Time `json:"probeTimeStr"`
 }
 
 type AssignedAddress struct {
@@ -22,6 +22,7 @@ limitations under the License.
 package v2beta1
 
 import (
+	tlspolicytypes "alauda.io/alb2/pkg/controller/ext/tlspolicy/types"
 	v1 "k8s.io/api/core/v1"
 	runtime "k8s.io/apimachinery/pkg/runtime"
 )
@@ -133,6 +134,13 @@ func (in *ALB2StatusDetail) DeepCopyInto(out *ALB2StatusDetail) {
 	in.Alb.DeepCopyInto(&out.Alb)
 	in.AddressStatus.DeepCopyInto(&out.AddressStatus)
 	out.Versions = in.Versions
+	if in.Certificates != nil {
+		in, out := &in.Certificates, &out.Certificates
+		*out = make([]CertificateStatus, len(*in))
+		for i := range *in {
+			(*in)[i].DeepCopyInto(&(*out)[i])
+		}
+	}
 	return
 }
 
@@ -156,6 +164,13 @@ func (in *AlbStatus) DeepCopyInto(out *AlbStatus) {
 			(*out)[key] = *val.DeepCopy()
 		}
 	}
+	if in.ReloadStatus != nil {
+		in, out := &in.ReloadStatus, &out.ReloadStatus
+		*out = make(map[string]ReloadStatus, len(*in))
+		for key, val := range *in {
+			(*out)[key] = *val.DeepCopy()
+		}
+	}
 	return
 }
 
@@ -200,6 +215,28 @@ func (in *AssignedAddress) DeepCopy() *AssignedAddress {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *CertificateStatus) DeepCopyInto(out *CertificateStatus) {
+	*out = *in
+	if in.SANs != nil {
+		in, out := &in.SANs, &out.SANs
+		*out = make([]string, len(*in))
+		copy(*out, *in)
+	}
+	in.NotAfter.DeepCopyInto(&out.NotAfter)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new CertificateStatus.
+func (in *CertificateStatus) DeepCopy() *CertificateStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(CertificateStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *ContainerResource) DeepCopyInto(out *ContainerResource) {
 	*out = *in
@@ -453,6 +490,11 @@ func (in *ExternalAlbConfig) DeepCopyInto(out *ExternalAlbConfig) {
 		*out = new(bool)
 		**out = **in
 	}
+	if in.TLS != nil {
+		in, out := &in.TLS, &out.TLS
+		*out = new(tlspolicytypes.TLSCr)
+		(*in).DeepCopyInto(*out)
+	}
 	in.SharedCr.DeepCopyInto(&out.SharedCr)
 	return
 }
@@ -625,6 +667,23 @@ func (in *PortStatus) DeepCopy() *PortStatus {
 	return out
 }
 
+// DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
+func (in *ReloadStatus) DeepCopyInto(out *ReloadStatus) {
+	*out = *in
+	in.ProbeTimeStr.DeepCopyInto(&out.ProbeTimeStr)
+	return
+}
+
+// DeepCopy is an autogenerated deepcopy function, copying the receiver, creating a new ReloadStatus.
+func (in *ReloadStatus) DeepCopy() *ReloadStatus {
+	if in == nil {
+		return nil
+	}
+	out := new(ReloadStatus)
+	in.DeepCopyInto(out)
+	return out
+}
+
 // DeepCopyInto is an autogenerated deepcopy function, copying the receiver, writing into out. in must be non-nil.
 func (in *VersionStatus) DeepCopyInto(out *VersionStatus) {
 	*out = *in
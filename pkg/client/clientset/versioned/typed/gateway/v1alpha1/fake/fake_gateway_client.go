@@ -28,6 +28,10 @@ type FakeGatewayV1alpha1 struct {
 	*testing.Fake
 }
 
+func (c *FakeGatewayV1alpha1) RetryPolicies(namespace string) v1alpha1.RetryPolicyInterface {
+	return &FakeRetryPolicies{c, namespace}
+}
+
 func (c *FakeGatewayV1alpha1) TimeoutPolicies(namespace string) v1alpha1.TimeoutPolicyInterface {
 	return &FakeTimeoutPolicies{c, namespace}
 }
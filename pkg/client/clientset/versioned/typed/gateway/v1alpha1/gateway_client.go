@@ -28,6 +28,7 @@ import (
 
 type GatewayV1alpha1Interface interface {
 	RESTClient() rest.Interface
+	RetryPoliciesGetter
 	TimeoutPoliciesGetter
 }
 
@@ -36,6 +37,10 @@ type GatewayV1alpha1Client struct {
 	restClient rest.Interface
 }
 
+func (c *GatewayV1alpha1Client) RetryPolicies(namespace string) RetryPolicyInterface {
+	return newRetryPolicies(c, namespace)
+}
+
 func (c *GatewayV1alpha1Client) TimeoutPolicies(namespace string) TimeoutPolicyInterface {
 	return newTimeoutPolicies(c, namespace)
 }
@@ -24,6 +24,8 @@ import (
 
 // Interface provides access to all the informers in this group version.
 type Interface interface {
+	// RetryPolicies returns a RetryPolicyInformer.
+	RetryPolicies() RetryPolicyInformer
 	// TimeoutPolicies returns a TimeoutPolicyInformer.
 	TimeoutPolicies() TimeoutPolicyInformer
 }
@@ -39,6 +41,11 @@ func New(f internalinterfaces.SharedInformerFactory, namespace string, tweakList
 	return &version{factory: f, namespace: namespace, tweakListOptions: tweakListOptions}
 }
 
+// RetryPolicies returns a RetryPolicyInformer.
+func (v *version) RetryPolicies() RetryPolicyInformer {
+	return &retryPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
+}
+
 // TimeoutPolicies returns a TimeoutPolicyInformer.
 func (v *version) TimeoutPolicies() TimeoutPolicyInformer {
 	return &timeoutPolicyInformer{factory: v.factory, namespace: v.namespace, tweakListOptions: v.tweakListOptions}
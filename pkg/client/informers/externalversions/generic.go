@@ -67,6 +67,8 @@ func (f *sharedInformerFactory) ForResource(resource schema.GroupVersionResource
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Crd().V2beta1().ALB2s().Informer()}, nil
 
 		// Group=gateway.crd.alauda.io, Version=v1alpha1
+	case v1alpha1.SchemeGroupVersion.WithResource("retrypolicies"):
+		return &genericInformer{resource: resource.GroupResource(), informer: f.Gateway().V1alpha1().RetryPolicies().Informer()}, nil
 	case v1alpha1.SchemeGroupVersion.WithResource("timeoutpolicies"):
 		return &genericInformer{resource: resource.GroupResource(), informer: f.Gateway().V1alpha1().TimeoutPolicies().Informer()}, nil
 
@@ -18,6 +18,14 @@ limitations under the License.
 
 package v1alpha1
 
+// RetryPolicyListerExpansion allows custom methods to be added to
+// RetryPolicyLister.
+type RetryPolicyListerExpansion interface{}
+
+// RetryPolicyNamespaceListerExpansion allows custom methods to be added to
+// RetryPolicyNamespaceLister.
+type RetryPolicyNamespaceListerExpansion interface{}
+
 // TimeoutPolicyListerExpansion allows custom methods to be added to
 // TimeoutPolicyLister.
 type TimeoutPolicyListerExpansion interface{}
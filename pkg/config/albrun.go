@@ -139,6 +139,8 @@ const (
 // alb 内部使用的一些配置
 const (
 	INTERVAL            string = "INTERVAL"
+	RELOAD_DEBOUNCE     string = "RELOAD_DEBOUNCE"
+	CERT_EXPIRY_DAYS    string = "CERT_EXPIRY_DAYS"
 	NEW_POLICY_PATH     string = "NEW_POLICY_PATH"
 	NEW_CONFIG_PATH     string = "NEW_CONFIG_PATH"
 	OLD_CONFIG_PATH     string = "OLD_CONFIG_PATH"
@@ -154,9 +156,14 @@ const (
 	NEW_CONFIG_PATH_VAL         string = "/etc/alb2/nginx/nginx.conf.new"
 	OLD_CONFIG_PATH_VAL         string = "/etc/alb2/nginx/nginx.conf"
 	STATUS_FILE_PARENT_PATH_VAL string = "/etc/alb2/nginx/last_status"
+	NGINX_CHECK_DIR_VAL         string = "/etc/alb2/nginx/check"
+	LAST_GOOD_DIR_VAL           string = "/etc/alb2/nginx/last_good"
 	TWEAK_DIR_VAL               string = "/alb/tweak/"
 	INTERVAL_VAL                int    = 5
+	RELOAD_DEBOUNCE_VAL         int    = 200
 	DEFAULT_RELOAD_TIMEOUT_VAL  int    = 600
+	NGINX_CHECK_TIMEOUT_VAL     int    = 30
+	CERT_EXPIRY_DAYS_VAL        int    = 30
 )
 
 // TODO we should use alb cr instead of env..
@@ -0,0 +1,196 @@
+package clienttls
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/clienttls/types"
+	"alauda.io/alb2/pkg/controller/ext/waf"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	"github.com/go-logr/logr"
+	nv1 "k8s.io/api/networking/v1"
+)
+
+const (
+	VerifyOn           = "on"
+	VerifyOff          = "off"
+	VerifyOptional     = "optional"
+	VerifyOptionalNoCa = "optional_no_ca"
+	DefaultVerifyDepth = 1
+)
+
+var validVerifyClient = map[string]bool{
+	VerifyOn:           true,
+	VerifyOff:          true,
+	VerifyOptional:     true,
+	VerifyOptionalNoCa: true,
+}
+
+// ClientTLSCtl verify the certificate of client (mtls).
+// the ca is sent to client and used to verify its certificate in the tls handshake (see getCertMap and cert_tool.lua),
+// and the result of verification is checked per request by the client_tls plugin, since the handshake does not fail
+// when the certificate of client is invalid.
+type ClientTLSCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewClientTLSCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &ClientTLSCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		IngressAnnotationToRule: x.IngressAnnotationToRule,
+		InitL7Ft:                x.InitL7Ft,
+		ToInternalRule:          x.ToInternalRule,
+		ToPolicy:                x.ToPolicy,
+	}
+}
+
+// 配置优先级：index.{rindex}-{pindex}.alb.ingress.{domain} > alb.ingress.{domain} > nginx.ingress.kubernetes.io
+func (x *ClientTLSCtl) IngressAnnotationToRule(ing *nv1.Ingress, rindex int, pindex int, rule *albv1.Rule) {
+	prefix := []string{fmt.Sprintf("index.%d-%d.alb.ingress.%s", rindex, pindex, x.domain), fmt.Sprintf("alb.ingress.%s", x.domain), "nginx.ingress.kubernetes.io"}
+	ing_tls := ClientTLSIngress{}
+	has, err := ResolverClientTLSIngressFromAnnotation(&ing_tls, ing.Annotations, prefix)
+	if err != nil || !has {
+		return
+	}
+	cr, err := IngressToClientTLSCr(&ing_tls, ing.Namespace)
+	if err != nil {
+		x.log.Error(err, "invalid auth-tls annotation", "ingress", ing.Name)
+		return
+	}
+	if cr == nil {
+		return
+	}
+	rule.Spec.Config.ClientTLS = cr
+}
+
+// return nil if auth-tls-secret is not set. the secret could be in format ns/name or name, the namespace of ingress is used by default.
+func IngressToClientTLSCr(ing *ClientTLSIngress, ns string) (*ClientTLSCr, error) {
+	secret := strings.TrimSpace(ing.Secret)
+	if secret == "" {
+		return nil, nil
+	}
+	if !strings.Contains(secret, "/") {
+		secret = ns + "/" + secret
+	}
+	cr := &ClientTLSCr{
+		CaSecretRef:  secret + "#" + ct.CaCert,
+		VerifyClient: strings.ToLower(strings.TrimSpace(ing.VerifyClient)),
+	}
+	if ing.VerifyDepth != "" {
+		depth, err := strconv.Atoi(strings.TrimSpace(ing.VerifyDepth))
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth-tls-verify-depth %w", err)
+		}
+		cr.VerifyDepth = &depth
+	}
+	if ing.PassCertificateToUpstream != "" {
+		pass, err := strconv.ParseBool(strings.TrimSpace(ing.PassCertificateToUpstream))
+		if err != nil {
+			return nil, fmt.Errorf("invalid auth-tls-pass-certificate-to-upstream %w", err)
+		}
+		cr.PassCertificateToUpstream = pass
+	}
+	if err := Valid(cr); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func Valid(cfg *ClientTLSCr) error {
+	if cfg.VerifyClient != "" && !validVerifyClient[cfg.VerifyClient] {
+		return fmt.Errorf("invalid verify client %s", cfg.VerifyClient)
+	}
+	if cfg.VerifyDepth != nil && *cfg.VerifyDepth <= 0 {
+		return fmt.Errorf("verify depth should be greater than 0")
+	}
+	if !Enabled(cfg) {
+		return nil
+	}
+	if _, _, _, err := waf.ParseCmRef(cfg.CaSecretRef); err != nil {
+		return fmt.Errorf("invalid ca secret ref %s", cfg.CaSecretRef)
+	}
+	return nil
+}
+
+func Enabled(cfg *ClientTLSCr) bool {
+	return cfg != nil && cfg.VerifyClient != VerifyOff
+}
+
+func GetVerifyClient(cfg *ClientTLSCr) string {
+	if cfg.VerifyClient == "" {
+		return VerifyOn
+	}
+	return cfg.VerifyClient
+}
+
+func GetVerifyDepth(cfg *ClientTLSCr) int {
+	if cfg.VerifyDepth == nil {
+		return DefaultVerifyDepth
+	}
+	return *cfg.VerifyDepth
+}
+
+// CaId identify the ca which the client is verified with in the tls handshake. the ca is chosen by sni, but the rule is
+// matched by host, so the client_tls plugin compares it with the one of rule, a client verified by the ca of other domain is rejected.
+func CaId(cfg *ClientTLSCr) string {
+	return fmt.Sprintf("%s/%d", cfg.CaSecretRef, GetVerifyDepth(cfg))
+}
+
+func isTLSFt(protocol albv1.FtProtocol) bool {
+	return protocol == albv1.FtProtocolHTTPS || protocol == albv1.FtProtocolgRPC
+}
+
+// the client tls of ft is used to verify the client which does not match any rule with domain, e.g. request without sni.
+func (x *ClientTLSCtl) InitL7Ft(mft *m.Frontend, cft *ct.Frontend) {
+	cfg := mft.GetFtConfig()
+	if cfg == nil || cfg.ClientTLS == nil || !isTLSFt(cft.Protocol) {
+		return
+	}
+	cft.Config.ClientTLS = cfg.ClientTLS
+}
+
+// 配置优先级：Rule Config > Frontend Config
+func (x *ClientTLSCtl) ToInternalRule(rule *m.Rule, ir *ct.InternalRule) {
+	if rule.FT == nil || !isTLSFt(rule.FT.Spec.Protocol) {
+		return
+	}
+	if rule.GetConfig() != nil && rule.GetConfig().ClientTLS != nil {
+		ir.Config.ClientTLS = rule.GetConfig().ClientTLS
+		ir.Config.Source[ct.ClientTLS] = rule.Name
+		return
+	}
+	if rule.GetFtConfig() != nil && rule.GetFtConfig().ClientTLS != nil {
+		ir.Config.ClientTLS = rule.GetFtConfig().ClientTLS
+		ir.Config.Source[ct.ClientTLS] = rule.FT.Name
+		return
+	}
+}
+
+func (x *ClientTLSCtl) ToPolicy(ir *ct.InternalRule, p *ct.Policy, refs ct.RefMap) {
+	cfg := ir.Config.ClientTLS
+	if !Enabled(cfg) {
+		return
+	}
+	verify := GetVerifyClient(cfg)
+	caId := CaId(cfg)
+	if err := Valid(cfg); err != nil {
+		// the ca will not be sent to client, so the request will be rejected.
+		// the id is left empty, so that a client verified by the ca of other domain could not pass either.
+		x.log.Error(err, "invalid client tls config, reject all request", "rule", ir.RuleID)
+		verify = VerifyOn
+		caId = ""
+	}
+	p.Config.ClientTLS = &ClientTLSPolicy{
+		VerifyClient:              verify,
+		PassCertificateToUpstream: cfg.PassCertificateToUpstream,
+		CaId:                      caId,
+	}
+}
@@ -0,0 +1,52 @@
+package clienttls
+
+import (
+	"testing"
+
+	ct "alauda.io/alb2/controller/types"
+	. "alauda.io/alb2/pkg/controller/ext/clienttls/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestIngressToClientTLSCr(t *testing.T) {
+	cr, err := IngressToClientTLSCr(&ClientTLSIngress{VerifyClient: "on"}, "default")
+	assert.NoError(t, err)
+	assert.Nil(t, cr)
+
+	cr, err = IngressToClientTLSCr(&ClientTLSIngress{Secret: "ca", VerifyClient: "optional", VerifyDepth: "2", PassCertificateToUpstream: "true"}, "default")
+	assert.NoError(t, err)
+	depth := 2
+	assert.Equal(t, &ClientTLSCr{CaSecretRef: "default/ca#ca.crt", VerifyClient: "optional", VerifyDepth: &depth, PassCertificateToUpstream: true}, cr)
+
+	cr, err = IngressToClientTLSCr(&ClientTLSIngress{Secret: "cpaas-system/ca"}, "default")
+	assert.NoError(t, err)
+	assert.Equal(t, "cpaas-system/ca#ca.crt", cr.CaSecretRef)
+	assert.Equal(t, VerifyOn, GetVerifyClient(cr))
+	assert.Equal(t, DefaultVerifyDepth, GetVerifyDepth(cr))
+
+	_, err = IngressToClientTLSCr(&ClientTLSIngress{Secret: "ca", VerifyClient: "require"}, "default")
+	assert.Error(t, err)
+	_, err = IngressToClientTLSCr(&ClientTLSIngress{Secret: "ca", VerifyDepth: "0"}, "default")
+	assert.Error(t, err)
+	_, err = IngressToClientTLSCr(&ClientTLSIngress{Secret: "ca", PassCertificateToUpstream: "yes"}, "default")
+	assert.Error(t, err)
+}
+
+func TestToPolicy(t *testing.T) {
+	x := &ClientTLSCtl{log: logr.Discard()}
+	ir := &ct.InternalRule{}
+	p := &ct.Policy{}
+	ir.Config.ClientTLS = &ClientTLSCr{VerifyClient: VerifyOff}
+	x.ToPolicy(ir, p, ct.RefMap{})
+	assert.Nil(t, p.Config.ClientTLS)
+
+	ir.Config.ClientTLS = &ClientTLSCr{CaSecretRef: "default/ca#ca.crt", VerifyClient: VerifyOptional, PassCertificateToUpstream: true}
+	x.ToPolicy(ir, p, ct.RefMap{})
+	assert.Equal(t, &ClientTLSPolicy{VerifyClient: VerifyOptional, PassCertificateToUpstream: true, CaId: "default/ca#ca.crt/1"}, p.Config.ClientTLS)
+
+	// invalid config rejects all request
+	ir.Config.ClientTLS = &ClientTLSCr{CaSecretRef: "ca", VerifyClient: VerifyOptionalNoCa}
+	x.ToPolicy(ir, p, ct.RefMap{})
+	assert.Equal(t, &ClientTLSPolicy{VerifyClient: VerifyOn}, p.Config.ClientTLS)
+}
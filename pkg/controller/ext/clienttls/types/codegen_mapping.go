@@ -0,0 +1,52 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	// make go happy
+	_ = strings.Clone
+	_ = fmt.Sprintf
+}
+
+var ClientTLSIngressAnnotationList = []string{
+
+	"auth-tls-pass-certificate-to-upstream",
+
+	"auth-tls-secret",
+
+	"auth-tls-verify-client",
+
+	"auth-tls-verify-depth",
+}
+
+func ResolverClientTLSIngressFromAnnotation(ing *ClientTLSIngress, annotation map[string]string, prefix []string) (bool, error) {
+	find := false
+	for _, annotation_key := range ClientTLSIngressAnnotationList {
+		for _, prefix := range prefix {
+			annotation_full_key := fmt.Sprintf("%s/%s", prefix, annotation_key)
+			if val, ok := annotation[annotation_full_key]; ok {
+				find = true
+				switch annotation_key {
+
+				case "auth-tls-pass-certificate-to-upstream":
+					ing.PassCertificateToUpstream = val
+
+				case "auth-tls-secret":
+					ing.Secret = val
+
+				case "auth-tls-verify-client":
+					ing.VerifyClient = val
+
+				case "auth-tls-verify-depth":
+					ing.VerifyDepth = val
+
+				}
+				break
+			}
+		}
+	}
+	return find, nil
+}
@@ -0,0 +1,31 @@
+package types
+
+// auth-tls-* are compatible with ingress-nginx.
+type ClientTLSIngress struct {
+	Secret                    string `annotation:"auth-tls-secret"`        // in format ns/name, the ca is read from ca.crt
+	VerifyClient              string `annotation:"auth-tls-verify-client"` // on off optional optional_no_ca
+	VerifyDepth               string `annotation:"auth-tls-verify-depth"`
+	PassCertificateToUpstream string `annotation:"auth-tls-pass-certificate-to-upstream"`
+}
+
+// verify the certificate of client (mtls). only take effect on https/grpc frontend.
+// the ca is bound to the sni of the tls handshake, so rules with the same domain in a frontend should use the same ca.
+// +k8s:deepcopy-gen=true
+type ClientTLSCr struct {
+	// secret which contains the pem encoded ca bundle, in format ns/name#key.
+	CaSecretRef string `json:"caSecretRef"`
+	// one of on off optional optional_no_ca, default is on, the same as ssl_verify_client of nginx.
+	VerifyClient string `json:"verifyClient,omitempty"`
+	// default is 1, the same as nginx.
+	VerifyDepth *int `json:"verifyDepth,omitempty"`
+	// pass the escaped client certificate to upstream via the ssl-client-cert header.
+	PassCertificateToUpstream bool `json:"passCertificateToUpstream,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type ClientTLSPolicy struct {
+	VerifyClient              string `json:"verify_client"`
+	PassCertificateToUpstream bool   `json:"pass_certificate_to_upstream"`
+	// id of the ca which should verify the client, see CaId.
+	CaId string `json:"ca_id,omitempty"`
+}
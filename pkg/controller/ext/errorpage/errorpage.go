@@ -0,0 +1,261 @@
+package errorpage
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/errorpage/types"
+	"alauda.io/alb2/pkg/controller/ext/waf"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	"github.com/go-logr/logr"
+	nv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultContentType = "text/html"
+	// the default-backend annotation without custom-http-errors is used when the service has no endpoints, the same as ingress-nginx.
+	DefaultBackendCode = 503
+	DefaultBackendPort = 80
+)
+
+// ErrorPageCtl replace the body of error response with a custom page, the body is from a configmap or a service.
+// the page is rendered in the header/body filter phases of lua, so it works for both the response of upstream and the error of alb.
+// config could be set on alb/ft/rule, the nearest one wins.
+type ErrorPageCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewErrorPageCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &ErrorPageCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		IngressAnnotationToRule: x.IngressAnnotationToRule,
+		ToInternalRule:          x.ToInternalRule,
+		CollectRefs:             x.CollectRefs,
+		ToPolicy:                x.ToPolicy,
+	}
+}
+
+// 配置优先级：index.{rindex}-{pindex}.alb.ingress.{domain} > alb.ingress.{domain} > nginx.ingress.kubernetes.io
+func (x *ErrorPageCtl) IngressAnnotationToRule(ing *nv1.Ingress, rindex int, pindex int, rule *albv1.Rule) {
+	prefix := []string{fmt.Sprintf("index.%d-%d.alb.ingress.%s", rindex, pindex, x.domain), fmt.Sprintf("alb.ingress.%s", x.domain), "nginx.ingress.kubernetes.io"}
+	ing_page := ErrorPageIngress{}
+	has, err := ResolverErrorPageIngressFromAnnotation(&ing_page, ing.Annotations, prefix)
+	if err != nil || !has {
+		return
+	}
+	cr, err := IngressToErrorPageCr(&ing_page, ing.Namespace)
+	if err != nil {
+		x.log.Error(err, "invalid errorpage annotation", "ingress", ing.Name)
+		return
+	}
+	rule.Spec.Config.ErrorPage = cr
+}
+
+// alb does not have a global default backend, so custom-http-errors only works with default-backend.
+func IngressToErrorPageCr(ing *ErrorPageIngress, ns string) (*ErrorPageCr, error) {
+	backend := strings.TrimSpace(ing.DefaultBackend)
+	if backend == "" {
+		return nil, fmt.Errorf("custom-http-errors without default-backend is not supported")
+	}
+	svc := &ErrorPageService{Namespace: ns, Name: backend, Port: DefaultBackendPort}
+	if name, port, ok := strings.Cut(backend, ":"); ok {
+		p, err := strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("invalid default-backend %s %w", backend, err)
+		}
+		svc.Name = name
+		svc.Port = p
+	}
+	codes := []int{DefaultBackendCode}
+	if s := strings.TrimSpace(ing.CustomHttpErrors); s != "" {
+		var err error
+		if codes, err = ParseCodes(s); err != nil {
+			return nil, fmt.Errorf("invalid custom-http-errors %w", err)
+		}
+	}
+	cr := &ErrorPageCr{Pages: []ErrorPage{{Codes: codes, Service: svc}}}
+	if err := Valid(cr); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+// ParseCodes parse comma separated status codes.
+func ParseCodes(s string) ([]int, error) {
+	codes := []int{}
+	for _, c := range strings.Split(s, ",") {
+		c = strings.TrimSpace(c)
+		if c == "" {
+			continue
+		}
+		code, err := strconv.Atoi(c)
+		if err != nil {
+			return nil, fmt.Errorf("invalid status code %s", c)
+		}
+		codes = append(codes, code)
+	}
+	return codes, nil
+}
+
+func Valid(cfg *ErrorPageCr) error {
+	for i, p := range cfg.Pages {
+		if len(p.Codes) == 0 {
+			return fmt.Errorf("page %d has no status code", i)
+		}
+		for _, c := range p.Codes {
+			if c < 400 || c > 599 {
+				return fmt.Errorf("invalid status code %d, should be in 400-599", c)
+			}
+		}
+		if (p.CmRef == "") == (p.Service == nil) {
+			return fmt.Errorf("page %d should have one and only one of cmRef and service", i)
+		}
+		if p.CmRef != "" {
+			if _, _, _, err := waf.ParseCmRef(p.CmRef); err != nil {
+				return err
+			}
+		}
+		if p.Service != nil && (p.Service.Name == "" || p.Service.Port <= 0 || p.Service.Port > 65535) {
+			return fmt.Errorf("invalid service %s:%d of page %d", p.Service.Name, p.Service.Port, i)
+		}
+	}
+	return nil
+}
+
+// 配置优先级：Rule Config > Frontend Config > ALB Config
+// the namespace of service is default to the namespace of the cr which the config belongs to.
+func (x *ErrorPageCtl) ToInternalRule(rule *m.Rule, ir *ct.InternalRule) {
+	if rule.GetConfig() != nil && rule.GetConfig().ErrorPage != nil {
+		ir.Config.ErrorPage = withNamespace(rule.GetConfig().ErrorPage, rule.Namespace)
+		ir.Config.Source[ct.ErrorPage] = rule.Name
+		return
+	}
+	if rule.GetFtConfig() != nil && rule.GetFtConfig().ErrorPage != nil {
+		ir.Config.ErrorPage = withNamespace(rule.GetFtConfig().ErrorPage, rule.FT.Namespace)
+		ir.Config.Source[ct.ErrorPage] = rule.FT.Name
+		return
+	}
+	if rule.GetAlbConfig() != nil && rule.GetAlbConfig().ErrorPage != nil {
+		ir.Config.ErrorPage = withNamespace(rule.GetAlbConfig().ErrorPage, rule.FT.LB.Alb.Namespace)
+		ir.Config.Source[ct.ErrorPage] = rule.FT.LB.Alb.Name
+		return
+	}
+}
+
+func withNamespace(cfg *ErrorPageCr, ns string) *ErrorPageCr {
+	cfg = cfg.DeepCopy()
+	for _, p := range cfg.Pages {
+		if p.Service != nil && p.Service.Namespace == "" {
+			p.Service.Namespace = ns
+		}
+	}
+	return cfg
+}
+
+func (x *ErrorPageCtl) CollectRefs(ir *ct.InternalRule, refs ct.RefMap) {
+	cfg := ir.Config.ErrorPage
+	if cfg == nil {
+		return
+	}
+	for _, p := range cfg.Pages {
+		if p.CmRef == "" {
+			continue
+		}
+		ns, name, _, err := waf.ParseCmRef(p.CmRef)
+		if err != nil {
+			x.log.Error(err, "invalid errorpage cmref", "rule", ir.RuleID, "ref", p.CmRef)
+			continue
+		}
+		refs.ConfigMap[client.ObjectKey{Namespace: ns, Name: name}] = nil
+	}
+}
+
+// Services return the services which the pages are fetched from.
+func Services(cfg *ErrorPageCr) []ErrorPageService {
+	ret := []ErrorPageService{}
+	for _, p := range cfg.Pages {
+		if p.Service != nil {
+			ret = append(ret, *p.Service)
+		}
+	}
+	return ret
+}
+
+// UpstreamName return the name of the backend group of the service, it is shared by all rules which use the same service.
+func UpstreamName(svc ErrorPageService) string {
+	return fmt.Sprintf("errorpage-%s-%s-%d", svc.Namespace, svc.Name, svc.Port)
+}
+
+func (x *ErrorPageCtl) ToPolicy(ir *ct.InternalRule, p *ct.Policy, refs ct.RefMap) {
+	cfg := ir.Config.ErrorPage
+	if cfg == nil {
+		return
+	}
+	if err := Valid(cfg); err != nil {
+		x.log.Error(err, "invalid errorpage config, ignore it", "rule", ir.RuleID)
+		return
+	}
+	policy, err := ToErrorPagePolicy(cfg, refs)
+	if err != nil {
+		// the pages which could be found are still used.
+		x.log.Error(err, "invalid errorpage config", "rule", ir.RuleID)
+	}
+	if len(policy.Pages) == 0 {
+		return
+	}
+	p.Config.ErrorPage = policy
+}
+
+// ToErrorPagePolicy return the policy and the error of the pages which could not be resolved.
+func ToErrorPagePolicy(cfg *ErrorPageCr, refs ct.RefMap) (*ErrorPagePolicy, error) {
+	policy := &ErrorPagePolicy{Pages: []ErrorPagePolicyPage{}}
+	errs := []string{}
+	for _, page := range cfg.Pages {
+		pp := ErrorPagePolicyPage{Codes: append([]int{}, page.Codes...)}
+		if page.Service != nil {
+			pp.Upstream = UpstreamName(*page.Service)
+			policy.Pages = append(policy.Pages, pp)
+			continue
+		}
+		body, err := bodyFromCm(page.CmRef, refs)
+		if err != nil {
+			errs = append(errs, err.Error())
+			continue
+		}
+		pp.Body = body
+		pp.ContentType = page.ContentType
+		if pp.ContentType == "" {
+			pp.ContentType = DefaultContentType
+		}
+		policy.Pages = append(policy.Pages, pp)
+	}
+	if len(errs) != 0 {
+		return policy, fmt.Errorf("%s", strings.Join(errs, ";"))
+	}
+	return policy, nil
+}
+
+func bodyFromCm(ref string, refs ct.RefMap) (string, error) {
+	ns, name, key, err := waf.ParseCmRef(ref)
+	if err != nil {
+		return "", err
+	}
+	cm := refs.ConfigMap[client.ObjectKey{Namespace: ns, Name: name}]
+	if cm == nil {
+		return "", fmt.Errorf("could not find configmap %s", ref)
+	}
+	body, has := cm.Data[key]
+	if !has {
+		return "", fmt.Errorf("could not find key in configmap %s", ref)
+	}
+	return body, nil
+}
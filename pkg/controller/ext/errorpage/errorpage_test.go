@@ -0,0 +1,99 @@
+package errorpage
+
+import (
+	"testing"
+
+	ct "alauda.io/alb2/controller/types"
+	. "alauda.io/alb2/pkg/controller/ext/errorpage/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestIngressToErrorPageCr(t *testing.T) {
+	cr, err := IngressToErrorPageCr(&ErrorPageIngress{CustomHttpErrors: "404, 503", DefaultBackend: "err:8080"}, "ns1")
+	assert.NoError(t, err)
+	assert.Equal(t, &ErrorPageCr{Pages: []ErrorPage{{Codes: []int{404, 503}, Service: &ErrorPageService{Namespace: "ns1", Name: "err", Port: 8080}}}}, cr)
+
+	// default-backend only is used when the service has no endpoints.
+	cr, err = IngressToErrorPageCr(&ErrorPageIngress{DefaultBackend: "err"}, "ns1")
+	assert.NoError(t, err)
+	assert.Equal(t, &ErrorPageCr{Pages: []ErrorPage{{Codes: []int{503}, Service: &ErrorPageService{Namespace: "ns1", Name: "err", Port: 80}}}}, cr)
+
+	for _, ing := range []ErrorPageIngress{
+		{CustomHttpErrors: "404"},
+		{DefaultBackend: "err:http"},
+		{DefaultBackend: "err:0"},
+		{DefaultBackend: "err", CustomHttpErrors: "404,abc"},
+		{DefaultBackend: "err", CustomHttpErrors: "200"},
+	} {
+		_, err := IngressToErrorPageCr(&ing, "ns1")
+		assert.Error(t, err, ing)
+	}
+}
+
+func TestValid(t *testing.T) {
+	svc := &ErrorPageService{Name: "err", Port: 80}
+	assert.NoError(t, Valid(&ErrorPageCr{Pages: []ErrorPage{{Codes: []int{404}, CmRef: "ns/cm#404.html"}, {Codes: []int{502}, Service: svc}}}))
+	invalid := []ErrorPage{
+		{CmRef: "ns/cm#404.html"},
+		{Codes: []int{302}, CmRef: "ns/cm#404.html"},
+		{Codes: []int{404}},
+		{Codes: []int{404}, CmRef: "ns/cm#404.html", Service: svc},
+		{Codes: []int{404}, CmRef: "cm#404.html"},
+		{Codes: []int{404}, Service: &ErrorPageService{Name: "err", Port: 65536}},
+	}
+	for _, p := range invalid {
+		assert.Error(t, Valid(&ErrorPageCr{Pages: []ErrorPage{p}}), p)
+	}
+}
+
+func TestWithNamespace(t *testing.T) {
+	cfg := &ErrorPageCr{Pages: []ErrorPage{
+		{Codes: []int{502}, Service: &ErrorPageService{Name: "a", Port: 80}},
+		{Codes: []int{503}, Service: &ErrorPageService{Namespace: "other", Name: "b", Port: 80}},
+	}}
+	out := withNamespace(cfg, "ns1")
+	assert.Equal(t, "ns1", out.Pages[0].Service.Namespace)
+	assert.Equal(t, "other", out.Pages[1].Service.Namespace)
+	// the cr is shared by rules, it should not be modified.
+	assert.Equal(t, "", cfg.Pages[0].Service.Namespace)
+}
+
+func TestToPolicy(t *testing.T) {
+	x := &ErrorPageCtl{log: logr.Discard()}
+	refs := ct.RefMap{ConfigMap: map[client.ObjectKey]*corev1.ConfigMap{}, Secret: map[client.ObjectKey]*corev1.Secret{}}
+
+	ir := &ct.InternalRule{}
+	ir.RuleID = "r1"
+	ir.Config.ErrorPage = &ErrorPageCr{Pages: []ErrorPage{
+		{Codes: []int{404}, CmRef: "ns1/page#404.html"},
+		{Codes: []int{502, 503}, Service: &ErrorPageService{Namespace: "ns1", Name: "err", Port: 80}},
+	}}
+	x.CollectRefs(ir, refs)
+	assert.Contains(t, refs.ConfigMap, client.ObjectKey{Namespace: "ns1", Name: "page"})
+
+	// the page of missing configmap is skipped.
+	p := &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.Equal(t, &ErrorPagePolicy{Pages: []ErrorPagePolicyPage{{Codes: []int{502, 503}, Upstream: "errorpage-ns1-err-80"}}}, p.Config.ErrorPage)
+
+	refs.ConfigMap[client.ObjectKey{Namespace: "ns1", Name: "page"}] = &corev1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns1", Name: "page"},
+		Data:       map[string]string{"404.html": "<h1>not found</h1>"},
+	}
+	p = &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.Equal(t, &ErrorPagePolicy{Pages: []ErrorPagePolicyPage{
+		{Codes: []int{404}, ContentType: DefaultContentType, Body: "<h1>not found</h1>"},
+		{Codes: []int{502, 503}, Upstream: "errorpage-ns1-err-80"},
+	}}, p.Config.ErrorPage)
+
+	// invalid config is ignored.
+	ir.Config.ErrorPage = &ErrorPageCr{Pages: []ErrorPage{{Codes: []int{404}}}}
+	p = &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.Nil(t, p.Config.ErrorPage)
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	// make go happy
+	_ = strings.Clone
+	_ = fmt.Sprintf
+}
+
+var ErrorPageIngressAnnotationList = []string{
+
+	"custom-http-errors",
+
+	"default-backend",
+}
+
+func ResolverErrorPageIngressFromAnnotation(ing *ErrorPageIngress, annotation map[string]string, prefix []string) (bool, error) {
+	find := false
+	for _, annotation_key := range ErrorPageIngressAnnotationList {
+		for _, prefix := range prefix {
+			annotation_full_key := fmt.Sprintf("%s/%s", prefix, annotation_key)
+			if val, ok := annotation[annotation_full_key]; ok {
+				find = true
+				switch annotation_key {
+
+				case "custom-http-errors":
+					ing.CustomHttpErrors = val
+
+				case "default-backend":
+					ing.DefaultBackend = val
+
+				}
+				break
+			}
+		}
+	}
+	return find, nil
+}
@@ -0,0 +1,48 @@
+package types
+
+// custom-http-errors/default-backend are compatible with ingress-nginx.
+type ErrorPageIngress struct {
+	CustomHttpErrors string `annotation:"custom-http-errors"` // comma separated status codes, e.g. "404,503"
+	DefaultBackend   string `annotation:"default-backend"`    // service in the namespace of ingress, in format name or name:port, port default 80
+}
+
+// replace the body of error response with a custom page.
+// +k8s:deepcopy-gen=true
+type ErrorPageCr struct {
+	// the first page which matches the status code is used.
+	Pages []ErrorPage `json:"pages,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type ErrorPage struct {
+	// status codes to replace, from 400 to 599.
+	Codes []int `json:"codes"`
+	// configmap which contains the body, in format ns/name#key.
+	CmRef string `json:"cmRef,omitempty"`
+	// content type of the body from configmap, default text/html.
+	ContentType string `json:"contentType,omitempty"`
+	// fetch the page from the service instead, the status code is passed via the X-Code header.
+	Service *ErrorPageService `json:"service,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type ErrorPageService struct {
+	// default is the namespace of rule/ft/alb which the config belongs to.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	Port      int    `json:"port"`
+}
+
+// +k8s:deepcopy-gen=true
+type ErrorPagePolicy struct {
+	Pages []ErrorPagePolicyPage `json:"pages"`
+}
+
+// +k8s:deepcopy-gen=true
+type ErrorPagePolicyPage struct {
+	Codes       []int  `json:"codes"`
+	ContentType string `json:"content_type,omitempty"`
+	Body        string `json:"body,omitempty"`
+	// name of the backend group of the service.
+	Upstream string `json:"upstream,omitempty"`
+}
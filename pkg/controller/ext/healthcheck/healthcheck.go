@@ -0,0 +1,221 @@
+package healthcheck
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/healthcheck/types"
+	"alauda.io/alb2/pkg/controller/ext/timeout"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	"github.com/go-logr/logr"
+	nv1 "k8s.io/api/networking/v1"
+)
+
+const (
+	TypeHTTP  = "http"
+	TypeHTTPS = "https"
+	TypeTCP   = "tcp"
+
+	DefaultPath               = "/"
+	DefaultIntervalMs         = 5000
+	DefaultTimeoutMs          = 1000
+	DefaultHealthyThreshold   = 2
+	DefaultUnhealthyThreshold = 3
+	// the checker in lua wakes up every second.
+	MinIntervalMs = 1000
+)
+
+var validType = map[string]bool{
+	TypeHTTP:  true,
+	TypeHTTPS: true,
+	TypeTCP:   true,
+}
+
+// HealthCheckCtl probe the endpoints of service group actively.
+// the config is set on the service group of rule/ft, and rendered into the backend group of policy.json,
+// the probe is done by the worker 0 of nginx, and the unhealthy endpoints are ejected from the balancer of each worker.
+type HealthCheckCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewHealthCheckCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &HealthCheckCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		IngressAnnotationToRule: x.IngressAnnotationToRule,
+	}
+}
+
+// 配置优先级：index.{rindex}-{pindex}.alb.ingress.{domain} > alb.ingress.{domain}
+func (x *HealthCheckCtl) IngressAnnotationToRule(ing *nv1.Ingress, rindex int, pindex int, rule *albv1.Rule) {
+	if rule.Spec.ServiceGroup == nil {
+		return
+	}
+	prefix := []string{fmt.Sprintf("index.%d-%d.alb.ingress.%s", rindex, pindex, x.domain), fmt.Sprintf("alb.ingress.%s", x.domain)}
+	ing_hc := HealthCheckIngress{}
+	has, err := ResolverHealthCheckIngressFromAnnotation(&ing_hc, ing.Annotations, prefix)
+	if err != nil || !has {
+		return
+	}
+	cr, err := IngressToHealthCheckCr(&ing_hc)
+	if err != nil {
+		x.log.Error(err, "invalid health check annotation", "ingress", ing.Name)
+		return
+	}
+	rule.Spec.ServiceGroup.HealthCheck = cr
+}
+
+func IngressToHealthCheckCr(ing *HealthCheckIngress) (*HealthCheckCr, error) {
+	cr := &HealthCheckCr{
+		Type: strings.ToLower(strings.TrimSpace(ing.Type)),
+		Path: strings.TrimSpace(ing.Path),
+		Host: strings.TrimSpace(ing.Host),
+	}
+	var err error
+	if cr.Port, err = parseInt(ing.Port); err != nil {
+		return nil, fmt.Errorf("invalid health-check-port %w", err)
+	}
+	if cr.IntervalMs, err = timeout.ParseTimeout(strings.TrimSpace(ing.Interval)); err != nil {
+		return nil, fmt.Errorf("invalid health-check-interval %w", err)
+	}
+	if cr.TimeoutMs, err = timeout.ParseTimeout(strings.TrimSpace(ing.Timeout)); err != nil {
+		return nil, fmt.Errorf("invalid health-check-timeout %w", err)
+	}
+	if cr.HealthyThreshold, err = parseUint(ing.HealthyThreshold); err != nil {
+		return nil, fmt.Errorf("invalid health-check-healthy-threshold %w", err)
+	}
+	if cr.UnhealthyThreshold, err = parseUint(ing.UnhealthyThreshold); err != nil {
+		return nil, fmt.Errorf("invalid health-check-unhealthy-threshold %w", err)
+	}
+	for _, s := range strings.Split(ing.ExpectedStatus, ",") {
+		s = strings.TrimSpace(s)
+		if s == "" {
+			continue
+		}
+		code, err := strconv.Atoi(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid health-check-expected-status %w", err)
+		}
+		cr.ExpectedStatus = append(cr.ExpectedStatus, code)
+	}
+	if v := strings.TrimSpace(ing.Verify); v != "" {
+		if cr.Verify, err = strconv.ParseBool(v); err != nil {
+			return nil, fmt.Errorf("invalid health-check-verify %w", err)
+		}
+	}
+	if err := Valid(cr); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func parseInt(s string) (*int, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.Atoi(s)
+	if err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+func parseUint(s string) (*uint, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseUint(s, 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	ret := uint(v)
+	return &ret, nil
+}
+
+func Valid(cfg *HealthCheckCr) error {
+	if cfg.Type != "" && !validType[cfg.Type] {
+		return fmt.Errorf("invalid health check type %s", cfg.Type)
+	}
+	if cfg.Path != "" && !strings.HasPrefix(cfg.Path, "/") {
+		return fmt.Errorf("health check path should start with /")
+	}
+	if cfg.Port != nil && (*cfg.Port <= 0 || *cfg.Port > 65535) {
+		return fmt.Errorf("invalid health check port %d", *cfg.Port)
+	}
+	if cfg.IntervalMs != nil && *cfg.IntervalMs < MinIntervalMs {
+		return fmt.Errorf("health check interval should not be less than %dms", MinIntervalMs)
+	}
+	if cfg.TimeoutMs != nil && *cfg.TimeoutMs == 0 {
+		return fmt.Errorf("health check timeout should be greater than 0")
+	}
+	if cfg.HealthyThreshold != nil && *cfg.HealthyThreshold == 0 {
+		return fmt.Errorf("health check healthy threshold should be greater than 0")
+	}
+	if cfg.UnhealthyThreshold != nil && *cfg.UnhealthyThreshold == 0 {
+		return fmt.Errorf("health check unhealthy threshold should be greater than 0")
+	}
+	for _, code := range cfg.ExpectedStatus {
+		if code < 100 || code > 599 {
+			return fmt.Errorf("invalid health check expected status %d", code)
+		}
+	}
+	return nil
+}
+
+// ToPolicy fill the default value of health check. return nil if cfg is nil.
+func ToPolicy(cfg *HealthCheckCr) (*HealthCheckPolicy, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	if err := Valid(cfg); err != nil {
+		return nil, err
+	}
+	p := &HealthCheckPolicy{
+		Type:               TypeHTTP,
+		IntervalMs:         DefaultIntervalMs,
+		TimeoutMs:          DefaultTimeoutMs,
+		HealthyThreshold:   DefaultHealthyThreshold,
+		UnhealthyThreshold: DefaultUnhealthyThreshold,
+		Host:               cfg.Host,
+	}
+	if cfg.Type != "" {
+		p.Type = cfg.Type
+	}
+	if p.Type != TypeTCP {
+		p.Path = DefaultPath
+		if cfg.Path != "" {
+			p.Path = cfg.Path
+		}
+		if len(cfg.ExpectedStatus) != 0 {
+			p.ExpectedStatus = append([]int{}, cfg.ExpectedStatus...)
+			sort.Ints(p.ExpectedStatus)
+		}
+		p.Verify = p.Type == TypeHTTPS && cfg.Verify
+	} else {
+		p.Host = ""
+	}
+	if cfg.Port != nil {
+		p.Port = *cfg.Port
+	}
+	if cfg.IntervalMs != nil {
+		p.IntervalMs = *cfg.IntervalMs
+	}
+	if cfg.TimeoutMs != nil {
+		p.TimeoutMs = *cfg.TimeoutMs
+	}
+	if cfg.HealthyThreshold != nil {
+		p.HealthyThreshold = *cfg.HealthyThreshold
+	}
+	if cfg.UnhealthyThreshold != nil {
+		p.UnhealthyThreshold = *cfg.UnhealthyThreshold
+	}
+	return p, nil
+}
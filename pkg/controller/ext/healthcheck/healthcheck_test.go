@@ -0,0 +1,99 @@
+package healthcheck
+
+import (
+	"testing"
+
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/healthcheck/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	nv1 "k8s.io/api/networking/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestIngressToHealthCheckCr(t *testing.T) {
+	cr, err := IngressToHealthCheckCr(&HealthCheckIngress{Type: "HTTP", Path: "/healthz", Interval: "10s", Timeout: "500ms", UnhealthyThreshold: "5", ExpectedStatus: "200, 204"})
+	assert.NoError(t, err)
+	assert.Equal(t, TypeHTTP, cr.Type)
+	assert.Equal(t, uint(10000), *cr.IntervalMs)
+	assert.Equal(t, uint(500), *cr.TimeoutMs)
+	assert.Equal(t, uint(5), *cr.UnhealthyThreshold)
+	assert.Nil(t, cr.HealthyThreshold)
+	assert.Equal(t, []int{200, 204}, cr.ExpectedStatus)
+	assert.False(t, cr.Verify)
+
+	cr, err = IngressToHealthCheckCr(&HealthCheckIngress{Type: "https", Verify: "true"})
+	assert.NoError(t, err)
+	assert.True(t, cr.Verify)
+
+	for _, ing := range []HealthCheckIngress{
+		{Type: "udp"},
+		{Path: "healthz"},
+		{Port: "70000"},
+		{Interval: "500ms"},
+		{HealthyThreshold: "0"},
+		{ExpectedStatus: "200,abc"},
+		{ExpectedStatus: "700"},
+		{Verify: "yes"},
+	} {
+		_, err := IngressToHealthCheckCr(&ing)
+		assert.Error(t, err, ing)
+	}
+}
+
+func TestIngressAnnotationToRule(t *testing.T) {
+	x := &HealthCheckCtl{log: logr.Discard(), domain: "cpaas.io"}
+	ing := &nv1.Ingress{ObjectMeta: metav1.ObjectMeta{Annotations: map[string]string{
+		"alb.ingress.cpaas.io/health-check-type":                "tcp",
+		"index.0-1.alb.ingress.cpaas.io/health-check-type":      "http",
+		"nginx.ingress.kubernetes.io/health-check-path":         "/ignored",
+		"index.0-1.alb.ingress.cpaas.io/health-check-interval":  "2s",
+		"alb.ingress.cpaas.io/health-check-unhealthy-threshold": "x",
+	}}}
+	rule := &albv1.Rule{Spec: albv1.RuleSpec{ServiceGroup: &albv1.ServiceGroup{}}}
+	// invalid annotation is ignored.
+	x.IngressAnnotationToRule(ing, 0, 1, rule)
+	assert.Nil(t, rule.Spec.ServiceGroup.HealthCheck)
+
+	delete(ing.Annotations, "alb.ingress.cpaas.io/health-check-unhealthy-threshold")
+	x.IngressAnnotationToRule(ing, 0, 1, rule)
+	assert.Equal(t, TypeHTTP, rule.Spec.ServiceGroup.HealthCheck.Type)
+	assert.Equal(t, "", rule.Spec.ServiceGroup.HealthCheck.Path)
+	assert.Equal(t, uint(2000), *rule.Spec.ServiceGroup.HealthCheck.IntervalMs)
+
+	rule = &albv1.Rule{Spec: albv1.RuleSpec{ServiceGroup: &albv1.ServiceGroup{}}}
+	x.IngressAnnotationToRule(ing, 1, 0, rule)
+	assert.Equal(t, TypeTCP, rule.Spec.ServiceGroup.HealthCheck.Type)
+}
+
+func TestToPolicy(t *testing.T) {
+	p, err := ToPolicy(nil)
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	p, err = ToPolicy(&HealthCheckCr{})
+	assert.NoError(t, err)
+	assert.Equal(t, &HealthCheckPolicy{Type: TypeHTTP, Path: DefaultPath, IntervalMs: DefaultIntervalMs, TimeoutMs: DefaultTimeoutMs, HealthyThreshold: DefaultHealthyThreshold, UnhealthyThreshold: DefaultUnhealthyThreshold}, p)
+
+	port := 8080
+	p, err = ToPolicy(&HealthCheckCr{Type: TypeTCP, Path: "/a", Host: "a.com", Port: &port, ExpectedStatus: []int{200}})
+	assert.NoError(t, err)
+	assert.Equal(t, &HealthCheckPolicy{Type: TypeTCP, Port: 8080, IntervalMs: DefaultIntervalMs, TimeoutMs: DefaultTimeoutMs, HealthyThreshold: DefaultHealthyThreshold, UnhealthyThreshold: DefaultUnhealthyThreshold}, p)
+
+	p, err = ToPolicy(&HealthCheckCr{Type: TypeHTTPS, Host: "a.com", ExpectedStatus: []int{204, 200}})
+	assert.NoError(t, err)
+	assert.Equal(t, "a.com", p.Host)
+	assert.Equal(t, []int{200, 204}, p.ExpectedStatus)
+	assert.False(t, p.Verify)
+
+	// verify only makes sense for https.
+	p, err = ToPolicy(&HealthCheckCr{Type: TypeHTTPS, Verify: true})
+	assert.NoError(t, err)
+	assert.True(t, p.Verify)
+	p, err = ToPolicy(&HealthCheckCr{Type: TypeHTTP, Verify: true})
+	assert.NoError(t, err)
+	assert.False(t, p.Verify)
+
+	_, err = ToPolicy(&HealthCheckCr{Type: "udp"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,82 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	// make go happy
+	_ = strings.Clone
+	_ = fmt.Sprintf
+}
+
+var HealthCheckIngressAnnotationList = []string{
+
+	"health-check-expected-status",
+
+	"health-check-healthy-threshold",
+
+	"health-check-host",
+
+	"health-check-interval",
+
+	"health-check-path",
+
+	"health-check-port",
+
+	"health-check-timeout",
+
+	"health-check-type",
+
+	"health-check-unhealthy-threshold",
+
+	"health-check-verify",
+}
+
+func ResolverHealthCheckIngressFromAnnotation(ing *HealthCheckIngress, annotation map[string]string, prefix []string) (bool, error) {
+	find := false
+	for _, annotation_key := range HealthCheckIngressAnnotationList {
+		for _, prefix := range prefix {
+			annotation_full_key := fmt.Sprintf("%s/%s", prefix, annotation_key)
+			if val, ok := annotation[annotation_full_key]; ok {
+				find = true
+				switch annotation_key {
+
+				case "health-check-expected-status":
+					ing.ExpectedStatus = val
+
+				case "health-check-healthy-threshold":
+					ing.HealthyThreshold = val
+
+				case "health-check-host":
+					ing.Host = val
+
+				case "health-check-interval":
+					ing.Interval = val
+
+				case "health-check-path":
+					ing.Path = val
+
+				case "health-check-port":
+					ing.Port = val
+
+				case "health-check-timeout":
+					ing.Timeout = val
+
+				case "health-check-type":
+					ing.Type = val
+
+				case "health-check-unhealthy-threshold":
+					ing.UnhealthyThreshold = val
+
+				case "health-check-verify":
+					ing.Verify = val
+
+				}
+				break
+			}
+		}
+	}
+	return find, nil
+}
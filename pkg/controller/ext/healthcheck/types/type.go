@@ -0,0 +1,54 @@
+package types
+
+// annotations of health check, only alb.ingress.{domain} prefix is supported since ingress-nginx does not have active health check.
+type HealthCheckIngress struct {
+	Type               string `annotation:"health-check-type"`                // http, https or tcp
+	Path               string `annotation:"health-check-path"`                // path of http probe
+	Host               string `annotation:"health-check-host"`                // host header of http probe
+	Port               string `annotation:"health-check-port"`                // port to probe
+	Interval           string `annotation:"health-check-interval"`            // 5s or 500ms
+	Timeout            string `annotation:"health-check-timeout"`             // 1s or 500ms
+	HealthyThreshold   string `annotation:"health-check-healthy-threshold"`   // consecutive successes to mark endpoint healthy
+	UnhealthyThreshold string `annotation:"health-check-unhealthy-threshold"` // consecutive failures to mark endpoint unhealthy
+	ExpectedStatus     string `annotation:"health-check-expected-status"`     // e.g. "200,204"
+	Verify             string `annotation:"health-check-verify"`              // "true" to verify the certificate of https probe
+}
+
+// active health check of the endpoints of a service group, unhealthy endpoints will be ejected by balancer.
+// +k8s:deepcopy-gen=true
+type HealthCheckCr struct {
+	// http, https or tcp. default is http.
+	Type string `json:"type,omitempty"`
+	// path of http probe. default is /.
+	Path string `json:"path,omitempty"`
+	// host header of http probe. default is the address of endpoint.
+	Host string `json:"host,omitempty"`
+	// port to probe. default is the port of endpoint.
+	Port *int `json:"port,omitempty"`
+	// default is 5000.
+	IntervalMs *uint `json:"intervalMs,omitempty"`
+	// default is 1000.
+	TimeoutMs *uint `json:"timeoutMs,omitempty"`
+	// consecutive successes to mark a unhealthy endpoint healthy. default is 2.
+	HealthyThreshold *uint `json:"healthyThreshold,omitempty"`
+	// consecutive failures to mark a healthy endpoint unhealthy. default is 3.
+	UnhealthyThreshold *uint `json:"unhealthyThreshold,omitempty"`
+	// status code of http probe which means healthy. default is 200-399.
+	ExpectedStatus []int `json:"expectedStatus,omitempty"`
+	// verify the certificate of https probe with the system ca, the name to verify is the host. default is false.
+	Verify bool `json:"verify,omitempty"`
+}
+
+// health check config in backend group of policy.json, all fields are defaulted.
+type HealthCheckPolicy struct {
+	Type               string `json:"type"`
+	Path               string `json:"path,omitempty"`
+	Host               string `json:"host,omitempty"`
+	Port               int    `json:"port,omitempty"`
+	IntervalMs         uint   `json:"interval_ms"`
+	TimeoutMs          uint   `json:"timeout_ms"`
+	HealthyThreshold   uint   `json:"healthy_threshold"`
+	UnhealthyThreshold uint   `json:"unhealthy_threshold"`
+	ExpectedStatus     []int  `json:"expected_status,omitempty"`
+	Verify             bool   `json:"verify,omitempty"`
+}
@@ -0,0 +1,245 @@
+package ipacl
+
+import (
+	"fmt"
+	"net/netip"
+	"strings"
+
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/ipacl/types"
+	"alauda.io/alb2/pkg/controller/ext/waf"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	"github.com/go-logr/logr"
+	nv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// DenyAll is used when the config is invalid, e.g. the configmap could not be found.
+// ipacl is a security feature, so we prefer to reject the requests rather than let them go.
+var DenyAll = []IpRange{
+	{Start: "0.0.0.0", End: "255.255.255.255"},
+	{Start: "::", End: "ffff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"},
+}
+
+// IpAclCtl allow or deny request via the client ip.
+// config could be set on alb/ft/rule, the nearest one wins.
+type IpAclCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewIpAclCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &IpAclCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		IngressAnnotationToRule: x.IngressAnnotationToRule,
+		ToInternalRule:          x.ToInternalRule,
+		CollectRefs:             x.CollectRefs,
+		ToPolicy:                x.ToPolicy,
+	}
+}
+
+// 配置优先级：index.{rindex}-{pindex}.alb.ingress.{domain} > alb.ingress.{domain} > nginx.ingress.kubernetes.io
+func (x *IpAclCtl) IngressAnnotationToRule(ing *nv1.Ingress, rindex int, pindex int, rule *albv1.Rule) {
+	prefix := []string{fmt.Sprintf("index.%d-%d.alb.ingress.%s", rindex, pindex, x.domain), fmt.Sprintf("alb.ingress.%s", x.domain), "nginx.ingress.kubernetes.io"}
+	ing_acl := IpAclIngress{}
+	has, err := ResolverIpAclIngressFromAnnotation(&ing_acl, ing.Annotations, prefix)
+	if err != nil || !has {
+		return
+	}
+	cr, err := IngressToIpAclCr(&ing_acl)
+	if err != nil {
+		x.log.Error(err, "invalid ipacl annotation", "ingress", ing.Name)
+		return
+	}
+	if cr == nil {
+		return
+	}
+	rule.Spec.Config.IpAcl = cr
+}
+
+// return nil if both of whitelist and denylist are empty.
+func IngressToIpAclCr(ing *IpAclIngress) (*IpAclCr, error) {
+	cr := &IpAclCr{
+		Allow: ParseCidrList(ing.Whitelist),
+		Deny:  ParseCidrList(ing.Denylist),
+	}
+	if len(cr.Allow) == 0 && len(cr.Deny) == 0 {
+		return nil, nil
+	}
+	for _, list := range [][]string{cr.Allow, cr.Deny} {
+		for _, c := range list {
+			if _, err := CidrToRange(c); err != nil {
+				return nil, err
+			}
+		}
+	}
+	return cr, nil
+}
+
+// ParseCidrList parse cidr separated by comma or newline. content after # in a line is ignored.
+func ParseCidrList(s string) []string {
+	ret := []string{}
+	for _, line := range strings.Split(s, "\n") {
+		line, _, _ = strings.Cut(line, "#")
+		for _, c := range strings.Split(line, ",") {
+			c = strings.TrimSpace(c)
+			if c == "" {
+				continue
+			}
+			ret = append(ret, c)
+		}
+	}
+	if len(ret) == 0 {
+		return nil
+	}
+	return ret
+}
+
+// CidrToRange return the first and last ip of a cidr. a single ip is treated as a /32 (or /128) cidr.
+func CidrToRange(cidr string) (IpRange, error) {
+	var prefix netip.Prefix
+	if strings.Contains(cidr, "/") {
+		p, err := netip.ParsePrefix(cidr)
+		if err != nil {
+			return IpRange{}, fmt.Errorf("invalid cidr %s %w", cidr, err)
+		}
+		prefix = p.Masked()
+	} else {
+		addr, err := netip.ParseAddr(cidr)
+		if err != nil {
+			return IpRange{}, fmt.Errorf("invalid ip %s %w", cidr, err)
+		}
+		prefix = netip.PrefixFrom(addr, addr.BitLen())
+	}
+	start := prefix.Addr()
+	bytes := start.AsSlice()
+	for i := prefix.Bits(); i < len(bytes)*8; i++ {
+		bytes[i/8] |= 1 << (7 - i%8)
+	}
+	end, _ := netip.AddrFromSlice(bytes)
+	return IpRange{Start: start.String(), End: end.String()}, nil
+}
+
+// 配置优先级：Rule Config > Frontend Config > ALB Config
+func (x *IpAclCtl) ToInternalRule(rule *m.Rule, ir *ct.InternalRule) {
+	if rule.GetConfig() != nil && rule.GetConfig().IpAcl != nil {
+		ir.Config.IpAcl = rule.GetConfig().IpAcl
+		ir.Config.Source[ct.IpAcl] = rule.Name
+		return
+	}
+	if rule.GetFtConfig() != nil && rule.GetFtConfig().IpAcl != nil {
+		ir.Config.IpAcl = rule.GetFtConfig().IpAcl
+		ir.Config.Source[ct.IpAcl] = rule.FT.Name
+		return
+	}
+	if rule.GetAlbConfig() != nil && rule.GetAlbConfig().IpAcl != nil {
+		ir.Config.IpAcl = rule.GetAlbConfig().IpAcl
+		ir.Config.Source[ct.IpAcl] = rule.FT.LB.Alb.Name
+		return
+	}
+}
+
+func (x *IpAclCtl) CollectRefs(ir *ct.InternalRule, refs ct.RefMap) {
+	cfg := ir.Config.IpAcl
+	if cfg == nil {
+		return
+	}
+	for _, ref := range []string{cfg.AllowCmRef, cfg.DenyCmRef} {
+		if ref == "" {
+			continue
+		}
+		ns, name, _, err := waf.ParseCmRef(ref)
+		if err != nil {
+			x.log.Error(err, "invalid ipacl cmref", "rule", ir.RuleID, "ref", ref)
+			continue
+		}
+		refs.ConfigMap[client.ObjectKey{Namespace: ns, Name: name}] = nil
+	}
+}
+
+func (x *IpAclCtl) ToPolicy(ir *ct.InternalRule, p *ct.Policy, refs ct.RefMap) {
+	cfg := ir.Config.IpAcl
+	if cfg == nil {
+		return
+	}
+	policy, err := ToIpAclPolicy(cfg, refs)
+	if err != nil {
+		x.log.Error(err, "invalid ipacl config, deny all request", "rule", ir.RuleID)
+		policy = &IpAclPolicy{Deny: DenyAll}
+	}
+	if policy == nil {
+		return
+	}
+	p.Config.IpAcl = policy
+}
+
+// ToIpAclPolicy return nil if there is nothing to check.
+func ToIpAclPolicy(cfg *IpAclCr, refs ct.RefMap) (*IpAclPolicy, error) {
+	allow := append([]string{}, cfg.Allow...)
+	deny := append([]string{}, cfg.Deny...)
+	if cfg.AllowCmRef != "" {
+		list, err := cidrFromCm(cfg.AllowCmRef, refs)
+		if err != nil {
+			return nil, err
+		}
+		allow = append(allow, list...)
+	}
+	if cfg.DenyCmRef != "" {
+		list, err := cidrFromCm(cfg.DenyCmRef, refs)
+		if err != nil {
+			return nil, err
+		}
+		deny = append(deny, list...)
+	}
+	if len(allow) == 0 && len(deny) == 0 {
+		return nil, nil
+	}
+	p := &IpAclPolicy{}
+	var err error
+	if p.Allow, err = toRanges(allow); err != nil {
+		return nil, err
+	}
+	if p.Deny, err = toRanges(deny); err != nil {
+		return nil, err
+	}
+	if p.TrustedProxies, err = toRanges(cfg.TrustedProxies); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func cidrFromCm(ref string, refs ct.RefMap) ([]string, error) {
+	ns, name, key, err := waf.ParseCmRef(ref)
+	if err != nil {
+		return nil, err
+	}
+	cm := refs.ConfigMap[client.ObjectKey{Namespace: ns, Name: name}]
+	if cm == nil {
+		return nil, fmt.Errorf("could not find configmap %s", ref)
+	}
+	content, has := cm.Data[key]
+	if !has {
+		return nil, fmt.Errorf("could not find key in configmap %s", ref)
+	}
+	return ParseCidrList(content), nil
+}
+
+func toRanges(cidrs []string) ([]IpRange, error) {
+	if len(cidrs) == 0 {
+		return nil, nil
+	}
+	ret := make([]IpRange, 0, len(cidrs))
+	for _, c := range cidrs {
+		r, err := CidrToRange(c)
+		if err != nil {
+			return nil, err
+		}
+		ret = append(ret, r)
+	}
+	return ret, nil
+}
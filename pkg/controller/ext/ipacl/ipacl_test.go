@@ -0,0 +1,82 @@
+package ipacl
+
+import (
+	"testing"
+
+	ct "alauda.io/alb2/controller/types"
+	. "alauda.io/alb2/pkg/controller/ext/ipacl/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestCidrToRange(t *testing.T) {
+	cases := map[string]IpRange{
+		"10.0.0.0/8":        {Start: "10.0.0.0", End: "10.255.255.255"},
+		"192.168.1.10/24":   {Start: "192.168.1.0", End: "192.168.1.255"},
+		"1.1.1.1":           {Start: "1.1.1.1", End: "1.1.1.1"},
+		"0.0.0.0/0":         {Start: "0.0.0.0", End: "255.255.255.255"},
+		"fd00::/8":          {Start: "fd00::", End: "fdff:ffff:ffff:ffff:ffff:ffff:ffff:ffff"},
+		"2001:db8::1":       {Start: "2001:db8::1", End: "2001:db8::1"},
+		"2001:db8:0:1::/63": {Start: "2001:db8::", End: "2001:db8:0:1:ffff:ffff:ffff:ffff"},
+	}
+	for cidr, expect := range cases {
+		r, err := CidrToRange(cidr)
+		assert.NoError(t, err, cidr)
+		assert.Equal(t, expect, r, cidr)
+	}
+	_, err := CidrToRange("10.0.0.0/33")
+	assert.Error(t, err)
+	_, err = CidrToRange("abc")
+	assert.Error(t, err)
+}
+
+func TestIngressToIpAclCr(t *testing.T) {
+	cr, err := IngressToIpAclCr(&IpAclIngress{Whitelist: " , "})
+	assert.NoError(t, err)
+	assert.Nil(t, cr)
+
+	cr, err = IngressToIpAclCr(&IpAclIngress{Whitelist: "10.0.0.0/8, 192.168.0.1", Denylist: "10.1.0.0/16"})
+	assert.NoError(t, err)
+	assert.Equal(t, &IpAclCr{Allow: []string{"10.0.0.0/8", "192.168.0.1"}, Deny: []string{"10.1.0.0/16"}}, cr)
+
+	_, err = IngressToIpAclCr(&IpAclIngress{Denylist: "10.0.0.0/8,x"})
+	assert.Error(t, err)
+}
+
+func TestToIpAclPolicy(t *testing.T) {
+	refs := ct.RefMap{
+		ConfigMap: map[client.ObjectKey]*corev1.ConfigMap{
+			{Namespace: "cpaas-system", Name: "acl"}: {Data: map[string]string{"deny": "# blocked\n1.1.1.1\n2.2.2.0/24, 3.3.3.3 # bad guy\n"}},
+		},
+		Secret: map[client.ObjectKey]*corev1.Secret{},
+	}
+
+	p, err := ToIpAclPolicy(&IpAclCr{TrustedProxies: []string{"10.0.0.0/8"}}, refs)
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	p, err = ToIpAclPolicy(&IpAclCr{Deny: []string{"4.4.4.4"}, DenyCmRef: "cpaas-system/acl#deny", TrustedProxies: []string{"10.0.0.0/8"}}, refs)
+	assert.NoError(t, err)
+	assert.Equal(t, &IpAclPolicy{
+		Deny: []IpRange{
+			{Start: "4.4.4.4", End: "4.4.4.4"},
+			{Start: "1.1.1.1", End: "1.1.1.1"},
+			{Start: "2.2.2.0", End: "2.2.2.255"},
+			{Start: "3.3.3.3", End: "3.3.3.3"},
+		},
+		TrustedProxies: []IpRange{{Start: "10.0.0.0", End: "10.255.255.255"}},
+	}, p)
+
+	_, err = ToIpAclPolicy(&IpAclCr{AllowCmRef: "cpaas-system/none#allow"}, refs)
+	assert.Error(t, err)
+
+	// invalid config denies all request
+	x := &IpAclCtl{log: logr.Discard()}
+	ir := &ct.InternalRule{}
+	ir.Config.IpAcl = &IpAclCr{AllowCmRef: "cpaas-system/acl#allow"}
+	policy := &ct.Policy{}
+	x.ToPolicy(ir, policy, refs)
+	assert.Equal(t, &IpAclPolicy{Deny: DenyAll}, policy.Config.IpAcl)
+}
@@ -0,0 +1,42 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	// make go happy
+	_ = strings.Clone
+	_ = fmt.Sprintf
+}
+
+var IpAclIngressAnnotationList = []string{
+
+	"denylist-source-range",
+
+	"whitelist-source-range",
+}
+
+func ResolverIpAclIngressFromAnnotation(ing *IpAclIngress, annotation map[string]string, prefix []string) (bool, error) {
+	find := false
+	for _, annotation_key := range IpAclIngressAnnotationList {
+		for _, prefix := range prefix {
+			annotation_full_key := fmt.Sprintf("%s/%s", prefix, annotation_key)
+			if val, ok := annotation[annotation_full_key]; ok {
+				find = true
+				switch annotation_key {
+
+				case "denylist-source-range":
+					ing.Denylist = val
+
+				case "whitelist-source-range":
+					ing.Whitelist = val
+
+				}
+				break
+			}
+		}
+	}
+	return find, nil
+}
@@ -0,0 +1,38 @@
+package types
+
+// whitelist-source-range/denylist-source-range are compatible with ingress-nginx, comma separated cidr list.
+type IpAclIngress struct {
+	Whitelist string `annotation:"whitelist-source-range"`
+	Denylist  string `annotation:"denylist-source-range"`
+}
+
+// allow or deny request via the client ip.
+// a request is denied if the client ip is in the deny list, or the allow list is not empty and the client ip is not in it.
+// +k8s:deepcopy-gen=true
+type IpAclCr struct {
+	// cidr or ip, e.g. 10.0.0.0/8, 192.168.1.1, fd00::/8.
+	Allow []string `json:"allow,omitempty"`
+	Deny  []string `json:"deny,omitempty"`
+	// configmap which contains the allow list, in format ns/name#key. one cidr per line or comma separated, line starts with # is ignored.
+	// merged with allow.
+	AllowCmRef string `json:"allowCmRef,omitempty"`
+	// configmap which contains the deny list, in the same format as allowCmRef. merged with deny.
+	DenyCmRef string `json:"denyCmRef,omitempty"`
+	// cidr of the trusted proxies. the client ip is the remote addr (or the addr of proxy protocol) by default,
+	// x-forwarded-for is only used when the request comes from a trusted proxy.
+	TrustedProxies []string `json:"trustedProxies,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type IpAclPolicy struct {
+	Allow          []IpRange `json:"allow,omitempty"`
+	Deny           []IpRange `json:"deny,omitempty"`
+	TrustedProxies []IpRange `json:"trusted_proxies,omitempty"`
+}
+
+// the first and last ip of a cidr.
+// +k8s:deepcopy-gen=true
+type IpRange struct {
+	Start string `json:"start"`
+	End   string `json:"end"`
+}
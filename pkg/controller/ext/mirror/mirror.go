@@ -0,0 +1,99 @@
+package mirror
+
+import (
+	"fmt"
+	"strings"
+
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	. "alauda.io/alb2/pkg/controller/ext/mirror/types"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	"github.com/go-logr/logr"
+)
+
+// MirrorCtl copy request to another service, the response of mirror service will be ignored.
+// mirror could only be configured on rule. since the mirror service is always a specific version of a service.
+type MirrorCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewMirrorCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &MirrorCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		ToInternalRule: x.ToInternalRule,
+		ToPolicy:       x.ToPolicy,
+	}
+}
+
+func (x *MirrorCtl) ToInternalRule(rule *m.Rule, ir *ct.InternalRule) {
+	if rule.GetConfig() == nil || rule.GetConfig().Mirror == nil {
+		return
+	}
+	mirror := rule.GetConfig().Mirror.DeepCopy()
+	if mirror.Namespace == "" {
+		mirror.Namespace = rule.Namespace
+	}
+	ir.Config.Mirror = mirror
+	ir.Config.Source[ct.Mirror] = rule.Name
+}
+
+// ToPolicy should be called after backends filled up, since we need the name of mirror backend group.
+func (x *MirrorCtl) ToPolicy(ir *ct.InternalRule, p *ct.Policy, refs ct.RefMap) {
+	if ir.Config.Mirror == nil {
+		return
+	}
+	if err := Valid(ir.Config.Mirror); err != nil {
+		x.log.Error(err, "invalid mirror config, ignore it", "rule", ir.RuleID)
+		return
+	}
+	if ir.MirrorBackendGroup == nil {
+		x.log.Info("mirror backend group not found, ignore", "rule", ir.RuleID)
+		return
+	}
+	percent := ir.Config.Mirror.Percent
+	if percent != nil && *percent == 100 {
+		percent = nil
+	}
+	p.Config.Mirror = &MirrorPolicy{
+		Upstream:        ir.MirrorBackendGroup.Name,
+		BackendProtocol: mirrorBackendProtocol(ir.MirrorBackendGroup),
+		Percent:         percent,
+		Verify:          ir.Config.Mirror.Verify,
+		Hostname:        mirrorHostname(ir.Config.Mirror),
+	}
+}
+
+// the mirror request is sent to the pod directly, the sni should be the name of the mirror service instead of the host of request.
+func mirrorHostname(cfg *MirrorCr) string {
+	if cfg.Hostname != "" {
+		return cfg.Hostname
+	}
+	return fmt.Sprintf("%s.%s.svc", cfg.Name, cfg.Namespace)
+}
+
+func Valid(cfg *MirrorCr) error {
+	if cfg.Name == "" {
+		return fmt.Errorf("mirror service name is required")
+	}
+	if cfg.Port <= 0 || cfg.Port > 65535 {
+		return fmt.Errorf("invalid mirror service port %d", cfg.Port)
+	}
+	if cfg.Percent != nil && (*cfg.Percent < 0 || *cfg.Percent > 100) {
+		return fmt.Errorf("invalid mirror percent %d, should be in 0-100", *cfg.Percent)
+	}
+	return nil
+}
+
+// same as rule, use https if backend's app protocol is https.
+func mirrorBackendProtocol(bg *ct.BackendGroup) string {
+	for _, b := range bg.Backends {
+		if b.AppProtocol != nil && strings.ToLower(*b.AppProtocol) == "https" {
+			return "https"
+		}
+	}
+	return "http"
+}
@@ -0,0 +1,88 @@
+package mirror
+
+import (
+	"testing"
+
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/mirror/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+func TestToInternalRule(t *testing.T) {
+	x := &MirrorCtl{log: logr.Discard()}
+	rule := &m.Rule{Rule: &albv1.Rule{
+		ObjectMeta: metav1.ObjectMeta{Namespace: "ns", Name: "r1"},
+		Spec:       albv1.RuleSpec{Config: &albv1.RuleConfigInCr{Mirror: &MirrorCr{Name: "svc", Port: 80}}},
+	}}
+	ir := &ct.InternalRule{}
+	ir.Config.Source = ct.ConfigSource{}
+	x.ToInternalRule(rule, ir)
+	// namespace default to the namespace of rule, and the cr of rule is not modified.
+	assert.Equal(t, &MirrorCr{Namespace: "ns", Name: "svc", Port: 80}, ir.Config.Mirror)
+	assert.Equal(t, "", rule.Spec.Config.Mirror.Namespace)
+	assert.Equal(t, "r1", ir.Config.Source[ct.Mirror])
+}
+
+func TestValid(t *testing.T) {
+	p := func(v int) *int { return &v }
+	assert.NoError(t, Valid(&MirrorCr{Name: "svc", Port: 80}))
+	assert.NoError(t, Valid(&MirrorCr{Name: "svc", Port: 80, Percent: p(0)}))
+	assert.NoError(t, Valid(&MirrorCr{Name: "svc", Port: 80, Percent: p(100)}))
+	assert.Error(t, Valid(&MirrorCr{Name: "svc", Port: 80, Percent: p(-1)}))
+	assert.Error(t, Valid(&MirrorCr{Name: "svc", Port: 80, Percent: p(101)}))
+	assert.Error(t, Valid(&MirrorCr{Name: "svc", Port: 0}))
+	assert.Error(t, Valid(&MirrorCr{Name: "svc", Port: 65536}))
+	assert.Error(t, Valid(&MirrorCr{Port: 80}))
+}
+
+func TestToPolicy(t *testing.T) {
+	x := &MirrorCtl{log: logr.Discard()}
+	p := func(v int) *int { return &v }
+	https := "HTTPS"
+	ir := &ct.InternalRule{}
+	ir.RuleID = "r1"
+	ir.Config.Mirror = &MirrorCr{Namespace: "ns", Name: "svc", Port: 443, Percent: p(10)}
+	ir.MirrorBackendGroup = &ct.BackendGroup{
+		Name:     "mirror-ns-svc-443",
+		Backends: ct.Backends{{Address: "10.0.0.1", Port: 443, AppProtocol: &https}},
+	}
+	policy := &ct.Policy{}
+	x.ToPolicy(ir, policy, ct.RefMap{})
+	assert.Equal(t, &MirrorPolicy{Upstream: "mirror-ns-svc-443", BackendProtocol: "https", Percent: p(10), Hostname: "svc.ns.svc"}, policy.Config.Mirror)
+
+	ir.Config.Mirror.Verify = true
+	ir.Config.Mirror.Hostname = "mirror.example.com"
+	policy = &ct.Policy{}
+	x.ToPolicy(ir, policy, ct.RefMap{})
+	assert.True(t, policy.Config.Mirror.Verify)
+	assert.Equal(t, "mirror.example.com", policy.Config.Mirror.Hostname)
+
+	// mirror all request.
+	ir.Config.Mirror.Percent = p(100)
+	policy = &ct.Policy{}
+	x.ToPolicy(ir, policy, ct.RefMap{})
+	assert.Nil(t, policy.Config.Mirror.Percent)
+
+	// invalid percent is ignored, instead of mirror all request.
+	ir.Config.Mirror.Percent = p(200)
+	policy = &ct.Policy{}
+	x.ToPolicy(ir, policy, ct.RefMap{})
+	assert.Nil(t, policy.Config.Mirror)
+
+	// the service of mirror is not found.
+	ir.Config.Mirror.Percent = nil
+	ir.MirrorBackendGroup = nil
+	policy = &ct.Policy{}
+	x.ToPolicy(ir, policy, ct.RefMap{})
+	assert.Nil(t, policy.Config.Mirror)
+}
+
+func TestMirrorBackendProtocol(t *testing.T) {
+	h2c := "h2c"
+	assert.Equal(t, "http", mirrorBackendProtocol(&ct.BackendGroup{}))
+	assert.Equal(t, "http", mirrorBackendProtocol(&ct.BackendGroup{Backends: ct.Backends{{Address: "10.0.0.1", AppProtocol: &h2c}}}))
+}
@@ -0,0 +1,29 @@
+package types
+
+// mirror request to another service. the response of mirror request will be ignored.
+// +k8s:deepcopy-gen=true
+type MirrorCr struct {
+	// namespace of the mirror service, default is the namespace of rule.
+	Namespace string `json:"namespace,omitempty"`
+	Name      string `json:"name"`
+	// +kubebuilder:validation:Minimum=1
+	// +kubebuilder:validation:Maximum=65535
+	Port int `json:"port"`
+	// percentage of request to mirror, from 0 to 100. mirror all request if not set.
+	// +kubebuilder:validation:Minimum=0
+	// +kubebuilder:validation:Maximum=100
+	Percent *int `json:"percent,omitempty"`
+	// verify the certificate of https mirror service with the system ca. default is false, the same as the upstream without upstreamtls.
+	Verify bool `json:"verify,omitempty"`
+	// used as sni and the name to verify the certificate of https mirror service, default is <name>.<namespace>.svc.
+	Hostname string `json:"hostname,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type MirrorPolicy struct {
+	Upstream        string `json:"upstream"`         // name of the backend group of mirror service
+	BackendProtocol string `json:"backend_protocol"` // http|https
+	Percent         *int   `json:"percent,omitempty"`
+	Verify          bool   `json:"verify"`
+	Hostname        string `json:"hostname"` // sni of https mirror service
+}
@@ -109,6 +109,8 @@ func (o *OtelCtl) ResolveDnsIfNeed(cf *OtelConf) (*OtelConf, error) {
 	if err != nil {
 		return nil, err
 	}
+	// the config may be cached with the policy (see cli.PolicyCache), it should not be modified.
+	cf = cf.DeepCopy()
 	cf.Exporter.Collector.Address = addr
 	return cf, nil
 }
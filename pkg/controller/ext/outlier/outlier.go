@@ -0,0 +1,114 @@
+package outlier
+
+import (
+	"fmt"
+
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	. "alauda.io/alb2/pkg/controller/ext/outlier/types"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	"github.com/go-logr/logr"
+)
+
+const (
+	DefaultConsecutiveErrors  = 5
+	DefaultBaseEjectionTimeMs = 30 * 1000
+	DefaultMaxEjectionTimeMs  = 300 * 1000
+)
+
+// OutlierCtl eject the endpoints which fail consecutively and limit the concurrent requests of upstream.
+// config could be set on alb/ft/rule, the nearest one wins.
+// the state of endpoints is tracked in the http_outlier shared dict by the outlier plugin, so it is shared by all workers
+// but not by the alb instances.
+type OutlierCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewOutlierCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &OutlierCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		ToInternalRule: x.ToInternalRule,
+		ToPolicy:       x.ToPolicy,
+	}
+}
+
+// 配置优先级：Rule Config > Frontend Config > ALB Config
+func (x *OutlierCtl) ToInternalRule(rule *m.Rule, ir *ct.InternalRule) {
+	if rule.GetConfig() != nil && rule.GetConfig().Outlier != nil {
+		ir.Config.Outlier = rule.GetConfig().Outlier
+		ir.Config.Source[ct.Outlier] = rule.Name
+		return
+	}
+	if rule.GetFtConfig() != nil && rule.GetFtConfig().Outlier != nil {
+		ir.Config.Outlier = rule.GetFtConfig().Outlier
+		ir.Config.Source[ct.Outlier] = rule.FT.Name
+		return
+	}
+	if rule.GetAlbConfig() != nil && rule.GetAlbConfig().Outlier != nil {
+		ir.Config.Outlier = rule.GetAlbConfig().Outlier
+		ir.Config.Source[ct.Outlier] = rule.FT.LB.Alb.Name
+		return
+	}
+}
+
+func (x *OutlierCtl) ToPolicy(ir *ct.InternalRule, p *ct.Policy, refs ct.RefMap) {
+	cfg := ir.Config.Outlier
+	if cfg == nil {
+		return
+	}
+	policy, err := ToOutlierPolicy(cfg)
+	if err != nil {
+		// outlier detection is a protection of upstream, the request should not be affected by a invalid config.
+		x.log.Error(err, "invalid outlier config, ignore it", "rule", ir.RuleID)
+		return
+	}
+	if policy == nil {
+		return
+	}
+	p.Config.Outlier = policy
+}
+
+func Valid(cfg *OutlierCr) error {
+	if cfg.BaseEjectionTimeMs != nil && *cfg.BaseEjectionTimeMs == 0 {
+		return fmt.Errorf("base ejection time should be greater than 0")
+	}
+	if cfg.MaxEjectionTimeMs != nil && *cfg.MaxEjectionTimeMs == 0 {
+		return fmt.Errorf("max ejection time should be greater than 0")
+	}
+	return nil
+}
+
+// ToOutlierPolicy fill the default value. return nil if there is nothing to do, e.g. both ejection and concurrent limit are disabled.
+func ToOutlierPolicy(cfg *OutlierCr) (*OutlierPolicy, error) {
+	if err := Valid(cfg); err != nil {
+		return nil, err
+	}
+	p := &OutlierPolicy{
+		ConsecutiveErrors:  DefaultConsecutiveErrors,
+		BaseEjectionTimeMs: DefaultBaseEjectionTimeMs,
+		MaxEjectionTimeMs:  DefaultMaxEjectionTimeMs,
+	}
+	if cfg.ConsecutiveErrors != nil {
+		p.ConsecutiveErrors = *cfg.ConsecutiveErrors
+	}
+	if cfg.BaseEjectionTimeMs != nil {
+		p.BaseEjectionTimeMs = *cfg.BaseEjectionTimeMs
+	}
+	if cfg.MaxEjectionTimeMs != nil {
+		p.MaxEjectionTimeMs = *cfg.MaxEjectionTimeMs
+	}
+	if cfg.MaxConcurrentRequests != nil {
+		p.MaxConcurrentRequests = *cfg.MaxConcurrentRequests
+	}
+	if p.MaxEjectionTimeMs < p.BaseEjectionTimeMs {
+		return nil, fmt.Errorf("max ejection time %d should not be less than base ejection time %d", p.MaxEjectionTimeMs, p.BaseEjectionTimeMs)
+	}
+	if p.ConsecutiveErrors == 0 && p.MaxConcurrentRequests == 0 {
+		return nil, nil
+	}
+	return p, nil
+}
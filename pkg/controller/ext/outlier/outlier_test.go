@@ -0,0 +1,37 @@
+package outlier
+
+import (
+	"testing"
+
+	. "alauda.io/alb2/pkg/controller/ext/outlier/types"
+	"github.com/stretchr/testify/assert"
+)
+
+func uintp(v uint) *uint {
+	return &v
+}
+
+func TestToOutlierPolicy(t *testing.T) {
+	p, err := ToOutlierPolicy(&OutlierCr{})
+	assert.NoError(t, err)
+	assert.Equal(t, &OutlierPolicy{ConsecutiveErrors: 5, BaseEjectionTimeMs: 30000, MaxEjectionTimeMs: 300000}, p)
+
+	p, err = ToOutlierPolicy(&OutlierCr{ConsecutiveErrors: uintp(3), BaseEjectionTimeMs: uintp(1000), MaxEjectionTimeMs: uintp(10000), MaxConcurrentRequests: uintp(100)})
+	assert.NoError(t, err)
+	assert.Equal(t, &OutlierPolicy{ConsecutiveErrors: 3, BaseEjectionTimeMs: 1000, MaxEjectionTimeMs: 10000, MaxConcurrentRequests: 100}, p)
+
+	// only limit the concurrent requests
+	p, err = ToOutlierPolicy(&OutlierCr{ConsecutiveErrors: uintp(0), MaxConcurrentRequests: uintp(10)})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(0), p.ConsecutiveErrors)
+	assert.Equal(t, uint(10), p.MaxConcurrentRequests)
+
+	p, err = ToOutlierPolicy(&OutlierCr{ConsecutiveErrors: uintp(0)})
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	_, err = ToOutlierPolicy(&OutlierCr{BaseEjectionTimeMs: uintp(0)})
+	assert.Error(t, err)
+	_, err = ToOutlierPolicy(&OutlierCr{BaseEjectionTimeMs: uintp(60000), MaxEjectionTimeMs: uintp(1000)})
+	assert.Error(t, err)
+}
@@ -0,0 +1,21 @@
+package types
+
+// passive outlier detection and circuit breaking of the endpoints of upstream, like the outlier detection of envoy.
+// +k8s:deepcopy-gen=true
+type OutlierCr struct {
+	// eject a endpoint after it fails (5xx or connect failure) consecutiveErrors times in a row. default 5, 0 means do not eject.
+	ConsecutiveErrors *uint `json:"consecutiveErrors,omitempty"`
+	// a endpoint is ejected baseEjectionTimeMs * (times it has been ejected). default 30000.
+	BaseEjectionTimeMs *uint `json:"baseEjectionTimeMs,omitempty"`
+	// the max time a endpoint could be ejected. default 300000.
+	MaxEjectionTimeMs *uint `json:"maxEjectionTimeMs,omitempty"`
+	// the max concurrent requests of the upstream in each alb instance, request beyond it will be rejected with 503 immediately. 0 means no limit.
+	MaxConcurrentRequests *uint `json:"maxConcurrentRequests,omitempty"`
+}
+
+type OutlierPolicy struct {
+	ConsecutiveErrors     uint `json:"consecutive_errors"`
+	BaseEjectionTimeMs    uint `json:"base_ejection_time_ms"`
+	MaxEjectionTimeMs     uint `json:"max_ejection_time_ms"`
+	MaxConcurrentRequests uint `json:"max_concurrent_requests"`
+}
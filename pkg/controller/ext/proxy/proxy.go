@@ -0,0 +1,346 @@
+package proxy
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"alauda.io/alb2/config"
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/proxy/types"
+	"alauda.io/alb2/pkg/controller/ext/upstreamtls"
+	"alauda.io/alb2/pkg/controller/ext/waf"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	ngt "alauda.io/alb2/pkg/controller/ngxconf/types"
+	pu "alauda.io/alb2/pkg/utils"
+	"github.com/go-logr/logr"
+	nv1 "k8s.io/api/networking/v1"
+)
+
+// keep those as same as the http snippet of operator, the location inherits them if not set.
+const (
+	DefaultBufferSize = "4k"
+	DefaultBuffers    = "4 32k"
+	// the number of buffers when the buffer size is set via proxy-buffer-size annotation, the same as ingress-nginx.
+	DefaultBuffersNumber = 4
+)
+
+// ProxyCtl config the body size and buffering of the request proxied to upstream.
+// nginx could not set client_max_body_size/proxy_buffering via variable, so we generate a named location for each proxy config,
+// policy which has proxy config will be exec to this location.
+// config could be set on alb/ft/rule, the nearest one wins.
+type ProxyCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewProxyCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &ProxyCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		IngressAnnotationToRule: x.IngressAnnotationToRule,
+		ToInternalRule:          x.ToInternalRule,
+		ToPolicy:                x.ToPolicy,
+		UpdateNgxTmpl:           x.UpdateNgxTmpl,
+	}
+}
+
+// 配置优先级：index.{rindex}-{pindex}.alb.ingress.{domain} > alb.ingress.{domain} > nginx.ingress.kubernetes.io
+func (x *ProxyCtl) IngressAnnotationToRule(ing *nv1.Ingress, rindex int, pindex int, rule *albv1.Rule) {
+	prefix := []string{fmt.Sprintf("index.%d-%d.alb.ingress.%s", rindex, pindex, x.domain), fmt.Sprintf("alb.ingress.%s", x.domain), "nginx.ingress.kubernetes.io"}
+	ing_proxy := ProxyIngress{}
+	has, err := ResolverProxyIngressFromAnnotation(&ing_proxy, ing.Annotations, prefix)
+	if err != nil || !has {
+		return
+	}
+	cr, err := IngressToProxyCr(&ing_proxy)
+	if err != nil {
+		x.log.Error(err, "invalid proxy annotation", "ingress", ing.Name)
+		return
+	}
+	rule.Spec.Config.Proxy = cr
+}
+
+// like ingress-nginx, proxy-buffer-size is used as the size of proxy_buffer_size and each of proxy_buffers.
+func IngressToProxyCr(ing *ProxyIngress) (*ProxyCr, error) {
+	cr := &ProxyCr{}
+	if s := strings.TrimSpace(ing.BodySize); s != "" {
+		cr.ClientMaxBodySize = &s
+	}
+	if s := strings.TrimSpace(ing.Buffering); s != "" {
+		on, err := parseOnOff(s)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy-buffering %w", err)
+		}
+		cr.Buffering = &on
+	}
+	if s := strings.TrimSpace(ing.BufferSize); s != "" {
+		buffers := fmt.Sprintf("%d %s", DefaultBuffersNumber, s)
+		cr.BufferSize = &s
+		cr.Buffers = &buffers
+	}
+	if err := Valid(cr); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func parseOnOff(s string) (bool, error) {
+	switch strings.ToLower(s) {
+	case "on", "true":
+		return true, nil
+	case "off", "false":
+		return false, nil
+	}
+	return false, fmt.Errorf("%s should be on or off", s)
+}
+
+// ParseSize parse the size in the format of nginx, e.g. 1024, 512k, 8m, 1g.
+func ParseSize(s string) (int64, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return 0, fmt.Errorf("empty size")
+	}
+	unit := int64(1)
+	switch s[len(s)-1] {
+	case 'k', 'K':
+		unit = 1 << 10
+	case 'm', 'M':
+		unit = 1 << 20
+	case 'g', 'G':
+		unit = 1 << 30
+	}
+	num := s
+	if unit != 1 {
+		num = s[:len(s)-1]
+	}
+	v, err := strconv.ParseInt(num, 10, 64)
+	if err != nil || v < 0 {
+		return 0, fmt.Errorf("invalid size %s", s)
+	}
+	if v > (1<<62)/unit {
+		return 0, fmt.Errorf("size %s is too large", s)
+	}
+	return v * unit, nil
+}
+
+// nginx does not accept g for buffer size.
+func parseBufferSize(s string) (int64, error) {
+	if strings.HasSuffix(s, "g") || strings.HasSuffix(s, "G") {
+		return 0, fmt.Errorf("buffer size %s is too large", s)
+	}
+	v, err := ParseSize(s)
+	if err != nil {
+		return 0, err
+	}
+	if v == 0 {
+		return 0, fmt.Errorf("buffer size should not be 0")
+	}
+	return v, nil
+}
+
+// parse buffers in format of "number size".
+func parseBuffers(s string) (int64, int64, error) {
+	fields := strings.Fields(s)
+	if len(fields) != 2 {
+		return 0, 0, fmt.Errorf("invalid buffers %s, should be in format of 'number size'", s)
+	}
+	num, err := strconv.ParseInt(fields[0], 10, 64)
+	if err != nil || num < 2 {
+		return 0, 0, fmt.Errorf("invalid buffers %s, number should be at least 2", s)
+	}
+	size, err := parseBufferSize(fields[1])
+	if err != nil {
+		return 0, 0, err
+	}
+	return num, size, nil
+}
+
+// bufferSizes return the proxy_buffer_size and the size of each of proxy_buffers, fallback to the default of http block.
+func bufferSizes(cfg *ProxyCr) (int64, int64, int64, error) {
+	bufferSize := DefaultBufferSize
+	if cfg.BufferSize != nil {
+		bufferSize = *cfg.BufferSize
+	}
+	buffers := DefaultBuffers
+	if cfg.Buffers != nil {
+		buffers = *cfg.Buffers
+	}
+	size, err := parseBufferSize(bufferSize)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	num, bufSize, err := parseBuffers(buffers)
+	if err != nil {
+		return 0, 0, 0, err
+	}
+	return size, num, bufSize, nil
+}
+
+// Valid check the config, so that nginx -t will not fail because of it.
+func Valid(cfg *ProxyCr) error {
+	if cfg.ClientMaxBodySize != nil {
+		if _, err := ParseSize(*cfg.ClientMaxBodySize); err != nil {
+			return fmt.Errorf("invalid client max body size %w", err)
+		}
+	}
+	if cfg.HttpVersion != nil && *cfg.HttpVersion != "1.0" && *cfg.HttpVersion != "1.1" {
+		return fmt.Errorf("invalid http version %s, should be 1.0 or 1.1", *cfg.HttpVersion)
+	}
+	if cfg.BufferSize == nil && cfg.Buffers == nil {
+		return nil
+	}
+	size, num, bufSize, err := bufferSizes(cfg)
+	if err != nil {
+		return err
+	}
+	// proxy_busy_buffers_size should be at least the max of proxy_buffer_size and one of proxy_buffers,
+	// and at most the size of all proxy_buffers minus one buffer.
+	if max(size, bufSize) > (num-1)*bufSize {
+		return fmt.Errorf("buffer size %d is too large for buffers %d*%d", size, num, bufSize)
+	}
+	return nil
+}
+
+// 配置优先级：Rule Config > Frontend Config > ALB Config
+func (x *ProxyCtl) ToInternalRule(rule *m.Rule, ir *ct.InternalRule) {
+	if rule.GetConfig() != nil && rule.GetConfig().Proxy != nil {
+		ir.Config.Proxy = rule.GetConfig().Proxy
+		ir.Config.Source[ct.Proxy] = rule.Name
+		return
+	}
+	if rule.GetFtConfig() != nil && rule.GetFtConfig().Proxy != nil {
+		ir.Config.Proxy = rule.GetFtConfig().Proxy
+		ir.Config.Source[ct.Proxy] = rule.FT.Name
+		return
+	}
+	if rule.GetAlbConfig() != nil && rule.GetAlbConfig().Proxy != nil {
+		ir.Config.Proxy = rule.GetAlbConfig().Proxy
+		ir.Config.Source[ct.Proxy] = rule.FT.LB.Alb.Name
+		return
+	}
+}
+
+func (x *ProxyCtl) ToPolicy(ir *ct.InternalRule, p *ct.Policy, refs ct.RefMap) {
+	cfg := ir.Config.Proxy
+	if cfg == nil {
+		return
+	}
+	if err := Valid(cfg); err != nil {
+		x.log.Error(err, "invalid proxy config, ignore it", "rule", ir.RuleID)
+		return
+	}
+	if cfg.ClientMaxBodySize != nil {
+		size, _ := ParseSize(*cfg.ClientMaxBodySize)
+		if size != 0 {
+			p.Config.Proxy = &ProxyPolicy{ClientMaxBodySize: size}
+		}
+	}
+	loc, ok := x.genLocation(ir, refs, "")
+	if !ok {
+		return
+	}
+	name := loc.Name
+	p.ToLocation = &name
+}
+
+// GenLocation combine the proxy directives with the location of upstream tls, since a request could only be exec to one location.
+// return false if there is nothing to set.
+func GenLocation(ir *ct.InternalRule, refs ct.RefMap, caDir string) (ngt.FtCustomLocation, bool) {
+	raw := GenLocationRaw(ir.Config.Proxy)
+	if raw == "" {
+		return ngt.FtCustomLocation{}, false
+	}
+	name := "proxy_" + pu.Hash(raw)[:16]
+	if ir.Config.UpstreamTLS == nil {
+		return ngt.FtCustomLocation{Name: name, LocationRaw: raw}, true
+	}
+	// the error of upstream tls has already been reported by the upstream tls extension.
+	tls, _ := upstreamtls.GenLocation(ir.Config.UpstreamTLS, refs, caDir)
+	return ngt.FtCustomLocation{
+		Name:        tls.Name + "_" + name,
+		LocationRaw: tls.Raw + "\n" + raw,
+	}, true
+}
+
+// the location of waf is set before, the proxy directives must be merged into it.
+func (x *ProxyCtl) genLocation(ir *ct.InternalRule, refs ct.RefMap, caDir string) (ngt.FtCustomLocation, bool) {
+	loc, ok := GenLocation(ir, refs, caDir)
+	if !ok {
+		return loc, false
+	}
+	return waf.WithWaf(ir, refs, loc), true
+}
+
+// GenLocationRaw render the proxy config into nginx directives. the config should be valid.
+func GenLocationRaw(cfg *ProxyCr) string {
+	if cfg == nil {
+		return ""
+	}
+	lines := []string{}
+	if cfg.ClientMaxBodySize != nil {
+		lines = append(lines, fmt.Sprintf("client_max_body_size %s;", strings.TrimSpace(*cfg.ClientMaxBodySize)))
+	}
+	if cfg.RequestBuffering != nil {
+		lines = append(lines, fmt.Sprintf("proxy_request_buffering %s;", onOff(*cfg.RequestBuffering)))
+	}
+	if cfg.Buffering != nil {
+		lines = append(lines, fmt.Sprintf("proxy_buffering %s;", onOff(*cfg.Buffering)))
+	}
+	if cfg.BufferSize != nil || cfg.Buffers != nil {
+		size, num, bufSize, _ := bufferSizes(cfg)
+		busy := max(size, bufSize)
+		lines = append(lines,
+			fmt.Sprintf("proxy_buffer_size %d;", size),
+			fmt.Sprintf("proxy_buffers %d %d;", num, bufSize),
+			// the busy buffers size and temp file write size of http block may not fit the buffers.
+			fmt.Sprintf("proxy_busy_buffers_size %d;", busy),
+			fmt.Sprintf("proxy_temp_file_write_size %d;", busy),
+		)
+	}
+	if cfg.HttpVersion != nil {
+		lines = append(lines, fmt.Sprintf("proxy_http_version %s;", *cfg.HttpVersion))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func onOff(on bool) string {
+	if on {
+		return "on"
+	}
+	return "off"
+}
+
+func (x *ProxyCtl) UpdateNgxTmpl(tmpl_cfg *ngt.NginxTemplateConfig, alb *ct.LoadBalancer, cfg *config.Config) {
+	caDir := upstreamtls.GetCaDir(cfg)
+	for _, f := range alb.Frontends {
+		ft, has := tmpl_cfg.Frontends[f.String()]
+		if !has {
+			continue
+		}
+		exist := map[string]bool{}
+		for _, loc := range ft.CustomLocation {
+			exist[loc.Name] = true
+		}
+		for _, r := range f.Rules {
+			if r.Config.Proxy == nil || Valid(r.Config.Proxy) != nil {
+				continue
+			}
+			loc, ok := x.genLocation(r, alb.Refs, caDir)
+			if !ok || exist[loc.Name] {
+				continue
+			}
+			exist[loc.Name] = true
+			ft.CustomLocation = append(ft.CustomLocation, loc)
+		}
+		sort.Slice(ft.CustomLocation, func(i, j int) bool {
+			return ft.CustomLocation[i].Name < ft.CustomLocation[j].Name
+		})
+		tmpl_cfg.Frontends[f.String()] = ft
+	}
+}
@@ -0,0 +1,149 @@
+package proxy
+
+import (
+	"strings"
+	"testing"
+
+	ct "alauda.io/alb2/controller/types"
+	. "alauda.io/alb2/pkg/controller/ext/proxy/types"
+	upstreamtls_t "alauda.io/alb2/pkg/controller/ext/upstreamtls/types"
+	waft "alauda.io/alb2/pkg/controller/ext/waf/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func strp(s string) *string {
+	return &s
+}
+
+func boolp(b bool) *bool {
+	return &b
+}
+
+func TestParseSize(t *testing.T) {
+	cases := map[string]int64{
+		"0":    0,
+		"1024": 1024,
+		"8k":   8 << 10,
+		"8K":   8 << 10,
+		"10m":  10 << 20,
+		"1g":   1 << 30,
+	}
+	for s, expect := range cases {
+		v, err := ParseSize(s)
+		assert.NoError(t, err, s)
+		assert.Equal(t, expect, v, s)
+	}
+	for _, s := range []string{"", "m", "-1", "1.5m", "10mb", "1t"} {
+		_, err := ParseSize(s)
+		assert.Error(t, err, s)
+	}
+}
+
+func TestIngressToProxyCr(t *testing.T) {
+	cr, err := IngressToProxyCr(&ProxyIngress{BodySize: "8m", Buffering: "on", BufferSize: "16k"})
+	assert.NoError(t, err)
+	assert.Equal(t, &ProxyCr{ClientMaxBodySize: strp("8m"), Buffering: boolp(true), BufferSize: strp("16k"), Buffers: strp("4 16k")}, cr)
+
+	cr, err = IngressToProxyCr(&ProxyIngress{Buffering: "off"})
+	assert.NoError(t, err)
+	assert.Equal(t, &ProxyCr{Buffering: boolp(false)}, cr)
+
+	_, err = IngressToProxyCr(&ProxyIngress{Buffering: "yes"})
+	assert.Error(t, err)
+	_, err = IngressToProxyCr(&ProxyIngress{BodySize: "8mb"})
+	assert.Error(t, err)
+}
+
+func TestValid(t *testing.T) {
+	assert.NoError(t, Valid(&ProxyCr{}))
+	assert.NoError(t, Valid(&ProxyCr{HttpVersion: strp("1.0")}))
+	assert.Error(t, Valid(&ProxyCr{HttpVersion: strp("2")}))
+	// 4 32k of http block
+	assert.NoError(t, Valid(&ProxyCr{BufferSize: strp("96k")}))
+	assert.Error(t, Valid(&ProxyCr{BufferSize: strp("128k")}))
+	assert.NoError(t, Valid(&ProxyCr{BufferSize: strp("128k"), Buffers: strp("4 128k")}))
+	assert.Error(t, Valid(&ProxyCr{Buffers: strp("1 128k")}))
+	assert.Error(t, Valid(&ProxyCr{Buffers: strp("128k")}))
+	assert.Error(t, Valid(&ProxyCr{Buffers: strp("4 1g")}))
+	assert.Error(t, Valid(&ProxyCr{BufferSize: strp("0")}))
+}
+
+func TestGenLocationRaw(t *testing.T) {
+	assert.Equal(t, "", GenLocationRaw(&ProxyCr{}))
+	assert.Equal(t, `client_max_body_size 8m;
+proxy_request_buffering off;
+proxy_buffering on;
+proxy_buffer_size 16384;
+proxy_buffers 8 16384;
+proxy_busy_buffers_size 16384;
+proxy_temp_file_write_size 16384;
+proxy_http_version 1.0;`, GenLocationRaw(&ProxyCr{
+		ClientMaxBodySize: strp("8m"),
+		RequestBuffering:  boolp(false),
+		Buffering:         boolp(true),
+		BufferSize:        strp("16k"),
+		Buffers:           strp("8 16k"),
+		HttpVersion:       strp("1.0"),
+	}))
+	// the buffers of http block is used if not set.
+	assert.Equal(t, `proxy_buffer_size 65536;
+proxy_buffers 4 32768;
+proxy_busy_buffers_size 65536;
+proxy_temp_file_write_size 65536;`, GenLocationRaw(&ProxyCr{BufferSize: strp("64k")}))
+}
+
+func TestToPolicy(t *testing.T) {
+	x := &ProxyCtl{log: logr.Discard()}
+	refs := ct.RefMap{ConfigMap: map[client.ObjectKey]*corev1.ConfigMap{}, Secret: map[client.ObjectKey]*corev1.Secret{}}
+
+	ir := &ct.InternalRule{}
+	ir.RuleID = "r1"
+	ir.Config.Proxy = &ProxyCr{ClientMaxBodySize: strp("1k"), Buffering: boolp(true)}
+	p := &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.True(t, strings.HasPrefix(*p.ToLocation, "proxy_"))
+	assert.Equal(t, &ProxyPolicy{ClientMaxBodySize: 1024}, p.Config.Proxy)
+
+	// rules with the same config share the same location.
+	ir2 := &ct.InternalRule{}
+	ir2.RuleID = "r2"
+	ir2.Config.Proxy = &ProxyCr{ClientMaxBodySize: strp("1k"), Buffering: boolp(true)}
+	p2 := &ct.Policy{}
+	x.ToPolicy(ir2, p2, refs)
+	assert.Equal(t, *p.ToLocation, *p2.ToLocation)
+
+	// no limit, the body size is not checked by lua.
+	ir.Config.Proxy = &ProxyCr{ClientMaxBodySize: strp("0")}
+	p = &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.Nil(t, p.Config.Proxy)
+	assert.NotNil(t, p.ToLocation)
+
+	// combined with upstream tls
+	ir.Config.UpstreamTLS = &upstreamtls_t.UpstreamTLSCr{Verify: false}
+	p = &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.True(t, strings.HasPrefix(*p.ToLocation, "upstream_tls_noverify_proxy_"))
+
+	// merged into the location of waf.
+	ir.Config.Waf = &waft.WafInternal{Key: "waf_rule_r1", Raw: waft.WafConf{UseRecommend: true}}
+	p = &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.True(t, strings.HasPrefix(*p.ToLocation, "waf_rule_r1_upstream_tls_noverify_proxy_"))
+	loc, ok := x.genLocation(ir, refs, "")
+	assert.True(t, ok)
+	assert.Equal(t, *p.ToLocation, loc.Name)
+	assert.Contains(t, loc.LocationRaw, "modsecurity on;")
+	assert.Contains(t, loc.LocationRaw, "client_max_body_size 0;")
+
+	// invalid config is ignored.
+	ir = &ct.InternalRule{}
+	ir.Config.Proxy = &ProxyCr{ClientMaxBodySize: strp("1x")}
+	p = &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.Nil(t, p.ToLocation)
+	assert.Nil(t, p.Config.Proxy)
+}
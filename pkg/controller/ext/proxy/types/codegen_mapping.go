@@ -0,0 +1,47 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	// make go happy
+	_ = strings.Clone
+	_ = fmt.Sprintf
+}
+
+var ProxyIngressAnnotationList = []string{
+
+	"proxy-body-size",
+
+	"proxy-buffer-size",
+
+	"proxy-buffering",
+}
+
+func ResolverProxyIngressFromAnnotation(ing *ProxyIngress, annotation map[string]string, prefix []string) (bool, error) {
+	find := false
+	for _, annotation_key := range ProxyIngressAnnotationList {
+		for _, prefix := range prefix {
+			annotation_full_key := fmt.Sprintf("%s/%s", prefix, annotation_key)
+			if val, ok := annotation[annotation_full_key]; ok {
+				find = true
+				switch annotation_key {
+
+				case "proxy-body-size":
+					ing.BodySize = val
+
+				case "proxy-buffer-size":
+					ing.BufferSize = val
+
+				case "proxy-buffering":
+					ing.Buffering = val
+
+				}
+				break
+			}
+		}
+	}
+	return find, nil
+}
@@ -0,0 +1,35 @@
+package types
+
+// proxy-body-size/proxy-buffering/proxy-buffer-size are compatible with ingress-nginx.
+type ProxyIngress struct {
+	BodySize   string `annotation:"proxy-body-size"`   // e.g. 8m, 0 means no limit
+	Buffering  string `annotation:"proxy-buffering"`   // on or off
+	BufferSize string `annotation:"proxy-buffer-size"` // e.g. 8k, used as the size of proxy_buffer_size and each of proxy_buffers
+}
+
+// the body size and buffering of the request proxied to upstream.
+// nginx could not set these directives via variable, so rules with the same proxy config share a named location.
+// +k8s:deepcopy-gen=true
+type ProxyCr struct {
+	// max size of the request body, e.g. 512k, 8m, 1g. 0 means no limit, which is the default.
+	// request exceeds it will be rejected with 413.
+	ClientMaxBodySize *string `json:"clientMaxBodySize,omitempty"`
+	// read the whole request body before sending it to upstream. default on.
+	RequestBuffering *bool `json:"requestBuffering,omitempty"`
+	// buffer the response of upstream. default off.
+	Buffering *bool `json:"buffering,omitempty"`
+	// size of the buffer used for reading the first part (the headers) of response, e.g. 8k. default 4k.
+	BufferSize *string `json:"bufferSize,omitempty"`
+	// number and size of the buffers used for reading response, e.g. "8 16k". default "4 32k".
+	Buffers *string `json:"buffers,omitempty"`
+	// http version used to proxy, 1.0 or 1.1. default 1.1.
+	HttpVersion *string `json:"httpVersion,omitempty"`
+}
+
+// client_max_body_size of named location is not checked against the content-length header by nginx,
+// it is checked by the proxy plugin.
+// +k8s:deepcopy-gen=true
+type ProxyPolicy struct {
+	// in bytes.
+	ClientMaxBodySize int64 `json:"client_max_body_size"`
+}
@@ -0,0 +1,364 @@
+package ratelimit
+
+import (
+	"fmt"
+	"net"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"alauda.io/alb2/config"
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/ratelimit/types"
+	"alauda.io/alb2/pkg/controller/ext/waf"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	ngt "alauda.io/alb2/pkg/controller/ngxconf/types"
+	"alauda.io/alb2/utils"
+	"github.com/go-logr/logr"
+	nv1 "k8s.io/api/networking/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	DefaultBurstMultiplier = 5
+	DefaultRejectCode      = 503
+	DefaultRedisPort       = 6379
+	DefaultRedisTimeoutMs  = 100
+	// password of redis are written into this dir which is next to policy.new, see getPassword.
+	PasswordDir = "redis_password"
+)
+
+var validKeyType = map[string]bool{
+	utils.KEY_SRC_IP: true,
+	utils.KEY_HOST:   true,
+	utils.KEY_URL:    true,
+	utils.KEY_METHOD: true,
+	utils.KEY_HEADER: true,
+	utils.KEY_COOKIE: true,
+	utils.KEY_PARAM:  true,
+}
+
+// key type which need a name.
+var namedKeyType = map[string]bool{
+	utils.KEY_HEADER: true,
+	utils.KEY_COOKIE: true,
+	utils.KEY_PARAM:  true,
+}
+
+// RateLimitCtl limit the request rate and concurrent connections of client.
+// config could be set on alb/ft/rule, the nearest one wins, and the counters are shared by the rules which use the same config.
+type RateLimitCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewRateLimitCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &RateLimitCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		IngressAnnotationToRule: x.IngressAnnotationToRule,
+		ToInternalRule:          x.ToInternalRule,
+		CollectRefs:             x.CollectRefs,
+		ToPolicy:                x.ToPolicy,
+		UpdateNgxTmpl:           x.UpdateNgxTmpl,
+	}
+}
+
+// 配置优先级：index.{rindex}-{pindex}.alb.ingress.{domain} > alb.ingress.{domain} > nginx.ingress.kubernetes.io
+func (x *RateLimitCtl) IngressAnnotationToRule(ing *nv1.Ingress, rindex int, pindex int, rule *albv1.Rule) {
+	prefix := []string{fmt.Sprintf("index.%d-%d.alb.ingress.%s", rindex, pindex, x.domain), fmt.Sprintf("alb.ingress.%s", x.domain), "nginx.ingress.kubernetes.io"}
+	ing_limit := RateLimitIngress{}
+	has, err := ResolverRateLimitIngressFromAnnotation(&ing_limit, ing.Annotations, prefix)
+	if err != nil || !has {
+		return
+	}
+	cr, err := IngressToRateLimitCr(&ing_limit)
+	if err != nil {
+		x.log.Error(err, "invalid ratelimit annotation", "ingress", ing.Name)
+		return
+	}
+	if cr == nil {
+		return
+	}
+	rule.Spec.Config.RateLimit = cr
+}
+
+// return nil if none of rps/rpm/connections is set.
+func IngressToRateLimitCr(ing *RateLimitIngress) (*RateLimitCr, error) {
+	cr := &RateLimitCr{}
+	var err error
+	if cr.Rps, err = parseUint(ing.Rps); err != nil {
+		return nil, fmt.Errorf("invalid limit-rps %w", err)
+	}
+	if cr.Rpm, err = parseUint(ing.Rpm); err != nil {
+		return nil, fmt.Errorf("invalid limit-rpm %w", err)
+	}
+	if cr.Connections, err = parseUint(ing.Connections); err != nil {
+		return nil, fmt.Errorf("invalid limit-connections %w", err)
+	}
+	if cr.Rps == nil && cr.Rpm == nil && cr.Connections == nil {
+		return nil, nil
+	}
+	if cr.BurstMultiplier, err = parseUint(ing.BurstMultiplier); err != nil {
+		return nil, fmt.Errorf("invalid limit-burst-multiplier %w", err)
+	}
+	if ing.RejectCode != "" {
+		code, err := strconv.Atoi(ing.RejectCode)
+		if err != nil {
+			return nil, fmt.Errorf("invalid limit-reject-code %w", err)
+		}
+		cr.RejectCode = &code
+	}
+	if ing.Key != "" {
+		key, err := ParseKey(ing.Key)
+		if err != nil {
+			return nil, err
+		}
+		cr.Key = key
+	}
+	if ing.Redis != "" {
+		cr.Redis = &RedisCr{Address: ing.Redis}
+	}
+	return cr, nil
+}
+
+func parseUint(s string) (*uint, error) {
+	if s == "" {
+		return nil, nil
+	}
+	v, err := strconv.ParseUint(strings.TrimSpace(s), 10, 32)
+	if err != nil {
+		return nil, err
+	}
+	ret := uint(v)
+	return &ret, nil
+}
+
+// ParseKey parse key in format type[:name], e.g. SRC_IP, HEADER:x-user-id.
+func ParseKey(s string) (*RateLimitKey, error) {
+	t, name, _ := strings.Cut(strings.TrimSpace(s), ":")
+	key := &RateLimitKey{Type: strings.ToUpper(t), Name: name}
+	if err := validKey(key); err != nil {
+		return nil, err
+	}
+	return key, nil
+}
+
+func validKey(key *RateLimitKey) error {
+	if !validKeyType[key.Type] {
+		return fmt.Errorf("invalid ratelimit key type %s", key.Type)
+	}
+	if namedKeyType[key.Type] && key.Name == "" {
+		return fmt.Errorf("ratelimit key %s need a name", key.Type)
+	}
+	return nil
+}
+
+// 配置优先级：Rule Config > Frontend Config > ALB Config
+func (x *RateLimitCtl) ToInternalRule(rule *m.Rule, ir *ct.InternalRule) {
+	if rule.GetConfig() != nil && rule.GetConfig().RateLimit != nil {
+		ir.Config.RateLimit = rule.GetConfig().RateLimit
+		ir.Config.Source[ct.RateLimit] = rule.Name
+		return
+	}
+	if rule.GetFtConfig() != nil && rule.GetFtConfig().RateLimit != nil {
+		ir.Config.RateLimit = rule.GetFtConfig().RateLimit
+		ir.Config.Source[ct.RateLimit] = rule.FT.Name
+		return
+	}
+	if rule.GetAlbConfig() != nil && rule.GetAlbConfig().RateLimit != nil {
+		ir.Config.RateLimit = rule.GetAlbConfig().RateLimit
+		ir.Config.Source[ct.RateLimit] = rule.FT.LB.Alb.Name
+		return
+	}
+}
+
+func (x *RateLimitCtl) CollectRefs(ir *ct.InternalRule, refs ct.RefMap) {
+	cfg := ir.Config.RateLimit
+	if cfg == nil || cfg.Redis == nil || cfg.Redis.PasswordSecretRef == "" {
+		return
+	}
+	ns, name, _, err := waf.ParseCmRef(cfg.Redis.PasswordSecretRef)
+	if err != nil {
+		x.log.Error(err, "invalid redis password secret ref", "rule", ir.RuleID, "ref", cfg.Redis.PasswordSecretRef)
+		return
+	}
+	refs.Secret[client.ObjectKey{Namespace: ns, Name: name}] = nil
+}
+
+func (x *RateLimitCtl) ToPolicy(ir *ct.InternalRule, p *ct.Policy, refs ct.RefMap) {
+	cfg := ir.Config.RateLimit
+	if cfg == nil {
+		return
+	}
+	policy, err := ToRateLimitPolicy(cfg, ir.Config.Source[ct.RateLimit], refs)
+	if err != nil {
+		x.log.Error(err, "invalid ratelimit config, ignore it", "rule", ir.RuleID)
+		return
+	}
+	if policy == nil {
+		return
+	}
+	p.Config.RateLimit = policy
+}
+
+// ToRateLimitPolicy return nil if there is nothing to limit.
+func ToRateLimitPolicy(cfg *RateLimitCr, zone string, refs ct.RefMap) (*RateLimitPolicy, error) {
+	multiplier := uint(DefaultBurstMultiplier)
+	if cfg.BurstMultiplier != nil {
+		multiplier = *cfg.BurstMultiplier
+	}
+	p := &RateLimitPolicy{
+		Zone:       zone,
+		Key:        RateLimitKey{Type: utils.KEY_SRC_IP},
+		RejectCode: DefaultRejectCode,
+	}
+	if cfg.Rps != nil && *cfg.Rps > 0 {
+		p.Requests = append(p.Requests, RequestLimit{Limit: *cfg.Rps, Window: 1, Burst: *cfg.Rps * multiplier})
+	}
+	if cfg.Rpm != nil && *cfg.Rpm > 0 {
+		p.Requests = append(p.Requests, RequestLimit{Limit: *cfg.Rpm, Window: 60, Burst: *cfg.Rpm * multiplier})
+	}
+	if cfg.Connections != nil {
+		p.Connections = *cfg.Connections
+	}
+	if len(p.Requests) == 0 && p.Connections == 0 {
+		return nil, nil
+	}
+	if cfg.Key != nil {
+		if err := validKey(cfg.Key); err != nil {
+			return nil, err
+		}
+		p.Key = *cfg.Key
+	}
+	if cfg.RejectCode != nil {
+		if *cfg.RejectCode < 100 || *cfg.RejectCode > 599 {
+			return nil, fmt.Errorf("invalid reject code %d", *cfg.RejectCode)
+		}
+		p.RejectCode = *cfg.RejectCode
+	}
+	if cfg.Redis != nil {
+		redis, err := toRedisPolicy(cfg.Redis, refs)
+		if err != nil {
+			return nil, err
+		}
+		p.Redis = redis
+	}
+	return p, nil
+}
+
+func toRedisPolicy(cfg *RedisCr, refs ct.RefMap) (*RedisPolicy, error) {
+	p := &RedisPolicy{
+		Port:      DefaultRedisPort,
+		TimeoutMs: DefaultRedisTimeoutMs,
+	}
+	host, port, err := net.SplitHostPort(cfg.Address)
+	if err != nil {
+		// address without port
+		host = cfg.Address
+	} else {
+		p.Port, err = strconv.Atoi(port)
+		if err != nil {
+			return nil, fmt.Errorf("invalid redis address %s", cfg.Address)
+		}
+	}
+	if host == "" {
+		return nil, fmt.Errorf("invalid redis address %s", cfg.Address)
+	}
+	p.Host = host
+	if cfg.Db != nil {
+		p.Db = *cfg.Db
+	}
+	if cfg.TimeoutMs != nil {
+		p.TimeoutMs = *cfg.TimeoutMs
+	}
+	if cfg.PasswordSecretRef != "" {
+		file, _, err := getPassword(cfg, refs)
+		if err != nil {
+			return nil, err
+		}
+		p.PasswordFile = file
+	}
+	return p, nil
+}
+
+// getPassword return the name of password file and the password. the name changes with the version of secret,
+// so the password cached by nginx is refreshed when the secret is updated.
+func getPassword(cfg *RedisCr, refs ct.RefMap) (string, string, error) {
+	ns, name, key, err := waf.ParseCmRef(cfg.PasswordSecretRef)
+	if err != nil {
+		return "", "", err
+	}
+	secret := refs.Secret[client.ObjectKey{Namespace: ns, Name: name}]
+	if secret == nil || len(secret.Data[key]) == 0 {
+		return "", "", fmt.Errorf("could not find redis password in secret %s", cfg.PasswordSecretRef)
+	}
+	return fmt.Sprintf("%s_%s_%s_%s", ns, name, key, secret.ResourceVersion), string(secret.Data[key]), nil
+}
+
+// GetPasswordDir return the dir which the password of redis are written into.
+func GetPasswordDir(cfg *config.Config) string {
+	return filepath.Join(filepath.Dir(cfg.GetNginxCfg().NewPolicyPath), PasswordDir)
+}
+
+// UpdateNgxTmpl write the password of redis into files, the password is not put into policy, since policy.new is readable by anyone who could read the nginx config.
+func (x *RateLimitCtl) UpdateNgxTmpl(_ *ngt.NginxTemplateConfig, alb *ct.LoadBalancer, cfg *config.Config) {
+	dir := GetPasswordDir(cfg)
+	passwords := map[string]string{}
+	for _, f := range alb.Frontends {
+		for _, r := range f.Rules {
+			rl := r.Config.RateLimit
+			if rl == nil || rl.Redis == nil || rl.Redis.PasswordSecretRef == "" {
+				continue
+			}
+			file, password, err := getPassword(rl.Redis, alb.Refs)
+			if err != nil {
+				continue
+			}
+			passwords[filepath.Join(dir, file)] = password
+		}
+	}
+	if err := syncPasswordFiles(dir, passwords); err != nil {
+		x.log.Error(err, "sync redis password fail", "dir", dir)
+	}
+}
+
+// write the passwords which are used now, and remove the others. the files are readable by the owner and group only.
+func syncPasswordFiles(dir string, passwords map[string]string) error {
+	if len(passwords) == 0 {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for path, password := range passwords {
+		origin, err := os.ReadFile(path)
+		if err == nil && string(origin) == password {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(password), 0o640); err != nil {
+			return err
+		}
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.Name())
+		if _, used := passwords[path]; used {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,79 @@
+package ratelimit
+
+import (
+	"encoding/json"
+	"testing"
+
+	ct "alauda.io/alb2/controller/types"
+	. "alauda.io/alb2/pkg/controller/ext/ratelimit/types"
+	"alauda.io/alb2/utils"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestIngressToRateLimitCr(t *testing.T) {
+	cr, err := IngressToRateLimitCr(&RateLimitIngress{Key: "HEADER:x-user"})
+	assert.NoError(t, err)
+	assert.Nil(t, cr)
+
+	cr, err = IngressToRateLimitCr(&RateLimitIngress{Rps: "10", Connections: "5", Key: "header:x-user", Redis: "redis.default:6380"})
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), *cr.Rps)
+	assert.Equal(t, uint(5), *cr.Connections)
+	assert.Equal(t, RateLimitKey{Type: utils.KEY_HEADER, Name: "x-user"}, *cr.Key)
+	assert.Equal(t, "redis.default:6380", cr.Redis.Address)
+
+	_, err = IngressToRateLimitCr(&RateLimitIngress{Rps: "-1"})
+	assert.Error(t, err)
+	_, err = IngressToRateLimitCr(&RateLimitIngress{Rps: "1", Key: "HEADER"})
+	assert.Error(t, err)
+	_, err = IngressToRateLimitCr(&RateLimitIngress{Rps: "1", Key: "BODY:x"})
+	assert.Error(t, err)
+}
+
+func TestToRateLimitPolicy(t *testing.T) {
+	refs := ct.RefMap{
+		ConfigMap: map[client.ObjectKey]*corev1.ConfigMap{},
+		Secret: map[client.ObjectKey]*corev1.Secret{
+			{Namespace: "cpaas-system", Name: "redis"}: {ObjectMeta: metav1.ObjectMeta{ResourceVersion: "1"}, Data: map[string][]byte{"password": []byte("pass")}},
+		},
+	}
+	u := func(v uint) *uint { return &v }
+
+	p, err := ToRateLimitPolicy(&RateLimitCr{}, "r1", refs)
+	assert.NoError(t, err)
+	assert.Nil(t, p)
+
+	p, err = ToRateLimitPolicy(&RateLimitCr{Rps: u(10), Rpm: u(100)}, "r1", refs)
+	assert.NoError(t, err)
+	assert.Equal(t, &RateLimitPolicy{
+		Zone: "r1",
+		Requests: []RequestLimit{
+			{Limit: 10, Window: 1, Burst: 50},
+			{Limit: 100, Window: 60, Burst: 500},
+		},
+		Key:        RateLimitKey{Type: utils.KEY_SRC_IP},
+		RejectCode: 503,
+	}, p)
+
+	code := 429
+	p, err = ToRateLimitPolicy(&RateLimitCr{
+		Rps:             u(10),
+		BurstMultiplier: u(1),
+		RejectCode:      &code,
+		Redis:           &RedisCr{Address: "redis.default", PasswordSecretRef: "cpaas-system/redis#password"},
+	}, "r1", refs)
+	assert.NoError(t, err)
+	assert.Equal(t, uint(10), p.Requests[0].Burst)
+	assert.Equal(t, 429, p.RejectCode)
+	assert.Equal(t, &RedisPolicy{Host: "redis.default", Port: 6379, PasswordFile: "cpaas-system_redis_password_1", TimeoutMs: 100}, p.Redis)
+	// password is never written into the policy.
+	raw, err := json.Marshal(p)
+	assert.NoError(t, err)
+	assert.NotContains(t, string(raw), "pass\"")
+
+	_, err = ToRateLimitPolicy(&RateLimitCr{Rps: u(10), Redis: &RedisCr{Address: "redis:6379", PasswordSecretRef: "cpaas-system/none#password"}}, "r1", refs)
+	assert.Error(t, err)
+}
@@ -0,0 +1,67 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	// make go happy
+	_ = strings.Clone
+	_ = fmt.Sprintf
+}
+
+var RateLimitIngressAnnotationList = []string{
+
+	"limit-burst-multiplier",
+
+	"limit-connections",
+
+	"limit-key",
+
+	"limit-redis",
+
+	"limit-reject-code",
+
+	"limit-rpm",
+
+	"limit-rps",
+}
+
+func ResolverRateLimitIngressFromAnnotation(ing *RateLimitIngress, annotation map[string]string, prefix []string) (bool, error) {
+	find := false
+	for _, annotation_key := range RateLimitIngressAnnotationList {
+		for _, prefix := range prefix {
+			annotation_full_key := fmt.Sprintf("%s/%s", prefix, annotation_key)
+			if val, ok := annotation[annotation_full_key]; ok {
+				find = true
+				switch annotation_key {
+
+				case "limit-burst-multiplier":
+					ing.BurstMultiplier = val
+
+				case "limit-connections":
+					ing.Connections = val
+
+				case "limit-key":
+					ing.Key = val
+
+				case "limit-redis":
+					ing.Redis = val
+
+				case "limit-reject-code":
+					ing.RejectCode = val
+
+				case "limit-rpm":
+					ing.Rpm = val
+
+				case "limit-rps":
+					ing.Rps = val
+
+				}
+				break
+			}
+		}
+	}
+	return find, nil
+}
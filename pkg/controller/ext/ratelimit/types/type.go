@@ -0,0 +1,79 @@
+package types
+
+// limit-rps/limit-rpm/limit-connections/limit-burst-multiplier are compatible with ingress-nginx.
+type RateLimitIngress struct {
+	Rps             string `annotation:"limit-rps"`
+	Rpm             string `annotation:"limit-rpm"`
+	Connections     string `annotation:"limit-connections"`
+	BurstMultiplier string `annotation:"limit-burst-multiplier"`
+	Key             string `annotation:"limit-key"`         // in format type[:name], e.g. SRC_IP, HEADER:x-user-id
+	RejectCode      string `annotation:"limit-reject-code"` // default 503, the same as ingress-nginx
+	Redis           string `annotation:"limit-redis"`       // address of redis, enable the global mode
+}
+
+// limit the request rate and concurrent connections of client.
+// requests are counted in the shared dict of each alb instance, or in redis (global mode) when redis is configured.
+// connections are always limited per alb instance.
+// +k8s:deepcopy-gen=true
+type RateLimitCr struct {
+	// requests per second.
+	Rps *uint `json:"rps,omitempty"`
+	// requests per minute.
+	Rpm *uint `json:"rpm,omitempty"`
+	// concurrent connections.
+	Connections *uint `json:"connections,omitempty"`
+	// burst = limit * burstMultiplier, default is 5, the same as ingress-nginx. only take effect in local mode.
+	BurstMultiplier *uint `json:"burstMultiplier,omitempty"`
+	// how to identify the client, default is the client ip.
+	Key *RateLimitKey `json:"key,omitempty"`
+	// status code of rejected request, default is 503.
+	RejectCode *int `json:"rejectCode,omitempty"`
+	// global mode, counters are stored in redis and shared by all alb instances.
+	Redis *RedisCr `json:"redis,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type RateLimitKey struct {
+	// one of SRC_IP HOST URL METHOD HEADER COOKIE PARAM, the same as the key in dslx.
+	Type string `json:"type"`
+	// name of the header/cookie/param.
+	Name string `json:"name,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type RedisCr struct {
+	// in format host:port.
+	Address string `json:"address"`
+	// secret which contains the password, in format ns/name#key.
+	PasswordSecretRef string `json:"passwordSecretRef,omitempty"`
+	Db                *int   `json:"db,omitempty"`
+	// default is 100ms. request will be allowed if redis is unavailable.
+	TimeoutMs *uint `json:"timeoutMs,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type RateLimitPolicy struct {
+	Zone        string         `json:"zone"` // counters are shared by the policies in the same zone
+	Requests    []RequestLimit `json:"requests,omitempty"`
+	Connections uint           `json:"connections,omitempty"`
+	Key         RateLimitKey   `json:"key"`
+	RejectCode  int            `json:"reject_code"`
+	Redis       *RedisPolicy   `json:"redis,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type RequestLimit struct {
+	Limit  uint `json:"limit"`  // requests in window
+	Window uint `json:"window"` // in seconds
+	Burst  uint `json:"burst"`
+}
+
+// +k8s:deepcopy-gen=true
+type RedisPolicy struct {
+	Host string `json:"host"`
+	Port int    `json:"port"`
+	// name of the file which contains the password, the password itself is never written into the policy.
+	PasswordFile string `json:"password_file,omitempty"`
+	Db           int    `json:"db"`
+	TimeoutMs    uint   `json:"timeout_ms"`
+}
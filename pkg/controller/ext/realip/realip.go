@@ -0,0 +1,163 @@
+package realip
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"strings"
+
+	"alauda.io/alb2/config"
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/realip/types"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	ngt "alauda.io/alb2/pkg/controller/ngxconf/types"
+	"github.com/go-logr/logr"
+)
+
+const (
+	HeaderProxyProtocol = "proxy_protocol"
+	DefaultHeader       = "X-Forwarded-For"
+)
+
+var headerReg = regexp.MustCompile(`^[A-Za-z0-9_-]+$`)
+
+// RealIPCtl 端口级别的proxy protocol和realip配置,通过nginx template 配置.
+// the client ip is resolved by the realip module of nginx at server level, so $remote_addr is the client ip in access logs,
+// and SRC_IP matching/ipacl use it instead of the x-real-ip/x-forwarded-for which could be spoofed by client.
+type RealIPCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewRealIPCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &RealIPCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		InitL4Ft:      x.InitL4Ft,
+		InitL7Ft:      x.InitL7Ft,
+		UpdateNgxTmpl: x.UpdateNgxTmpl,
+	}
+}
+
+func (x *RealIPCtl) InitL4Ft(mft *m.Frontend, cft *ct.Frontend) {
+	// nginx does not support proxy protocol on udp.
+	if cft.Protocol != albv1.FtProtocolTCP {
+		return
+	}
+	defaultInitFt(mft, cft)
+}
+
+func (x *RealIPCtl) InitL7Ft(mft *m.Frontend, cft *ct.Frontend) {
+	defaultInitFt(mft, cft)
+}
+
+func defaultInitFt(mft *m.Frontend, cft *ct.Frontend) {
+	cfg := mft.GetFtConfig()
+	if cfg == nil {
+		return
+	}
+	cft.Config.ProxyProtocol = cfg.ProxyProtocol
+	cft.Config.RealIP = cfg.RealIP
+}
+
+func Valid(pp *ProxyProtocolCr, rip *RealIPCr, protocol albv1.FtProtocol) error {
+	l4 := protocol == albv1.FtProtocolTCP
+	accept := pp != nil && pp.Accept
+	if pp != nil && pp.Send && !l4 {
+		return fmt.Errorf("send proxy protocol is only supported on tcp frontend")
+	}
+	if rip == nil {
+		return nil
+	}
+	if len(rip.TrustedCIDRs) == 0 {
+		return fmt.Errorf("trustedCIDRs of realIP is empty")
+	}
+	for _, c := range rip.TrustedCIDRs {
+		if _, _, err := net.ParseCIDR(c); err == nil {
+			continue
+		}
+		if net.ParseIP(c) == nil {
+			return fmt.Errorf("invalid trusted cidr %s", c)
+		}
+	}
+	header := Header(pp, rip)
+	if !headerReg.MatchString(header) {
+		return fmt.Errorf("invalid realIP header %s", header)
+	}
+	if header == HeaderProxyProtocol && !accept {
+		return fmt.Errorf("realIP header is proxy_protocol but the proxy protocol is not accepted")
+	}
+	if l4 && header != HeaderProxyProtocol {
+		return fmt.Errorf("only proxy_protocol header is supported on tcp frontend")
+	}
+	return nil
+}
+
+// Header return the header which the client ip comes from.
+func Header(pp *ProxyProtocolCr, rip *RealIPCr) string {
+	if rip != nil && rip.Header != "" {
+		return rip.Header
+	}
+	if pp != nil && pp.Accept {
+		return HeaderProxyProtocol
+	}
+	return DefaultHeader
+}
+
+// GenServerRaw return the directives in the server block, and whether $remote_addr is resolved by the realip module.
+// the peer must be a proxy when the proxy protocol is accepted, so all address is trusted if realIP is not set.
+func GenServerRaw(pp *ProxyProtocolCr, rip *RealIPCr, protocol albv1.FtProtocol) (string, bool) {
+	l4 := protocol == albv1.FtProtocolTCP
+	accept := pp != nil && pp.Accept
+	lines := []string{}
+	cidrs := []string{"0.0.0.0/0", "::/0"}
+	if rip != nil {
+		cidrs = rip.TrustedCIDRs
+	}
+	realip := rip != nil || accept
+	if realip {
+		for _, c := range cidrs {
+			lines = append(lines, fmt.Sprintf("set_real_ip_from %s;", c))
+		}
+		// the stream realip module only supports proxy protocol.
+		if !l4 {
+			lines = append(lines, fmt.Sprintf("real_ip_header %s;", Header(pp, rip)))
+			if rip != nil && rip.Recursive {
+				lines = append(lines, "real_ip_recursive on;")
+			}
+		}
+	}
+	if l4 && pp != nil && pp.Send {
+		lines = append(lines, "proxy_protocol on;")
+	}
+	return strings.Join(lines, "\n"), realip && !l4
+}
+
+func (x *RealIPCtl) UpdateNgxTmpl(tmpl_cfg *ngt.NginxTemplateConfig, alb *ct.LoadBalancer, _ *config.Config) {
+	for _, ft := range alb.Frontends {
+		pp := ft.Config.ProxyProtocol
+		rip := ft.Config.RealIP
+		if pp == nil && rip == nil {
+			continue
+		}
+		if err := Valid(pp, rip, ft.Protocol); err != nil {
+			x.log.Error(err, "invalid proxy protocol or realip config, ignore it", "ft", ft.FtName)
+			continue
+		}
+		ft_tmpl, ok := tmpl_cfg.Frontends[ft.String()]
+		if !ok {
+			continue
+		}
+		if pp != nil && pp.Accept {
+			ft_tmpl.Listen = ft_tmpl.Listen + " proxy_protocol"
+		}
+		server, realip := GenServerRaw(pp, rip, ft.Protocol)
+		ft_tmpl.Server = strings.TrimSpace(ft_tmpl.Server + "\n" + server)
+		ft_tmpl.RealIP = realip
+		tmpl_cfg.Frontends[ft.String()] = ft_tmpl
+	}
+}
@@ -0,0 +1,75 @@
+package realip
+
+import (
+	"testing"
+
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/realip/types"
+	ngt "alauda.io/alb2/pkg/controller/ngxconf/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestValid(t *testing.T) {
+	accept := &ProxyProtocolCr{Accept: true}
+	rip := &RealIPCr{TrustedCIDRs: []string{"10.0.0.0/8", "192.168.1.1", "fd00::/8"}}
+	assert.NoError(t, Valid(accept, nil, albv1.FtProtocolHTTP))
+	assert.NoError(t, Valid(nil, rip, albv1.FtProtocolHTTPS))
+	assert.NoError(t, Valid(accept, rip, albv1.FtProtocolTCP))
+	assert.NoError(t, Valid(&ProxyProtocolCr{Send: true}, nil, albv1.FtProtocolTCP))
+
+	invalid := []struct {
+		pp       *ProxyProtocolCr
+		rip      *RealIPCr
+		protocol albv1.FtProtocol
+	}{
+		{&ProxyProtocolCr{Send: true}, nil, albv1.FtProtocolHTTP},
+		{nil, &RealIPCr{}, albv1.FtProtocolHTTP},
+		{nil, &RealIPCr{TrustedCIDRs: []string{"10.0.0.0/33"}}, albv1.FtProtocolHTTP},
+		{nil, &RealIPCr{TrustedCIDRs: []string{"10.0.0.1"}, Header: "X-Real-IP;"}, albv1.FtProtocolHTTP},
+		{nil, &RealIPCr{TrustedCIDRs: []string{"10.0.0.1"}, Header: HeaderProxyProtocol}, albv1.FtProtocolHTTP},
+		// tcp only supports the address of proxy protocol.
+		{nil, rip, albv1.FtProtocolTCP},
+	}
+	for _, c := range invalid {
+		assert.Error(t, Valid(c.pp, c.rip, c.protocol), c)
+	}
+}
+
+func TestGenServerRaw(t *testing.T) {
+	raw, realip := GenServerRaw(&ProxyProtocolCr{Accept: true}, nil, albv1.FtProtocolHTTP)
+	assert.Equal(t, "set_real_ip_from 0.0.0.0/0;\nset_real_ip_from ::/0;\nreal_ip_header proxy_protocol;", raw)
+	assert.True(t, realip)
+
+	raw, realip = GenServerRaw(nil, &RealIPCr{TrustedCIDRs: []string{"10.0.0.0/8"}, Recursive: true}, albv1.FtProtocolHTTPS)
+	assert.Equal(t, "set_real_ip_from 10.0.0.0/8;\nreal_ip_header X-Forwarded-For;\nreal_ip_recursive on;", raw)
+	assert.True(t, realip)
+
+	raw, realip = GenServerRaw(&ProxyProtocolCr{Accept: true, Send: true}, &RealIPCr{TrustedCIDRs: []string{"10.0.0.0/8"}}, albv1.FtProtocolTCP)
+	assert.Equal(t, "set_real_ip_from 10.0.0.0/8;\nproxy_protocol on;", raw)
+	assert.False(t, realip)
+
+	raw, realip = GenServerRaw(&ProxyProtocolCr{Send: true}, nil, albv1.FtProtocolTCP)
+	assert.Equal(t, "proxy_protocol on;", raw)
+	assert.False(t, realip)
+}
+
+func TestUpdateNgxTmpl(t *testing.T) {
+	x := &RealIPCtl{log: logr.Discard()}
+	http := &ct.Frontend{Port: 80, Protocol: albv1.FtProtocolHTTP}
+	http.Config.ProxyProtocol = &ProxyProtocolCr{Accept: true}
+	invalid := &ct.Frontend{Port: 81, Protocol: albv1.FtProtocolHTTP}
+	invalid.Config.RealIP = &RealIPCr{}
+	alb := &ct.LoadBalancer{Frontends: []*ct.Frontend{http, invalid}}
+	tmpl := &ngt.NginxTemplateConfig{Frontends: map[string]ngt.FtConfig{
+		http.String():    {Listen: "so_keepalive=on"},
+		invalid.String(): {},
+	}}
+	x.UpdateNgxTmpl(tmpl, alb, nil)
+	ft := tmpl.Frontends[http.String()]
+	assert.Equal(t, "so_keepalive=on proxy_protocol", ft.Listen)
+	assert.Contains(t, ft.Server, "real_ip_header proxy_protocol;")
+	assert.True(t, ft.RealIP)
+	assert.Equal(t, ngt.FtConfig{}, tmpl.Frontends[invalid.String()])
+}
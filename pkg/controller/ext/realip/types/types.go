@@ -0,0 +1,23 @@
+package types
+
+// accept or send the proxy protocol on the frontend.
+// +k8s:deepcopy-gen=true
+type ProxyProtocolCr struct {
+	// accept the proxy protocol on listen. the client ip is the address in the proxy protocol header, unless realIP is set.
+	Accept bool `json:"accept,omitempty"`
+	// send the proxy protocol to upstream. only take effect on tcp frontend.
+	Send bool `json:"send,omitempty"`
+}
+
+// resolve the client ip from the request of trusted proxies, via the realip module of nginx.
+// the client ip is used by SRC_IP matching, access logs and ipacl.
+// +k8s:deepcopy-gen=true
+type RealIPCr struct {
+	// cidr or ip of the trusted proxies, e.g. 10.0.0.0/8, 192.168.1.1, fd00::/8.
+	TrustedCIDRs []string `json:"trustedCIDRs,omitempty"`
+	// the header which contains the client ip, or proxy_protocol. default is proxy_protocol when the proxy protocol is accepted, otherwise X-Forwarded-For.
+	// only proxy_protocol is supported on tcp frontend.
+	Header string `json:"header,omitempty"`
+	// use the last non-trusted address in the header instead of the last one, the same as real_ip_recursive of nginx.
+	Recursive bool `json:"recursive,omitempty"`
+}
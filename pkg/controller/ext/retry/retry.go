@@ -0,0 +1,248 @@
+package retry
+
+import (
+	"fmt"
+	"sort"
+	"strconv"
+	"strings"
+
+	"alauda.io/alb2/config"
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	"alauda.io/alb2/pkg/controller/ext/proxy"
+	. "alauda.io/alb2/pkg/controller/ext/retry/types"
+	"alauda.io/alb2/pkg/controller/ext/timeout"
+	timeout_t "alauda.io/alb2/pkg/controller/ext/timeout/types"
+	"alauda.io/alb2/pkg/controller/ext/upstreamtls"
+	"alauda.io/alb2/pkg/controller/ext/waf"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	ngt "alauda.io/alb2/pkg/controller/ngxconf/types"
+	pu "alauda.io/alb2/pkg/utils"
+	"github.com/go-logr/logr"
+	nv1 "k8s.io/api/networking/v1"
+)
+
+const (
+	ConditionOff = "off"
+	// nginx will not retry POST, LOCK, PATCH unless non_idempotent is set.
+	NonIdempotent = "non_idempotent"
+)
+
+var DefaultConditions = []string{"error", "timeout"}
+
+var validCondition = map[string]bool{
+	"error":          true,
+	"timeout":        true,
+	"invalid_header": true,
+	"http_500":       true,
+	"http_502":       true,
+	"http_503":       true,
+	"http_504":       true,
+	"http_403":       true,
+	"http_404":       true,
+	"http_429":       true,
+	ConditionOff:     true,
+}
+
+// RetryCtl retry request to next upstream when the upstream fails.
+// nginx could not set proxy_next_upstream via variable, so we generate a named location for each retry config,
+// policy which need retry will be exec to this location. the per-try timeout is applied via the timeout plugin.
+type RetryCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewRetryCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &RetryCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		IngressAnnotationToRule: x.IngressAnnotationToRule,
+		ToInternalRule:          x.ToInternalRule,
+		ToPolicy:                x.ToPolicy,
+		UpdateNgxTmpl:           x.UpdateNgxTmpl,
+	}
+}
+
+// 配置优先级：index.{rindex}-{pindex}.alb.ingress.{domain} > alb.ingress.{domain} > nginx.ingress.kubernetes.io
+func (x *RetryCtl) IngressAnnotationToRule(ing *nv1.Ingress, rindex int, pindex int, rule *albv1.Rule) {
+	prefix := []string{fmt.Sprintf("index.%d-%d.alb.ingress.%s", rindex, pindex, x.domain), fmt.Sprintf("alb.ingress.%s", x.domain), "nginx.ingress.kubernetes.io"}
+	ing_retry := RetryIngress{}
+	has, err := ResolverRetryIngressFromAnnotation(&ing_retry, ing.Annotations, prefix)
+	if err != nil || !has {
+		return
+	}
+	cr, err := IngressToRetryCr(&ing_retry)
+	if err != nil {
+		x.log.Error(err, "invalid retry annotation", "ingress", ing.Name)
+		return
+	}
+	rule.Spec.Config.Retry = cr
+}
+
+func IngressToRetryCr(ing *RetryIngress) (*RetryCr, error) {
+	cr := &RetryCr{}
+	nonIdempotent := false
+	for _, c := range strings.Fields(ing.NextUpstream) {
+		if c == NonIdempotent {
+			nonIdempotent = true
+			continue
+		}
+		cr.On = append(cr.On, c)
+	}
+	if nonIdempotent {
+		cr.RetryNonIdempotent = &nonIdempotent
+	}
+	if ing.NextUpstreamTries != "" {
+		tries, err := strconv.ParseUint(strings.TrimSpace(ing.NextUpstreamTries), 10, 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid proxy-next-upstream-tries %w", err)
+		}
+		attempts := uint(tries)
+		cr.Attempts = &attempts
+	}
+	var err error
+	if cr.TimeoutMs, err = timeout.ParseTimeout(strings.TrimSpace(ing.NextUpstreamTimeout)); err != nil {
+		return nil, fmt.Errorf("invalid proxy-next-upstream-timeout %w", err)
+	}
+	if cr.PerTryTimeoutMs, err = timeout.ParseTimeout(strings.TrimSpace(ing.PerTryTimeout)); err != nil {
+		return nil, fmt.Errorf("invalid retry-per-try-timeout %w", err)
+	}
+	if err := Valid(cr); err != nil {
+		return nil, err
+	}
+	return cr, nil
+}
+
+func Valid(cfg *RetryCr) error {
+	if cfg.Attempts != nil && *cfg.Attempts == 0 {
+		return fmt.Errorf("retry attempts should be greater than 0")
+	}
+	for _, c := range cfg.On {
+		if !validCondition[c] {
+			return fmt.Errorf("invalid retry condition %s", c)
+		}
+		if c == ConditionOff && len(cfg.On) != 1 {
+			return fmt.Errorf("retry condition off could not be used with others")
+		}
+	}
+	return nil
+}
+
+// retry only could be set on rule.
+func (x *RetryCtl) ToInternalRule(rule *m.Rule, ir *ct.InternalRule) {
+	if rule.GetConfig() == nil || rule.GetConfig().Retry == nil {
+		return
+	}
+	ir.Config.Retry = rule.GetConfig().Retry.DeepCopy()
+}
+
+func (x *RetryCtl) ToPolicy(ir *ct.InternalRule, p *ct.Policy, refs ct.RefMap) {
+	cfg := ir.Config.Retry
+	if cfg == nil {
+		return
+	}
+	if err := Valid(cfg); err != nil {
+		x.log.Error(err, "invalid retry config, ignore it", "rule", ir.RuleID)
+		return
+	}
+	name := x.genLocation(ir, refs, "").Name
+	p.ToLocation = &name
+	if cfg.PerTryTimeoutMs != nil {
+		p.Config.Timeout = WithPerTryTimeout(p.Config.Timeout, *cfg.PerTryTimeoutMs)
+		// the timeout is not the one inherited from rule/ft/alb anymore, let it be shared via hash.
+		delete(ir.Config.Source, ct.Timeout)
+	}
+}
+
+// WithPerTryTimeout return a copy of timeout with send and read timeout set to the per-try timeout.
+// nginx applies the timeouts set in balancer to each try.
+func WithPerTryTimeout(origin *timeout_t.TimeoutCr, ms uint) *timeout_t.TimeoutCr {
+	ret := &timeout_t.TimeoutCr{}
+	if origin != nil {
+		ret = origin.DeepCopy()
+	}
+	ret.ProxySendTimeoutMs = &ms
+	ret.ProxyReadTimeoutMs = &ms
+	return ret
+}
+
+// genLocation combine the retry directives with the location of waf, upstream tls and proxy, since a request could only be exec to one location.
+func (x *RetryCtl) genLocation(ir *ct.InternalRule, refs ct.RefMap, caDir string) ngt.FtCustomLocation {
+	return waf.WithWaf(ir, refs, genLocation(ir, refs, caDir))
+}
+
+func genLocation(ir *ct.InternalRule, refs ct.RefMap, caDir string) ngt.FtCustomLocation {
+	raw := GenLocationRaw(ir.Config.Retry)
+	name := "retry_" + pu.Hash(raw)[:16]
+	if ir.Config.Proxy != nil && proxy.Valid(ir.Config.Proxy) == nil {
+		// the proxy location already contains the upstream tls one.
+		if base, ok := proxy.GenLocation(ir, refs, caDir); ok {
+			return ngt.FtCustomLocation{
+				Name:        base.Name + "_" + name,
+				LocationRaw: base.LocationRaw + "\n" + raw,
+			}
+		}
+	}
+	if ir.Config.UpstreamTLS == nil {
+		return ngt.FtCustomLocation{Name: name, LocationRaw: raw}
+	}
+	// the error of upstream tls has already been reported by the upstream tls extension.
+	tls, _ := upstreamtls.GenLocation(ir.Config.UpstreamTLS, refs, caDir)
+	return ngt.FtCustomLocation{
+		Name:        tls.Name + "_" + name,
+		LocationRaw: tls.Raw + "\n" + raw,
+	}
+}
+
+// GenLocationRaw render the retry config into nginx directives. the conditions are sorted, so the same config
+// always has the same location.
+func GenLocationRaw(cfg *RetryCr) string {
+	on := append([]string{}, DefaultConditions...)
+	if len(cfg.On) != 0 {
+		on = append([]string{}, cfg.On...)
+		sort.Strings(on)
+	}
+	if cfg.RetryNonIdempotent != nil && *cfg.RetryNonIdempotent && !(len(on) == 1 && on[0] == ConditionOff) {
+		on = append(on, NonIdempotent)
+	}
+	lines := []string{fmt.Sprintf("proxy_next_upstream %s;", strings.Join(on, " "))}
+	if cfg.Attempts != nil {
+		lines = append(lines, fmt.Sprintf("proxy_next_upstream_tries %d;", *cfg.Attempts))
+	}
+	if cfg.TimeoutMs != nil {
+		lines = append(lines, fmt.Sprintf("proxy_next_upstream_timeout %dms;", *cfg.TimeoutMs))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func (x *RetryCtl) UpdateNgxTmpl(tmpl_cfg *ngt.NginxTemplateConfig, alb *ct.LoadBalancer, cfg *config.Config) {
+	caDir := upstreamtls.GetCaDir(cfg)
+	for _, f := range alb.Frontends {
+		ft, has := tmpl_cfg.Frontends[f.String()]
+		if !has {
+			continue
+		}
+		exist := map[string]bool{}
+		for _, loc := range ft.CustomLocation {
+			exist[loc.Name] = true
+		}
+		for _, r := range f.Rules {
+			if r.Config.Retry == nil || Valid(r.Config.Retry) != nil {
+				continue
+			}
+			loc := x.genLocation(r, alb.Refs, caDir)
+			if exist[loc.Name] {
+				continue
+			}
+			exist[loc.Name] = true
+			ft.CustomLocation = append(ft.CustomLocation, loc)
+		}
+		sort.Slice(ft.CustomLocation, func(i, j int) bool {
+			return ft.CustomLocation[i].Name < ft.CustomLocation[j].Name
+		})
+		tmpl_cfg.Frontends[f.String()] = ft
+	}
+}
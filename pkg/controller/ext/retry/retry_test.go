@@ -0,0 +1,101 @@
+package retry
+
+import (
+	"strings"
+	"testing"
+
+	ct "alauda.io/alb2/controller/types"
+	proxy_t "alauda.io/alb2/pkg/controller/ext/proxy/types"
+	. "alauda.io/alb2/pkg/controller/ext/retry/types"
+	timeout_t "alauda.io/alb2/pkg/controller/ext/timeout/types"
+	upstreamtls_t "alauda.io/alb2/pkg/controller/ext/upstreamtls/types"
+	waft "alauda.io/alb2/pkg/controller/ext/waf/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestIngressToRetryCr(t *testing.T) {
+	cr, err := IngressToRetryCr(&RetryIngress{NextUpstream: "error timeout http_502 non_idempotent", NextUpstreamTries: "3", NextUpstreamTimeout: "10", PerTryTimeout: "500ms"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"error", "timeout", "http_502"}, cr.On)
+	assert.True(t, *cr.RetryNonIdempotent)
+	assert.Equal(t, uint(3), *cr.Attempts)
+	assert.Equal(t, uint(10000), *cr.TimeoutMs)
+	assert.Equal(t, uint(500), *cr.PerTryTimeoutMs)
+
+	cr, err = IngressToRetryCr(&RetryIngress{NextUpstream: "off"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"off"}, cr.On)
+	assert.Nil(t, cr.RetryNonIdempotent)
+
+	_, err = IngressToRetryCr(&RetryIngress{NextUpstream: "error http_501"})
+	assert.Error(t, err)
+	_, err = IngressToRetryCr(&RetryIngress{NextUpstream: "off error"})
+	assert.Error(t, err)
+	_, err = IngressToRetryCr(&RetryIngress{NextUpstreamTries: "0"})
+	assert.Error(t, err)
+}
+
+func TestGenLocationRaw(t *testing.T) {
+	u := func(v uint) *uint { return &v }
+	yes := true
+	assert.Equal(t, "proxy_next_upstream error timeout;", GenLocationRaw(&RetryCr{}))
+	assert.Equal(t, `proxy_next_upstream error http_503 non_idempotent;
+proxy_next_upstream_tries 3;
+proxy_next_upstream_timeout 2000ms;`, GenLocationRaw(&RetryCr{On: []string{"http_503", "error"}, Attempts: u(3), TimeoutMs: u(2000), RetryNonIdempotent: &yes}))
+	assert.Equal(t, "proxy_next_upstream off;", GenLocationRaw(&RetryCr{On: []string{"off"}, RetryNonIdempotent: &yes}))
+	// order of conditions does not matter
+	assert.Equal(t, GenLocationRaw(&RetryCr{On: []string{"error", "http_502"}}), GenLocationRaw(&RetryCr{On: []string{"http_502", "error"}}))
+}
+
+func TestToPolicy(t *testing.T) {
+	u := func(v uint) *uint { return &v }
+	x := &RetryCtl{log: logr.Discard()}
+	refs := ct.RefMap{ConfigMap: map[client.ObjectKey]*corev1.ConfigMap{}, Secret: map[client.ObjectKey]*corev1.Secret{}}
+
+	ir := &ct.InternalRule{}
+	ir.RuleID = "r1"
+	ir.Config.Retry = &RetryCr{Attempts: u(2), PerTryTimeoutMs: u(1000)}
+	ir.Config.Source = ct.ConfigSource{ct.Timeout: "ft-80"}
+	p := &ct.Policy{}
+	p.Config.Timeout = &timeout_t.TimeoutCr{ProxyConnectTimeoutMs: u(100), ProxyReadTimeoutMs: u(60000)}
+	origin := p.Config.Timeout
+	x.ToPolicy(ir, p, refs)
+	assert.True(t, strings.HasPrefix(*p.ToLocation, "retry_"))
+	assert.Equal(t, &timeout_t.TimeoutCr{ProxyConnectTimeoutMs: u(100), ProxySendTimeoutMs: u(1000), ProxyReadTimeoutMs: u(1000)}, p.Config.Timeout)
+	assert.Equal(t, uint(60000), *origin.ProxyReadTimeoutMs)
+	assert.NotContains(t, ir.Config.Source, ct.Timeout)
+
+	// combined with upstream tls
+	ir.Config.UpstreamTLS = &upstreamtls_t.UpstreamTLSCr{Verify: false}
+	p = &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.True(t, strings.HasPrefix(*p.ToLocation, "upstream_tls_noverify_retry_"))
+	loc := x.genLocation(ir, refs, "")
+	assert.Contains(t, loc.LocationRaw, "proxy_ssl_server_name on;")
+	assert.Contains(t, loc.LocationRaw, "proxy_next_upstream_tries 2;")
+
+	// combined with upstream tls and proxy
+	size := "1m"
+	ir.Config.Proxy = &proxy_t.ProxyCr{ClientMaxBodySize: &size}
+	p = &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.True(t, strings.HasPrefix(*p.ToLocation, "upstream_tls_noverify_proxy_"))
+	loc = x.genLocation(ir, refs, "")
+	assert.Equal(t, *p.ToLocation, loc.Name)
+	assert.Contains(t, loc.LocationRaw, "proxy_ssl_server_name on;")
+	assert.Contains(t, loc.LocationRaw, "client_max_body_size 1m;")
+	assert.Contains(t, loc.LocationRaw, "proxy_next_upstream_tries 2;")
+
+	// merged into the location of waf.
+	ir.Config.Waf = &waft.WafInternal{Key: "waf_rule_r1", Raw: waft.WafConf{UseRecommend: true}}
+	p = &ct.Policy{}
+	x.ToPolicy(ir, p, refs)
+	assert.True(t, strings.HasPrefix(*p.ToLocation, "waf_rule_r1_upstream_tls_noverify_proxy_"))
+	loc = x.genLocation(ir, refs, "")
+	assert.Equal(t, *p.ToLocation, loc.Name)
+	assert.Contains(t, loc.LocationRaw, "modsecurity on;")
+	assert.Contains(t, loc.LocationRaw, "proxy_next_upstream_tries 2;")
+}
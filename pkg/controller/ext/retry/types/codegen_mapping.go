@@ -0,0 +1,52 @@
+package types
+
+import (
+	"fmt"
+	"strings"
+)
+
+func init() {
+	// make go happy
+	_ = strings.Clone
+	_ = fmt.Sprintf
+}
+
+var RetryIngressAnnotationList = []string{
+
+	"proxy-next-upstream",
+
+	"proxy-next-upstream-timeout",
+
+	"proxy-next-upstream-tries",
+
+	"retry-per-try-timeout",
+}
+
+func ResolverRetryIngressFromAnnotation(ing *RetryIngress, annotation map[string]string, prefix []string) (bool, error) {
+	find := false
+	for _, annotation_key := range RetryIngressAnnotationList {
+		for _, prefix := range prefix {
+			annotation_full_key := fmt.Sprintf("%s/%s", prefix, annotation_key)
+			if val, ok := annotation[annotation_full_key]; ok {
+				find = true
+				switch annotation_key {
+
+				case "proxy-next-upstream":
+					ing.NextUpstream = val
+
+				case "proxy-next-upstream-timeout":
+					ing.NextUpstreamTimeout = val
+
+				case "proxy-next-upstream-tries":
+					ing.NextUpstreamTries = val
+
+				case "retry-per-try-timeout":
+					ing.PerTryTimeout = val
+
+				}
+				break
+			}
+		}
+	}
+	return find, nil
+}
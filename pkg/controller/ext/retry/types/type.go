@@ -0,0 +1,26 @@
+package types
+
+// proxy-next-upstream/proxy-next-upstream-tries/proxy-next-upstream-timeout are compatible with ingress-nginx.
+type RetryIngress struct {
+	NextUpstream        string `annotation:"proxy-next-upstream"`         // e.g. "error timeout http_502", "off" disables retry
+	NextUpstreamTries   string `annotation:"proxy-next-upstream-tries"`   // total tries, include the first one
+	NextUpstreamTimeout string `annotation:"proxy-next-upstream-timeout"` // in seconds, 0 means no limit
+	PerTryTimeout       string `annotation:"retry-per-try-timeout"`       // 3s or 500ms, applied to send and read timeout of each try
+}
+
+// retry request to next upstream when the upstream fails.
+// nginx could not set proxy_next_upstream via variable, so rules with the same retry config share a named location.
+// +k8s:deepcopy-gen=true
+type RetryCr struct {
+	// number of tries, include the first one. 1 means no retry. default is 5, the same as proxy_next_upstream_tries of http block.
+	Attempts *uint `json:"attempts,omitempty"`
+	// conditions which should be retried, one of error timeout invalid_header http_500 http_502 http_503 http_504 http_403 http_404 http_429 off.
+	// default is error timeout.
+	On []string `json:"on,omitempty"`
+	// send and read timeout of each try, overrides the send and read timeout of the timeout extension.
+	PerTryTimeoutMs *uint `json:"perTryTimeoutMs,omitempty"`
+	// limit the total time of all tries, 0 means no limit.
+	TimeoutMs *uint `json:"timeoutMs,omitempty"`
+	// retry non-idempotent request (POST, LOCK, PATCH), it is not allowed by default.
+	RetryNonIdempotent *bool `json:"retryNonIdempotent,omitempty"`
+}
@@ -18,12 +18,12 @@ const (
 	maxTimeoutMs uint64 = 1<<32 - 1 // 最大超时时间（毫秒）
 )
 
-// parseTimeout 解析超时时间字符串为毫秒值
+// ParseTimeout 解析超时时间字符串为毫秒值
 // 支持的格式：
 // - 纯数字：按秒处理
 // - 带ms后缀：按毫秒处理
 // - 带s后缀：按秒处理
-func parseTimeout(s string) (*uint, error) {
+func ParseTimeout(s string) (*uint, error) {
 	if s == "" {
 		return nil, nil
 	}
@@ -90,7 +90,7 @@ func (t *TimeoutCtl) IngressAnnotationToRule(ing *nv1.Ingress, rindex int, pinde
 	}
 	cr_timeout := TimeoutCr{}
 	err = ReAssignTimeoutIngressToTimeoutCr(&ing_timeout, &cr_timeout, &ReAssignTimeoutIngressToTimeoutCrOpt{
-		Time_from_string: parseTimeout,
+		Time_from_string: ParseTimeout,
 	})
 	if err != nil {
 		t.log.Error(err, "failed to resolve timeout cr", "ingress", ing.Name)
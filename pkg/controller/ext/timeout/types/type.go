@@ -13,4 +13,6 @@ type TimeoutCr struct {
 	ProxyConnectTimeoutMs *uint `json:"proxy_connect_timeout_ms,omitempty" key:"proxy_connect_timeout_ms" trans:"time_from_string"`
 	ProxySendTimeoutMs    *uint `json:"proxy_send_timeout_ms,omitempty" key:"proxy_send_timeout_ms" trans:"time_from_string"`
 	ProxyReadTimeoutMs    *uint `json:"proxy_read_timeout_ms,omitempty" key:"proxy_read_timeout_ms" trans:"time_from_string"`
+	// the deadline of the whole request, including retries. the timeouts of each try are limited by the time left. only for http.
+	RequestTimeoutMs *uint `json:"request_timeout_ms,omitempty"`
 }
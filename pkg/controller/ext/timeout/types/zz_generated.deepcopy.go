@@ -39,6 +39,11 @@ func (in *TimeoutCr) DeepCopyInto(out *TimeoutCr) {
 		*out = new(uint)
 		**out = **in
 	}
+	if in.RequestTimeoutMs != nil {
+		in, out := &in.RequestTimeoutMs, &out.RequestTimeoutMs
+		*out = new(uint)
+		**out = **in
+	}
 	return
 }
 
@@ -0,0 +1,298 @@
+package tlspolicy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"alauda.io/alb2/config"
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/tlspolicy/types"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	ngt "alauda.io/alb2/pkg/controller/ngxconf/types"
+	"github.com/go-logr/logr"
+)
+
+const (
+	DefaultMinVersion = "TLSv1.2"
+	DefaultMaxVersion = "TLSv1.3"
+	DefaultHSTSMaxAge = 31536000
+)
+
+// in order, the same as the names of ssl_protocols of nginx.
+var versions = []string{"TLSv1", "TLSv1.1", "TLSv1.2", "TLSv1.3"}
+
+// the TLSv1.3 cipher suites which openssl supports, they could not be set via ssl_ciphers.
+var tls13Ciphers = map[string]bool{
+	"TLS_AES_128_GCM_SHA256":       true,
+	"TLS_AES_256_GCM_SHA384":       true,
+	"TLS_CHACHA20_POLY1305_SHA256": true,
+	"TLS_AES_128_CCM_SHA256":       true,
+	"TLS_AES_128_CCM_8_SHA256":     true,
+}
+
+// the curve names and aliases which openssl accepts in ssl_ecdh_curve.
+var curves = map[string]bool{
+	"auto":       true,
+	"X25519":     true,
+	"X448":       true,
+	"prime256v1": true,
+	"secp384r1":  true,
+	"secp521r1":  true,
+	"P-256":      true,
+	"P-384":      true,
+	"P-521":      true,
+}
+
+// the 3DES ciphers of openssl 1.1.1 which openresty is built with, they are not in the generated list of openssl 3.
+var legacyCiphers = map[string]bool{
+	"DES-CBC3-SHA":               true,
+	"ECDHE-RSA-DES-CBC3-SHA":     true,
+	"ECDHE-ECDSA-DES-CBC3-SHA":   true,
+	"EDH-RSA-DES-CBC3-SHA":       true,
+	"EDH-DSS-DES-CBC3-SHA":       true,
+	"AECDH-DES-CBC3-SHA":         true,
+	"ADH-DES-CBC3-SHA":           true,
+	"PSK-3DES-EDE-CBC-SHA":       true,
+	"RSA-PSK-3DES-EDE-CBC-SHA":   true,
+	"DHE-PSK-3DES-EDE-CBC-SHA":   true,
+	"ECDHE-PSK-3DES-EDE-CBC-SHA": true,
+	"SRP-3DES-EDE-CBC-SHA":       true,
+	"SRP-RSA-3DES-EDE-CBC-SHA":   true,
+	"SRP-DSS-3DES-EDE-CBC-SHA":   true,
+}
+
+// the aliases of cipher list, see `man ciphers` of openssl 1.1.1.
+var cipherAliases = map[string]bool{
+	"DEFAULT": true, "COMPLEMENTOFDEFAULT": true, "ALL": true, "COMPLEMENTOFALL": true,
+	"HIGH": true, "MEDIUM": true, "LOW": true,
+	"eNULL": true, "NULL": true, "aNULL": true,
+	"kRSA": true, "aRSA": true, "RSA": true,
+	"kDHr": true, "kDHd": true, "kDH": true, "kDHE": true, "kEDH": true, "DH": true, "DHE": true, "EDH": true, "ADH": true,
+	"kEECDH": true, "kECDHE": true, "ECDH": true, "ECDHE": true, "EECDH": true, "AECDH": true,
+	"aDSS": true, "DSS": true, "aDH": true, "aECDSA": true, "ECDSA": true,
+	"TLSv1.2": true, "TLSv1.0": true, "TLSv1": true, "SSLv3": true,
+	"AES128": true, "AES256": true, "AES": true, "AESGCM": true, "AESCCM": true, "AESCCM8": true,
+	"ARIA128": true, "ARIA256": true, "ARIA": true,
+	"CAMELLIA128": true, "CAMELLIA256": true, "CAMELLIA": true, "CHACHA20": true,
+	"3DES": true, "DES": true, "RC4": true, "RC2": true, "IDEA": true, "SEED": true,
+	"MD5": true, "SHA1": true, "SHA": true, "SHA256": true, "SHA384": true,
+	"aGOST": true, "aGOST01": true, "kGOST": true, "GOST94": true, "GOST89MAC": true,
+	"PSK": true, "kPSK": true, "kECDHEPSK": true, "kDHEPSK": true, "kRSAPSK": true, "aPSK": true,
+	"SUITEB128": true, "SUITEB128ONLY": true, "SUITEB192": true,
+	"SRP": true, "kSRP": true, "aSRP": true,
+}
+
+var secLevelReg = regexp.MustCompile(`^@SECLEVEL=[0-5]$`)
+
+// validCipher check a element of cipher list, which is a cipher name, a alias (e.g. HIGH), a combination of them (e.g. ECDHE+AESGCM)
+// with an optional operator (e.g. !aNULL), or a special keyword (e.g. @STRENGTH).
+func validCipher(c string) bool {
+	if c == "@STRENGTH" || secLevelReg.MatchString(c) {
+		return true
+	}
+	c = strings.TrimLeft(c, "!+-")
+	if c == "" {
+		return false
+	}
+	for _, part := range strings.Split(c, "+") {
+		if !opensslCiphers[part] && !legacyCiphers[part] && !cipherAliases[part] {
+			return false
+		}
+	}
+	return true
+}
+
+// TLSPolicyCtl 端口级别的tls配置,通过nginx template 配置.
+// it could be set on alb/ft, the one of ft wins. only take effect on https/grpc frontend.
+type TLSPolicyCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewTLSPolicyCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &TLSPolicyCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		InitL7Ft:      x.InitL7Ft,
+		UpdateNgxTmpl: x.UpdateNgxTmpl,
+	}
+}
+
+func isTLSFt(protocol albv1.FtProtocol) bool {
+	return protocol == albv1.FtProtocolHTTPS || protocol == albv1.FtProtocolgRPC
+}
+
+// 配置优先级：Frontend Config > ALB Config
+func (x *TLSPolicyCtl) InitL7Ft(mft *m.Frontend, cft *ct.Frontend) {
+	if !isTLSFt(cft.Protocol) {
+		return
+	}
+	if cfg := mft.GetFtConfig(); cfg != nil && cfg.TLS != nil {
+		cft.Config.TLS = cfg.TLS
+		return
+	}
+	if cfg := mft.GetAlbConfig(); cfg != nil && cfg.TLS != nil {
+		cft.Config.TLS = cfg.TLS
+	}
+}
+
+func versionIndex(v string) int {
+	for i, x := range versions {
+		if x == v {
+			return i
+		}
+	}
+	return -1
+}
+
+// Protocols return the versions between min and max, the default is used if it is not set.
+func Protocols(cfg *TLSCr) ([]string, error) {
+	minv := cfg.MinVersion
+	maxv := cfg.MaxVersion
+	if maxv == "" {
+		maxv = DefaultMaxVersion
+	}
+	if minv == "" {
+		minv = DefaultMinVersion
+		// e.g. a legacy frontend which is TLSv1.1 only.
+		if versionIndex(maxv) >= 0 && versionIndex(maxv) < versionIndex(minv) {
+			minv = maxv
+		}
+	}
+	mini := versionIndex(minv)
+	maxi := versionIndex(maxv)
+	if mini < 0 {
+		return nil, fmt.Errorf("invalid min version %s, should be one of %s", minv, strings.Join(versions, " "))
+	}
+	if maxi < 0 {
+		return nil, fmt.Errorf("invalid max version %s, should be one of %s", maxv, strings.Join(versions, " "))
+	}
+	if mini > maxi {
+		return nil, fmt.Errorf("min version %s is greater than max version %s", minv, maxv)
+	}
+	return versions[mini : maxi+1], nil
+}
+
+// SplitCiphers split the ciphers into the ones of ssl_ciphers and the TLSv1.3 cipher suites.
+func SplitCiphers(ciphers []string) (legacy []string, tls13 []string) {
+	for _, c := range ciphers {
+		if strings.HasPrefix(c, "TLS_") {
+			tls13 = append(tls13, c)
+			continue
+		}
+		legacy = append(legacy, c)
+	}
+	return legacy, tls13
+}
+
+func Valid(cfg *TLSCr) error {
+	if _, err := Protocols(cfg); err != nil {
+		return err
+	}
+	legacy, tls13 := SplitCiphers(cfg.Ciphers)
+	for _, c := range legacy {
+		if !validCipher(c) {
+			return fmt.Errorf("invalid cipher %s", c)
+		}
+	}
+	for _, c := range tls13 {
+		if !tls13Ciphers[c] {
+			return fmt.Errorf("invalid TLSv1.3 cipher suite %s", c)
+		}
+	}
+	for _, c := range cfg.Curves {
+		if !curves[c] {
+			return fmt.Errorf("invalid curve %s", c)
+		}
+	}
+	if len(cfg.Curves) > 1 && containsAuto(cfg.Curves) {
+		return fmt.Errorf("curve auto could not be used with others")
+	}
+	if cfg.HSTS != nil && cfg.HSTS.MaxAge != nil && *cfg.HSTS.MaxAge < 0 {
+		return fmt.Errorf("invalid hsts max age %d", *cfg.HSTS.MaxAge)
+	}
+	return nil
+}
+
+func containsAuto(curves []string) bool {
+	for _, c := range curves {
+		if c == "auto" {
+			return true
+		}
+	}
+	return false
+}
+
+// GenServerRaw return the directives in the server block, only the fields which are set are rendered,
+// the others are inherited from the http block.
+func GenServerRaw(cfg *TLSCr) string {
+	lines := []string{}
+	if cfg.MinVersion != "" || cfg.MaxVersion != "" {
+		protocols, _ := Protocols(cfg)
+		lines = append(lines, fmt.Sprintf("ssl_protocols %s;", strings.Join(protocols, " ")))
+	}
+	legacy, tls13 := SplitCiphers(cfg.Ciphers)
+	if len(legacy) != 0 {
+		lines = append(lines, fmt.Sprintf("ssl_ciphers %s;", strings.Join(legacy, ":")))
+	}
+	if len(tls13) != 0 {
+		lines = append(lines, fmt.Sprintf("ssl_conf_command Ciphersuites %s;", strings.Join(tls13, ":")))
+	}
+	if len(cfg.Curves) != 0 {
+		lines = append(lines, fmt.Sprintf("ssl_ecdh_curve %s;", strings.Join(cfg.Curves, ":")))
+	}
+	if cfg.SessionTickets != nil {
+		lines = append(lines, fmt.Sprintf("ssl_session_tickets %s;", onOff(*cfg.SessionTickets)))
+	}
+	if cfg.HSTS != nil {
+		lines = append(lines, fmt.Sprintf(`add_header Strict-Transport-Security "%s" always;`, HSTSValue(cfg.HSTS)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+func HSTSValue(hsts *HSTSCr) string {
+	age := DefaultHSTSMaxAge
+	if hsts.MaxAge != nil {
+		age = *hsts.MaxAge
+	}
+	v := fmt.Sprintf("max-age=%d", age)
+	if hsts.IncludeSubDomains {
+		v += "; includeSubDomains"
+	}
+	if hsts.Preload {
+		v += "; preload"
+	}
+	return v
+}
+
+func onOff(b bool) string {
+	if b {
+		return "on"
+	}
+	return "off"
+}
+
+// invalid config is ignored, so that nginx -t will not fail because of it.
+func (x *TLSPolicyCtl) UpdateNgxTmpl(tmpl_cfg *ngt.NginxTemplateConfig, alb *ct.LoadBalancer, _ *config.Config) {
+	for _, ft := range alb.Frontends {
+		cfg := ft.Config.TLS
+		if cfg == nil {
+			continue
+		}
+		if err := Valid(cfg); err != nil {
+			x.log.Error(err, "invalid tls config, ignore it", "ft", ft.FtName)
+			continue
+		}
+		ft_tmpl, ok := tmpl_cfg.Frontends[ft.String()]
+		if !ok {
+			continue
+		}
+		ft_tmpl.Server = strings.TrimSpace(ft_tmpl.Server + "\n" + GenServerRaw(cfg))
+		tmpl_cfg.Frontends[ft.String()] = ft_tmpl
+	}
+}
@@ -0,0 +1,102 @@
+package tlspolicy
+
+import (
+	"testing"
+
+	ct "alauda.io/alb2/controller/types"
+	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
+	. "alauda.io/alb2/pkg/controller/ext/tlspolicy/types"
+	ngt "alauda.io/alb2/pkg/controller/ngxconf/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+)
+
+func intp(i int) *int {
+	return &i
+}
+
+func boolp(b bool) *bool {
+	return &b
+}
+
+func TestProtocols(t *testing.T) {
+	cases := []struct {
+		min, max string
+		expect   []string
+	}{
+		{"", "", []string{"TLSv1.2", "TLSv1.3"}},
+		{"TLSv1.3", "", []string{"TLSv1.3"}},
+		{"TLSv1", "TLSv1.2", []string{"TLSv1", "TLSv1.1", "TLSv1.2"}},
+		// the default min version follows the max version.
+		{"", "TLSv1.1", []string{"TLSv1.1"}},
+	}
+	for _, c := range cases {
+		p, err := Protocols(&TLSCr{MinVersion: c.min, MaxVersion: c.max})
+		assert.NoError(t, err, c)
+		assert.Equal(t, c.expect, p, c)
+	}
+	for _, c := range [][2]string{{"TLSv1.3", "TLSv1.2"}, {"SSLv3", ""}, {"", "TLSv1.4"}} {
+		_, err := Protocols(&TLSCr{MinVersion: c[0], MaxVersion: c[1]})
+		assert.Error(t, err, c)
+	}
+}
+
+func TestValid(t *testing.T) {
+	assert.NoError(t, Valid(&TLSCr{
+		Ciphers: []string{"ECDHE-RSA-AES128-GCM-SHA256", "!aNULL", "ECDHE+AESGCM", "-DES-CBC3-SHA", "@STRENGTH", "@SECLEVEL=2", "TLS_AES_128_GCM_SHA256"},
+		Curves:  []string{"X25519", "secp384r1"},
+		HSTS:    &HSTSCr{MaxAge: intp(0)},
+	}))
+	invalid := []TLSCr{
+		{MinVersion: "TLSv1.4"},
+		{Ciphers: []string{"ECDHE-RSA-AES128-GCM-SHA256; include /etc/passwd"}},
+		{Ciphers: []string{"TLS_AES_128_CBC_SHA256"}},
+		// tokens which look like a cipher, but openssl does not know.
+		{Ciphers: []string{"ECDHE-RSA-AES128-GCM-SHA512"}},
+		{Ciphers: []string{"ECDHE+AESGCM+FOO"}},
+		{Ciphers: []string{"!"}},
+		{Ciphers: []string{"@SECLEVEL=9"}},
+		{Curves: []string{"secp256k1"}},
+		{Curves: []string{"auto", "X25519"}},
+		{HSTS: &HSTSCr{MaxAge: intp(-1)}},
+	}
+	for _, c := range invalid {
+		assert.Error(t, Valid(&c), c)
+	}
+}
+
+func TestGenServerRaw(t *testing.T) {
+	assert.Equal(t, "", GenServerRaw(&TLSCr{}))
+	raw := GenServerRaw(&TLSCr{
+		MinVersion:     "TLSv1.3",
+		Ciphers:        []string{"TLS_AES_256_GCM_SHA384", "TLS_CHACHA20_POLY1305_SHA256"},
+		Curves:         []string{"X25519", "prime256v1"},
+		SessionTickets: boolp(false),
+		HSTS:           &HSTSCr{IncludeSubDomains: true, Preload: true},
+	})
+	assert.Equal(t, `ssl_protocols TLSv1.3;
+ssl_conf_command Ciphersuites TLS_AES_256_GCM_SHA384:TLS_CHACHA20_POLY1305_SHA256;
+ssl_ecdh_curve X25519:prime256v1;
+ssl_session_tickets off;
+add_header Strict-Transport-Security "max-age=31536000; includeSubDomains; preload" always;`, raw)
+
+	raw = GenServerRaw(&TLSCr{MaxVersion: "TLSv1.2", Ciphers: []string{"ECDHE-RSA-AES256-GCM-SHA384", "ECDHE-RSA-AES128-GCM-SHA256"}})
+	assert.Equal(t, "ssl_protocols TLSv1.2;\nssl_ciphers ECDHE-RSA-AES256-GCM-SHA384:ECDHE-RSA-AES128-GCM-SHA256;", raw)
+}
+
+func TestUpdateNgxTmpl(t *testing.T) {
+	x := &TLSPolicyCtl{log: logr.Discard()}
+	https := &ct.Frontend{Port: 443, Protocol: albv1.FtProtocolHTTPS}
+	https.Config.TLS = &TLSCr{MinVersion: "TLSv1.3"}
+	invalid := &ct.Frontend{Port: 444, Protocol: albv1.FtProtocolHTTPS}
+	invalid.Config.TLS = &TLSCr{MinVersion: "TLSv2"}
+	alb := &ct.LoadBalancer{Frontends: []*ct.Frontend{https, invalid}}
+	tmpl := &ngt.NginxTemplateConfig{Frontends: map[string]ngt.FtConfig{
+		// e.g. the realip config
+		https.String():   {Server: "set_real_ip_from 10.0.0.0/8;"},
+		invalid.String(): {},
+	}}
+	x.UpdateNgxTmpl(tmpl, alb, nil)
+	assert.Equal(t, "set_real_ip_from 10.0.0.0/8;\nssl_protocols TLSv1.3;", tmpl.Frontends[https.String()].Server)
+	assert.Equal(t, "", tmpl.Frontends[invalid.String()].Server)
+}
@@ -0,0 +1,26 @@
+package types
+
+// the tls policy of https/grpc frontend, the same as the ssl_* directives of nginx.
+// +k8s:deepcopy-gen=true
+type TLSCr struct {
+	// one of TLSv1 TLSv1.1 TLSv1.2 TLSv1.3, default TLSv1.2.
+	MinVersion string `json:"minVersion,omitempty"`
+	// one of TLSv1 TLSv1.1 TLSv1.2 TLSv1.3, default TLSv1.3.
+	MaxVersion string `json:"maxVersion,omitempty"`
+	// openssl cipher names, e.g. ECDHE-RSA-AES128-GCM-SHA256. the TLSv1.3 cipher suites (e.g. TLS_AES_128_GCM_SHA256) are set via the Ciphersuites command of openssl.
+	Ciphers []string `json:"ciphers,omitempty"`
+	// openssl curve names, e.g. X25519, prime256v1, secp384r1.
+	Curves []string `json:"curves,omitempty"`
+	// ssl_session_tickets of nginx, default on.
+	SessionTickets *bool `json:"sessionTickets,omitempty"`
+	// add the Strict-Transport-Security header to response.
+	HSTS *HSTSCr `json:"hsts,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type HSTSCr struct {
+	// seconds, default 31536000.
+	MaxAge            *int `json:"maxAge,omitempty"`
+	IncludeSubDomains bool `json:"includeSubDomains,omitempty"`
+	Preload           bool `json:"preload,omitempty"`
+}
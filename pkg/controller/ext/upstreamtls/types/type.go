@@ -0,0 +1,23 @@
+package types
+
+// tls between alb and upstream. only take effect when backend protocol is https.
+// +k8s:deepcopy-gen=true
+type UpstreamTLSCr struct {
+	// verify the certificate of upstream.
+	Verify bool `json:"verify"`
+	// used as sni and the name to verify the certificate of upstream, default is the host of request.
+	Hostname string `json:"hostname,omitempty"`
+	// configmap which contains the pem encoded ca bundle, in format ns/name#key.
+	CaCmRef string `json:"caCmRef,omitempty"`
+	// secret which contains the pem encoded ca bundle, in format ns/name#key.
+	CaSecretRef string `json:"caSecretRef,omitempty"`
+	// use the ca bundle of system.
+	UseSystemCa bool `json:"useSystemCa,omitempty"`
+	// default is 1, the same as nginx.
+	VerifyDepth *int `json:"verifyDepth,omitempty"`
+}
+
+// +k8s:deepcopy-gen=true
+type UpstreamTLSPolicy struct {
+	Hostname string `json:"hostname"` // empty means use the host of request
+}
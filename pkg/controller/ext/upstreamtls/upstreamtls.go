@@ -0,0 +1,263 @@
+package upstreamtls
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"alauda.io/alb2/config"
+	m "alauda.io/alb2/controller/modules"
+	ct "alauda.io/alb2/controller/types"
+	. "alauda.io/alb2/pkg/controller/ext/upstreamtls/types"
+	"alauda.io/alb2/pkg/controller/ext/waf"
+	et "alauda.io/alb2/pkg/controller/extctl/types"
+	ngt "alauda.io/alb2/pkg/controller/ngxconf/types"
+	pu "alauda.io/alb2/pkg/utils"
+	"github.com/go-logr/logr"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+const (
+	SystemCaPath = "/etc/ssl/certs/ca-certificates.crt"
+	// ca bundles are written into this dir which is next to nginx.conf, so nginx could read them.
+	CaDir = "upstream_tls"
+	// request will be rejected when the ca of upstream could not be found, instead of proxy without verification.
+	InvalidLocation  = "upstream_tls_invalid"
+	NoVerifyLocation = "upstream_tls_noverify"
+)
+
+// UpstreamTLSCtl config the tls between alb and upstream.
+// nginx could not set proxy_ssl_verify via variable, so we generate a named location for each ca bundle,
+// policy which need to verify upstream will be exec to this location, and the sni is set via lua.
+type UpstreamTLSCtl struct {
+	log    logr.Logger
+	domain string
+}
+
+func NewUpstreamTLSCtl(log logr.Logger, domain string) et.ExtensionInterface {
+	x := &UpstreamTLSCtl{
+		log:    log,
+		domain: domain,
+	}
+	return et.ExtensionInterface{
+		ToInternalRule: x.ToInternalRule,
+		CollectRefs:    x.CollectRefs,
+		ToPolicy:       x.ToPolicy,
+		UpdateNgxTmpl:  x.UpdateNgxTmpl,
+	}
+}
+
+func (x *UpstreamTLSCtl) ToInternalRule(rule *m.Rule, ir *ct.InternalRule) {
+	if rule.GetConfig() == nil || rule.GetConfig().UpstreamTLS == nil {
+		return
+	}
+	ir.Config.UpstreamTLS = rule.GetConfig().UpstreamTLS.DeepCopy()
+	ir.Config.Source[ct.UpstreamTLS] = rule.Name
+}
+
+func (x *UpstreamTLSCtl) CollectRefs(ir *ct.InternalRule, refs ct.RefMap) {
+	cfg := ir.Config.UpstreamTLS
+	if cfg == nil {
+		return
+	}
+	if cfg.CaCmRef != "" {
+		ns, name, _, err := waf.ParseCmRef(cfg.CaCmRef)
+		if err != nil {
+			x.log.Error(err, "invalid ca cm ref", "rule", ir.RuleID, "ref", cfg.CaCmRef)
+		} else {
+			refs.ConfigMap[client.ObjectKey{Namespace: ns, Name: name}] = nil
+		}
+	}
+	if cfg.CaSecretRef != "" {
+		ns, name, _, err := waf.ParseCmRef(cfg.CaSecretRef)
+		if err != nil {
+			x.log.Error(err, "invalid ca secret ref", "rule", ir.RuleID, "ref", cfg.CaSecretRef)
+		} else {
+			refs.Secret[client.ObjectKey{Namespace: ns, Name: name}] = nil
+		}
+	}
+}
+
+func (x *UpstreamTLSCtl) ToPolicy(ir *ct.InternalRule, p *ct.Policy, refs ct.RefMap) {
+	cfg := ir.Config.UpstreamTLS
+	if cfg == nil {
+		return
+	}
+	loc, err := GenLocation(cfg, refs, "")
+	if err != nil {
+		x.log.Error(err, "invalid upstream tls config, reject request", "rule", ir.RuleID)
+	}
+	// the location of waf is set before, the proxy_ssl_* directives must be merged into it, otherwise the upstream is not verified.
+	name := waf.WithWaf(ir, refs, loc.CustomLocation()).Name
+	p.ToLocation = &name
+	p.Config.UpstreamTLS = &UpstreamTLSPolicy{
+		Hostname: cfg.Hostname,
+	}
+}
+
+// Location is a named location which proxy to upstream with specific tls config.
+type Location struct {
+	Name   string
+	CaPath string
+	Ca     string
+	Raw    string
+}
+
+func (l Location) CustomLocation() ngt.FtCustomLocation {
+	return ngt.FtCustomLocation{Name: l.Name, LocationRaw: l.Raw}
+}
+
+// rules with same ca bundle and verify depth share the same location. the name of location contains the hash of ca,
+// so nginx.conf will be changed(and reload) when ca changed.
+// if err is not nil, the location is a location which rejects all request.
+func GenLocation(cfg *UpstreamTLSCr, refs ct.RefMap, caDir string) (Location, error) {
+	if !cfg.Verify {
+		return Location{
+			Name: NoVerifyLocation,
+			Raw:  proxySslName(),
+		}, nil
+	}
+	depth := 1
+	if cfg.VerifyDepth != nil {
+		depth = *cfg.VerifyDepth
+	}
+	ca, err := pickCa(cfg, refs)
+	if err != nil {
+		return Location{Name: InvalidLocation, Raw: "return 502;"}, err
+	}
+	loc := Location{}
+	if cfg.UseSystemCa && ca == "" {
+		loc.Name = fmt.Sprintf("upstream_tls_system_%d", depth)
+		loc.CaPath = SystemCaPath
+	} else {
+		hash := pu.Hash(fmt.Sprintf("%d-%s", depth, ca))[:16]
+		loc.Name = fmt.Sprintf("upstream_tls_%s", hash)
+		loc.Ca = ca
+		loc.CaPath = filepath.Join(caDir, loc.Name+".crt")
+	}
+	loc.Raw = fmt.Sprintf(`%s
+proxy_ssl_verify on;
+proxy_ssl_verify_depth %d;
+proxy_ssl_trusted_certificate %s;`, proxySslName(), depth, loc.CaPath)
+	return loc, nil
+}
+
+// $upstream_ssl_name will be set by upstream_tls plugin if hostname specified.
+func proxySslName() string {
+	return `set $upstream_ssl_name $host;
+proxy_ssl_server_name on;
+proxy_ssl_name $upstream_ssl_name;`
+}
+
+func pickCa(cfg *UpstreamTLSCr, refs ct.RefMap) (string, error) {
+	if cfg.CaCmRef != "" {
+		ns, name, key, err := waf.ParseCmRef(cfg.CaCmRef)
+		if err != nil {
+			return "", err
+		}
+		cm := refs.ConfigMap[client.ObjectKey{Namespace: ns, Name: name}]
+		if cm == nil || strings.TrimSpace(cm.Data[key]) == "" {
+			return "", fmt.Errorf("could not find ca in cm %s", cfg.CaCmRef)
+		}
+		return cm.Data[key], nil
+	}
+	if cfg.CaSecretRef != "" {
+		ns, name, key, err := waf.ParseCmRef(cfg.CaSecretRef)
+		if err != nil {
+			return "", err
+		}
+		secret := refs.Secret[client.ObjectKey{Namespace: ns, Name: name}]
+		if secret == nil || strings.TrimSpace(string(secret.Data[key])) == "" {
+			return "", fmt.Errorf("could not find ca in secret %s", cfg.CaSecretRef)
+		}
+		return string(secret.Data[key]), nil
+	}
+	if cfg.UseSystemCa {
+		return "", nil
+	}
+	return "", fmt.Errorf("verify upstream without ca")
+}
+
+// GetCaDir return the dir which the ca bundles are written into.
+func GetCaDir(cfg *config.Config) string {
+	return filepath.Join(filepath.Dir(cfg.GetNginxCfg().NewConfigPath), CaDir)
+}
+
+func (x *UpstreamTLSCtl) UpdateNgxTmpl(tmpl_cfg *ngt.NginxTemplateConfig, alb *ct.LoadBalancer, cfg *config.Config) {
+	caDir := GetCaDir(cfg)
+	cas := map[string]string{}
+	custom_location := map[string]map[string]ngt.FtCustomLocation{}
+	for _, f := range alb.Frontends {
+		for _, r := range f.Rules {
+			if r.Config.UpstreamTLS == nil {
+				continue
+			}
+			loc, err := GenLocation(r.Config.UpstreamTLS, alb.Refs, caDir)
+			if err != nil {
+				x.log.Error(err, "invalid upstream tls config", "rule", r.RuleID)
+			}
+			if loc.Ca != "" {
+				cas[loc.CaPath] = loc.Ca
+			}
+			if _, ok := custom_location[f.String()]; !ok {
+				custom_location[f.String()] = map[string]ngt.FtCustomLocation{}
+			}
+			cl := waf.WithWaf(r, alb.Refs, loc.CustomLocation())
+			custom_location[f.String()][cl.Name] = cl
+		}
+	}
+	if err := syncCaFiles(caDir, cas); err != nil {
+		x.log.Error(err, "sync upstream ca fail", "dir", caDir)
+	}
+	for f, ftmap := range custom_location {
+		ft, has := tmpl_cfg.Frontends[f]
+		if !has {
+			x.log.Info("ft not find?", "ft", f)
+			continue
+		}
+		for _, loc := range ftmap {
+			ft.CustomLocation = append(ft.CustomLocation, loc)
+		}
+		sort.Slice(ft.CustomLocation, func(i, j int) bool {
+			return ft.CustomLocation[i].Name < ft.CustomLocation[j].Name
+		})
+		tmpl_cfg.Frontends[f] = ft
+	}
+}
+
+// write the ca bundles which are used now, and remove the others.
+func syncCaFiles(dir string, cas map[string]string) error {
+	if len(cas) == 0 {
+		if _, err := os.Stat(dir); os.IsNotExist(err) {
+			return nil
+		}
+	}
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	for path, ca := range cas {
+		origin, err := os.ReadFile(path)
+		if err == nil && string(origin) == ca {
+			continue
+		}
+		if err := os.WriteFile(path, []byte(ca), 0o644); err != nil {
+			return err
+		}
+	}
+	files, err := os.ReadDir(dir)
+	if err != nil {
+		return err
+	}
+	for _, f := range files {
+		path := filepath.Join(dir, f.Name())
+		if _, used := cas[path]; used {
+			continue
+		}
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	return nil
+}
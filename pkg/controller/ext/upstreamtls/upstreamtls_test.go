@@ -0,0 +1,77 @@
+package upstreamtls
+
+import (
+	"strings"
+	"testing"
+
+	ct "alauda.io/alb2/controller/types"
+	. "alauda.io/alb2/pkg/controller/ext/upstreamtls/types"
+	"alauda.io/alb2/pkg/controller/ext/waf"
+	waft "alauda.io/alb2/pkg/controller/ext/waf/types"
+	"github.com/go-logr/logr"
+	"github.com/stretchr/testify/assert"
+	corev1 "k8s.io/api/core/v1"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+func TestGenLocation(t *testing.T) {
+	refs := ct.RefMap{
+		ConfigMap: map[client.ObjectKey]*corev1.ConfigMap{
+			{Namespace: "cpaas-system", Name: "ca"}: {Data: map[string]string{"ca.crt": "xx"}},
+		},
+		Secret: map[client.ObjectKey]*corev1.Secret{},
+	}
+	loc, err := GenLocation(&UpstreamTLSCr{Verify: true, CaCmRef: "cpaas-system/ca#ca.crt"}, refs, "/etc/alb2/nginx/upstream_tls")
+	assert.NoError(t, err)
+	assert.True(t, strings.HasPrefix(loc.Name, "upstream_tls_"))
+	assert.Equal(t, "xx", loc.Ca)
+	assert.Equal(t, "/etc/alb2/nginx/upstream_tls/"+loc.Name+".crt", loc.CaPath)
+	assert.Contains(t, loc.Raw, "proxy_ssl_verify on;")
+	assert.Contains(t, loc.Raw, "proxy_ssl_verify_depth 1;")
+
+	// same ca share the same location
+	loc1, err := GenLocation(&UpstreamTLSCr{Verify: true, CaCmRef: "cpaas-system/ca#ca.crt", Hostname: "a.com"}, refs, "/etc/alb2/nginx/upstream_tls")
+	assert.NoError(t, err)
+	assert.Equal(t, loc.Name, loc1.Name)
+
+	loc, err = GenLocation(&UpstreamTLSCr{Verify: true, UseSystemCa: true}, refs, "")
+	assert.NoError(t, err)
+	assert.Equal(t, "upstream_tls_system_1", loc.Name)
+	assert.Contains(t, loc.Raw, SystemCaPath)
+
+	loc, err = GenLocation(&UpstreamTLSCr{Verify: true, CaCmRef: "cpaas-system/not-exist#ca.crt"}, refs, "")
+	assert.Error(t, err)
+	assert.Equal(t, InvalidLocation, loc.Name)
+
+	loc, err = GenLocation(&UpstreamTLSCr{Verify: false}, refs, "")
+	assert.NoError(t, err)
+	assert.Equal(t, NoVerifyLocation, loc.Name)
+	assert.NotContains(t, loc.Raw, "proxy_ssl_verify")
+}
+
+func TestToPolicyWithWaf(t *testing.T) {
+	x := &UpstreamTLSCtl{log: logr.Discard()}
+	refs := ct.RefMap{ConfigMap: map[client.ObjectKey]*corev1.ConfigMap{}, Secret: map[client.ObjectKey]*corev1.Secret{}}
+	ir := &ct.InternalRule{}
+	ir.RuleID = "r1"
+	ir.Config.Waf = &waft.WafInternal{Key: "waf_rule_r1", Raw: waft.WafConf{UseRecommend: true}}
+	ir.Config.UpstreamTLS = &UpstreamTLSCr{Verify: true, UseSystemCa: true}
+
+	// the location of waf is set before upstream tls.
+	p := &ct.Policy{}
+	wafLoc := "waf_rule_r1"
+	p.ToLocation = &wafLoc
+	x.ToPolicy(ir, p, refs)
+	assert.Equal(t, "waf_rule_r1_upstream_tls_system_1", *p.ToLocation)
+	tls, err := GenLocation(ir.Config.UpstreamTLS, refs, "")
+	assert.NoError(t, err)
+	loc := waf.WithWaf(ir, refs, tls.CustomLocation())
+	assert.Equal(t, *p.ToLocation, loc.Name)
+	assert.Contains(t, loc.LocationRaw, "modsecurity on;")
+	assert.Contains(t, loc.LocationRaw, "proxy_ssl_verify on;")
+
+	// invalid config rejects the request even with waf.
+	ir.Config.UpstreamTLS = &UpstreamTLSCr{Verify: true, CaCmRef: "cpaas-system/not-exist#ca.crt"}
+	x.ToPolicy(ir, p, refs)
+	assert.Equal(t, "waf_rule_r1_"+InvalidLocation, *p.ToLocation)
+}
@@ -182,6 +182,18 @@ func (w *WafCtl) UpdateNgxTmpl(tmpl_cfg *NginxTemplateConfig, alb *LoadBalancer,
 	}
 }
 
+// WithWaf prepend the waf directives to the custom location of other extension (e.g. upstream tls), since a request
+// could only be exec to one location. the location is returned as is if the rule has no waf.
+func WithWaf(ir *ct.InternalRule, refs RefMap, loc FtCustomLocation) FtCustomLocation {
+	if ir.Config.Waf == nil {
+		return loc
+	}
+	return FtCustomLocation{
+		Name:        ir.Config.Waf.Key + "_" + loc.Name,
+		LocationRaw: GenLocation(refs, ir) + "\n" + loc.LocationRaw,
+	}
+}
+
 func GenLocation(cms RefMap, r *ct.InternalRule) string {
 	waf := r.Config.Waf
 	if waf.Snippet != "" {
@@ -11,10 +11,22 @@ import (
 
 	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
 	"alauda.io/alb2/pkg/controller/ext/auth"
+	"alauda.io/alb2/pkg/controller/ext/clienttls"
+	"alauda.io/alb2/pkg/controller/ext/errorpage"
+	"alauda.io/alb2/pkg/controller/ext/healthcheck"
+	"alauda.io/alb2/pkg/controller/ext/ipacl"
 	"alauda.io/alb2/pkg/controller/ext/keepalive"
+	"alauda.io/alb2/pkg/controller/ext/mirror"
 	"alauda.io/alb2/pkg/controller/ext/otel"
+	"alauda.io/alb2/pkg/controller/ext/outlier"
+	"alauda.io/alb2/pkg/controller/ext/proxy"
+	"alauda.io/alb2/pkg/controller/ext/ratelimit"
+	"alauda.io/alb2/pkg/controller/ext/realip"
 	"alauda.io/alb2/pkg/controller/ext/redirect"
+	"alauda.io/alb2/pkg/controller/ext/retry"
 	"alauda.io/alb2/pkg/controller/ext/timeout"
+	"alauda.io/alb2/pkg/controller/ext/tlspolicy"
+	"alauda.io/alb2/pkg/controller/ext/upstreamtls"
 	"alauda.io/alb2/pkg/controller/ext/waf"
 	. "alauda.io/alb2/pkg/controller/extctl/types"
 	ngt "alauda.io/alb2/pkg/controller/ngxconf/types"
@@ -64,6 +76,20 @@ func NewExtensionCtl(opt ExtCtlCfgOpt) ExtCtl {
 			redirect.NewRedirectCtl(opt.Log, opt.Domain),
 			timeout.NewTimeoutCtl(opt.Log, opt.Domain),
 			keepalive.NewKeepAliveCtl(opt.Log, opt.Domain),
+			realip.NewRealIPCtl(opt.Log, opt.Domain),
+			tlspolicy.NewTLSPolicyCtl(opt.Log, opt.Domain),
+			mirror.NewMirrorCtl(opt.Log, opt.Domain),
+			upstreamtls.NewUpstreamTLSCtl(opt.Log, opt.Domain),
+			ratelimit.NewRateLimitCtl(opt.Log, opt.Domain),
+			ipacl.NewIpAclCtl(opt.Log, opt.Domain),
+			clienttls.NewClientTLSCtl(opt.Log, opt.Domain),
+			healthcheck.NewHealthCheckCtl(opt.Log, opt.Domain),
+			outlier.NewOutlierCtl(opt.Log, opt.Domain),
+			errorpage.NewErrorPageCtl(opt.Log, opt.Domain),
+			// proxy must be after upstreamtls, it may override the location of it.
+			proxy.NewProxyCtl(opt.Log, opt.Domain),
+			// retry must be after upstreamtls, proxy and timeout, it may override the location and timeout of them.
+			retry.NewRetryCtl(opt.Log, opt.Domain),
 		},
 	}
 	// TODO 当有更多的插件需要配置时，暴露到interface上
@@ -155,18 +181,26 @@ func (c ExtCtl) MergeSamePolicyConfig(ngx *ct.NgxPolicy) {
 		}
 	}
 	// TODO  需要 stream mode下 config cache机制？
+	init_stream_plugin := func(p *ct.Policy) {
+		if p.Config.Refs == nil {
+			p.Config.Refs = map[ct.PolicyExtKind]string{}
+		}
+		used_plugin_map := map[string]bool{}
+		for k := range p.Config.ToMaps() {
+			used_plugin_map[string(k)] = true
+		}
+		used_plugin_list := maps.Keys(used_plugin_map)
+		sort.Strings(used_plugin_list)
+		p.Plugins = used_plugin_list
+	}
 	for _, ps := range ngx.Stream.Tcp {
 		for _, p := range ps {
-			if p.Config.Refs == nil {
-				p.Config.Refs = map[ct.PolicyExtKind]string{}
-			}
-			used_plugin_map := map[string]bool{}
-			for k := range p.Config.ToMaps() {
-				used_plugin_map[string(k)] = true
-			}
-			used_plugin_list := maps.Keys(used_plugin_map)
-			sort.Strings(used_plugin_list)
-			p.Plugins = used_plugin_list
+			init_stream_plugin(p)
+		}
+	}
+	for _, ps := range ngx.Stream.Tls {
+		for _, p := range ps {
+			init_stream_plugin(p)
 		}
 	}
 
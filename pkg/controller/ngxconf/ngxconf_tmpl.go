@@ -67,6 +67,7 @@ func (c *NgxCli) GenerateNginxTemplateConfig(alb *types.LoadBalancer, phase stri
 			Protocol:        ft.Protocol,
 			EnableHTTP2:     nginxParam.EnableHTTP2,
 			CertificateName: ft.CertificateName,
+			SslPreread:      ft.TlsPassthrough,
 		}
 	}
 	// calculate hash by tweak dir
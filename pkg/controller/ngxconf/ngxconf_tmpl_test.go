@@ -202,3 +202,30 @@ func TestFtSpecialConfig(t *testing.T) {
 	assert.NoError(t, err)
 	fmt.Println(ngx_cfg)
 }
+
+// plugins such as ratelimit and outlier release the slot of request in log phase, it must be rendered even if prometheus is disabled.
+func TestLogPhaseWithoutPrometheus(t *testing.T) {
+	for _, prometheus := range []bool{false, true} {
+		tmpl_cfg := NginxTemplateConfig{
+			Name:      "test",
+			NginxBase: "/alb/nginx",
+			RestyBase: "/usr/local/openresty",
+			ShareBase: "/etc/alb2/nginx",
+			Frontends: map[string]FtConfig{
+				"http-80": {
+					IpV4BindAddress: []string{"0.0.0.0"},
+					Port:            80,
+					Protocol:        v1.FtProtocolHTTP,
+					CustomLocation:  []FtCustomLocation{{Name: "xx", LocationRaw: "proxy_read_timeout 1s;"}},
+				},
+			},
+			Phase:      "running",
+			NginxParam: NginxParam{EnablePrometheus: prometheus},
+			Flags:      DefaulNgxTmplFlags(),
+		}
+		ngx_cfg, err := RenderNginxConfigEmbed(tmpl_cfg)
+		assert.NoError(t, err)
+		assert.Equal(t, 2, strings.Count(ngx_cfg, "log_by_lua_file /alb/nginx/lua/phase/log_phase.lua;"))
+		assert.Contains(t, ngx_cfg, fmt.Sprintf(`default "%v";`, prometheus))
+	}
+}
@@ -151,6 +151,8 @@ var _ = DescribeTable("ngx config should work",
 				rewrite_by_lua_file /alb/nginx/lua/phase/l7_rewrite_phase.lua;
 				proxy_pass $backend_protocol://http_backend;
 				header_filter_by_lua_file /alb/nginx/lua/phase/l7_header_filter_phase.lua;
+				body_filter_by_lua_file /alb/nginx/lua/phase/l7_body_filter_phase.lua;
+				log_by_lua_file /alb/nginx/lua/phase/log_phase.lua;
 			`))
 		},
 	),
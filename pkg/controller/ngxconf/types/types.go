@@ -31,9 +31,14 @@ type FtConfig struct {
 	Protocol        albv1.FtProtocol   `yaml:"protocol"`
 	EnableHTTP2     bool               `yaml:"enableHTTP2"`
 	CertificateName string             `yaml:"certificateName"`
+	SslPreread      bool               `yaml:"sslPreread"` // tcp ft which route by sni
 	IpV4BindAddress []string           `yaml:"ipV4BindAddress"`
 	IpV6BindAddress []string           `yaml:"ipV6BindAddress"`
 	CustomLocation  []FtCustomLocation `yaml:"customLocation"`
+	// raw directives in the server block, e.g. the config of realip module.
+	Server string `yaml:"server"`
+	// $remote_addr is resolved by the realip module, it is used as the client ip by SRC_IP matching and ipacl.
+	RealIP bool `yaml:"realIP"`
 }
 
 // 目前我们直接把nginx 配置merge到一个文件里去
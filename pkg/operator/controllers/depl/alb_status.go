@@ -3,6 +3,7 @@ package depl
 import (
 	"fmt"
 	"reflect"
+	"sort"
 	"strings"
 	"time"
 
@@ -161,6 +162,19 @@ func MergeAlbStatus(status *albv2.ALB2Status, cf cfg.Config) {
 			return
 		}
 	}
+	// nginx.conf rejected by nginx -t
+	{
+		if len(status.Detail.Alb.ReloadStatus) != 0 {
+			status.State = albv2.ALB2StateWarning
+			pods := []string{}
+			for pod := range status.Detail.Alb.ReloadStatus {
+				pods = append(pods, pod)
+			}
+			sort.Strings(pods)
+			status.Reason = fmt.Sprintf("nginx.conf of %s is invalid, keep the last valid one.", strings.Join(pods, ","))
+			return
+		}
+	}
 	// lb svc not ready
 	{
 		if cf.ALB.Vip.EnableLbSvc && !status.Detail.AddressStatus.Ok {
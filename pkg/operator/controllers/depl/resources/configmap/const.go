@@ -28,6 +28,9 @@ const HTTP = `
     lua_shared_dict http_raw     5m;
     lua_shared_dict prometheus_metrics 20m;
     lua_shared_dict http_ipc_shared_dict 1m;
+    lua_shared_dict http_ratelimit 10m;
+    lua_shared_dict http_healthcheck 5m;
+    lua_shared_dict http_outlier 5m;
 
     proxy_connect_timeout      5s;
     proxy_send_timeout         120s;
@@ -100,6 +103,12 @@ const HTTPSERVER = `
     location /custom_error {
         internal;
         echo "X-Error: $status";
+        header_filter_by_lua_block {
+            require("plugins.errorpage").error_location_header_filter()
+        }
+        body_filter_by_lua_block {
+            require("plugins.errorpage").body_filter()
+        }
     }
 `
 
@@ -125,6 +134,7 @@ const STREAM_COMMON = `
     lua_shared_dict stream_alb_cache     20m;
     lua_shared_dict stream_raw     5m;
     lua_shared_dict stream_ipc_shared_dict 1m;
+    lua_shared_dict stream_healthcheck 5m;
 `
 
 const STREAM_TCP = `
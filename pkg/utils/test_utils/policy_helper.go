@@ -46,12 +46,17 @@ type XCli struct {
 }
 
 func NewXCli(ctx PolicyGetCtx) XCli {
+	return NewXCliWithCache(ctx, nil)
+}
+
+// NewXCliWithCache keep the translation results between GetPolicyAndNgx, like the reload loop of alb.
+func NewXCliWithCache(ctx PolicyGetCtx, cache *cli.PolicyCache) XCli {
 	s := time.Now()
 	defer func() {
 		pm.Write("test/init-policy-cli", float64(time.Since(s).Milliseconds()))
 	}()
 	acli := cli.NewAlbCli(ctx.Drv, ctx.L)
-	pcli := cli.NewPolicyCli(ctx.Drv, ctx.L, cli.PolicyCliOpt{MetricsPort: 0})
+	pcli := cli.NewPolicyCli(ctx.Drv, ctx.L, cli.PolicyCliOpt{MetricsPort: 0, Cache: cache})
 	ncli := NewNgxCli(ctx.Drv, ctx.L, NgxCliOpt{})
 	return XCli{
 		alb:    acli,
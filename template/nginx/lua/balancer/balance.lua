@@ -13,6 +13,9 @@ local common = require "utils.common"
 local shm = require "config.shmap"
 local e = require "error"
 local actx = require "ctx.alb_ctx"
+local bc = require "balancer.common"
+local hc = require "balancer.healthcheck"
+local outlier = require "plugins.outlier"
 
 local _M = {}
 local balancers = {}
@@ -42,9 +45,7 @@ local function format_ipv6_endpoints(endpoints)
     local formatted_endpoints = {}
     for _, endpoint in ipairs(endpoints) do
         local formatted_endpoint = endpoint
-        if not endpoint.address:match("^%d+.%d+.%d+.%d+$") then
-            formatted_endpoint.address = string.format("[%s]", endpoint.address)
-        end
+        formatted_endpoint.address = bc.format_address(endpoint.address)
         table.insert(formatted_endpoints, formatted_endpoint)
     end
     return formatted_endpoints
@@ -57,6 +58,9 @@ local function sync_backend(backend)
     end
 
     backend.backends = format_ipv6_endpoints(backend.backends)
+    if backend.health_check then
+        backend.backends = hc.filter_healthy(backend.name, backend.backends)
+    end
 
     local implementation = get_implementation(backend)
     local balancer = balancers[backend.name]
@@ -118,6 +122,17 @@ local function get_balancer()
     return balancer
 end
 
+--- pick a peer from the given upstream, used by plugins which need to send request by themselves (e.g. mirror).
+---@param upstream string
+---@return string? peer
+function _M.get_peer(upstream)
+    local balancer = balancers[upstream]
+    if not balancer then
+        return nil
+    end
+    return balancer:balance()
+end
+
 function _M.balance()
     local balancer = get_balancer()
     local alb_ctx = actx.get_alb_ctx()
@@ -133,11 +148,12 @@ function _M.balance()
         e.exit(e.InvalidBalancer, "no peer")
         return
     end
+    peer = outlier.pick(alb_ctx, balancer, peer)
 
     alb_ctx.peer = { peer = peer, conf = balancer:get_peer_conf(peer) }
-    -- TODO 在实现retrypolicy时这里需要被重写。注意测试。
+    -- 每次进入balancer都允许再重试一次,真正的重试次数和重试条件由proxy_next_upstream*决定。
+    -- 配置了retry的rule会跳转到retry extension生成的location中,在那里覆盖proxy_next_upstream_tries等配置。
     ngx_balancer.set_more_tries(1)
-    -- TODO FIXME
     local ok, err = ngx_balancer.set_current_peer(peer)
     if not ok then
         ngx.log(ngx.ERR, string.format("error while setting current upstream peer %s: %s", peer, err))
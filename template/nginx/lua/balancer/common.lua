@@ -15,6 +15,16 @@ local _M = {}
 --    ]
 -- }
 
+--- ipv6 address should be wrapped by [], since it is used as host of upstream.
+---@param address string
+---@return string
+function _M.format_address(address)
+    if not address:match("^%d+.%d+.%d+.%d+$") and not address:match("^%[") then
+        return string.format("[%s]", address)
+    end
+    return address
+end
+
 function _M.node_key(ep)
     return ep.address .. ":" .. ep.port
 end
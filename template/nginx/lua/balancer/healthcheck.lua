@@ -0,0 +1,323 @@
+-- format:on style:emmy
+-- THIS MODULE EVALED IN BOTH HTTP AND STREAM CTX
+-- active health check of the endpoints of backend group which has health_check.
+-- the worker 0 probes the endpoints and saves the state in the shared dict, each worker ejects the unhealthy endpoints when sync backends.
+local ngx = ngx
+local ngx_log = ngx.log
+local ngx_shared = ngx.shared
+local string_format = string.format
+local common = require "utils.common"
+local subsys = require "utils.subsystem"
+local shm = require "config.shmap"
+local bc = require "balancer.common"
+
+local _M = {}
+
+local HEALTHY = "healthy"
+local UNHEALTHY = "unhealthy"
+-- probe at most MAX_CONCURRENCY endpoints at the same time.
+local MAX_CONCURRENCY = 100
+
+_M.HEALTHY = HEALTHY
+_M.UNHEALTHY = UNHEALTHY
+
+local function dict()
+    return ngx_shared[subsys.CURRENT_SYBSYSTEM .. "_healthcheck"]
+end
+
+-- time of next probe of each target, only used in worker 0.
+local next_check = {}
+-- targets which have state in dict, only used in worker 0.
+local known
+local running = false
+
+---@param group string
+---@param node_key string
+---@return string
+function _M.target_key(group, node_key)
+    return group .. "/" .. node_key
+end
+
+--- @class HealthCheckState
+--- @field status string
+--- @field successes number
+--- @field failures number
+--- @field msg string|nil
+--- @field checked_at number|nil
+
+---@param key string
+---@return HealthCheckState
+function _M.get_state(key)
+    local raw = dict():get("d:" .. key)
+    if raw == nil then
+        return { status = HEALTHY, successes = 0, failures = 0 }
+    end
+    return common.json_decode(raw)
+end
+
+--- endpoint is healthy until it fails unhealthy_threshold times in a row, and unhealthy until it succeeds healthy_threshold times in a row.
+---@param state HealthCheckState
+---@param ok boolean
+---@param cfg HealthCheckPolicy
+---@return HealthCheckState
+function _M.next_state(state, ok, cfg)
+    local ret = { status = state.status, successes = state.successes, failures = state.failures }
+    if ok then
+        ret.successes = ret.successes + 1
+        ret.failures = 0
+        if ret.status == UNHEALTHY and ret.successes >= cfg.healthy_threshold then
+            ret.status = HEALTHY
+        end
+    else
+        ret.failures = ret.failures + 1
+        ret.successes = 0
+        if ret.status == HEALTHY and ret.failures >= cfg.unhealthy_threshold then
+            ret.status = UNHEALTHY
+        end
+    end
+    return ret
+end
+
+---@param expected number[]|nil
+---@param status number
+---@return boolean
+function _M.is_expected_status(expected, status)
+    if expected == nil or #expected == 0 then
+        return status >= 200 and status < 400
+    end
+    for _, s in ipairs(expected) do
+        if s == status then
+            return true
+        end
+    end
+    return false
+end
+
+---@param line string
+---@return number|nil
+function _M.parse_status_line(line)
+    local status = line:match("^HTTP/%d[%.%d]* (%d%d%d)")
+    return tonumber(status)
+end
+
+--- the sni of https probe is the host, except the address of endpoint which is not a valid sni.
+---@param host string
+---@return string|nil
+function _M.ssl_server_name(host)
+    if host:match("^[%d%.]+$") or host:find(":", 1, true) then
+        return nil
+    end
+    return host
+end
+
+--- probe a endpoint, return false and the reason if it is unhealthy.
+---@param node {address:string, port:number}
+---@param cfg HealthCheckPolicy
+---@return boolean ok
+---@return string|nil msg
+function _M.probe(node, cfg)
+    local port = node.port
+    if cfg.port and cfg.port > 0 then
+        port = cfg.port
+    end
+    local sock = ngx.socket.tcp()
+    sock:settimeout(cfg.timeout_ms)
+    local ok, err = sock:connect(node.address, port)
+    if not ok then
+        return false, "connect fail " .. tostring(err)
+    end
+    if cfg.type == "tcp" then
+        sock:close()
+        return true
+    end
+    local host = cfg.host
+    if host == nil or host == "" then
+        host = node.address
+    end
+    if cfg.type == "https" then
+        local session
+        session, err = sock:sslhandshake(nil, _M.ssl_server_name(host), cfg.verify == true)
+        if not session then
+            sock:close()
+            return false, "ssl handshake fail " .. tostring(err)
+        end
+    end
+    local req = string_format("GET %s HTTP/1.0\r\nHost: %s\r\nUser-Agent: alb-healthcheck\r\nConnection: close\r\n\r\n", cfg.path, host)
+    local bytes
+    bytes, err = sock:send(req)
+    if not bytes then
+        sock:close()
+        return false, "send fail " .. tostring(err)
+    end
+    local line
+    line, err = sock:receive("*l")
+    sock:close()
+    if not line then
+        return false, "receive fail " .. tostring(err)
+    end
+    local status = _M.parse_status_line(line)
+    if status == nil then
+        return false, "invalid status line " .. line
+    end
+    if not _M.is_expected_status(cfg.expected_status, status) then
+        return false, "unexpected status " .. status
+    end
+    return true
+end
+
+---@param group string
+---@param node_key string
+---@param healthy boolean|nil nil means the target is removed
+local function report_metrics(group, node_key, healthy)
+    if not subsys.is_http_subsystem() then
+        return
+    end
+    local metrics = require("metrics")
+    metrics.set_endpoint_health(group, node_key, healthy)
+end
+
+local function check_one(key, group, node, cfg)
+    local ok, msg = _M.probe(node, cfg)
+    local state = _M.next_state(_M.get_state(key), ok, cfg)
+    state.msg = msg
+    state.checked_at = ngx.now()
+    if not ok then
+        ngx_log(ngx.INFO, string_format("health check of %s fail %s", key, tostring(msg)))
+    end
+    local d = dict()
+    local old = d:get("s:" .. key)
+    if old ~= state.status then
+        ngx_log(ngx.WARN, string_format("endpoint %s becomes %s %s", key, state.status, tostring(msg)))
+    end
+    d:set("s:" .. key, state.status)
+    d:set("d:" .. key, common.json_encode(state))
+    report_metrics(group, bc.node_key(node), state.status == HEALTHY)
+end
+
+local function load_known()
+    local ret = {}
+    for _, k in ipairs(dict():get_keys(0)) do
+        local key = k:match("^s:(.*)$")
+        if key then
+            ret[key] = true
+        end
+    end
+    return ret
+end
+
+--- remove the state of targets which do not need to be checked anymore.
+local function cleanup(alive)
+    local d = dict()
+    for key, _ in pairs(known) do
+        if not alive[key] then
+            d:delete("s:" .. key)
+            d:delete("d:" .. key)
+            next_check[key] = nil
+            local group, node_key = key:match("^(.*)/([^/]*)$")
+            if group then
+                report_metrics(group, node_key, nil)
+            end
+        end
+    end
+    known = alive
+end
+
+local function check_all()
+    local backends_data = shm.get_backends()
+    if not backends_data then
+        return
+    end
+    local backends = common.json_decode(backends_data)
+    if not backends then
+        return
+    end
+    if known == nil then
+        known = load_known()
+    end
+    local now = ngx.now() * 1000
+    local alive = {}
+    local threads = {}
+    for _, group in ipairs(backends) do
+        local cfg = group.health_check
+        if cfg and group.backends then
+            for _, ep in ipairs(group.backends) do
+                local node = { address = bc.format_address(ep.address), port = ep.port }
+                local key = _M.target_key(group.name, bc.node_key(node))
+                alive[key] = true
+                if (next_check[key] or 0) <= now then
+                    next_check[key] = now + cfg.interval_ms
+                    table.insert(threads, ngx.thread.spawn(check_one, key, group.name, node, cfg))
+                    if #threads >= MAX_CONCURRENCY then
+                        for _, t in ipairs(threads) do
+                            ngx.thread.wait(t)
+                        end
+                        threads = {}
+                    end
+                end
+            end
+        end
+    end
+    for _, t in ipairs(threads) do
+        ngx.thread.wait(t)
+    end
+    cleanup(alive)
+end
+
+--- called by the timer of worker 0.
+function _M.run(premature)
+    if premature or running then
+        return
+    end
+    running = true
+    local ok, err = pcall(check_all)
+    running = false
+    if not ok then
+        ngx_log(ngx.ERR, "health check fail ", tostring(err))
+    end
+end
+
+---@param group string
+---@param node_key string
+---@return boolean
+function _M.is_healthy(group, node_key)
+    return dict():get("s:" .. _M.target_key(group, node_key)) ~= UNHEALTHY
+end
+
+--- remove the unhealthy endpoints. if all endpoints are unhealthy, keep all of them, since a unhealthy endpoint is better than nothing.
+---@param group string
+---@param endpoints Backend[]
+---@return Backend[]
+function _M.filter_healthy(group, endpoints)
+    local ret = {}
+    for _, ep in ipairs(endpoints) do
+        if _M.is_healthy(group, bc.node_key(ep)) then
+            table.insert(ret, ep)
+        end
+    end
+    if #ret == 0 and #endpoints ~= 0 then
+        ngx_log(ngx.WARN, "all endpoints of " .. group .. " are unhealthy, use all of them")
+        return endpoints
+    end
+    return ret
+end
+
+--- state of all checked endpoints, used by the debug api.
+---@return table<string, table<string, HealthCheckState>>
+function _M.status()
+    local ret = {}
+    local d = dict()
+    for _, k in ipairs(d:get_keys(0)) do
+        local key = k:match("^d:(.*)$")
+        if key then
+            local group, node_key = key:match("^(.*)/([^/]*)$")
+            local raw = d:get(k)
+            if group and raw then
+                ret[group] = ret[group] or {}
+                ret[group][node_key] = common.json_decode(raw)
+            end
+        end
+    end
+    return ret
+end
+
+return _M
@@ -9,6 +9,10 @@ local _M = balancer_resty:new()
 local DEFAULT_COOKIE_NAME = "JSESSIONID"
 
 function _M.cookie_name(self)
+    local cfg = self.session_affinity_cookie
+    if cfg and cfg.name and cfg.name ~= "" then
+        return cfg.name
+    end
     if self.session_affinity_attribute and self.session_affinity_attribute ~= "" then
         return self.session_affinity_attribute
     else
@@ -23,7 +27,7 @@ function _M.header_name(self)
 end
 
 function _M.new(self)
-    local o = { session_affinity_attribute = nil, session_affinity_policy = nil }
+    local o = { session_affinity_attribute = nil, session_affinity_policy = nil, session_affinity_cookie = nil }
 
     setmetatable(o, self)
     self.__index = self
@@ -58,15 +62,7 @@ function _M.set_cookie(self, value)
     if not cookie then
         ngx.log(ngx.ERR, err)
     end
-    -- LuaFormatter off
-    local cookie_data = {
-        key = self:cookie_name(),
-        value = value,
-        path = "/",
-        httponly = true,
-        secure = ngx.var.https == "on"
-    }
-    -- LuaFormatter on
+    local cookie_data = _M.cookie_data(self.session_affinity_cookie, self:cookie_name(), value, ngx.var.https == "on", ngx.time())
 
     local ok
     ok, err = cookie:set(cookie_data)
@@ -75,6 +71,39 @@ function _M.set_cookie(self, value)
     end
 end
 
+--- build the cookie via the session_affinity_cookie of backend group, path is / and httponly is true by default.
+---@param cfg SessionAffinityCookie|nil
+---@param name string
+---@param value string
+---@param https boolean
+---@param now number
+---@return table
+function _M.cookie_data(cfg, name, value, https, now)
+    cfg = cfg or {}
+    local data = { key = name, value = value, path = "/", httponly = true, secure = https }
+    if cfg.path and cfg.path ~= "" then
+        data.path = cfg.path
+    end
+    if cfg.domain and cfg.domain ~= "" then
+        data.domain = cfg.domain
+    end
+    if cfg.max_age ~= nil then
+        data.max_age = cfg.max_age
+        -- old browser does not support max-age.
+        data.expires = ngx.cookie_time(now + cfg.max_age)
+    end
+    if cfg.secure ~= nil then
+        data.secure = cfg.secure
+    end
+    if cfg.httponly ~= nil then
+        data.httponly = cfg.httponly
+    end
+    if cfg.samesite and cfg.samesite ~= "" then
+        data.extension = "SameSite=" .. cfg.samesite
+    end
+    return data
+end
+
 local function get_failed_upstreams()
     local indexed_upstream_addrs = {}
     local upstream_addrs = common.split_upstream_var(ngx.var.upstream_addr) or {}
@@ -90,6 +119,13 @@ local function should_set_cookie()
     return true
 end
 
+--- the cookie is set again on each response when refresh is enabled, so it expires after max_age of inactivity.
+---@param cfg SessionAffinityCookie|nil
+---@return boolean
+function _M.should_refresh_cookie(cfg)
+    return cfg ~= nil and cfg.refresh == true and cfg.max_age ~= nil
+end
+
 function _M.balance(self)
     local upstream_from_key
 
@@ -101,6 +137,9 @@ function _M.balance(self)
     local should_pick_new_upstream = upstream_from_key == nil
 
     if not should_pick_new_upstream then
+        if self:is_sticky_cookie() and _M.should_refresh_cookie(self.session_affinity_cookie) then
+            self:set_cookie(key)
+        end
         -- 使用请求中的key 来hash到对应的upstream
         return upstream_from_key
     end
@@ -125,6 +164,7 @@ function _M.sync(self, backend)
 
     self.session_affinity_attribute = backend.session_affinity_attribute
     self.session_affinity_policy = backend.session_affinity_policy
+    self.session_affinity_cookie = backend.session_affinity_cookie
 end
 
 return _M
@@ -30,16 +30,38 @@ function M.select_cert()
 
     cache.cert_cache:update(0.1)
 
-    local key = tostring(host) .. "/" .. tostring(port)
-    local cert = cache.cert_cache:get(key, nil, M.get_domain_cert, host, port)
+    local cert = M.get_conn_cert(host, port)
     if cert == nil then
-        ngx.log(ngx.ERR, "no cert found for ", key)
+        ngx.log(ngx.ERR, "no cert found for ", tostring(host) .. "/" .. tostring(port))
         return ngx.exit(ngx.ERROR)
     end
 
     M.set_cert(cert)
 end
 
+-- return the cert which is used in the tls handshake of sni and port. it is also used in the request phase
+-- to find out the ca which the client certificate is verified with.
+---@param host string?
+---@param port string|number
+---@return Certificate?
+function M.get_conn_cert(host, port)
+    host = M.normalize_host(host)
+    local key = tostring(host) .. "/" .. tostring(port)
+    return cache.cert_cache:get(key, nil, M.get_domain_cert, host, port)
+end
+
+-- NOTE: when the SNI name is missing from the client handshake request,
+-- we use the server IP address accessed by the client to identify the site
+function M.normalize_host(host_name)
+    if host_name == "" then
+        return nil
+    end
+    if host_name ~= nil and tonumber(string_sub(host_name, -1)) ~= nil then
+        return nil
+    end
+    return host_name
+end
+
 function M.get_host()
     local host_name, err = ssl.server_name()
     if err then
@@ -50,13 +72,7 @@ function M.get_host()
     if err then
         return nil, nil, "failed to read server port: " .. tostring(err)
     end
-    -- NOTE: when the SNI name is missing from the client handshake request,
-    -- we use the server IP address accessed by the client to identify the site
-    if host_name ~= nil and tonumber(string_sub(host_name, -1)) ~= nil then
-        host_name = nil
-    end
-
-    return host_name, port, nil
+    return M.normalize_host(host_name), port, nil
 end
 
 function M.get_domain_cert(domain, port)
@@ -92,16 +108,17 @@ function M.try_get_domain_cert_from_l2_cache(domain_raw, port_raw)
         end
         return nil
     end
+    -- domain/port first, it is the copy of cert which carries the client ca of this port (see attachClientCa).
     local domain_str = tostring(domain_raw)
     local port_str = tostring(port_raw)
-    local cert_full_host, find = M.get_cert(domain_str)
+    local cert_full_host_in_port, find = M.get_cert(domain_str .. "/" .. port_str)
     if find then
-        return cert_full_host
+        return cert_full_host_in_port
     end
 
-    local cert_full_host_in_port, find = M.get_cert(domain_str .. "/" .. port_str)
+    local cert_full_host, find = M.get_cert(domain_str)
     if find then
-        return cert_full_host_in_port
+        return cert_full_host
     end
 
     -- wildcard host cert
@@ -112,14 +129,14 @@ function M.try_get_domain_cert_from_l2_cache(domain_raw, port_raw)
     end
 
     local wildcard_host = "*" .. string.sub(domain_str, index, #domain_str)
-    local cert_wildcard_host, find = M.get_cert(wildcard_host)
+    local cert_wildcard_host_in_port, find = M.get_cert(wildcard_host .. "/" .. port_str)
     if find then
-        return cert_wildcard_host
+        return cert_wildcard_host_in_port
     end
 
-    local cert_wildcard_host_in_port, find = M.get_cert(wildcard_host .. "/" .. port_str)
+    local cert_wildcard_host, find = M.get_cert(wildcard_host)
     if find then
-        return cert_wildcard_host_in_port
+        return cert_wildcard_host
     end
     if M.FallbackDefaultPortCert then
         return M.get_cert(port_str)
@@ -162,6 +179,34 @@ function M.set_cert(cert)
         ngx.log(ngx.ERR, "failed to set DER private key: ", err)
         return ngx.exit(ngx.ERROR)
     end
+
+    if cert["client_ca"] ~= nil then
+        M.verify_client(cert["client_ca"])
+    end
+end
+
+-- the parsed ca, key is the client_ca table which is cached in cert_cache.
+local parsed_ca = setmetatable({}, { __mode = "k" })
+
+-- request the certificate of client and verify it with the ca.
+-- the handshake will not fail if the certificate is invalid, the result ($ssl_client_verify) is checked by the client_tls plugin.
+---@param client_ca CaCertificate
+function M.verify_client(client_ca)
+    local ca = parsed_ca[client_ca]
+    if ca == nil then
+        local err
+        ca, err = ssl.parse_pem_cert(client_ca["cert"])
+        if not ca then
+            ngx.log(ngx.ERR, "failed to parse client ca: ", err)
+            return ngx.exit(ngx.ERROR)
+        end
+        parsed_ca[client_ca] = ca
+    end
+    local ok, err = ssl.verify_client(ca, client_ca["depth"])
+    if not ok then
+        ngx.log(ngx.ERR, "failed to verify client: ", err)
+        return ngx.exit(ngx.ERROR)
+    end
 end
 
 return M
@@ -93,6 +93,10 @@ local function update_stream_cache(policy, old_policy)
     local stream_udp_policy = common.access_or(policy, { "stream", "udp" }, {})
     local old_stream_udp_policy = common.access_or(old_policy, { "stream", "udp" }, {})
     update_stream_policy_cache(stream_udp_policy, old_stream_udp_policy, "udp")
+
+    local stream_tls_policy = common.access_or(policy, { "stream", "tls" }, {})
+    local old_stream_tls_policy = common.access_or(old_policy, { "stream", "tls" }, {})
+    update_stream_policy_cache(stream_tls_policy, old_stream_tls_policy, "tls")
 end
 
 ---@param policy table|nil
@@ -100,6 +104,7 @@ end
 local function update_http_cache(policy, old_policy)
     local certificate_map = common.access_or(policy, { "certificate_map" }, {})
     local old_certificate_map = common.access_or(old_policy, { "certificate_map" }, {})
+    local certificate_expiry = common.access_or(policy, { "certificate_expiry" }, {})
 
     local http_policy = common.access_or(policy, { "http", "tcp" }, {})
     local old_http_policy = common.access_or(old_policy, { "http", "tcp" }, {})
@@ -133,6 +138,8 @@ local function update_http_cache(policy, old_policy)
         end
     end
 
+    shm.set_cert_expiry(common.json_encode(certificate_expiry))
+
     -- update policy cache
     for port, reason in pairs(common.get_table_diff_keys(http_policy, old_http_policy)) do
         -- we only support http via tcp now.
@@ -38,6 +38,19 @@ function _M.get_http_cert(key)
     return ngx_shared["http_certs_cache"]:get(key)
 end
 
+-- the key of cert is domain or port, it never starts with /.
+local CERT_EXPIRY_KEY = "/expiry"
+
+---@param value string|nil json of CertificateExpiry[]
+function _M.set_cert_expiry(value)
+    ngx_shared["http_certs_cache"]:set(CERT_EXPIRY_KEY, value)
+end
+
+--- @return string|nil
+function _M.get_cert_expiry()
+    return ngx_shared["http_certs_cache"]:get(CERT_EXPIRY_KEY) --[[@as string|nil]]
+end
+
 ---@param port string
 function _M.del_http_rule(port)
     ngx_shared["http_policy"]:delete(port)
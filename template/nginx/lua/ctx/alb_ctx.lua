@@ -23,6 +23,10 @@ local var_proxy = require "ctx.var_proxy"
 ---@field var table          var proxy
 ---@field otel OtelCtx?
 ---@field auth AuthCtx?
+---@field ratelimit table?     the committed limit conn, which should leave in log phase
+---@field ipacl_denied string?  why the request is denied by ipacl
+---@field outlier table?       the committed limit conn of upstream, which should leave in log phase
+---@field outlier_overflow boolean?  the request is rejected since the concurrent requests of upstream reach the limit
 
 ---@param ctx AlbCtx
 function _M.get_last_upstream_status(ctx)
@@ -13,6 +13,11 @@ _M.BackendError = "BackendError"
 _M.TimeoutViaAlb = "TimeoutViaAlb"
 _M.TimeoutViaBackend = "TimeoutViaBackend"
 _M.AUTHFAIL = "AuthFail"
+_M.RateLimited = "RateLimited"
+_M.IpDenied = "IpDenied"
+_M.ClientCertInvalid = "ClientCertInvalid"
+_M.UpstreamOverflow = "UpstreamOverflow"
+_M.BodyTooLarge = "BodyTooLarge"
 
 ---comment
 -- exit with code 500 Internal Server Error
@@ -15,7 +15,12 @@ if port == nil then
     return e.exit(e.InvalidUpstream, msg)
 end
 
-local t_upstream, matched_policy, errmsg = upstream.get_upstream(subsystem, ngx_var.protocol, port)
+-- tls passthrough server will set $stream_protocol to tls, and route via sni.
+local protocol = ngx_var.stream_protocol
+if protocol == nil or protocol == "" then
+    protocol = ngx_var.protocol
+end
+local t_upstream, matched_policy, errmsg = upstream.get_upstream(subsystem, protocol, port)
 
 if t_upstream == nil then
     local msg = "alb upstream not found "
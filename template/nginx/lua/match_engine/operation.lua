@@ -10,6 +10,7 @@ local string_sub = string.sub
 local table_remove = table.remove
 local string_format = string.format
 local ip_util = require "utils.ip"
+local realip = require "utils.realip"
 local ngx = ngx
 local ngx_re = ngx.re
 local _M = {}
@@ -43,6 +44,9 @@ local function parse_single_matcher(matcher)
     elseif(matcher == "URL") then
         return ngx.ctx.alb_ctx.var.uri
     elseif(matcher == "SRC_IP") then
+        if realip.enabled(ngx.ctx.alb_ctx) then
+          return ngx.ctx.alb_ctx.var.remote_addr
+        end
         local x_real_ip = ngx.ctx.alb_ctx.var["http_" .. "x_real_ip"]
         if x_real_ip then
           return x_real_ip
@@ -80,6 +84,17 @@ local function parse_dual_matcher(matcher, key)
     return nil
 end
 
+-- get the value of matcher in current request, key is only used by HEADER/PARAM/COOKIE.
+-- example: ("SRC_IP", nil) -> client ip, ("HEADER", "UID") -> req.header["UID"]
+function _M.get_matcher_value(matcher, key)
+    if(single_matcher[matcher]) then
+        return parse_single_matcher(matcher)
+    elseif(dual_matcher[matcher]) then
+        return parse_dual_matcher(matcher, key)
+    end
+    return nil
+end
+
 -- split args to matcher that to apply operation and args that left
 -- return: matcher, args, error
 -- example: ["HOST", "www.baidu.com", "baidu.com"] -> req.host, ["www.baidu.com", "baidu.com"], nil
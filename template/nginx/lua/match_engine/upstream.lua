@@ -25,9 +25,36 @@ local function get_policies_of_port(key)
     return policies
 end
 
+--- find policy by sni. exact match first, then wildcard (*.a.com match x.a.com and x.y.a.com), then the default one ("").
+--- @param policies table<string, Policy>
+--- @param sni string|nil
+--- @return Policy|nil
+local function find_sni_policy(policies, sni)
+    if sni == nil or sni == "" then
+        return policies[""]
+    end
+    sni = string.lower(sni)
+    if policies[sni] ~= nil then
+        return policies[sni]
+    end
+    local suffix = sni
+    while true do
+        local dot = string.find(suffix, ".", 1, true)
+        if dot == nil then
+            break
+        end
+        suffix = string.sub(suffix, dot + 1)
+        local p = policies["*." .. suffix]
+        if p ~= nil then
+            return p
+        end
+    end
+    return policies[""]
+end
+
 ---comment
 --- @param subsystem string http|stream
---- @param protocol  string tcp|udp
+--- @param protocol  string tcp|udp|tls
 --- @param port  number
 --- @return string|nil upstream
 --- @return Policy|nil matched_policy
@@ -68,6 +95,13 @@ function _M.get_upstream(subsystem, protocol, port)
                 end
             end
         end
+    elseif subsystem == "stream" and protocol == "tls" then
+        local sni = ngx.var.ssl_preread_server_name
+        local policy = find_sni_policy(policies, sni)
+        if policy ~= nil then
+            return policy.upstream, policy, nil
+        end
+        return nil, nil, "no sni match " .. tostring(sni)
     elseif subsystem == "stream" and next(policies) ~= nil then
         return policies[1]["upstream"], policies[1], nil
     end
@@ -76,4 +110,6 @@ function _M.get_upstream(subsystem, protocol, port)
     return nil, nil, "no rule match"
 end
 
+_M.find_sni_policy = find_sni_policy
+
 return _M
@@ -11,6 +11,10 @@ local ngx_log = ngx.log
 local _metrics = {}
 local _prometheus
 local mauth = require("metrics_auth")
+local common = require("utils.common")
+local shm = require("config.shmap")
+-- the raw certificate_expiry which the gauge is set from in this worker.
+local _last_cert_expiry
 
 function _M.init()
     ngx_log(ngx.INFO, "init metrics for " .. tostring(ngx.worker.id()) .. " " .. tostring(ngx.worker.pid()))
@@ -53,6 +57,16 @@ function _M.init()
         "HTTP status code per rule per upstream",
         { "port", "rule", "upstream_ip", "status", "method", "source_type", "source_namespace", "source_name" }
     )
+    _metrics.upstream_outlier_transitions = _prometheus:counter(
+        "nginx_http_upstream_outlier_transitions",
+        "Number of state transitions of upstream reported by outlier detection",
+        { "port", "rule", "upstream_ip", "state" }
+    )
+    _metrics.upstream_overflow = _prometheus:counter(
+        "nginx_http_upstream_overflow",
+        "Number of requests rejected since the concurrent requests of upstream reach the limit",
+        { "port", "rule" }
+    )
     _metrics.upstream_latency = _prometheus:histogram(
         "nginx_http_upstream_request_duration_seconds",
         "HTTP request latency per upstream",
@@ -61,6 +75,21 @@ function _M.init()
     )
 
     _metrics.alb_error = _prometheus:counter("alb_error", "error cause by alb itself", { "port" })
+    _metrics.ipacl_denied = _prometheus:counter(
+        "alb_ipacl_denied",
+        "Number of requests denied by ipacl",
+        { "port", "rule", "reason" }
+    )
+    _metrics.endpoint_healthy = _prometheus:gauge(
+        "alb_upstream_endpoint_healthy",
+        "Health state of endpoint reported by active health check, 1 is healthy",
+        { "backend_group", "endpoint" }
+    )
+    _metrics.certificate_expiry = _prometheus:gauge(
+        "alb_certificate_expiry_seconds",
+        "Seconds until the certificate expires, negative if it has expired",
+        { "domain", "secret" }
+    )
     _metrics.metrics_free_cache_size = _prometheus:gauge("metrics_cache_size", "size of metrics cache")
     -- LuaFormatter on
 end
@@ -100,10 +129,66 @@ function _M.log()
     if ngx.ctx.is_alb_err == true then
         _metrics.alb_error:inc(1, { server_port })
     end
+    local alb_ctx = ngx.ctx.alb_ctx
+    if alb_ctx ~= nil and alb_ctx.ipacl_denied ~= nil then
+        _metrics.ipacl_denied:inc(1, { server_port, rule_name, alb_ctx.ipacl_denied })
+    end
+    if alb_ctx ~= nil and alb_ctx.outlier_overflow == true then
+        _metrics.upstream_overflow:inc(1, { server_port, rule_name })
+    end
+end
+
+--- state is one of ejected, half_open and closed, see plugins/outlier.lua.
+---@param rule string
+---@param upstream_ip string
+---@param state string
+function _M.inc_outlier_transition(rule, upstream_ip, state)
+    if _metrics.upstream_outlier_transitions == nil then
+        return
+    end
+    _metrics.upstream_outlier_transitions:inc(1, { ngx_var.server_port or "", rule, upstream_ip, state })
+end
+
+--- set by the health checker of worker 0, nil means the endpoint is not checked anymore.
+---@param group string
+---@param endpoint string
+---@param healthy boolean|nil
+function _M.set_endpoint_health(group, endpoint, healthy)
+    if _metrics.endpoint_healthy == nil then
+        return
+    end
+    if healthy == nil then
+        _metrics.endpoint_healthy:del({ group, endpoint })
+        return
+    end
+    _metrics.endpoint_healthy:set(healthy and 1 or 0, { group, endpoint })
+end
+
+--- set the expiry of certificates in use from the certificate_expiry of policy, it is updated on each scrape.
+local function set_certificate_expiry()
+    local raw = shm.get_cert_expiry()
+    if raw ~= _last_cert_expiry then
+        -- the certificate may be removed.
+        _metrics.certificate_expiry:reset()
+        _last_cert_expiry = raw
+    end
+    if raw == nil then
+        return
+    end
+    ---@type CertificateExpiry[]|nil
+    local certs = common.json_decode(raw)
+    if certs == nil then
+        return
+    end
+    local now = ngx.time()
+    for _, c in ipairs(certs) do
+        _metrics.certificate_expiry:set(c.not_after - now, { c.domain, c.secret })
+    end
 end
 
 function _M.collect()
     mauth.verify_auth()
+    set_certificate_expiry()
     _metrics.connection:set(ngx_var.connections_reading, { "reading" })
     _metrics.connection:set(ngx_var.connections_waiting, { "waiting" })
     _metrics.connection:set(ngx_var.connections_writing, { "writing" })
@@ -6,6 +6,7 @@ local string_format = string.format
 local ngx = ngx
 
 local balancer = require "balancer.balance"
+local healthcheck = require "balancer.healthcheck"
 local metrics = require("metrics")
 local ngx_log = ngx.log
 local ngx_timer = ngx.timer
@@ -38,6 +39,14 @@ if err then
     ngx_log(ngx.ERR, string_format("error when setting up timer.every for sync_backends: %s", tostring(err)))
 end
 
+-- active health check is done by the worker 0, the state is shared with other workers via shared dict.
+if ngx_worker.id() == 0 then
+    local _, err = ngx_timer.every(1, healthcheck.run)
+    if err then
+        ngx_log(ngx.ERR, string_format("error when setting up timer.every for health check: %s", tostring(err)))
+    end
+end
+
 local clean_metrics = function(premature)
     if premature then
         return
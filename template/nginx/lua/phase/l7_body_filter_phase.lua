@@ -0,0 +1,7 @@
+-- format:on
+local errorpage = require("plugins.errorpage")
+
+if ngx.ctx.matched_policy == nil then
+    return
+end
+errorpage.body_filter()
@@ -2,6 +2,7 @@
 local e = require "error"
 local str = require "resty.string"
 local pm = require("plugins.core.plugin_manager")
+local errorpage = require("plugins.errorpage")
 
 local matched_policy = ngx.ctx.matched_policy
 if matched_policy == nil then
@@ -12,6 +13,8 @@ local rewrite_header = require "rewrite_header"
 
 cors.header_filter()
 rewrite_header.rewrite_response_header()
+-- the error of alb could also be replaced by the custom page.
+errorpage.header_filter(ngx.ctx.alb_ctx)
 if ngx.ctx.is_alb_err then
     return
 end
@@ -7,7 +7,9 @@ local pm = require("plugins.core.plugin_manager")
 if not ngx.ctx.alb_ctx then
     return
 end
-metrics.log()
+if ngx.var.alb_enable_prometheus == "true" then
+    metrics.log()
+end
 if ngx.ctx.alb_ctx.matched_policy then
     pm.log_hook(ngx.ctx.alb_ctx)
 end
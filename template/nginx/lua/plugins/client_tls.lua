@@ -0,0 +1,94 @@
+-- format:on style:emmy
+-- check the result of client certificate verification, which is done in the tls handshake (see cert_tool.verify_client).
+-- on: the client must send a valid certificate.
+-- optional: the certificate is optional, but it must be valid if it is sent.
+-- optional_no_ca: the certificate is optional and not verified, only pass it to upstream.
+local _m = {}
+local cache = require("config.cache")
+local cert_tool = require("cert_tool")
+local eh = require("error")
+local ngx = ngx
+local string_sub = string.sub
+
+local VERIFY_SUCCESS = "SUCCESS"
+local VERIFY_NONE = "NONE"
+local HTTP_MISDIRECTED_REQUEST = 421
+
+---@param ctx AlbCtx
+function _m.after_rule_match_hook(ctx)
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil then
+        return
+    end
+    local verify = ctx.var["ssl_client_verify"] or VERIFY_NONE
+    local reason = _m.check(cfg.verify_client, verify)
+    if reason ~= nil then
+        return eh.exit_with_code(eh.ClientCertInvalid, reason, ngx.HTTP_BAD_REQUEST)
+    end
+    -- the ca is chosen by sni in the handshake, but the rule is matched by host.
+    -- a client verified by the ca of other domain (e.g. sni b.com with host a.com) should not pass.
+    if verify == VERIFY_SUCCESS and cfg.verify_client ~= "optional_no_ca" then
+        reason = _m.check_ca(cfg.ca_id, _m.get_conn_ca_id(ctx))
+        if reason ~= nil then
+            return eh.exit_with_code(eh.ClientCertInvalid, reason, HTTP_MISDIRECTED_REQUEST)
+        end
+    end
+    -- the same headers as ingress-nginx
+    ngx.req.set_header("ssl-client-verify", verify)
+    if verify == VERIFY_NONE then
+        return
+    end
+    ngx.req.set_header("ssl-client-subject-dn", ctx.var["ssl_client_s_dn"])
+    ngx.req.set_header("ssl-client-issuer-dn", ctx.var["ssl_client_i_dn"])
+    if cfg.pass_certificate_to_upstream then
+        ngx.req.set_header("ssl-client-cert", ctx.var["ssl_client_escaped_cert"])
+    end
+end
+
+--- return the reason if the request should be rejected.
+---@param mode string
+---@param verify string SUCCESS NONE or FAILED:reason
+---@return string?
+function _m.check(mode, verify)
+    if mode == "optional_no_ca" or verify == VERIFY_SUCCESS then
+        return nil
+    end
+    if verify == VERIFY_NONE then
+        if mode == "optional" then
+            return nil
+        end
+        return "no required ssl certificate was sent"
+    end
+    return "invalid certificate " .. string_sub(verify, #"FAILED:" + 1)
+end
+
+--- return the reason if the ca which verified the client is not the one of rule.
+---@param expect string?
+---@param actual string?
+---@return string?
+function _m.check_ca(expect, actual)
+    if expect ~= nil and expect ~= "" and expect == actual then
+        return nil
+    end
+    return "the client certificate is not verified by the ca of this host"
+end
+
+--- the id of the ca which the client certificate is verified with in the handshake.
+---@param ctx AlbCtx
+---@return string?
+function _m.get_conn_ca_id(ctx)
+    local cert = cert_tool.get_conn_cert(ctx.var["ssl_server_name"], ctx.var["server_port"])
+    if cert == nil or cert.client_ca == nil then
+        return nil
+    end
+    return cert.client_ca.id
+end
+
+---@param ctx AlbCtx
+---@return ClientTLSPolicy?
+---@return any? error
+function _m.get_config(ctx)
+    return cache.get_config_from_policy(ctx.matched_policy, "client_tls")
+end
+
+return _m
@@ -13,10 +13,26 @@ function _m.init()
     local otel = require("plugins.otel.otel")
     local auth = require("plugins.auth.auth")
     local timeout = require("plugins.timeout")
+    local mirror = require("plugins.mirror")
+    local upstream_tls = require("plugins.upstream_tls")
+    local ratelimit = require("plugins.ratelimit")
+    local ipacl = require("plugins.ipacl")
+    local client_tls = require("plugins.client_tls")
+    local outlier = require("plugins.outlier")
+    local proxy = require("plugins.proxy")
+    local errorpage = require("plugins.errorpage")
     _m.plugins = {
         ["auth"] = auth,
         ["otel"] = otel,
         ["timeout"] = timeout,
+        ["mirror"] = mirror,
+        ["upstream_tls"] = upstream_tls,
+        ["ratelimit"] = ratelimit,
+        ["ipacl"] = ipacl,
+        ["client_tls"] = client_tls,
+        ["outlier"] = outlier,
+        ["proxy"] = proxy,
+        ["errorpage"] = errorpage,
     }
 end
 
@@ -0,0 +1,232 @@
+-- format:on style:emmy
+-- replace the body of error response with a custom page, the page is from configmap or a service.
+-- request could not be sent in the header/body filter phases, so the page of service is fetched in background and cached,
+-- the stale one is used while it is refreshing. the pages are prefetched when the config is loaded by the first matched request,
+-- the original body is used until the page is fetched.
+-- the error generated by nginx (e.g. 502 when upstream could not be connected) is redirected to the /custom_error location
+-- via error_page, where ngx.ctx is cleared, so the ref of config is passed via the $errorpage_ref variable.
+local _m = {}
+local cache = require("config.cache")
+local lrucache = require("resty.lrucache")
+local ngx = ngx
+local ipairs = ipairs
+local tostring = tostring
+local string_find = string.find
+local string_sub = string.sub
+
+local PAGE_TTL_S = 60
+local FETCH_TIMEOUT_MS = 3 * 1000
+local DEFAULT_FORMAT = "text/html"
+
+local pages = lrucache.new(1000)
+-- keys which are being fetched in this worker
+local fetching = {}
+-- the policy cfg is cached in lru, cache the code map with it.
+local parsed_cache = setmetatable({}, { __mode = "k" })
+-- cfgs whose service pages have been prefetched in this worker, a new cfg is loaded when the policy changes.
+local prefetched = setmetatable({}, { __mode = "k" })
+
+---@param ctx AlbCtx
+function _m.after_rule_match_hook(ctx)
+    local config = ctx.matched_policy.config
+    if config == nil or type(config.refs) ~= "table" or config.refs.errorpage == nil then
+        return
+    end
+    ngx.var.errorpage_ref = config.refs.errorpage
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil then
+        return
+    end
+    _m.prefetch(cfg)
+end
+
+-- fetch the service pages of the configured codes in background, so the first error could be replaced.
+---@param cfg ErrorPagePolicy
+function _m.prefetch(cfg)
+    if prefetched[cfg] then
+        return
+    end
+    prefetched[cfg] = true
+    for _, p in ipairs(_m.service_pages(cfg)) do
+        _m.get_service_page(p.upstream, p.code, DEFAULT_FORMAT)
+    end
+end
+
+-- the codes which are replaced by the page of service.
+---@param cfg ErrorPagePolicy
+---@return {upstream:string, code:number}[]
+function _m.service_pages(cfg)
+    local ret = {}
+    for _, page in ipairs(cfg.pages or {}) do
+        if page.upstream ~= nil and page.upstream ~= "" then
+            for _, code in ipairs(page.codes or {}) do
+                if _m.find_page(cfg, code) == page then
+                    ret[#ret + 1] = { upstream = page.upstream, code = code }
+                end
+            end
+        end
+    end
+    return ret
+end
+
+-- called in the header filter phase of the location which the policy matched.
+---@param ctx AlbCtx
+function _m.header_filter(ctx)
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil then
+        return
+    end
+    _m.render(cfg)
+end
+
+-- called in the header filter phase of the /custom_error location.
+function _m.error_location_header_filter()
+    local ref = ngx.var.errorpage_ref
+    if ref == nil or ref == "" then
+        return
+    end
+    local cfg, err = cache.get_config(ref)
+    if err ~= nil or cfg == nil or cfg.errorpage == nil then
+        return
+    end
+    _m.render(cfg.errorpage)
+end
+
+function _m.body_filter()
+    local body = ngx.ctx.errorpage_body
+    if body == nil then
+        return
+    end
+    if ngx.ctx.errorpage_sent then
+        ngx.arg[1] = nil
+        return
+    end
+    ngx.ctx.errorpage_sent = true
+    ngx.arg[1] = body
+    ngx.arg[2] = true
+end
+
+---@param cfg ErrorPagePolicy
+function _m.render(cfg)
+    local page = _m.find_page(cfg, ngx.status)
+    if page == nil then
+        return
+    end
+    local body, content_type
+    if page.upstream ~= nil and page.upstream ~= "" then
+        body, content_type = _m.get_service_page(page.upstream, ngx.status, _m.format(ngx.var.http_accept))
+    else
+        body, content_type = page.body, page.content_type
+    end
+    if body == nil then
+        return
+    end
+    ngx.header["Content-Type"] = content_type
+    ngx.header["Content-Length"] = #body
+    ngx.header["Content-Encoding"] = nil
+    ngx.ctx.errorpage_body = body
+end
+
+---@param cfg ErrorPagePolicy
+---@param code number
+---@return ErrorPagePolicyPage?
+function _m.find_page(cfg, code)
+    local codes = parsed_cache[cfg]
+    if codes == nil then
+        codes = {}
+        for _, page in ipairs(cfg.pages or {}) do
+            for _, c in ipairs(page.codes or {}) do
+                -- the first page wins
+                if codes[c] == nil then
+                    codes[c] = page
+                end
+            end
+        end
+        parsed_cache[cfg] = codes
+    end
+    return codes[code]
+end
+
+-- the first media type of accept header, it is passed to the service via the X-Format header like ingress-nginx.
+---@param accept string?
+---@return string
+function _m.format(accept)
+    if accept == nil or accept == "" then
+        return DEFAULT_FORMAT
+    end
+    local format = accept
+    local i = string_find(format, "[,;]")
+    if i ~= nil then
+        format = string_sub(format, 1, i - 1)
+    end
+    format = format:match("^%s*(.-)%s*$")
+    if format == "" or format == "*/*" then
+        return DEFAULT_FORMAT
+    end
+    return format
+end
+
+---@param upstream string
+---@param code number
+---@param format string
+---@return string? body
+---@return string? content_type
+function _m.get_service_page(upstream, code, format)
+    local key = upstream .. "/" .. tostring(code) .. "/" .. format
+    -- the page does not expire in lru, so the outdated one is still used while it is refreshing.
+    local page = pages:get(key)
+    if page == nil or ngx.now() - page.fetched_at > PAGE_TTL_S then
+        _m.refresh(key, upstream, code, format)
+    end
+    if page ~= nil then
+        return page.body, page.content_type
+    end
+    return nil, nil
+end
+
+function _m.refresh(key, upstream, code, format)
+    if fetching[key] then
+        return
+    end
+    -- require here to avoid circular require, balance require plugin_manager.
+    local balance = require("balancer.balance")
+    local peer = balance.get_peer(upstream)
+    if peer == nil then
+        ngx.log(ngx.WARN, "errorpage: no peer found for ", upstream)
+        return
+    end
+    fetching[key] = true
+    local ok, err = ngx.timer.at(0, _m.fetch, key, peer, code, format)
+    if not ok then
+        fetching[key] = nil
+        ngx.log(ngx.ERR, "errorpage: create timer fail ", err)
+    end
+end
+
+function _m.fetch(premature, key, peer, code, format)
+    if premature then
+        fetching[key] = nil
+        return
+    end
+    local httpc = require("resty.http").new()
+    httpc:set_timeout(FETCH_TIMEOUT_MS)
+    local res, err = httpc:request_uri("http://" .. peer .. "/", {
+        method = "GET",
+        headers = { ["X-Code"] = tostring(code), ["X-Format"] = format },
+    })
+    fetching[key] = nil
+    if err ~= nil then
+        ngx.log(ngx.WARN, "errorpage: fetch page fail ", peer, " ", err)
+        return
+    end
+    pages:set(key, { body = res.body or "", content_type = res.headers["Content-Type"] or format, fetched_at = ngx.now() })
+end
+
+---@param ctx AlbCtx
+---@return ErrorPagePolicy?
+---@return any? error
+function _m.get_config(ctx)
+    return cache.get_config_from_policy(ctx.matched_policy, "errorpage")
+end
+
+return _m
@@ -0,0 +1,156 @@
+-- format:on style:emmy
+-- allow or deny request via the client ip.
+-- the client ip is the addr of proxy protocol if it is enabled, otherwise the remote addr.
+-- if it is a trusted proxy, x-forwarded-for is walked from right to left, the first untrusted addr is the client ip.
+-- request is denied if the client ip is in the deny list, or the allow list is not empty and the client ip is not in it.
+local _m = {}
+local cache = require("config.cache")
+local eh = require("error")
+local ip_util = require("utils.ip")
+local realip = require("utils.realip")
+local ngx = ngx
+local ipairs = ipairs
+local string_gmatch = string.gmatch
+
+local REASON_DENYLIST = "denylist"
+local REASON_NOT_IN_ALLOWLIST = "not_in_allowlist"
+
+---@class ParsedIpRange
+---@field family number
+---@field start number[]
+---@field finish number[]
+
+---@class ParsedIpAcl
+---@field allow ParsedIpRange[]
+---@field deny ParsedIpRange[]
+---@field trusted_proxies ParsedIpRange[]
+
+-- the policy cfg is cached in lru, cache the parsed ranges with it.
+local parsed_cache = setmetatable({}, { __mode = "k" })
+
+---@param ctx AlbCtx
+function _m.after_rule_match_hook(ctx)
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil then
+        return
+    end
+    local acl = _m.parse(cfg)
+    local proxy_protocol_addr = ctx.var["proxy_protocol_addr"]
+    -- the remote addr has been resolved from the proxy protocol or the trusted proxies of frontend.
+    if realip.enabled(ctx) then
+        proxy_protocol_addr = nil
+    end
+    local ip = _m.get_client_ip(acl, proxy_protocol_addr, ctx.var["remote_addr"], ctx.var["http_x_forwarded_for"])
+    local reason = _m.check(acl, ip)
+    if reason == nil then
+        return
+    end
+    ctx.ipacl_denied = reason
+    return eh.exit_with_code(eh.IpDenied, tostring(ip) .. " " .. reason, ngx.HTTP_FORBIDDEN)
+end
+
+---@param cfg IpAclPolicy
+---@return ParsedIpAcl
+function _m.parse(cfg)
+    local acl = parsed_cache[cfg]
+    if acl ~= nil then
+        return acl
+    end
+    acl = {
+        allow = _m.parse_ranges(cfg.allow),
+        deny = _m.parse_ranges(cfg.deny),
+        trusted_proxies = _m.parse_ranges(cfg.trusted_proxies),
+    }
+    parsed_cache[cfg] = acl
+    return acl
+end
+
+---@param ranges IpRange[]?
+---@return ParsedIpRange[]
+function _m.parse_ranges(ranges)
+    local ret = {}
+    for _, r in ipairs(ranges or {}) do
+        local family, start = ip_util.parse_ip(r.start)
+        local efamily, finish = ip_util.parse_ip(r["end"])
+        if family == nil or family ~= efamily then
+            ngx.log(ngx.ERR, "ipacl: invalid ip range ", r.start, " ", r["end"])
+        else
+            ret[#ret + 1] = { family = family, start = start, finish = finish }
+        end
+    end
+    return ret
+end
+
+---@param ranges ParsedIpRange[]
+---@param family number?
+---@param ip number[]?
+---@return boolean
+function _m.in_ranges(ranges, family, ip)
+    if family == nil then
+        return false
+    end
+    for _, r in ipairs(ranges) do
+        if r.family == family and ip_util.compare(r.start, ip) <= 0 and ip_util.compare(ip, r.finish) <= 0 then
+            return true
+        end
+    end
+    return false
+end
+
+---@param acl ParsedIpAcl
+---@param ip string?
+---@return boolean
+function _m.is_trusted(acl, ip)
+    local family, parsed = ip_util.parse_ip(ip)
+    return _m.in_ranges(acl.trusted_proxies, family, parsed)
+end
+
+---@param acl ParsedIpAcl
+---@param proxy_protocol_addr string?
+---@param remote_addr string?
+---@param xff string?
+---@return string?
+function _m.get_client_ip(acl, proxy_protocol_addr, remote_addr, xff)
+    local ip = remote_addr
+    if proxy_protocol_addr ~= nil and proxy_protocol_addr ~= "" then
+        ip = proxy_protocol_addr
+    end
+    if #acl.trusted_proxies == 0 or xff == nil or xff == "" or not _m.is_trusted(acl, ip) then
+        return ip
+    end
+    local hops = {}
+    for hop in string_gmatch(xff, "[^,%s]+") do
+        hops[#hops + 1] = hop
+    end
+    for i = #hops, 1, -1 do
+        ip = hops[i]
+        if not _m.is_trusted(acl, ip) then
+            return ip
+        end
+    end
+    -- all of the hops are trusted, the leftmost one is the client.
+    return ip
+end
+
+---@param acl ParsedIpAcl
+---@param ip string?
+---@return string? reason why the ip is denied, nil if it is allowed.
+function _m.check(acl, ip)
+    local family, parsed = ip_util.parse_ip(ip)
+    if _m.in_ranges(acl.deny, family, parsed) then
+        return REASON_DENYLIST
+    end
+    if #acl.allow ~= 0 and not _m.in_ranges(acl.allow, family, parsed) then
+        return REASON_NOT_IN_ALLOWLIST
+    end
+    return nil
+end
+
+---@param ctx AlbCtx
+---@return IpAclPolicy?
+---@return any? error
+function _m.get_config(ctx)
+    return cache.get_config_from_policy(ctx.matched_policy, "ipacl")
+end
+
+return _m
@@ -0,0 +1,204 @@
+-- format:on style:emmy
+-- copy the request to mirror upstream in background, the response of mirror upstream will be ignored.
+-- mirror must not hurt the original request, so it is skipped instead of waiting or buffering when:
+-- the body is larger than MAX_BODY_SIZE, is chunked or has been buffered to a temporary file, or there are too many mirror requests in flight.
+local _m = {}
+local cache = require("config.cache")
+local json = require "cjson"
+
+local MIRROR_TIMEOUT_MS = 5 * 1000
+-- only the body which could be kept in memory is mirrored.
+local MAX_BODY_SIZE = 64 * 1024
+-- mirror requests in flight of each worker, the timers are shared with other features (lua_max_running_timers).
+local MAX_INFLIGHT = 64
+
+-- hop-by-hop headers and the headers which describe the framing of body, they are set by the http client itself.
+local SKIP_HEADERS = {
+    ["connection"] = true,
+    ["keep-alive"] = true,
+    ["proxy-connection"] = true,
+    ["proxy-authenticate"] = true,
+    ["proxy-authorization"] = true,
+    ["te"] = true,
+    ["trailer"] = true,
+    ["transfer-encoding"] = true,
+    ["upgrade"] = true,
+    ["content-length"] = true,
+}
+
+-- methods which do not have body, used when the length of body is unknown (http2 without content-length).
+local BODYLESS_METHODS = {
+    ["GET"] = true,
+    ["HEAD"] = true,
+    ["OPTIONS"] = true,
+    ["DELETE"] = true,
+}
+
+local inflight = 0
+
+---@param ctx AlbCtx
+function _m.after_rule_match_hook(ctx)
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil then
+        return
+    end
+    if not _m.should_mirror(cfg.percent, math.random(100)) then
+        return
+    end
+    if inflight >= MAX_INFLIGHT then
+        ngx.log(ngx.WARN, "mirror: too many mirror requests in flight, skip ", cfg.upstream)
+        return
+    end
+    local method = ngx.req.get_method()
+    local body, ok = _m.read_body(method, ngx.var.http_content_length, ngx.var.http_transfer_encoding, ngx.req.http_version())
+    if not ok then
+        ngx.log(ngx.INFO, "mirror: body could not be mirrored, skip ", cfg.upstream)
+        return
+    end
+    -- require here to avoid circular require, balance require plugin_manager.
+    local balance = require("balancer.balance")
+    local peer = balance.get_peer(cfg.upstream)
+    if peer == nil then
+        ngx.log(ngx.WARN, "mirror: no peer found for ", cfg.upstream)
+        return
+    end
+    local ssl_verify, ssl_server_name = _m.ssl_options(cfg)
+    local req = {
+        url = _m.mirror_url(cfg.backend_protocol, peer, ngx.var.request_uri),
+        method = method,
+        headers = _m.mirror_headers(ngx.req.get_headers()),
+        body = body,
+        ssl_verify = ssl_verify,
+        ssl_server_name = ssl_server_name,
+    }
+    local tok, terr = ngx.timer.at(0, _m.send, req)
+    if not tok then
+        ngx.log(ngx.ERR, "mirror: create timer fail ", terr)
+        return
+    end
+    inflight = inflight + 1
+end
+
+---@param percent number?
+---@param random number 1-100
+---@return boolean
+function _m.should_mirror(percent, random)
+    if percent == nil or percent == json.null then
+        return true
+    end
+    return random <= percent
+end
+
+---@param protocol string
+---@param peer string
+---@param uri string
+---@return string
+function _m.mirror_url(protocol, peer, uri)
+    if protocol ~= "https" then
+        protocol = "http"
+    end
+    return protocol .. "://" .. peer .. uri
+end
+
+--- the request is sent to the pod of mirror service, the sni is the name of mirror service rather than the host of request.
+--- the certificate is verified with lua_ssl_trusted_certificate, which is the system ca.
+---@param cfg MirrorPolicy
+---@return boolean ssl_verify
+---@return string? ssl_server_name
+function _m.ssl_options(cfg)
+    local hostname = cfg.hostname
+    if hostname == "" or hostname == json.null then
+        hostname = nil
+    end
+    return cfg.verify == true, hostname
+end
+
+--- return the body to mirror, ok is false if the body should not be mirrored.
+--- the body is only read when it is small enough, and is never read from the temporary file.
+---@param method string
+---@param content_length string?
+---@param transfer_encoding string?
+---@param http_version number?
+---@return string? body
+---@return boolean ok
+function _m.read_body(method, content_length, transfer_encoding, http_version)
+    if transfer_encoding ~= nil then
+        return nil, false
+    end
+    if content_length == nil then
+        -- http2 request could have body without content-length.
+        if http_version ~= nil and http_version >= 2 and not BODYLESS_METHODS[method] then
+            return nil, false
+        end
+        return nil, true
+    end
+    local len = tonumber(content_length)
+    if len == nil or len > MAX_BODY_SIZE then
+        return nil, false
+    end
+    if len == 0 then
+        return nil, true
+    end
+    ngx.req.read_body()
+    local body = ngx.req.get_body_data()
+    if body == nil then
+        -- buffered to a temporary file
+        return nil, false
+    end
+    return body, true
+end
+
+--- copy the headers except the hop-by-hop ones, and the ones listed in connection header.
+---@param headers table
+---@return table
+function _m.mirror_headers(headers)
+    local skip = {}
+    local conn = headers["connection"]
+    if type(conn) == "table" then
+        conn = table.concat(conn, ",")
+    end
+    if conn ~= nil then
+        for h in string.gmatch(string.lower(conn), "[^,%s]+") do
+            skip[h] = true
+        end
+    end
+    local ret = {}
+    for k, v in pairs(headers) do
+        local lk = string.lower(k)
+        if not SKIP_HEADERS[lk] and not skip[lk] then
+            ret[lk] = v
+        end
+    end
+    return ret
+end
+
+function _m.send(premature, req)
+    local ok, err = pcall(_m.do_send, premature, req)
+    inflight = inflight - 1
+    if not ok then
+        ngx.log(ngx.ERR, "mirror: send request fail ", req.url, " ", err)
+    end
+end
+
+function _m.do_send(premature, req)
+    if premature then
+        return
+    end
+    local httpc = require("resty.http").new()
+    httpc:set_timeout(MIRROR_TIMEOUT_MS)
+    local res, err = httpc:request_uri(req.url, req)
+    if err ~= nil then
+        ngx.log(ngx.WARN, "mirror: send request fail ", req.url, " ", err)
+        return
+    end
+    ngx.log(ngx.INFO, "mirror: ", req.url, " code ", res.status)
+end
+
+---@param ctx AlbCtx
+---@return MirrorPolicy?
+---@return any? error
+function _m.get_config(ctx)
+    return cache.get_config_from_policy(ctx.matched_policy, "mirror")
+end
+
+return _m
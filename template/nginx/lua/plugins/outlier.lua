@@ -0,0 +1,247 @@
+-- format:on style:emmy
+-- passive outlier detection and circuit breaking of upstream.
+-- the result of each try is checked in log phase, a endpoint is ejected after it fails (5xx or connect failure) consecutive_errors times in a row.
+-- the ejected endpoint is skipped by the balancer until the ejection expires. after that it is half open, the result of next request
+-- decides whether it is closed or ejected again for a longer time.
+-- the concurrent requests of upstream are limited via limit conn, request beyond the limit is rejected with 503 immediately.
+-- the state is kept in the shared dict, so it is shared by all workers of a alb instance.
+local _m = {}
+local cache = require("config.cache")
+local eh = require("error")
+local subsys = require("utils.subsystem")
+local limit_conn = require("resty.limit.conn")
+local ngx = ngx
+local ngx_log = ngx.log
+local ipairs = ipairs
+local tonumber = tonumber
+local string_format = string.format
+local string_gmatch = string.gmatch
+
+local DICT = "http_outlier"
+local EJECTED = "ejected"
+local HALF_OPEN = "half_open"
+local CLOSED = "closed"
+-- forget the endpoint which has not been ejected for a long time.
+local STATE_TTL_S = 3600
+-- used by limit conn to estimate the request latency, the real latency will be used in log phase.
+local DEFAULT_CONN_DELAY_S = 0.5
+
+_m.EJECTED = EJECTED
+_m.HALF_OPEN = HALF_OPEN
+_m.CLOSED = CLOSED
+
+local function dict()
+    return ngx.shared[DICT]
+end
+
+---@param group string
+---@param peer string
+---@return string
+function _m.target_key(group, peer)
+    return group .. "/" .. peer
+end
+
+---@param ctx AlbCtx
+function _m.after_rule_match_hook(ctx)
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil or cfg.max_concurrent_requests == 0 then
+        return
+    end
+    local group = ngx.ctx.upstream
+    local lim, lerr = limit_conn.new(DICT, cfg.max_concurrent_requests, 0, DEFAULT_CONN_DELAY_S)
+    if lim == nil then
+        ngx_log(ngx.ERR, "outlier: init limit conn fail ", lerr)
+        return
+    end
+    local key = "c:" .. group
+    local delay, cerr = lim:incoming(key, true)
+    if delay == nil then
+        if cerr == "rejected" then
+            ctx.outlier_overflow = true
+            return eh.exit_with_code(eh.UpstreamOverflow, group, ngx.HTTP_SERVICE_UNAVAILABLE)
+        end
+        ngx_log(ngx.ERR, "outlier: limit conn fail ", cerr)
+        return
+    end
+    if lim:is_committed() then
+        ctx.outlier = { conn = lim, key = key }
+    end
+end
+
+---@param ctx AlbCtx
+function _m.log_hook(ctx)
+    local o = ctx.outlier
+    if o ~= nil then
+        local latency = tonumber(ngx.var.request_time) or DEFAULT_CONN_DELAY_S
+        local _, err = o.conn:leave(o.key, latency)
+        if err ~= nil then
+            ngx_log(ngx.ERR, "outlier: leave conn fail ", err)
+        end
+    end
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil or cfg.consecutive_errors == 0 then
+        return
+    end
+    local group = ngx.ctx.upstream
+    if group == nil then
+        return
+    end
+    local rule = ctx.matched_policy.rule or ""
+    for _, t in ipairs(_m.parse_tries(ctx.var["upstream_addr"], ctx.var["upstream_status"])) do
+        _m.on_result(cfg, rule, group, t.peer, _m.is_failure(t.status))
+    end
+end
+
+--- split the $upstream_addr and $upstream_status into tries. tries are separated by comma, and internal redirects are separated by colon.
+---@param addrs string|nil
+---@param statuses string|nil
+---@return {peer:string, status:number|nil}[]
+function _m.parse_tries(addrs, statuses)
+    local ret = {}
+    if addrs == nil or statuses == nil or addrs == "" then
+        return ret
+    end
+    local function split(s)
+        local list = {}
+        for item in string_gmatch(s:gsub(" : ", ", "), "[^,]+") do
+            table.insert(list, (item:gsub("^%s+", ""):gsub("%s+$", "")))
+        end
+        return list
+    end
+    local status_list = split(statuses)
+    for i, peer in ipairs(split(addrs)) do
+        table.insert(ret, { peer = peer, status = tonumber(status_list[i]) })
+    end
+    return ret
+end
+
+--- connect failure is reported as 502 or 504 by nginx, so it is a 5xx too.
+---@param status number|nil
+---@return boolean
+function _m.is_failure(status)
+    return status ~= nil and status >= 500
+end
+
+--- the ejection time grows with the times the endpoint has been ejected, and is capped by max_ejection_time_ms.
+---@param cfg OutlierPolicy
+---@param times number
+---@return number
+function _m.ejection_time_ms(cfg, times)
+    return math.min(cfg.base_ejection_time_ms * times, cfg.max_ejection_time_ms)
+end
+
+---@param group string
+---@param peer string
+---@return boolean
+function _m.is_ejected(group, peer)
+    return dict():get("e:" .. _m.target_key(group, peer)) ~= nil
+end
+
+---@param rule string
+---@param peer string
+---@param state string
+local function report(rule, peer, state)
+    local metrics = require("metrics")
+    metrics.inc_outlier_transition(rule, peer, state)
+end
+
+---@param cfg OutlierPolicy
+---@param rule string
+---@param group string
+---@param peer string
+---@param failed boolean
+function _m.on_result(cfg, rule, group, peer, failed)
+    local d = dict()
+    local key = _m.target_key(group, peer)
+    if d:get("e:" .. key) ~= nil then
+        -- the request was sent before the endpoint is ejected, or all endpoints are ejected.
+        return
+    end
+    local state = d:get("s:" .. key)
+    if state == EJECTED then
+        -- the ejection is expired, this request is the trial.
+        state = HALF_OPEN
+        d:set("s:" .. key, HALF_OPEN, STATE_TTL_S)
+        report(rule, peer, HALF_OPEN)
+    end
+    if failed then
+        if state == HALF_OPEN then
+            _m.eject(cfg, rule, group, peer)
+            return
+        end
+        local count = d:incr("f:" .. key, 1, 0, STATE_TTL_S)
+        if count ~= nil and count >= cfg.consecutive_errors then
+            _m.eject(cfg, rule, group, peer)
+        end
+        return
+    end
+    if d:get("f:" .. key) ~= nil then
+        d:delete("f:" .. key)
+    end
+    if state == HALF_OPEN then
+        d:delete("s:" .. key)
+        d:delete("n:" .. key)
+        ngx_log(ngx.WARN, string_format("outlier: endpoint %s is recovered", key))
+        report(rule, peer, CLOSED)
+    end
+end
+
+---@param cfg OutlierPolicy
+---@param rule string
+---@param group string
+---@param peer string
+function _m.eject(cfg, rule, group, peer)
+    local d = dict()
+    local key = _m.target_key(group, peer)
+    local times = (d:get("n:" .. key) or 0) + 1
+    local ms = _m.ejection_time_ms(cfg, times)
+    -- concurrent failures of the same endpoint should only eject it once.
+    local ok = d:add("e:" .. key, true, ms / 1000)
+    if not ok then
+        return
+    end
+    local ttl = ms / 1000 + STATE_TTL_S
+    d:set("n:" .. key, times, ttl)
+    d:set("s:" .. key, EJECTED, ttl)
+    d:delete("f:" .. key)
+    ngx_log(ngx.WARN, string_format("outlier: endpoint %s is ejected for %sms, times %s", key, ms, times))
+    report(rule, peer, EJECTED)
+end
+
+--- pick another peer if the picked one is ejected. if all peers are ejected, use the picked one, since a ejected endpoint is better than nothing.
+---@param ctx AlbCtx
+---@param balancer table
+---@param peer string
+---@return string
+function _m.pick(ctx, balancer, peer)
+    if not subsys.is_http_subsystem() or ctx.matched_policy == nil then
+        return peer
+    end
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil or cfg.consecutive_errors == 0 then
+        return peer
+    end
+    local group = ngx.ctx.upstream
+    local backend = balancer.backend
+    local max = backend and backend.backends and #backend.backends or 1
+    local p = peer
+    for _ = 1, max do
+        if not _m.is_ejected(group, p) then
+            return p
+        end
+        p = balancer:balance()
+        if p == nil then
+            break
+        end
+    end
+    return peer
+end
+
+---@param ctx AlbCtx
+---@return OutlierPolicy?
+---@return any? error
+function _m.get_config(ctx)
+    return cache.get_config_from_policy(ctx.matched_policy, "outlier")
+end
+
+return _m
@@ -0,0 +1,45 @@
+-- format:on style:emmy
+-- reject the request whose body is larger than client_max_body_size.
+-- policy with proxy config is exec to a named location which has client_max_body_size configured, but nginx only
+-- checks the content-length header when finding the location, not in the internal redirect to a named location.
+-- the chunked body is still checked by nginx when reading it.
+local _m = {}
+local cache = require("config.cache")
+local eh = require("error")
+local ngx = ngx
+local tonumber = tonumber
+
+---@param ctx AlbCtx
+function _m.after_rule_match_hook(ctx)
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil then
+        return
+    end
+    local len = ctx.var["content_length"]
+    if _m.too_large(cfg.client_max_body_size, len) then
+        return eh.exit_with_code(eh.BodyTooLarge, "content length " .. tostring(len), ngx.HTTP_REQUEST_ENTITY_TOO_LARGE)
+    end
+end
+
+---@param limit number?
+---@param content_length string?
+---@return boolean
+function _m.too_large(limit, content_length)
+    if limit == nil or limit == 0 then
+        return false
+    end
+    local len = tonumber(content_length)
+    if len == nil then
+        return false
+    end
+    return len > limit
+end
+
+---@param ctx AlbCtx
+---@return ProxyPolicy?
+---@return any? error
+function _m.get_config(ctx)
+    return cache.get_config_from_policy(ctx.matched_policy, "proxy")
+end
+
+return _m
@@ -0,0 +1,203 @@
+-- format:on style:emmy
+-- limit the request rate and concurrent connections of client.
+-- local mode: requests are limited via leaky bucket in the shared dict of each alb instance.
+-- global mode: when redis is configured, requests are counted in a fixed window in redis, shared by all alb instances.
+-- connections are always limited in local mode.
+-- request will be allowed if the limiter is broken, e.g. redis is unavailable.
+local _m = {}
+local cache = require("config.cache")
+local eh = require("error")
+local op = require("match_engine.operation")
+local limit_req = require("resty.limit.req")
+local limit_conn = require("resty.limit.conn")
+
+local DICT = "http_ratelimit"
+local REDIS_KEY_PREFIX = "alb:ratelimit:"
+local REDIS_POOL_SIZE = 100
+local REDIS_IDLE_TIMEOUT_MS = 10 * 1000
+-- used by limit conn to estimate the request latency, the real latency will be used in log phase.
+local DEFAULT_CONN_DELAY_S = 0.5
+-- the password of redis is written into files in this dir (next to policy.new), only the name of file is in the policy.
+local PASSWORD_DIR = (string.match(os.getenv("NEW_POLICY_PATH") or "", "^(.*)/") or ".") .. "/redis_password/"
+-- the name of file changes with the secret, so the password is cached forever.
+local passwords = {}
+
+---@param file string
+---@return string? password
+---@return string? error
+function _m.get_password(file)
+    if passwords[file] ~= nil then
+        return passwords[file], nil
+    end
+    local f, err = io.open(PASSWORD_DIR .. file, "r")
+    if f == nil then
+        return nil, err
+    end
+    local password = f:read("*a")
+    f:close()
+    passwords[file] = password
+    return password, nil
+end
+
+---@param ctx AlbCtx
+function _m.after_rule_match_hook(ctx)
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil then
+        return
+    end
+    local key = _m.get_key(cfg)
+    if key == nil then
+        -- could not identify the client, do not limit it.
+        return
+    end
+    for _, r in ipairs(cfg.requests or {}) do
+        local allow, lerr
+        if cfg.redis ~= nil then
+            allow, lerr = _m.incoming_global(cfg.redis, r, key)
+        else
+            allow, lerr = _m.incoming_local(r, key)
+        end
+        if lerr ~= nil then
+            ngx.log(ngx.ERR, "ratelimit: limit req fail ", lerr)
+        elseif not allow then
+            return eh.exit_with_code(eh.RateLimited, key, cfg.reject_code)
+        end
+    end
+    if cfg.connections ~= nil and cfg.connections > 0 then
+        local lim, lerr = limit_conn.new(DICT, cfg.connections, 0, DEFAULT_CONN_DELAY_S)
+        if lim == nil then
+            ngx.log(ngx.ERR, "ratelimit: init limit conn fail ", lerr)
+            return
+        end
+        local delay, cerr = lim:incoming(key, true)
+        if delay == nil then
+            if cerr == "rejected" then
+                return eh.exit_with_code(eh.RateLimited, key, cfg.reject_code)
+            end
+            ngx.log(ngx.ERR, "ratelimit: limit conn fail ", cerr)
+            return
+        end
+        if lim:is_committed() then
+            ctx.ratelimit = { conn = lim, key = key }
+        end
+    end
+end
+
+---@param ctx AlbCtx
+function _m.log_hook(ctx)
+    local r = ctx.ratelimit
+    if r == nil then
+        return
+    end
+    local latency = tonumber(ngx.var.request_time) or DEFAULT_CONN_DELAY_S
+    local _, err = r.conn:leave(r.key, latency)
+    if err ~= nil then
+        ngx.log(ngx.ERR, "ratelimit: leave conn fail ", err)
+    end
+end
+
+---@param cfg RateLimitPolicy
+---@return string?
+function _m.get_key(cfg)
+    local val = op.get_matcher_value(cfg.key.type, cfg.key.name)
+    if val == nil or val == "" then
+        return nil
+    end
+    return cfg.zone .. ":" .. tostring(val)
+end
+
+---@param r RequestLimit
+---@param key string
+---@return boolean allow
+---@return string? error
+function _m.incoming_local(r, key)
+    local lim, err = limit_req.new(DICT, r.limit / r.window, r.burst)
+    if lim == nil then
+        return true, err
+    end
+    local delay, ierr = lim:incoming(key .. ":" .. r.window, true)
+    if delay == nil then
+        if ierr == "rejected" then
+            return false, nil
+        end
+        return true, ierr
+    end
+    -- requests in burst are not delayed, the same as nodelay of ingress-nginx
+    return true, nil
+end
+
+--- counters are stored in a fixed window.
+---@param key string
+---@param window number
+---@param now number
+---@return string
+function _m.window_key(key, window, now)
+    return REDIS_KEY_PREFIX .. key .. ":" .. window .. ":" .. math.floor(now / window)
+end
+
+---@param count number
+---@param r RequestLimit
+---@return boolean
+function _m.allow_in_window(count, r)
+    return count <= r.limit
+end
+
+---@param redis_cfg RedisPolicy
+---@param r RequestLimit
+---@param key string
+---@return boolean allow
+---@return string? error
+function _m.incoming_global(redis_cfg, r, key)
+    local redis = require("resty.redis")
+    local red = redis:new()
+    red:set_timeout(redis_cfg.timeout_ms)
+    -- connections authenticated with different password should not be shared.
+    local pool = redis_cfg.host .. ":" .. redis_cfg.port .. ":" .. (redis_cfg.password_file or "") .. ":" .. (redis_cfg.db or 0)
+    local ok, err = red:connect(redis_cfg.host, redis_cfg.port, { pool = pool })
+    if not ok then
+        return true, "connect redis " .. tostring(err)
+    end
+    -- reused connection has been authenticated
+    local count, cerr = red:get_reused_times()
+    if count == 0 then
+        if redis_cfg.password_file ~= nil and redis_cfg.password_file ~= "" then
+            local password, ferr = _m.get_password(redis_cfg.password_file)
+            if password == nil then
+                red:close()
+                return true, "read redis password " .. tostring(ferr)
+            end
+            local _, aerr = red:auth(password)
+            if aerr ~= nil then
+                red:close()
+                return true, "auth redis " .. tostring(aerr)
+            end
+        end
+    elseif cerr ~= nil then
+        return true, cerr
+    end
+    local wkey = _m.window_key(key, r.window, ngx.time())
+    red:init_pipeline()
+    red:select(redis_cfg.db or 0)
+    red:incr(wkey)
+    red:expire(wkey, r.window * 2)
+    local res, perr = red:commit_pipeline()
+    if res == nil then
+        red:close()
+        return true, "incr redis " .. tostring(perr)
+    end
+    red:set_keepalive(REDIS_IDLE_TIMEOUT_MS, REDIS_POOL_SIZE)
+    local current = tonumber(res[2])
+    if current == nil then
+        return true, "invalid redis response " .. tostring(res[2])
+    end
+    return _m.allow_in_window(current, r), nil
+end
+
+---@param ctx AlbCtx
+---@return RateLimitPolicy?
+---@return any? error
+function _m.get_config(ctx)
+    return cache.get_config_from_policy(ctx.matched_policy, "ratelimit")
+end
+
+return _m
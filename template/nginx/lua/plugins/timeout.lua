@@ -3,6 +3,7 @@ local cache = require("config.cache")
 local json = require "cjson"
 local eh = require("error")
 local ngx_balancer = require "ngx.balancer"
+local subsys = require "utils.subsystem"
 
 --- milliseconds to seconds,if ms is nil return nil
 --- @param ms number|nil
@@ -17,6 +18,32 @@ local function ms2sec(ms)
     return ms / 1000
 end
 
+--- the timeouts of this try. request_timeout_ms is the deadline of the whole request (including retries),
+--- so the timeouts of each try are limited by the time left.
+---@param cfg TimeoutCr
+---@param elapsed number|nil seconds since the request started, nil for stream which has no deadline
+---@return number|nil connect
+---@return number|nil send
+---@return number|nil read
+function _m.get_timeouts(cfg, elapsed)
+    local connect = ms2sec(cfg.proxy_connect_timeout_ms)
+    local send = ms2sec(cfg.proxy_send_timeout_ms)
+    local read = ms2sec(cfg.proxy_read_timeout_ms)
+    local request = ms2sec(cfg.request_timeout_ms)
+    if request == nil or elapsed == nil then
+        return connect, send, read
+    end
+    -- the deadline is already exceeded (by the previous tries), 1ms is the min timeout nginx accepts, let this try fail with 504 immediately.
+    local left = math.max(request - elapsed, 0.001)
+    local function limit(t)
+        if t == nil or t > left then
+            return left
+        end
+        return t
+    end
+    return limit(connect), limit(send), limit(read)
+end
+
 ---@param ctx AlbCtx
 function _m.balancer_hook(ctx)
     local timeout_cfg, err = _m.get_config(ctx)
@@ -24,9 +51,11 @@ function _m.balancer_hook(ctx)
         return
     end
 
-    local connect = ms2sec(timeout_cfg.proxy_connect_timeout_ms)
-    local send = ms2sec(timeout_cfg.proxy_send_timeout_ms)
-    local read = ms2sec(timeout_cfg.proxy_read_timeout_ms)
+    local elapsed = nil
+    if subsys.is_http_subsystem() then
+        elapsed = ngx.now() - ngx.req.start_time()
+    end
+    local connect, send, read = _m.get_timeouts(timeout_cfg, elapsed)
     -- ngx.log(ngx.ERR, "[debug] set timeout ", connect, " ", send, " ", read)
     local _, err = ngx_balancer.set_timeouts(connect, send, read)
     if err ~= nil then
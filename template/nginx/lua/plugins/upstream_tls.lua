@@ -0,0 +1,30 @@
+-- format:on style:emmy
+-- set the sni and the name to verify of upstream tls.
+-- proxy_ssl_verify could not be set via variable, policy with upstream_tls is exec to a named location
+-- which has proxy_ssl_verify configured, this plugin only runs in that location.
+local _m = {}
+local cache = require("config.cache")
+
+---@param ctx AlbCtx
+function _m.after_rule_match_hook(ctx)
+    local cfg, err = _m.get_config(ctx)
+    if err ~= nil or cfg == nil then
+        return
+    end
+    if ngx.var.location_mode ~= "sub" then
+        return
+    end
+    if cfg.hostname == nil or cfg.hostname == "" then
+        return
+    end
+    ngx.var.upstream_ssl_name = cfg.hostname
+end
+
+---@param ctx AlbCtx
+---@return UpstreamTLSPolicy?
+---@return any? error
+function _m.get_config(ctx)
+    return cache.get_config_from_policy(ctx.matched_policy, "upstream_tls")
+end
+
+return _m
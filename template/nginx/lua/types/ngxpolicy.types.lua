@@ -2,6 +2,7 @@
 --- @class NgxPolicy
 --- @field backend_group BackendGroup[]
 --- @field certificate_map table<string, Certificate>
+--- @field certificate_expiry CertificateExpiry[]|nil
 --- @field config table<string, RefBox>
 --- @field http HttpPolicy
 --- @field stream StreamPolicy
@@ -14,6 +15,7 @@
 --- @class StreamPolicy
 --- @field tcp table<number, Policy[]>
 --- @field udp table<number, Policy[]>
+--- @field tls table<number, table<string, Policy>>
 
 
 --- @class BackendGroup
@@ -22,31 +24,78 @@
 --- @field name string
 --- @field session_affinity_attribute string
 --- @field session_affinity_policy string
+--- @field session_affinity_cookie SessionAffinityCookie|nil
+--- @field health_check HealthCheckPolicy|nil
+
+--- @class HealthCheckPolicy
+--- @field type string http, https or tcp
+--- @field path string|nil
+--- @field host string|nil
+--- @field port number|nil
+--- @field interval_ms number
+--- @field timeout_ms number
+--- @field healthy_threshold number
+--- @field unhealthy_threshold number
+--- @field expected_status number[]|nil
+--- @field verify boolean|nil
+
+--- @class SessionAffinityCookie
+--- @field name string|nil
+--- @field path string|nil
+--- @field domain string|nil
+--- @field max_age number|nil
+--- @field refresh boolean|nil
+--- @field samesite string|nil
+--- @field secure boolean|nil
+--- @field httponly boolean|nil
 
 
 --- @class Certificate
 --- @field cert string
 --- @field key string
+--- @field client_ca CaCertificate?
+
+
+--- @class CertificateExpiry
+--- @field domain string
+--- @field secret string
+--- @field not_after number unix timestamp in seconds
 
 
 --- @class RefBox
 --- @field note string?
 --- @field type string
 --- @field auth AuthPolicy?
+--- @field client_tls ClientTLSPolicy?
+--- @field errorpage ErrorPagePolicy?
+--- @field ipacl IpAclPolicy?
+--- @field mirror MirrorPolicy?
 --- @field otel OtelConf?
+--- @field outlier OutlierPolicy?
+--- @field proxy ProxyPolicy?
+--- @field ratelimit RateLimitPolicy?
 --- @field redirect RedirectCr?
 --- @field rewrite_request RewriteRequestConfig?
 --- @field rewrite_response RewriteResponseConfig?
 --- @field timeout TimeoutCr?
+--- @field upstream_tls UpstreamTLSPolicy?
 
 
 --- @class PolicyExt
 --- @field auth AuthPolicy?
+--- @field client_tls ClientTLSPolicy?
+--- @field errorpage ErrorPagePolicy?
+--- @field ipacl IpAclPolicy?
+--- @field mirror MirrorPolicy?
 --- @field otel OtelConf?
+--- @field outlier OutlierPolicy?
+--- @field proxy ProxyPolicy?
+--- @field ratelimit RateLimitPolicy?
 --- @field redirect RedirectCr?
 --- @field rewrite_request RewriteRequestConfig?
 --- @field rewrite_response RewriteResponseConfig?
 --- @field timeout TimeoutCr?
+--- @field upstream_tls UpstreamTLSPolicy?
 
 
 --- @class Backend
@@ -84,6 +133,40 @@
 --- @field forward_auth ForwardAuthPolicy?
 
 
+--- @class CaCertificate
+--- @field cert string
+--- @field depth number
+--- @field id string
+
+
+--- @class ClientTLSPolicy
+--- @field ca_id string?
+--- @field pass_certificate_to_upstream boolean
+--- @field verify_client string
+
+
+--- @class ErrorPagePolicy
+--- @field pages ErrorPagePolicyPage[]
+
+
+--- @class ErrorPagePolicyPage
+--- @field codes number[]
+--- @field content_type string?
+--- @field body string?
+--- @field upstream string?
+
+
+--- @class IpAclPolicy
+--- @field allow IpRange[]?
+--- @field deny IpRange[]?
+--- @field trusted_proxies IpRange[]?
+
+
+--- @class IpRange
+--- @field end string
+--- @field start string
+
+
 --- @class LegacyExtInPolicy
 --- @field cors_allow_headers string
 --- @field cors_allow_origin string
@@ -96,6 +179,14 @@
 --- @field vhost string
 
 
+--- @class MirrorPolicy
+--- @field backend_protocol string
+--- @field hostname string
+--- @field percent number?
+--- @field upstream string
+--- @field verify boolean
+
+
 --- @class OtelConf
 --- @field exporter Exporter?
 --- @field flags Flags?
@@ -103,14 +194,47 @@
 --- @field sampler Sampler?
 
 
+--- @class OutlierPolicy
+--- @field base_ejection_time_ms number
+--- @field consecutive_errors number
+--- @field max_concurrent_requests number
+--- @field max_ejection_time_ms number
+
+
 --- @class PolicyExtCfg
 --- @field refs table<string, string>
 --- @field auth AuthPolicy?
+--- @field client_tls ClientTLSPolicy?
+--- @field errorpage ErrorPagePolicy?
+--- @field ipacl IpAclPolicy?
+--- @field mirror MirrorPolicy?
 --- @field otel OtelConf?
+--- @field outlier OutlierPolicy?
+--- @field proxy ProxyPolicy?
+--- @field ratelimit RateLimitPolicy?
 --- @field redirect RedirectCr?
 --- @field rewrite_request RewriteRequestConfig?
 --- @field rewrite_response RewriteResponseConfig?
 --- @field timeout TimeoutCr?
+--- @field upstream_tls UpstreamTLSPolicy?
+
+
+--- @class ProxyPolicy
+--- @field client_max_body_size number
+
+
+--- @class RateLimitKey
+--- @field name string?
+--- @field type string
+
+
+--- @class RateLimitPolicy
+--- @field connections number?
+--- @field key RateLimitKey
+--- @field redis RedisPolicy?
+--- @field reject_code number
+--- @field requests RequestLimit[]?
+--- @field zone string
 
 
 --- @class RedirectCr
@@ -123,6 +247,20 @@
 --- @field url string
 
 
+--- @class RedisPolicy
+--- @field db number
+--- @field host string
+--- @field password_file string?
+--- @field port number
+--- @field timeout_ms number
+
+
+--- @class RequestLimit
+--- @field burst number
+--- @field limit number
+--- @field window number
+
+
 --- @class RewriteRequestConfig
 --- @field headers table<string, string>
 --- @field headers_add table<string, string[]>
@@ -147,6 +285,11 @@
 --- @field proxy_connect_timeout_ms number?
 --- @field proxy_read_timeout_ms number?
 --- @field proxy_send_timeout_ms number?
+--- @field request_timeout_ms number?
+
+
+--- @class UpstreamTLSPolicy
+--- @field hostname string
 
 
 --- @class Cors
@@ -69,5 +69,31 @@ do
 end
 _M.parse_ipv6 = parse_ipv6
 
+-- parse ip into a comparable form.
+-- return the family (4 or 6) and an array of uint32, or nil if it is not a valid ip.
+function _M.parse_ip(ip)
+    local v4 = parse_ipv4(ip)
+    if v4 then
+        return 4, { v4 }
+    end
+    local v6 = parse_ipv6(ip)
+    if v6 then
+        return 6, v6
+    end
+    return nil, nil
+end
+
+-- compare two ip parsed by parse_ip in the same family. return -1, 0 or 1.
+function _M.compare(a, b)
+    for i = 1, #a do
+        if a[i] < b[i] then
+            return -1
+        elseif a[i] > b[i] then
+            return 1
+        end
+    end
+    return 0
+end
+
 
 return _M
\ No newline at end of file
@@ -0,0 +1,12 @@
+-- format:on style:emmy
+-- the proxy protocol/realIP of frontend is handled by the realip module of nginx at server level,
+-- $remote_addr is the client ip resolved from the trusted proxies then, the headers from client should not be trusted.
+local _m = {}
+
+---@param ctx AlbCtx
+---@return boolean
+function _m.enabled(ctx)
+    return ctx.var["realip_enabled"] == "on"
+end
+
+return _m
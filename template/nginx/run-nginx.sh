@@ -15,4 +15,39 @@ if [ ! -f $cfg_path ]; then
   cat  $cfg_path
 fi
 
+# roll back to the last known good config (saved by alb after nginx -t passed) if the current one is invalid.
+if ! nginx -t -c $cfg_path -e /dev/stderr -p /alb/nginx/run; then
+  last_good_dir=/etc/alb2/nginx/last_good
+  last_good=$(ls $last_good_dir 2>/dev/null | sort -n | tail -n 1)
+  if [ -n "$last_good" ]; then
+    echo "nginx.conf is invalid, roll back to $last_good_dir/$last_good"
+    cp $last_good_dir/$last_good/nginx.conf $cfg_path
+    for f in $last_good_dir/$last_good/$(basename $NEW_POLICY_PATH)*; do
+      [ -f "$f" ] && cp "$f" $(dirname $NEW_POLICY_PATH)/
+    done
+  fi
+fi
+
+# run nginx -t for alb, see controller/nginx_check.go.
+check_dir=/etc/alb2/nginx/check
+mkdir -p $check_dir
+rm -f $check_dir/request $check_dir/result
+touch $check_dir/ready
+(
+  last=""
+  while true; do
+    sleep 1
+    [ -f $check_dir/request ] || continue
+    req=$(cat $check_dir/request)
+    [ "$req" = "$last" ] && continue
+    last="$req"
+    id=${req%% *}
+    path=${req#* }
+    out=$(nginx -t -c "$path" -e /dev/stderr -p /alb/nginx/run 2>&1)
+    code=$?
+    printf "%s\n%s\n%s\n" "$id" "$code" "$out" >$check_dir/result.tmp
+    mv $check_dir/result.tmp $check_dir/result
+  done
+) &
+
 nginx -g "daemon off;" -c /etc/alb2/nginx/nginx.conf -e /dev/stderr -p /alb/nginx/run
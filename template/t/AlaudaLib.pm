@@ -353,6 +353,7 @@ rootExtra: |
     env TEST_BASE;
     env ALB_LUA_UNIT_TEST_CASE;
     env ALB_LUA_TEST_CFG;
+    env ALB_LUA_TEST_REDIS;
 streamExtra: |
     lua_package_path "$lua_path";
     $init_full
@@ -47,6 +47,15 @@ function _M.test()
                         config = {
                             timeout = { proxy_read_timeout_ms = 300 }
                         }
+                    },
+                    {
+                        plugins = { "errorpage" },
+                        rule = "2",
+                        internal_dsl = { { "STARTS_WITH", "URL", "/t2" } },
+                        upstream = "u1",
+                        config = {
+                            errorpage = { pages = { { codes = { 404 }, body = "custom 404", content_type = "text/plain" } } }
+                        }
                     }
                 }
             }
@@ -86,6 +95,15 @@ function _M.test()
         h.assert_eq(res.status, 504)
         h.assert_eq(res.body, "")
     end
+
+    do
+        u.logs "error from backend with custom page"
+        local res, err = u.curl("http://127.0.0.1/t2/404")
+        u.logs(res, err)
+        h.assert_eq(res.status, 404)
+        h.assert_eq(res.headers["Content-Type"], "text/plain")
+        h.assert_eq(res.body, "custom 404")
+    end
 end
 
 return _M
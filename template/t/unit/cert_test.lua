@@ -13,7 +13,12 @@ function _M.test()
         ["b.com/8443"] = "full-host-withport-8443",
         ["10443"] = "10443-default",
         ["*.e.com"] = "wildcard-host",
-        ["*.f.com/443"] = "wildcard-host-443"
+        ["*.f.com/443"] = "wildcard-host-443",
+        -- copy of cert with the client ca of port 443
+        ["g.com"] = "full-host",
+        ["g.com/443"] = "full-host-withport-443",
+        ["*.h.com"] = "wildcard-host",
+        ["*.h.com/443"] = "wildcard-host-443",
     }
     ct.get_cert = function (domain)
         local cert = cert_map[domain]
@@ -38,6 +43,10 @@ function _M.test()
         { "c.e.com", "8443",  "wildcard-host" },
         { "a.f.com", "8443",  nil },
         { "a.f.com", "443",   "wildcard-host-443" },
+        { "g.com",   "443",   "full-host-withport-443" },
+        { "g.com",   "8443",  "full-host" },
+        { "a.h.com", "443",   "wildcard-host-443" },
+        { "a.h.com", "8443",  "wildcard-host" },
     }
     for k, c in pairs(cases) do
         h.P(F "case {k}")
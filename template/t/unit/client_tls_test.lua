@@ -0,0 +1,25 @@
+local _M = {}
+
+local h = require("test-helper");
+local client_tls = require("plugins.client_tls")
+
+function _M.test()
+    h.assert_eq(client_tls.check("on", "SUCCESS"), nil)
+    h.assert_eq(client_tls.check("on", "NONE"), "no required ssl certificate was sent")
+    h.assert_eq(client_tls.check("on", "FAILED:certificate has expired"), "invalid certificate certificate has expired")
+    h.assert_eq(client_tls.check("optional", "NONE"), nil)
+    h.assert_eq(client_tls.check("optional", "SUCCESS"), nil)
+    h.assert_eq(client_tls.check("optional", "FAILED:unable to get local issuer certificate"), "invalid certificate unable to get local issuer certificate")
+    h.assert_eq(client_tls.check("optional_no_ca", "NONE"), nil)
+    h.assert_eq(client_tls.check("optional_no_ca", "FAILED:self signed certificate"), nil)
+
+    local mismatch = "the client certificate is not verified by the ca of this host"
+    h.assert_eq(client_tls.check_ca("ns/ca#ca.crt/1", "ns/ca#ca.crt/1"), nil)
+    h.assert_eq(client_tls.check_ca("ns/ca#ca.crt/1", "ns/other#ca.crt/1"), mismatch)
+    h.assert_eq(client_tls.check_ca("ns/ca#ca.crt/1", nil), mismatch)
+    -- invalid config has no ca id, nothing could match it.
+    h.assert_eq(client_tls.check_ca("", ""), mismatch)
+    h.assert_eq(client_tls.check_ca(nil, nil), mismatch)
+end
+
+return _M
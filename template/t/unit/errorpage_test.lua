@@ -0,0 +1,32 @@
+local _M = {}
+
+local h = require("test-helper");
+local errorpage = require("plugins.errorpage")
+
+function _M.test()
+    ---@type ErrorPagePolicy
+    local cfg = {
+        pages = {
+            { codes = { 404, 503 }, body = "not found", content_type = "text/plain" },
+            { codes = { 503, 502 }, upstream = "errorpage-default-err-80" },
+        }
+    }
+    h.assert_eq(errorpage.find_page(cfg, 404).body, "not found")
+    -- the first page wins
+    h.assert_eq(errorpage.find_page(cfg, 503).body, "not found")
+    h.assert_eq(errorpage.find_page(cfg, 502).upstream, "errorpage-default-err-80")
+    h.assert_eq(errorpage.find_page(cfg, 500), nil)
+    h.assert_eq(errorpage.find_page({}, 500), nil)
+    -- 503 is replaced by the page of configmap.
+    h.assert_table_equals(errorpage.service_pages(cfg), { { upstream = "errorpage-default-err-80", code = 502 } })
+    h.assert_table_equals(errorpage.service_pages({}), {})
+
+    h.assert_eq(errorpage.format(nil), "text/html")
+    h.assert_eq(errorpage.format(""), "text/html")
+    h.assert_eq(errorpage.format("*/*"), "text/html")
+    h.assert_eq(errorpage.format("application/json"), "application/json")
+    h.assert_eq(errorpage.format("application/json;q=0.9, text/html"), "application/json")
+    h.assert_eq(errorpage.format(" text/plain , */*"), "text/plain")
+end
+
+return _M
@@ -0,0 +1,40 @@
+local _M = {}
+
+local h = require("test-helper");
+local hc = require("balancer.healthcheck")
+
+function _M.test()
+    h.assert_eq(hc.parse_status_line("HTTP/1.1 200 OK"), 200)
+    h.assert_eq(hc.parse_status_line("HTTP/1.0 503 Service Unavailable"), 503)
+    h.assert_eq(hc.parse_status_line("HTTP/2 404"), 404)
+    h.assert_eq(hc.parse_status_line("xx"), nil)
+
+    h.assert_eq(hc.is_expected_status(nil, 302), true)
+    h.assert_eq(hc.is_expected_status({}, 404), false)
+    h.assert_eq(hc.is_expected_status({ 200, 404 }, 404), true)
+    h.assert_eq(hc.is_expected_status({ 200 }, 204), false)
+
+    h.assert_eq(hc.ssl_server_name("a.com"), "a.com")
+    h.assert_eq(hc.ssl_server_name("192.168.0.1"), nil)
+    h.assert_eq(hc.ssl_server_name("fd00::1"), nil)
+
+    local cfg = { healthy_threshold = 2, unhealthy_threshold = 3 }
+    local s = { status = hc.HEALTHY, successes = 0, failures = 0 }
+    s = hc.next_state(s, false, cfg)
+    s = hc.next_state(s, false, cfg)
+    h.assert_eq(s.status, hc.HEALTHY)
+    s = hc.next_state(s, false, cfg)
+    h.assert_eq(s.status, hc.UNHEALTHY)
+    h.assert_eq(s.failures, 3)
+    s = hc.next_state(s, true, cfg)
+    h.assert_eq(s.status, hc.UNHEALTHY)
+    -- a failure resets the successes
+    s = hc.next_state(s, false, cfg)
+    s = hc.next_state(s, true, cfg)
+    h.assert_eq(s.status, hc.UNHEALTHY)
+    s = hc.next_state(s, true, cfg)
+    h.assert_eq(s.status, hc.HEALTHY)
+    h.assert_eq(s.failures, 0)
+end
+
+return _M
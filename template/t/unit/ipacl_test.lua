@@ -0,0 +1,35 @@
+local _M = {}
+
+local h = require("test-helper");
+local ipacl = require("plugins.ipacl")
+
+function _M.test()
+    local acl = ipacl.parse({
+        allow = { { start = "10.0.0.0", ["end"] = "10.255.255.255" }, { start = "fd00::", ["end"] = "fdff:ffff:ffff:ffff:ffff:ffff:ffff:ffff" } },
+        deny = { { start = "10.1.0.0", ["end"] = "10.1.255.255" } },
+        trusted_proxies = { { start = "192.168.0.0", ["end"] = "192.168.0.255" } },
+    })
+    h.assert_eq(ipacl.check(acl, "10.0.0.1"), nil)
+    h.assert_eq(ipacl.check(acl, "fd00::1"), nil)
+    h.assert_eq(ipacl.check(acl, "10.1.2.3"), "denylist")
+    h.assert_eq(ipacl.check(acl, "11.0.0.1"), "not_in_allowlist")
+    h.assert_eq(ipacl.check(acl, "fe00::1"), "not_in_allowlist")
+    h.assert_eq(ipacl.check(acl, "unix:"), "not_in_allowlist")
+
+    -- only deny list
+    local deny_only = ipacl.parse({ deny = { { start = "1.1.1.1", ["end"] = "1.1.1.1" } } })
+    h.assert_eq(ipacl.check(deny_only, "1.1.1.1"), "denylist")
+    h.assert_eq(ipacl.check(deny_only, "1.1.1.2"), nil)
+
+    -- xff is ignored if the peer is not trusted
+    h.assert_eq(ipacl.get_client_ip(acl, "", "1.1.1.1", "10.0.0.1"), "1.1.1.1")
+    h.assert_eq(ipacl.get_client_ip(deny_only, "", "192.168.0.1", "10.0.0.1"), "192.168.0.1")
+    -- the first untrusted addr from right to left
+    h.assert_eq(ipacl.get_client_ip(acl, "", "192.168.0.1", "6.6.6.6, 10.0.0.1, 192.168.0.2"), "10.0.0.1")
+    h.assert_eq(ipacl.get_client_ip(acl, "", "192.168.0.1", "192.168.0.3,192.168.0.2"), "192.168.0.3")
+    -- addr of proxy protocol is preferred
+    h.assert_eq(ipacl.get_client_ip(acl, "2.2.2.2", "192.168.0.1", "10.0.0.1"), "2.2.2.2")
+    h.assert_eq(ipacl.get_client_ip(acl, "192.168.0.9", "3.3.3.3", "10.0.0.1"), "10.0.0.1")
+end
+
+return _M
@@ -0,0 +1,55 @@
+local _M = {}
+
+local h = require("test-helper");
+local mirror = require("plugins.mirror")
+
+function _M.test()
+    h.assert_true(mirror.should_mirror(nil, 100))
+    h.assert_true(mirror.should_mirror(10, 10))
+    h.assert_true(not mirror.should_mirror(10, 11))
+    h.assert_true(not mirror.should_mirror(0, 1))
+    h.assert_eq(mirror.mirror_url("http", "1.1.1.1:80", "/a?b=c"), "http://1.1.1.1:80/a?b=c")
+    h.assert_eq(mirror.mirror_url("https", "1.1.1.1:443", "/"), "https://1.1.1.1:443/")
+    h.assert_eq(mirror.mirror_url("", "[::1]:80", "/"), "http://[::1]:80/")
+
+    -- https mirror use the name of mirror service as sni, and verify only when it is enabled.
+    local verify, sni = mirror.ssl_options({ backend_protocol = "https", hostname = "svc.ns.svc", verify = false })
+    h.assert_eq(verify, false)
+    h.assert_eq(sni, "svc.ns.svc")
+    verify, sni = mirror.ssl_options({ backend_protocol = "https", hostname = "mirror.example.com", verify = true })
+    h.assert_eq(verify, true)
+    h.assert_eq(sni, "mirror.example.com")
+    verify, sni = mirror.ssl_options({ backend_protocol = "https", hostname = "" })
+    h.assert_eq(verify, false)
+    h.assert_eq(sni, nil)
+
+    -- the body is not read if it is chunked, too large or its length is unknown.
+    local body, ok = mirror.read_body("POST", nil, "chunked", 1.1)
+    h.assert_eq(body, nil)
+    h.assert_eq(ok, false)
+    _, ok = mirror.read_body("POST", tostring(1024 * 1024), nil, 1.1)
+    h.assert_eq(ok, false)
+    _, ok = mirror.read_body("POST", "x", nil, 1.1)
+    h.assert_eq(ok, false)
+    _, ok = mirror.read_body("POST", nil, nil, 2)
+    h.assert_eq(ok, false)
+    body, ok = mirror.read_body("GET", nil, nil, 2)
+    h.assert_eq(body, nil)
+    h.assert_eq(ok, true)
+    body, ok = mirror.read_body("POST", "0", nil, 1.1)
+    h.assert_eq(body, nil)
+    h.assert_eq(ok, true)
+
+    local headers = mirror.mirror_headers({
+        ["Host"] = "a.com",
+        ["Connection"] = "keep-alive, X-Hop",
+        ["X-Hop"] = "1",
+        ["Content-Length"] = "10",
+        ["Transfer-Encoding"] = "chunked",
+        ["Upgrade"] = "websocket",
+        ["X-Id"] = { "1", "2" },
+    })
+    h.assert_eq(headers, { ["host"] = "a.com", ["x-id"] = { "1", "2" } })
+end
+
+return _M
@@ -0,0 +1,43 @@
+local _M = {}
+
+local h = require("test-helper");
+local outlier = require("plugins.outlier")
+
+function _M.test()
+    local tries = outlier.parse_tries("10.0.0.1:80, 10.0.0.2:80 : [fd00::1]:8080", "502, 504 : 200")
+    h.assert_eq(#tries, 3)
+    h.assert_eq(tries[1].peer, "10.0.0.1:80")
+    h.assert_eq(tries[1].status, 502)
+    h.assert_eq(tries[2].peer, "10.0.0.2:80")
+    h.assert_eq(tries[2].status, 504)
+    h.assert_eq(tries[3].peer, "[fd00::1]:8080")
+    h.assert_eq(tries[3].status, 200)
+    h.assert_eq(#outlier.parse_tries("", ""), 0)
+    h.assert_eq(#outlier.parse_tries(nil, nil), 0)
+    -- the request is not sent to upstream
+    h.assert_eq(outlier.parse_tries("10.0.0.1:80", "-")[1].status, nil)
+
+    h.assert_eq(outlier.is_failure(502), true)
+    h.assert_eq(outlier.is_failure(404), false)
+    h.assert_eq(outlier.is_failure(nil), false)
+
+    local cfg = { base_ejection_time_ms = 1000, max_ejection_time_ms = 2500 }
+    h.assert_eq(outlier.ejection_time_ms(cfg, 1), 1000)
+    h.assert_eq(outlier.ejection_time_ms(cfg, 2), 2000)
+    h.assert_eq(outlier.ejection_time_ms(cfg, 3), 2500)
+
+    -- the slot of concurrent requests is released in log phase, which is always rendered even if prometheus is disabled.
+    local get_config = outlier.get_config
+    outlier.get_config = function()
+        return { max_concurrent_requests = 1, consecutive_errors = 0 }, nil
+    end
+    ngx.ctx.upstream = "outlier_test"
+    local ctx = {}
+    outlier.after_rule_match_hook(ctx)
+    h.assert_eq(ngx.shared.http_outlier:get("c:outlier_test"), 1)
+    outlier.log_hook(ctx)
+    h.assert_eq(ngx.shared.http_outlier:get("c:outlier_test"), 0)
+    outlier.get_config = get_config
+end
+
+return _M
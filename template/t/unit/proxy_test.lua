@@ -0,0 +1,15 @@
+local _M = {}
+
+local h = require("test-helper");
+local proxy = require("plugins.proxy")
+
+function _M.test()
+    h.assert_eq(proxy.too_large(1024, "1025"), true)
+    h.assert_eq(proxy.too_large(1024, "1024"), false)
+    -- chunked body is checked by nginx
+    h.assert_eq(proxy.too_large(1024, nil), false)
+    h.assert_eq(proxy.too_large(0, "1025"), false)
+    h.assert_eq(proxy.too_large(nil, "1025"), false)
+end
+
+return _M
@@ -0,0 +1,45 @@
+local _M = {}
+
+local h = require("test-helper");
+local u = require("util")
+local ratelimit = require("plugins.ratelimit")
+
+function _M.test()
+    h.assert_eq(ratelimit.window_key("r1:1.1.1.1", 60, 119), "alb:ratelimit:r1:1.1.1.1:60:1")
+    h.assert_eq(ratelimit.window_key("r1:1.1.1.1", 1, 119), "alb:ratelimit:r1:1.1.1.1:1:119")
+    h.assert_true(ratelimit.allow_in_window(10, { limit = 10, window = 1, burst = 50 }))
+    h.assert_true(not ratelimit.allow_in_window(11, { limit = 10, window = 1, burst = 50 }))
+
+    -- local mode, burst is allowed without delay
+    local r = { limit = 1, window = 60, burst = 2 }
+    local key = "test:local:" .. ngx.now()
+    for i = 1, 3 do
+        local allow, err = ratelimit.incoming_local(r, key)
+        h.assert_eq(err, nil)
+        h.assert_true(allow, "req " .. i)
+    end
+    local allow, err = ratelimit.incoming_local(r, key)
+    h.assert_eq(err, nil)
+    h.assert_true(not allow)
+
+    -- global mode, set ALB_LUA_TEST_REDIS=127.0.0.1:6379 to test against a local redis.
+    local addr = os.getenv("ALB_LUA_TEST_REDIS")
+    if addr == nil or addr == "" then
+        u.logs("skip ratelimit redis test")
+        return
+    end
+    local host, port = addr:match("^(.+):(%d+)$")
+    local redis = { host = host, port = tonumber(port), db = 0, timeout_ms = 1000 }
+    local gr = { limit = 2, window = 60, burst = 0 }
+    local gkey = "test:global:" .. ngx.now()
+    for i = 1, 2 do
+        local gallow, gerr = ratelimit.incoming_global(redis, gr, gkey)
+        h.assert_eq(gerr, nil)
+        h.assert_true(gallow, "req " .. i)
+    end
+    local gallow, gerr = ratelimit.incoming_global(redis, gr, gkey)
+    h.assert_eq(gerr, nil)
+    h.assert_true(not gallow)
+end
+
+return _M
@@ -0,0 +1,22 @@
+local _M = {}
+
+local h = require("test-helper");
+local upstream = require("match_engine.upstream")
+
+function _M.test()
+    local policies = {
+        ["a.com"] = { rule = "exact" },
+        ["*.b.com"] = { rule = "wildcard" },
+        [""] = { rule = "default" },
+    }
+    h.assert_eq(upstream.find_sni_policy(policies, "a.com").rule, "exact")
+    h.assert_eq(upstream.find_sni_policy(policies, "A.com").rule, "exact")
+    h.assert_eq(upstream.find_sni_policy(policies, "x.b.com").rule, "wildcard")
+    h.assert_eq(upstream.find_sni_policy(policies, "x.y.b.com").rule, "wildcard")
+    h.assert_eq(upstream.find_sni_policy(policies, "b.com").rule, "default")
+    h.assert_eq(upstream.find_sni_policy(policies, "").rule, "default")
+    h.assert_eq(upstream.find_sni_policy(policies, nil).rule, "default")
+    h.assert_is_nil(upstream.find_sni_policy({ ["a.com"] = { rule = "exact" } }, "c.com"))
+end
+
+return _M
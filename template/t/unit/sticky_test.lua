@@ -0,0 +1,30 @@
+local _M = {}
+
+local h = require("test-helper");
+local sticky = require("balancer.sticky")
+
+function _M.test()
+    local c = sticky.cookie_data(nil, "JSESSIONID", "v", false, 0)
+    h.assert_eq(c, { key = "JSESSIONID", value = "v", path = "/", httponly = true, secure = false })
+
+    c = sticky.cookie_data({ path = "/app", domain = "a.com", max_age = 60, samesite = "None", secure = true, httponly = false }, "route", "v", false, 0)
+    h.assert_eq(c.path, "/app")
+    h.assert_eq(c.domain, "a.com")
+    h.assert_eq(c.max_age, 60)
+    h.assert_eq(c.expires, "Thu, 01-Jan-70 00:01:00 GMT")
+    h.assert_eq(c.extension, "SameSite=None")
+    h.assert_eq(c.secure, true)
+    h.assert_eq(c.httponly, false)
+
+    h.assert_eq(sticky.should_refresh_cookie(nil), false)
+    h.assert_eq(sticky.should_refresh_cookie({ refresh = true }), false)
+    h.assert_eq(sticky.should_refresh_cookie({ refresh = true, max_age = 60 }), true)
+
+    local s = sticky:new()
+    s.session_affinity_attribute = "a"
+    h.assert_eq(s:cookie_name(), "a")
+    s.session_affinity_cookie = { name = "b" }
+    h.assert_eq(s:cookie_name(), "b")
+end
+
+return _M
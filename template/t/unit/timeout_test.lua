@@ -0,0 +1,29 @@
+local _M = {}
+
+local h = require("test-helper");
+local timeout = require("plugins.timeout")
+
+function _M.test()
+    local connect, send, read = timeout.get_timeouts({ proxy_connect_timeout_ms = 1000, proxy_read_timeout_ms = 5000 }, 100)
+    h.assert_eq(connect, 1)
+    h.assert_eq(send, nil)
+    h.assert_eq(read, 5)
+
+    -- the timeouts of each try are limited by the time left of the whole request.
+    local cfg = { proxy_connect_timeout_ms = 1000, proxy_send_timeout_ms = 10000, proxy_read_timeout_ms = 10000, request_timeout_ms = 10000 }
+    connect, send, read = timeout.get_timeouts(cfg, 0)
+    h.assert_eq({ connect, send, read }, { 1, 10, 10 })
+    connect, send, read = timeout.get_timeouts(cfg, 7)
+    h.assert_eq({ connect, send, read }, { 1, 3, 3 })
+    -- the deadline is exceeded, the try should fail immediately.
+    connect, send, read = timeout.get_timeouts(cfg, 11)
+    h.assert_eq({ connect, send, read }, { 0.001, 0.001, 0.001 })
+    -- only the request timeout is set.
+    connect, send, read = timeout.get_timeouts({ request_timeout_ms = 10000 }, 4)
+    h.assert_eq({ connect, send, read }, { 6, 6, 6 })
+    -- stream has no deadline.
+    connect, send, read = timeout.get_timeouts({ proxy_read_timeout_ms = 10000, request_timeout_ms = 1000 }, nil)
+    h.assert_eq({ connect, send, read }, { nil, nil, 10 })
+end
+
+return _M
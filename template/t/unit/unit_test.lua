@@ -15,6 +15,17 @@ function _M.test()
     require("unit.cert_test").test()
     require("unit.cors_test").test()
     require("unit.common_test").test()
+    require("unit.sni_test").test()
+    require("unit.mirror_test").test()
+    require("unit.ratelimit_test").test()
+    require("unit.ipacl_test").test()
+    require("unit.client_tls_test").test()
+    require("unit.sticky_test").test()
+    require("unit.healthcheck_test").test()
+    require("unit.outlier_test").test()
+    require("unit.timeout_test").test()
+    require("unit.proxy_test").test()
+    require("unit.errorpage_test").test()
     require("unit.plugins.auth.auth_unit_test").test()
 end
 
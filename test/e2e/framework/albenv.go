@@ -164,6 +164,8 @@ func (a *Env) Start() error {
 	cfg.TweakDir = tweakDir
 	cfg.Pod = "p1"
 	cfg.StatusFileParentPath = statusDir
+	cfg.NginxCheckDir = base + "/check"
+	cfg.LastGoodDir = base + "/last_good"
 	cfg.Leader = config.LeaderConfig{
 		LeaseDuration: time.Second * time.Duration(3000),
 		RenewDeadline: time.Second * time.Duration(2000),
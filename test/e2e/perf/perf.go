@@ -8,10 +8,12 @@ import (
 
 	"github.com/go-logr/logr"
 	. "github.com/onsi/ginkgo/v2"
+	. "github.com/onsi/gomega"
 
 	pprof "runtime/pprof"
 
 	"alauda.io/alb2/config"
+	"alauda.io/alb2/controller/cli"
 	"alauda.io/alb2/driver"
 	"alauda.io/alb2/pkg/apis/alauda/shared"
 	albv1 "alauda.io/alb2/pkg/apis/alauda/v1"
@@ -59,7 +61,7 @@ var _ = Describe("rule perf", func() {
 		if os.Getenv("RULE_PERF") == "" {
 			return
 		}
-		init_k8s(env.GetRestCfg())
+		init_k8s(env.GetRestCfg(), 5000)
 		mock := config.DefaultMock()
 		drv, err := driver.NewDriver(driver.DrvOpt{Ctx: ctx, Cf: env.GetRestCfg(), Opt: driver.Cfg2opt(mock)})
 		GinkgoNoErr(err)
@@ -91,6 +93,77 @@ var _ = Describe("rule perf", func() {
 	})
 })
 
+// compare the regeneration cost with and without policy cache, one rule is changed in each round. RULE_PERF=true to run it.
+var _ = Describe("policy cache perf", func() {
+	var env *EnvtestExt
+	var ctx context.Context
+	var l logr.Logger
+	var ctx_cancel context.CancelFunc
+	BeforeEach(func() {
+		l = log.L()
+		env = NewEnvtestExt(InitBase(), l)
+		env.AssertStart()
+		ctx, ctx_cancel = context.WithCancel(context.Background())
+	})
+
+	AfterEach(func() {
+		ctx_cancel()
+		env.Stop()
+	})
+
+	It("should only re-translate the changed rule when has 10k rule", func() {
+		if os.Getenv("RULE_PERF") == "" {
+			return
+		}
+		count := 10000
+		rounds := 10
+		init_k8s(env.GetRestCfg(), count)
+		kc := NewK8sClient(ctx, env.GetRestCfg())
+		mock := config.DefaultMock()
+		drv, err := driver.NewDriver(driver.DrvOpt{Ctx: ctx, Cf: env.GetRestCfg(), Opt: driver.Cfg2opt(mock)})
+		GinkgoNoErr(err)
+		pctx := ptu.PolicyGetCtx{
+			Ctx: ctx, Name: "alb-dev", Ns: "cpaas-system", Drv: drv, L: l,
+			Cfg: mock,
+		}
+		// wait util the informer synced.
+		EventuallySuccess(func(g Gomega) {
+			p, _, err := ptu.GetPolicyAndNgx(pctx)
+			g.Expect(err).ShouldNot(HaveOccurred())
+			g.Expect(len(p.Http.Tcp[80])).Should(Equal(count))
+		}, l)
+
+		// change one rule in each round, like the real world.
+		touch := func(i int) {
+			r, err := kc.GetAlbClient().CrdV1().Rules("cpaas-system").Get(ctx, fmt.Sprintf("rule-%v", i), metav1.GetOptions{})
+			GinkgoNoErr(err)
+			r.Spec.Priority = 5
+			_, err = kc.GetAlbClient().CrdV1().Rules("cpaas-system").Update(ctx, r, metav1.UpdateOptions{})
+			GinkgoNoErr(err)
+			time.Sleep(100 * time.Millisecond)
+		}
+		run := func(cli ptu.XCli, offset int) time.Duration {
+			cost := time.Duration(0)
+			for i := 0; i < rounds; i++ {
+				touch(offset + i)
+				s := time.Now()
+				_, _, err := cli.GetPolicyAndNgx(pctx)
+				GinkgoNoErr(err)
+				cost += time.Since(s)
+			}
+			return cost / time.Duration(rounds)
+		}
+		full := run(ptu.NewXCli(pctx), 0)
+		cached := ptu.NewXCliWithCache(pctx, cli.NewPolicyCache())
+		// the first round fills up the cache.
+		_, _, err = cached.GetPolicyAndNgx(pctx)
+		GinkgoNoErr(err)
+		incr := run(cached, rounds)
+		l.Info("policy cache perf", "rules", count, "full", full, "cached", incr, "t", pm.Read())
+		Expect(incr < full).Should(BeTrue())
+	})
+})
+
 func init_svc_and_ep(ns string, name string, port int, ip string, kt *K8sClient) {
 	svc := k8sv1.Service{
 		ObjectMeta: metav1.ObjectMeta{
@@ -182,7 +255,7 @@ func gen_rule(alb string, ft string, count int, kc *K8sClient) error {
 	return nil
 }
 
-func init_k8s(cfg *rest.Config) {
+func init_k8s(cfg *rest.Config, count int) {
 	kt := NewKubectl("", cfg, log.L())
 	kc := NewK8sClient(context.Background(), cfg)
 	kt.AssertKubectlApply(`
@@ -210,5 +283,5 @@ spec:
   port: 80
   protocol: http
 `)
-	gen_rule("alb-dev", "alb-dev-00080", 5000, kc)
+	gen_rule("alb-dev", "alb-dev-00080", count, kc)
 }